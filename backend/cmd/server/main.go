@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/config"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/database"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/handlers"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/mailer"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/middleware"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
@@ -28,6 +32,8 @@ func main() {
 
 	app := fiber.New()
 
+	app.Use(middleware.RequestInfo())
+
 	api := app.Group("/api")
 
 				// Initialize dependencies
@@ -41,6 +47,7 @@ func main() {
 				venueRepo := repository.NewPGVenueRepository(dbPool)
 
 				slotRepo := repository.NewPGTimetableSlotRepository(dbPool)
+				classAttendanceRepo := repository.NewPGClassAttendanceRepository(dbPool)
 
 				assignmentRepo := repository.NewPGAssignmentRepository(dbPool)
 
@@ -56,21 +63,113 @@ func main() {
 
 				studySessionRepo := repository.NewPGStudySessionRepository(dbPool)
 
+				dailyStatsRepo := repository.NewPGDailyStatsRepository(dbPool)
+
+				activityLogRepo := repository.NewPGActivityLogRepository(dbPool)
+
+				timeEntryRepo := repository.NewPGTimeEntryRepository(dbPool)
+
+				bulkRepo := repository.NewPGBulkRepository(dbPool)
+
+				gradeSchemeRepo := repository.NewPGGradeSchemeRepository(dbPool)
+
+				notificationRepo := repository.NewPGNotificationRepository(dbPool)
+
+				questionReviewRepo := repository.NewPGQuestionReviewRepository(dbPool)
+
+				quizRepo := repository.NewPGQuizRepository(dbPool)
+
+				questionPaperRepo := repository.NewPGQuestionPaperRepository(dbPool)
+
+				examSeatingRepo := repository.NewPGExamSeatingRepository(dbPool)
+
+				pomodoroRepo := repository.NewPGPomodoroRepository(dbPool)
+
+				studyPlanTemplateRepo := repository.NewPGStudyPlanTemplateRepository(dbPool)
+
+				gamificationRepo := repository.NewPGGamificationRepository(dbPool)
+
+				exportRepo := repository.NewPGExportRepository(dbPool)
+
+				weeklyReviewRepo := repository.NewPGWeeklyReviewRepository(dbPool)
+
+				goalRepo := repository.NewPGGoalRepository(dbPool)
+
+				emailMailer := mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+
 			
 
+				eventBus := events.NewBus()
+
+				services.NewActivityLogSubscriber(activityLogRepo).Register(eventBus)
+
 				authService := services.NewAuthService(userRepo, cfg.JWTSecret)
 
-				timetableService := services.NewTimetableService(subjectRepo, staffRepo, venueRepo, slotRepo)
+				timetableService := services.NewTimetableService(subjectRepo, staffRepo, venueRepo, slotRepo, classAttendanceRepo, examRepo, examSeatingRepo, userRepo, eventBus)
 
-				assignmentService := services.NewAssignmentService(assignmentRepo)
+				assignmentService := services.NewAssignmentService(assignmentRepo, subjectRepo, slotRepo, userRepo, eventBus)
 
-				examService := services.NewExamService(examRepo, importantQuestionRepo)
+				examService := services.NewExamService(examRepo, importantQuestionRepo, eventBus)
 
 				labRecordService := services.NewLabRecordService(labRecordRepo)
 
 				documentService := services.NewDocumentService(documentRepo)
 
-				studyPlanService := services.NewStudyPlanService(studyPlanRepo, studySessionRepo)
+				coverageService := services.NewCoverageService(examRepo, studySessionRepo, importantQuestionRepo)
+
+				coverageService.Register(eventBus)
+
+				studyPlanService := services.NewStudyPlanService(studyPlanRepo, studySessionRepo, examRepo, assignmentRepo, coverageService, eventBus)
+
+				bulkService := services.NewBulkService(bulkRepo, subjectRepo, assignmentRepo, examRepo, eventBus)
+
+				gradeBookService := services.NewGradeBookService(gradeSchemeRepo, subjectRepo, examRepo, assignmentRepo, labRecordRepo)
+
+				notificationService := services.NewNotificationService(notificationRepo)
+
+				examConflictService := services.NewExamConflictService(examRepo, assignmentRepo, slotRepo, userRepo, notificationService)
+
+				spacedRepetitionService := services.NewSpacedRepetitionService(importantQuestionRepo, questionReviewRepo, examRepo, userRepo, eventBus)
+
+				quizService := services.NewQuizService(quizRepo, importantQuestionRepo, spacedRepetitionService, eventBus)
+
+				questionPaperService := services.NewQuestionPaperService(questionPaperRepo, importantQuestionRepo, documentRepo, examRepo)
+
+				questionBankService := services.NewQuestionBankService(importantQuestionRepo, examRepo)
+
+				examSeatingService := services.NewExamSeatingService(examSeatingRepo, examRepo, userRepo, subjectRepo, venueRepo)
+
+				studyPlanGeneratorService := services.NewStudyPlanGeneratorService(studyPlanRepo, studySessionRepo, examRepo, assignmentRepo, slotRepo, userRepo, coverageService, eventBus)
+
+				studyCarryOverService := services.NewStudyCarryOverService(studySessionRepo, studyPlanRepo, examRepo, assignmentRepo, slotRepo, userRepo, notificationService)
+
+				pomodoroService := services.NewPomodoroService(pomodoroRepo, studySessionRepo, timeEntryRepo, dailyStatsRepo, userRepo, coverageService, eventBus)
+
+				timerService := services.NewTimerService(timeEntryRepo, assignmentRepo, examRepo, studySessionRepo, userRepo, pomodoroService)
+
+				studyPlanTemplateService := services.NewStudyPlanTemplateService(studyPlanTemplateRepo, studyPlanRepo, studySessionRepo, subjectRepo, userRepo, eventBus)
+
+				analyticsService := services.NewAnalyticsService(activityLogRepo, dailyStatsRepo, userRepo)
+
+				dailyStatsAggregator := services.NewDailyStatsAggregator(dailyStatsRepo, userRepo)
+
+				dailyStatsAggregator.Register(eventBus)
+
+				gamificationService := services.NewGamificationService(gamificationRepo, userRepo, notificationService)
+
+				gamificationService.Register(eventBus)
+
+				goalService := services.NewGoalService(goalRepo, examRepo, subjectRepo, userRepo, notificationService)
+
+				dashboardService := services.NewDashboardService(slotRepo, examRepo, assignmentRepo, labRecordRepo, documentRepo, userRepo, analyticsService, gamificationService, goalService)
+
+				dashboardService.Register(eventBus)
+
+				exportService := services.NewExportService(exportRepo, userRepo)
+
+				insightsService := services.NewInsightsService(exportRepo, assignmentRepo, subjectRepo, userRepo)
+
+				weeklyReviewService := services.NewWeeklyReviewService(weeklyReviewRepo, exportRepo, examRepo, assignmentRepo, dailyStatsRepo, subjectRepo, userRepo, notificationService, emailMailer)
 
 			
 
@@ -88,6 +187,50 @@ func main() {
 
 				studyPlanHandler := handlers.NewStudyPlanHandler(studyPlanService)
 
+				timerHandler := handlers.NewTimerHandler(timerService)
+
+				bulkHandler := handlers.NewBulkHandler(bulkService)
+
+				gradeBookHandler := handlers.NewGradeBookHandler(gradeBookService)
+
+				notificationHandler := handlers.NewNotificationHandler(notificationService)
+
+				examConflictHandler := handlers.NewExamConflictHandler(examConflictService)
+
+				spacedRepetitionHandler := handlers.NewSpacedRepetitionHandler(spacedRepetitionService)
+
+				quizHandler := handlers.NewQuizHandler(quizService)
+
+				coverageHandler := handlers.NewCoverageHandler(coverageService)
+
+				questionPaperHandler := handlers.NewQuestionPaperHandler(questionPaperService)
+
+				questionBankHandler := handlers.NewQuestionBankHandler(questionBankService)
+
+				examSeatingHandler := handlers.NewExamSeatingHandler(examSeatingService)
+
+				studyPlanGeneratorHandler := handlers.NewStudyPlanGeneratorHandler(studyPlanGeneratorService)
+
+				studyCarryOverHandler := handlers.NewStudyCarryOverHandler(studyCarryOverService)
+
+				pomodoroHandler := handlers.NewPomodoroHandler(pomodoroService)
+
+				studyPlanTemplateHandler := handlers.NewStudyPlanTemplateHandler(studyPlanTemplateService)
+
+				analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, dailyStatsAggregator)
+
+				gamificationHandler := handlers.NewGamificationHandler(gamificationService)
+
+				dashboardHandler := handlers.NewDashboardHandler(dashboardService)
+
+				exportHandler := handlers.NewExportHandler(exportService)
+
+				insightsHandler := handlers.NewInsightsHandler(insightsService)
+
+				weeklyReviewHandler := handlers.NewWeeklyReviewHandler(weeklyReviewService)
+
+				goalHandler := handlers.NewGoalHandler(goalService)
+
 			
 
 				// --- Public Routes ---
@@ -128,6 +271,14 @@ func main() {
 
 				protected.Get("/me", authHandler.GetUserProfile)
 
+				protected.Get("/me/progress", gamificationHandler.GetProgress)
+
+				protected.Get("/xp-rules", gamificationHandler.GetXPRules)
+
+				protected.Put("/xp-rules/:rule", middleware.AdminOnly(), gamificationHandler.UpdateXPRule)
+
+				protected.Get("/dashboard", dashboardHandler.GetDashboard)
+
 			
 
 				// Timetable Protected Routes
@@ -148,6 +299,10 @@ func main() {
 
 				timetableProtectedRoutes.Get("/export-ics", timetableHandler.ExportICSCalendar)
 
+				timetableProtectedRoutes.Put("/attendance", timetableHandler.RecordClassAttendance)
+
+				timetableProtectedRoutes.Get("/attendance", timetableHandler.GetClassAttendance)
+
 			
 
 				// Assignment Protected Routes
@@ -162,6 +317,10 @@ func main() {
 
 				assignmentProtectedRoutes.Get("/overdue", assignmentHandler.GetOverdueAssignments)
 
+				assignmentProtectedRoutes.Get("/focus", assignmentHandler.GetFocusAssignments)
+
+				assignmentProtectedRoutes.Post("/bulk", bulkHandler.BulkAssignments)
+
 				assignmentProtectedRoutes.Get("/:id", assignmentHandler.GetAssignmentByID)
 
 				assignmentProtectedRoutes.Put("/:id", assignmentHandler.UpdateAssignment)
@@ -182,12 +341,34 @@ func main() {
 
 				examProtectedRoutes.Get("/upcoming", examHandler.GetUpcomingExams)
 
+				examProtectedRoutes.Post("/bulk", bulkHandler.BulkExams)
+
+				examProtectedRoutes.Get("/conflicts", examConflictHandler.GetExamConflicts)
+
+				examProtectedRoutes.Get("/coverage", coverageHandler.GetUpcomingExamCoverage)
+
+				examProtectedRoutes.Get("/briefing", examSeatingHandler.GetExamBriefing)
+
 				examProtectedRoutes.Get("/:id", examHandler.GetExamByID)
 
 				examProtectedRoutes.Put("/:id", examHandler.UpdateExam)
 
 				examProtectedRoutes.Patch("/:id/prep-status", examHandler.UpdateExamPrepStatus)
 
+				examProtectedRoutes.Delete("/:id/prep-status/override", coverageHandler.ClearPrepStatusOverride)
+
+				examProtectedRoutes.Get("/:id/coverage", coverageHandler.GetExamCoverage)
+
+				examProtectedRoutes.Get("/:id/question-suggestions", questionPaperHandler.GetExamQuestionSuggestions)
+
+				examProtectedRoutes.Get("/:id/seating", examSeatingHandler.GetExamSeating)
+
+				examProtectedRoutes.Put("/:id/seating", examSeatingHandler.SetExamSeating)
+
+				examProtectedRoutes.Post("/:id/seating/import", examSeatingHandler.ImportSeatingChart)
+
+				examProtectedRoutes.Delete("/:id/seating", examSeatingHandler.DeleteExamSeating)
+
 				examProtectedRoutes.Delete("/:id", examHandler.DeleteExam)
 
 				
@@ -200,12 +381,26 @@ func main() {
 
 				importantQuestionProtectedRoutes.Get("/subject/:subjectId", examHandler.GetImportantQuestionsBySubjectID)
 
+				importantQuestionProtectedRoutes.Get("/due", spacedRepetitionHandler.GetDueQuestions)
+
+				importantQuestionProtectedRoutes.Get("/review-session", spacedRepetitionHandler.GetReviewSession)
+
+				importantQuestionProtectedRoutes.Post("/review-session", spacedRepetitionHandler.SubmitReviewSession)
+
+				importantQuestionProtectedRoutes.Post("/import", questionBankHandler.ImportQuestions)
+
+				importantQuestionProtectedRoutes.Get("/export", questionBankHandler.ExportQuestions)
+
 				importantQuestionProtectedRoutes.Get("/:id", examHandler.GetImportantQuestionByID)
 
 				importantQuestionProtectedRoutes.Put("/:id", examHandler.UpdateImportantQuestion)
 
 				importantQuestionProtectedRoutes.Delete("/:id", examHandler.DeleteImportantQuestion)
 
+				importantQuestionProtectedRoutes.Post("/:id/review", spacedRepetitionHandler.ReviewQuestion)
+
+				importantQuestionProtectedRoutes.Get("/:id/reviews", spacedRepetitionHandler.GetQuestionReviews)
+
 			
 
 				// Lab Record Protected Routes
@@ -218,6 +413,8 @@ func main() {
 
 				labRecordProtectedRoutes.Get("/subject/:subjectId", labRecordHandler.GetLabRecordsBySubjectID)
 
+				labRecordProtectedRoutes.Post("/bulk", bulkHandler.BulkLabRecords)
+
 				labRecordProtectedRoutes.Get("/:id", labRecordHandler.GetLabRecordByID)
 
 				labRecordProtectedRoutes.Put("/:id", labRecordHandler.UpdateLabRecord)
@@ -238,12 +435,34 @@ func main() {
 
 				studyPlanProtectedRoutes.Get("/date", studyPlanHandler.GetStudyPlansByDate)
 
+				studyPlanProtectedRoutes.Post("/generate", studyPlanGeneratorHandler.GenerateStudyPlan)
+
 				studyPlanProtectedRoutes.Get("/:id", studyPlanHandler.GetStudyPlanByID)
 
 				studyPlanProtectedRoutes.Put("/:id", studyPlanHandler.UpdateStudyPlan)
 
 				studyPlanProtectedRoutes.Delete("/:id", studyPlanHandler.DeleteStudyPlan)
 
+				studyPlanProtectedRoutes.Post("/:id/clone", studyPlanTemplateHandler.CloneStudyPlan)
+
+			
+
+				// Study Plan Template Protected Routes
+
+				studyPlanTemplateProtectedRoutes := protected.Group("/study-plan-templates")
+
+				studyPlanTemplateProtectedRoutes.Post("/", studyPlanTemplateHandler.CreateStudyPlanTemplate)
+
+				studyPlanTemplateProtectedRoutes.Get("/", studyPlanTemplateHandler.GetStudyPlanTemplates)
+
+				studyPlanTemplateProtectedRoutes.Get("/:id", studyPlanTemplateHandler.GetStudyPlanTemplateByID)
+
+				studyPlanTemplateProtectedRoutes.Put("/:id", studyPlanTemplateHandler.UpdateStudyPlanTemplate)
+
+				studyPlanTemplateProtectedRoutes.Delete("/:id", studyPlanTemplateHandler.DeleteStudyPlanTemplate)
+
+				studyPlanTemplateProtectedRoutes.Post("/:id/instantiate", studyPlanTemplateHandler.InstantiateStudyPlanTemplate)
+
 			
 
 				studySessionProtectedRoutes := protected.Group("/study-sessions")
@@ -252,6 +471,8 @@ func main() {
 
 				studySessionProtectedRoutes.Get("/", studyPlanHandler.GetStudySessions)
 
+				studySessionProtectedRoutes.Post("/carry-over", studyCarryOverHandler.CarryOverSessions)
+
 				studySessionProtectedRoutes.Get("/plan/:studyPlanId", studyPlanHandler.GetStudySessionsByStudyPlanID)
 
 				studySessionProtectedRoutes.Get("/:id", studyPlanHandler.GetStudySessionByID)
@@ -260,8 +481,207 @@ func main() {
 
 				studySessionProtectedRoutes.Delete("/:id", studyPlanHandler.DeleteStudySession)
 
+				studySessionProtectedRoutes.Post("/:id/pomodoro", pomodoroHandler.StartPomodoro)
+
+				studySessionProtectedRoutes.Get("/:id/pomodoros", pomodoroHandler.GetPomodorosByStudySession)
+
+			
+
+				// Pomodoro Protected Routes
+
+				pomodoroProtectedRoutes := protected.Group("/pomodoro")
+
+				pomodoroProtectedRoutes.Get("/current", pomodoroHandler.GetCurrentPomodoro)
+
+				pomodoroProtectedRoutes.Post("/pause", pomodoroHandler.PausePomodoro)
+
+				pomodoroProtectedRoutes.Post("/resume", pomodoroHandler.ResumePomodoro)
+
+				pomodoroProtectedRoutes.Post("/skip-break", pomodoroHandler.SkipBreak)
+
+				pomodoroProtectedRoutes.Post("/interruptions", pomodoroHandler.RecordInterruption)
+
+				pomodoroProtectedRoutes.Post("/stop", pomodoroHandler.StopPomodoro)
+
+			
+
+				// Timer Protected Routes
+
+				timerProtectedRoutes := protected.Group("/timers")
+
+				timerProtectedRoutes.Post("/start", timerHandler.StartTimer)
+
+				timerProtectedRoutes.Post("/pause", timerHandler.PauseTimer)
+
+				timerProtectedRoutes.Post("/resume", timerHandler.ResumeTimer)
+
+				timerProtectedRoutes.Post("/stop", timerHandler.StopTimer)
+
+				timerProtectedRoutes.Get("/current", timerHandler.GetCurrentTimer)
+
+				timerProtectedRoutes.Get("/entries", timerHandler.GetTimeEntries)
+
+			
+
+				// Analytics Protected Routes
+
+				analyticsProtectedRoutes := protected.Group("/analytics")
+
+				analyticsProtectedRoutes.Post("/activity-logs", analyticsHandler.CreateActivityLog)
+
+				analyticsProtectedRoutes.Get("/activity-logs", analyticsHandler.GetActivityLogs)
+
+				analyticsProtectedRoutes.Get("/daily-stats", analyticsHandler.GetDailyStats)
+
+				analyticsProtectedRoutes.Get("/daily-stats/date", analyticsHandler.GetDailyStatsByDate)
+
+				analyticsProtectedRoutes.Put("/daily-stats", middleware.AdminOnly(), analyticsHandler.UpsertDailyStats)
+
+				analyticsProtectedRoutes.Post("/daily-stats/backfill", middleware.AdminOnly(), analyticsHandler.BackfillDailyStats)
+
+				analyticsProtectedRoutes.Get("/summary/weekly", analyticsHandler.GetWeeklyTotals)
+
+				analyticsProtectedRoutes.Get("/summary/monthly", analyticsHandler.GetMonthlyTotals)
+
+				analyticsProtectedRoutes.Get("/streaks", analyticsHandler.GetStudyStreak)
+
+				analyticsProtectedRoutes.Get("/timeseries", analyticsHandler.GetTimeSeries)
+
+				analyticsProtectedRoutes.Get("/export/:dataset", exportHandler.ExportAnalytics)
+
+				analyticsProtectedRoutes.Get("/insights", insightsHandler.GetProductivityInsights)
+
+			
+
+				// Grade Book Protected Routes
+
+				gradeBookProtectedRoutes := protected.Group("/grade-book")
+
+				gradeBookProtectedRoutes.Get("/", gradeBookHandler.GetGradeBook)
+
+				gradeBookProtectedRoutes.Get("/subjects/:subjectId", gradeBookHandler.GetSubjectGrade)
+
+				gradeBookProtectedRoutes.Get("/subjects/:subjectId/projection", gradeBookHandler.ProjectGrade)
+
+				gradeBookProtectedRoutes.Post("/schemes", gradeBookHandler.CreateGradeScheme)
+
+				gradeBookProtectedRoutes.Get("/schemes", gradeBookHandler.GetGradeSchemes)
+
+				gradeBookProtectedRoutes.Get("/schemes/:id", gradeBookHandler.GetGradeSchemeByID)
+
+				gradeBookProtectedRoutes.Put("/schemes/:id", gradeBookHandler.UpdateGradeScheme)
+
+				gradeBookProtectedRoutes.Delete("/schemes/:id", gradeBookHandler.DeleteGradeScheme)
+
+			
+
+				// Notification Protected Routes
+
+				notificationProtectedRoutes := protected.Group("/notifications")
+
+				notificationProtectedRoutes.Get("/", notificationHandler.GetNotifications)
+
+				notificationProtectedRoutes.Post("/read-all", notificationHandler.MarkAllNotificationsRead)
+
+				notificationProtectedRoutes.Patch("/:id/read", notificationHandler.MarkNotificationRead)
+
+				notificationProtectedRoutes.Delete("/:id", notificationHandler.DeleteNotification)
+
+			
+
+				// Quiz Protected Routes
+
+				quizProtectedRoutes := protected.Group("/quizzes")
+
+				quizProtectedRoutes.Post("/", quizHandler.CreateQuiz)
+
+				quizProtectedRoutes.Get("/", quizHandler.GetQuizzes)
+
+				quizProtectedRoutes.Get("/:id", quizHandler.GetQuizByID)
+
+				quizProtectedRoutes.Get("/:id/current", quizHandler.GetCurrentQuestion)
+
+				quizProtectedRoutes.Post("/:id/questions/:position/answer", quizHandler.AnswerQuestion)
+
+				quizProtectedRoutes.Post("/:id/complete", quizHandler.CompleteQuiz)
+
+				quizProtectedRoutes.Delete("/:id", quizHandler.DeleteQuiz)
+
+			
+
+				// Question Paper Protected Routes
+
+				questionPaperProtectedRoutes := protected.Group("/question-papers")
+
+				questionPaperProtectedRoutes.Post("/ingest", questionPaperHandler.IngestPaper)
+
+				questionPaperProtectedRoutes.Post("/documents/:documentId/ingest", questionPaperHandler.IngestDocument)
+
+				questionPaperProtectedRoutes.Get("/", questionPaperHandler.GetQuestionPapers)
+
+				questionPaperProtectedRoutes.Delete("/:id", questionPaperHandler.DeleteQuestionPaper)
+
 			
 
+				// Weekly Review Protected Routes
+
+				weeklyReviewProtectedRoutes := protected.Group("/weekly-reviews")
+
+				weeklyReviewProtectedRoutes.Post("/generate", weeklyReviewHandler.GenerateWeeklyReview)
+
+				weeklyReviewProtectedRoutes.Get("/", weeklyReviewHandler.GetWeeklyReviews)
+
+				weeklyReviewProtectedRoutes.Get("/:id", weeklyReviewHandler.GetWeeklyReview)
+
+				weeklyReviewProtectedRoutes.Get("/:id/html", weeklyReviewHandler.GetWeeklyReviewHTML)
+
+				weeklyReviewProtectedRoutes.Post("/:id/email", weeklyReviewHandler.EmailWeeklyReview)
+
+			
+
+				// Goal Protected Routes
+
+				goalProtectedRoutes := protected.Group("/goals")
+
+				goalProtectedRoutes.Post("/", goalHandler.CreateGoal)
+
+				goalProtectedRoutes.Get("/", goalHandler.GetGoals)
+
+				goalProtectedRoutes.Get("/:id", goalHandler.GetGoal)
+
+				goalProtectedRoutes.Put("/:id", goalHandler.UpdateGoal)
+
+				goalProtectedRoutes.Delete("/:id", goalHandler.DeleteGoal)
+
+				goalProtectedRoutes.Get("/:id/history", goalHandler.GetGoalHistory)
+
+			
+
+			
+
+				// Carry over unfinished study sessions once each user's day has ended
+				go studyCarryOverService.RunScheduler(context.Background(), time.Hour)
+
+			
+
+				// Reconcile each user's recent daily stats once a night in their timezone
+				go dailyStatsAggregator.RunScheduler(context.Background(), time.Hour)
+
+			
+
+				// Generate, and deliver, each user's weekly review on Sunday evening in their timezone
+				go weeklyReviewService.RunScheduler(context.Background(), time.Hour)
+
+			
+
+				// Record finished goal periods and warn about goals falling behind
+				go goalService.RunScheduler(context.Background(), time.Hour)
+
+			
+
+				// Notify users about exam clashes, overloaded days and crunch weeks
+				go examConflictService.RunScheduler(context.Background(), time.Hour)
+
 			
 
 				log.Printf("Starting server on port %s", cfg.Port)
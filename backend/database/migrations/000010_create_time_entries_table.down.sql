@@ -0,0 +1,4 @@
+-- Migration: 000010_create_time_entries_table.down.sql
+
+DROP TRIGGER IF EXISTS update_time_entries_updated_at ON time_entries;
+DROP TABLE IF EXISTS time_entries;
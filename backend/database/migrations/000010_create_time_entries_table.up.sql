@@ -0,0 +1,38 @@
+-- Migration: 000010_create_time_entries_table.up.sql
+
+-- Time Entries Table (start/pause/stop timers)
+CREATE TABLE time_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+
+    -- What the time was spent on
+    entity_type VARCHAR(30) NOT NULL CHECK (entity_type IN (
+        'assignment', 'exam', 'study_session'
+    )),
+    entity_id UUID NOT NULL,
+
+    -- Timing
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    duration_minutes INT,
+
+    status VARCHAR(20) DEFAULT 'running' CHECK (status IN (
+        'running', 'paused', 'stopped'
+    )),
+    auto_stopped BOOLEAN DEFAULT false, -- Closed by the server after being left running
+
+    notes TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_time_entries_user ON time_entries(user_id, started_at);
+CREATE INDEX idx_time_entries_entity ON time_entries(entity_type, entity_id);
+
+-- At most one running timer per user
+CREATE UNIQUE INDEX idx_time_entries_one_running ON time_entries(user_id) WHERE status = 'running';
+
+-- Apply the auto-update trigger to the new time_entries table
+CREATE TRIGGER update_time_entries_updated_at BEFORE UPDATE ON time_entries
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+-- Migration: 000011_add_tags_to_exams_and_lab_records.down.sql
+
+DROP INDEX IF EXISTS idx_lab_records_tags;
+DROP INDEX IF EXISTS idx_exams_tags;
+ALTER TABLE lab_records DROP COLUMN IF EXISTS tags;
+ALTER TABLE exams DROP COLUMN IF EXISTS tags;
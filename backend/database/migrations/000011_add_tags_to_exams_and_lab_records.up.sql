@@ -0,0 +1,7 @@
+-- Migration: 000011_add_tags_to_exams_and_lab_records.up.sql
+
+ALTER TABLE exams ADD COLUMN tags TEXT[];
+ALTER TABLE lab_records ADD COLUMN tags TEXT[];
+
+CREATE INDEX idx_exams_tags ON exams USING GIN(tags);
+CREATE INDEX idx_lab_records_tags ON lab_records USING GIN(tags);
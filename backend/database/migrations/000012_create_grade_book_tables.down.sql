@@ -0,0 +1,5 @@
+-- Migration: 000012_create_grade_book_tables.down.sql
+
+DROP TRIGGER IF EXISTS update_grade_schemes_updated_at ON grade_schemes;
+DROP TABLE IF EXISTS grade_scheme_components;
+DROP TABLE IF EXISTS grade_schemes;
@@ -0,0 +1,40 @@
+-- Migration: 000012_create_grade_book_tables.up.sql
+
+-- Grade Schemes Table (weightage of internal and final components per subject)
+CREATE TABLE grade_schemes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    subject_id UUID REFERENCES subjects(id) ON DELETE CASCADE, -- NULL means the user's default scheme
+
+    name VARCHAR(100) NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(user_id, subject_id)
+);
+
+-- Only one default scheme per user
+CREATE UNIQUE INDEX idx_grade_schemes_user_default ON grade_schemes(user_id) WHERE subject_id IS NULL;
+
+-- Grade Scheme Components Table
+CREATE TABLE grade_scheme_components (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    scheme_id UUID REFERENCES grade_schemes(id) ON DELETE CASCADE,
+
+    name VARCHAR(50) NOT NULL, -- e.g. 'CAT1', 'Assignments', 'Lab', 'FAT'
+    source_type VARCHAR(20) NOT NULL CHECK (source_type IN ('exam', 'assignment', 'lab_record')),
+    exam_type VARCHAR(30), -- Which exams count towards an 'exam' component, e.g. 'cat1'
+
+    weight DECIMAL(5,2) NOT NULL CHECK (weight > 0),
+    item_max_marks DECIMAL(5,2), -- Max marks for items that do not record their own
+    is_final BOOLEAN DEFAULT false, -- The end-semester component used for projections
+
+    sort_order INT DEFAULT 0
+);
+
+CREATE INDEX idx_grade_scheme_components_scheme ON grade_scheme_components(scheme_id);
+
+-- Apply the auto-update trigger to the new grade_schemes table
+CREATE TRIGGER update_grade_schemes_updated_at BEFORE UPDATE ON grade_schemes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
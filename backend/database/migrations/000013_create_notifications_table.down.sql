@@ -0,0 +1,3 @@
+-- Migration: 000013_create_notifications_table.down.sql
+
+DROP TABLE IF EXISTS notifications;
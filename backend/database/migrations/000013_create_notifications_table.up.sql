@@ -0,0 +1,27 @@
+-- Migration: 000013_create_notifications_table.up.sql
+
+-- Notifications Table
+CREATE TABLE notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+
+    notification_type VARCHAR(50) NOT NULL, -- e.g. 'exam_clash', 'exam_overload', 'crunch_week'
+    title VARCHAR(255) NOT NULL,
+    message TEXT,
+
+    entity_type VARCHAR(30), -- 'exam', 'assignment', etc.
+    entity_id UUID,
+
+    -- Identifies the underlying condition so repeated analyses do not notify twice
+    dedupe_key VARCHAR(255),
+
+    is_read BOOLEAN DEFAULT false,
+    read_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(user_id, dedupe_key)
+);
+
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at);
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE is_read = false;
@@ -0,0 +1,9 @@
+-- Migration: 000014_add_spaced_repetition_to_important_questions.down.sql
+
+DROP TABLE IF EXISTS question_reviews;
+DROP INDEX IF EXISTS idx_imp_questions_next_review;
+ALTER TABLE important_questions
+    DROP COLUMN IF EXISTS next_review_at,
+    DROP COLUMN IF EXISTS interval_days,
+    DROP COLUMN IF EXISTS repetition_count,
+    DROP COLUMN IF EXISTS ease_factor;
@@ -0,0 +1,31 @@
+-- Migration: 000014_add_spaced_repetition_to_important_questions.up.sql
+
+-- SM-2 scheduling state per question
+ALTER TABLE important_questions
+    ADD COLUMN ease_factor DECIMAL(4,2) NOT NULL DEFAULT 2.5,
+    ADD COLUMN repetition_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN interval_days INT NOT NULL DEFAULT 0,
+    ADD COLUMN next_review_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_imp_questions_next_review ON important_questions(user_id, next_review_at);
+
+-- Question Reviews Table (one row per practice attempt)
+CREATE TABLE question_reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    question_id UUID REFERENCES important_questions(id) ON DELETE CASCADE,
+
+    quality INT NOT NULL CHECK (quality BETWEEN 0 AND 5), -- SM-2 recall quality
+    confidence_level INT CHECK (confidence_level BETWEEN 1 AND 5),
+    time_spent_seconds INT,
+
+    -- Schedule after this review
+    ease_factor DECIMAL(4,2) NOT NULL,
+    interval_days INT NOT NULL,
+    next_review_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    reviewed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_question_reviews_question ON question_reviews(question_id, reviewed_at);
+CREATE INDEX idx_question_reviews_user ON question_reviews(user_id, reviewed_at);
@@ -0,0 +1,5 @@
+-- Migration: 000015_create_quizzes_tables.down.sql
+
+DROP TRIGGER IF EXISTS update_quizzes_updated_at ON quizzes;
+DROP TABLE IF EXISTS quiz_questions;
+DROP TABLE IF EXISTS quizzes;
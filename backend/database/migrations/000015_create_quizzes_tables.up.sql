@@ -0,0 +1,61 @@
+-- Migration: 000015_create_quizzes_tables.up.sql
+
+-- Quizzes Table (a practice session assembled from important questions)
+CREATE TABLE quizzes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+
+    -- Filters used to assemble the quiz
+    exam_id UUID REFERENCES exams(id) ON DELETE SET NULL,
+    subject_id UUID REFERENCES subjects(id) ON DELETE SET NULL,
+    unit VARCHAR(50),
+    topic VARCHAR(255),
+    marks INT,
+
+    status VARCHAR(20) DEFAULT 'in_progress' CHECK (status IN ('in_progress', 'completed')),
+
+    -- Results
+    total_questions INT NOT NULL DEFAULT 0,
+    answered_count INT NOT NULL DEFAULT 0,
+    correct_count INT NOT NULL DEFAULT 0,
+    partial_count INT NOT NULL DEFAULT 0,
+    incorrect_count INT NOT NULL DEFAULT 0,
+    skipped_count INT NOT NULL DEFAULT 0,
+    score_percentage DECIMAL(5,2),
+    average_confidence DECIMAL(3,2),
+    total_time_seconds INT NOT NULL DEFAULT 0,
+
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_quizzes_user ON quizzes(user_id, started_at);
+
+-- Quiz Questions Table (one row per question in a quiz, with the response)
+CREATE TABLE quiz_questions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    quiz_id UUID REFERENCES quizzes(id) ON DELETE CASCADE,
+    question_id UUID REFERENCES important_questions(id) ON DELETE SET NULL,
+    position INT NOT NULL,
+
+    -- Snapshot so results survive edits to the question bank
+    question_text TEXT NOT NULL,
+    marks INT,
+
+    self_grade VARCHAR(20) CHECK (self_grade IN ('correct', 'partial', 'incorrect', 'skipped')),
+    confidence_level INT CHECK (confidence_level BETWEEN 1 AND 5),
+    time_spent_seconds INT,
+    answered_at TIMESTAMP WITH TIME ZONE,
+
+    UNIQUE(quiz_id, position)
+);
+
+CREATE INDEX idx_quiz_questions_question ON quiz_questions(question_id);
+
+-- Apply the auto-update trigger to the new quizzes table
+CREATE TRIGGER update_quizzes_updated_at BEFORE UPDATE ON quizzes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
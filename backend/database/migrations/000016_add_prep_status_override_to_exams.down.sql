@@ -0,0 +1,3 @@
+-- Migration: 000016_add_prep_status_override_to_exams.down.sql
+
+ALTER TABLE exams DROP COLUMN IF EXISTS prep_status_override;
@@ -0,0 +1,4 @@
+-- Migration: 000016_add_prep_status_override_to_exams.up.sql
+
+-- Set when the user picks a prep status by hand; coverage tracking then stops advancing it
+ALTER TABLE exams ADD COLUMN prep_status_override BOOLEAN DEFAULT false;
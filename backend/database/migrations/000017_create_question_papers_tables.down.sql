@@ -0,0 +1,4 @@
+-- Migration: 000017_create_question_papers_tables.down.sql
+
+DROP TABLE IF EXISTS question_paper_occurrences;
+DROP TABLE IF EXISTS question_papers;
@@ -0,0 +1,38 @@
+-- Migration: 000017_create_question_papers_tables.up.sql
+
+-- Question Papers Table (one row per ingested previous paper)
+CREATE TABLE question_papers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    document_id UUID REFERENCES documents(id) ON DELETE SET NULL, -- Vault document the paper came from, if any
+    subject_id UUID REFERENCES subjects(id) ON DELETE SET NULL,
+    exam_id UUID REFERENCES exams(id) ON DELETE SET NULL,
+
+    source VARCHAR(255) NOT NULL, -- e.g. 'FAT Nov 2023'
+    file_name VARCHAR(255),
+
+    -- Ingestion results
+    extracted_count INT NOT NULL DEFAULT 0,
+    created_count INT NOT NULL DEFAULT 0,
+    merged_count INT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(user_id, document_id)
+);
+
+CREATE INDEX idx_question_papers_user ON question_papers(user_id, created_at);
+
+-- Question Paper Occurrences Table (which important questions appeared in which paper)
+CREATE TABLE question_paper_occurrences (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    paper_id UUID REFERENCES question_papers(id) ON DELETE CASCADE,
+    question_id UUID REFERENCES important_questions(id) ON DELETE CASCADE,
+
+    extracted_text TEXT NOT NULL,
+    similarity DECIMAL(4,3) NOT NULL, -- 1.000 for newly created questions
+
+    UNIQUE(paper_id, question_id)
+);
+
+CREATE INDEX idx_question_paper_occurrences_question ON question_paper_occurrences(question_id);
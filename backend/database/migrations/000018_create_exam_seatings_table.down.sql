@@ -0,0 +1,3 @@
+-- Migration: 000018_create_exam_seatings_table.down.sql
+
+DROP TABLE IF EXISTS exam_seatings;
@@ -0,0 +1,31 @@
+-- Migration: 000018_create_exam_seatings_table.up.sql
+
+-- Exam Seatings Table (hall ticket details for a user's exam)
+CREATE TABLE exam_seatings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    exam_id UUID REFERENCES exams(id) ON DELETE CASCADE,
+    venue_id UUID REFERENCES venues(id) ON DELETE SET NULL,
+
+    venue_name VARCHAR(255), -- As printed on the seating chart, e.g. 'SJT Block'
+    room VARCHAR(100),
+    seat_number VARCHAR(50),
+    register_number VARCHAR(50), -- Register number the seat was allocated to
+
+    reporting_time TIME,
+    items_allowed TEXT[] DEFAULT '{}', -- e.g. 'Calculator', 'Drawing instruments'
+    instructions TEXT,
+
+    source VARCHAR(20) DEFAULT 'manual' CHECK (source IN ('manual', 'import')),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(exam_id)
+);
+
+CREATE INDEX idx_exam_seatings_user ON exam_seatings(user_id);
+
+-- Apply the auto-update trigger to the new exam_seatings table
+CREATE TRIGGER update_exam_seatings_updated_at BEFORE UPDATE ON exam_seatings
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+-- Migration: 000019_add_planner_fields_to_study_plans.down.sql
+
+ALTER TABLE study_sessions DROP COLUMN IF EXISTS assignment_id;
+ALTER TABLE study_sessions DROP COLUMN IF EXISTS exam_id;
+ALTER TABLE study_plans DROP COLUMN IF EXISTS generation_settings;
+ALTER TABLE study_plans DROP COLUMN IF EXISTS end_date;
@@ -0,0 +1,9 @@
+-- Migration: 000019_add_planner_fields_to_study_plans.up.sql
+
+-- Multi-day plans produced by the study plan generator
+ALTER TABLE study_plans ADD COLUMN end_date DATE; -- Last day of a multi-day plan; NULL for single-day plans
+ALTER TABLE study_plans ADD COLUMN generation_settings JSONB; -- Planner settings of a generated plan; NULL for manual plans
+
+-- Link sessions to the exam or assignment they prepare for
+ALTER TABLE study_sessions ADD COLUMN exam_id UUID REFERENCES exams(id) ON DELETE SET NULL;
+ALTER TABLE study_sessions ADD COLUMN assignment_id UUID REFERENCES assignments(id) ON DELETE SET NULL;
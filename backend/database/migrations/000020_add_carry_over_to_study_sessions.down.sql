@@ -0,0 +1,5 @@
+-- Migration: 000020_add_carry_over_to_study_sessions.down.sql
+
+DROP INDEX IF EXISTS idx_study_sessions_carry_over;
+ALTER TABLE study_sessions DROP COLUMN IF EXISTS carried_over_at;
+ALTER TABLE study_sessions DROP COLUMN IF EXISTS carried_over_from;
@@ -0,0 +1,8 @@
+-- Migration: 000020_add_carry_over_to_study_sessions.up.sql
+
+-- Carry-over of unfinished topics from skipped and partial sessions
+ALTER TABLE study_sessions ADD COLUMN carried_over_from UUID REFERENCES study_sessions(id) ON DELETE SET NULL; -- Session whose unfinished work this one continues
+ALTER TABLE study_sessions ADD COLUMN carried_over_at TIMESTAMP WITH TIME ZONE; -- When the unfinished work of this session was carried over
+
+CREATE INDEX idx_study_sessions_carry_over ON study_sessions(planned_start_time)
+    WHERE status IN ('skipped', 'partial') AND carried_over_at IS NULL;
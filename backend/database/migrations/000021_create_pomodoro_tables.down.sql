@@ -0,0 +1,5 @@
+-- Migration: 000021_create_pomodoro_tables.down.sql
+
+DROP TABLE IF EXISTS pomodoro_interruptions;
+DROP TRIGGER IF EXISTS update_pomodoro_sessions_updated_at ON pomodoro_sessions;
+DROP TABLE IF EXISTS pomodoro_sessions;
@@ -0,0 +1,62 @@
+-- Migration: 000021_create_pomodoro_tables.up.sql
+
+-- Pomodoro Sessions Table (focused work/break cycles on a study session)
+CREATE TABLE pomodoro_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    study_session_id UUID NOT NULL REFERENCES study_sessions(id) ON DELETE CASCADE,
+
+    -- Configuration
+    work_minutes INT NOT NULL CHECK (work_minutes BETWEEN 5 AND 120),
+    short_break_minutes INT NOT NULL CHECK (short_break_minutes BETWEEN 1 AND 60),
+    long_break_minutes INT NOT NULL CHECK (long_break_minutes BETWEEN 1 AND 90),
+    cycles_before_long_break INT NOT NULL CHECK (cycles_before_long_break BETWEEN 1 AND 12),
+    target_cycles INT NOT NULL CHECK (target_cycles BETWEEN 1 AND 12),
+
+    -- Current state
+    phase VARCHAR(20) NOT NULL DEFAULT 'work' CHECK (phase IN (
+        'work', 'short_break', 'long_break'
+    )),
+    status VARCHAR(20) NOT NULL DEFAULT 'running' CHECK (status IN (
+        'running', 'paused', 'completed', 'stopped'
+    )),
+    completed_cycles INT NOT NULL DEFAULT 0,
+    phase_started_at TIMESTAMP WITH TIME ZONE NOT NULL, -- Start of the current stretch of the phase
+    phase_elapsed_seconds INT NOT NULL DEFAULT 0, -- Time spent in the phase before phase_started_at, across pauses
+
+    -- Totals
+    focus_seconds INT NOT NULL DEFAULT 0,
+    interruption_count INT NOT NULL DEFAULT 0,
+
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    paused_at TIMESTAMP WITH TIME ZONE,
+    ended_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_pomodoro_sessions_user ON pomodoro_sessions(user_id, started_at);
+CREATE INDEX idx_pomodoro_sessions_study_session ON pomodoro_sessions(study_session_id);
+
+-- At most one running or paused pomodoro per user
+CREATE UNIQUE INDEX idx_pomodoro_sessions_one_active ON pomodoro_sessions(user_id) WHERE status IN ('running', 'paused');
+
+-- Pomodoro Interruptions Table
+CREATE TABLE pomodoro_interruptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pomodoro_id UUID NOT NULL REFERENCES pomodoro_sessions(id) ON DELETE CASCADE,
+
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    phase VARCHAR(20) NOT NULL CHECK (phase IN ('work', 'short_break', 'long_break')),
+    kind VARCHAR(20) NOT NULL DEFAULT 'external' CHECK (kind IN ('internal', 'external')), -- Self-inflicted or from outside
+    reason TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_pomodoro_interruptions_pomodoro ON pomodoro_interruptions(pomodoro_id, occurred_at);
+
+-- Apply the auto-update trigger to the new pomodoro_sessions table
+CREATE TRIGGER update_pomodoro_sessions_updated_at BEFORE UPDATE ON pomodoro_sessions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+-- Migration: 000022_create_study_plan_templates.down.sql
+
+DROP TRIGGER IF EXISTS update_study_plan_templates_updated_at ON study_plan_templates;
+DROP TABLE IF EXISTS study_plan_templates;
@@ -0,0 +1,25 @@
+-- Migration: 000022_create_study_plan_templates.up.sql
+
+-- Study Plan Templates Table (reusable plans instantiated onto a date or date range)
+CREATE TABLE study_plan_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+
+    title VARCHAR(255) NOT NULL,
+    plan_type VARCHAR(30) NOT NULL CHECK (plan_type IN (
+        'daily', 'weekly', 'weekend', 'exam_prep', 'revision', 'custom'
+    )),
+    description TEXT,
+
+    repeat_every_days INT NOT NULL DEFAULT 7 CHECK (repeat_every_days BETWEEN 1 AND 28), -- Cycle length when instantiated onto a date range
+    sessions JSONB NOT NULL DEFAULT '[]', -- Session blueprints with day offsets, start times, subject and topic slots
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_study_plan_templates_user ON study_plan_templates(user_id);
+
+-- Apply the auto-update trigger to the new study_plan_templates table
+CREATE TRIGGER update_study_plan_templates_updated_at BEFORE UPDATE ON study_plan_templates
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
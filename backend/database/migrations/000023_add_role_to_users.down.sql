@@ -0,0 +1,3 @@
+-- Migration: 000023_add_role_to_users.down.sql
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
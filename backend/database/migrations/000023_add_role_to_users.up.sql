@@ -0,0 +1,4 @@
+-- Migration: 000023_add_role_to_users.up.sql
+
+-- Admins may correct and backfill other users' daily stats
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'student' CHECK (role IN ('student', 'admin'));
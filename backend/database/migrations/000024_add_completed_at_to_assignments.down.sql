@@ -0,0 +1,7 @@
+-- Migration: 000024_add_completed_at_to_assignments.down.sql
+
+DROP TRIGGER IF EXISTS set_assignments_completed_at ON assignments;
+DROP FUNCTION IF EXISTS set_assignment_completed_at();
+DROP INDEX IF EXISTS idx_assignments_user_created;
+DROP INDEX IF EXISTS idx_assignments_user_completed;
+ALTER TABLE assignments DROP COLUMN IF EXISTS completed_at;
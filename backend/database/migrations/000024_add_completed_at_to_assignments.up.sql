@@ -0,0 +1,26 @@
+-- Migration: 000024_add_completed_at_to_assignments.up.sql
+
+-- When the assignment was finished; the daily stats aggregator counts completions per day from it
+ALTER TABLE assignments ADD COLUMN completed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE assignments SET completed_at = COALESCE(submitted_at, updated_at)
+    WHERE status IN ('completed', 'submitted', 'graded');
+
+CREATE INDEX idx_assignments_user_completed ON assignments(user_id, completed_at) WHERE completed_at IS NOT NULL;
+CREATE INDEX idx_assignments_user_created ON assignments(user_id, created_at);
+
+-- Stamp completed_at when an assignment reaches a finished status, however the status was changed
+CREATE OR REPLACE FUNCTION set_assignment_completed_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status IN ('completed', 'submitted', 'graded') THEN
+        NEW.completed_at = COALESCE(NEW.completed_at, NOW());
+    ELSE
+        NEW.completed_at = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER set_assignments_completed_at BEFORE INSERT OR UPDATE OF status ON assignments
+    FOR EACH ROW EXECUTE FUNCTION set_assignment_completed_at();
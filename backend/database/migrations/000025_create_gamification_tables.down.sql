@@ -0,0 +1,8 @@
+-- Migration: 000025_create_gamification_tables.down.sql
+
+DROP TRIGGER IF EXISTS update_user_progress_updated_at ON user_progress;
+DROP TRIGGER IF EXISTS update_xp_rules_updated_at ON xp_rules;
+DROP TABLE IF EXISTS user_achievements;
+DROP TABLE IF EXISTS user_progress;
+DROP TABLE IF EXISTS xp_awards;
+DROP TABLE IF EXISTS xp_rules;
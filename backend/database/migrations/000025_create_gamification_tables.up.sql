@@ -0,0 +1,72 @@
+-- Migration: 000025_create_gamification_tables.up.sql
+
+-- XP Rules Table (how much XP each kind of activity earns; admins can tune them)
+CREATE TABLE xp_rules (
+    rule VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL,
+    xp INT NOT NULL CHECK (xp >= 0),
+    daily_cap INT CHECK (daily_cap > 0), -- Most XP the rule awards a user per day; NULL for no cap
+    is_active BOOLEAN DEFAULT true,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO xp_rules (rule, description, xp, daily_cap) VALUES
+    ('study_session_completed', 'Completing a study session', 20, NULL),
+    ('assignment_on_time', 'Completing an assignment before its due date', 30, NULL),
+    ('assignment_late', 'Completing an assignment after its due date', 10, NULL),
+    ('question_practised', 'Practising an important question', 2, 60),
+    ('quiz_completed', 'Finishing a quiz', 15, 45),
+    ('class_attended', 'Attending a class', 5, NULL);
+
+-- XP Awards Table (ledger of every rewarded activity)
+CREATE TABLE xp_awards (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rule VARCHAR(50) NOT NULL REFERENCES xp_rules(rule) ON DELETE CASCADE,
+
+    source_key VARCHAR(255) NOT NULL, -- What earned the XP, e.g. the completed session's ID
+    xp INT NOT NULL, -- 0 once the rule's daily cap is reached
+    award_date DATE NOT NULL, -- In the user's timezone
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(user_id, rule, source_key) -- The same activity never earns XP twice
+);
+
+CREATE INDEX idx_xp_awards_user_date ON xp_awards(user_id, award_date);
+
+-- User Progress Table (XP, level and daily study streak)
+CREATE TABLE user_progress (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    total_xp INT NOT NULL DEFAULT 0,
+    level INT NOT NULL DEFAULT 1,
+
+    current_streak INT NOT NULL DEFAULT 0,
+    longest_streak INT NOT NULL DEFAULT 0,
+    last_active_date DATE, -- Last day with study activity, in the user's timezone
+
+    freeze_tokens INT NOT NULL DEFAULT 0, -- Each one covers a missed day without breaking the streak
+    freezes_used INT NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- User Achievements Table
+CREATE TABLE user_achievements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement VARCHAR(50) NOT NULL, -- Code of an achievement defined by the server
+    unlocked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    UNIQUE(user_id, achievement)
+);
+
+-- Apply the auto-update trigger to the new tables
+CREATE TRIGGER update_xp_rules_updated_at BEFORE UPDATE ON xp_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_user_progress_updated_at BEFORE UPDATE ON user_progress
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
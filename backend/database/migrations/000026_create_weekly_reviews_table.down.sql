@@ -0,0 +1,4 @@
+-- Migration: 000026_create_weekly_reviews_table.down.sql
+
+DROP TRIGGER IF EXISTS update_weekly_reviews_updated_at ON weekly_reviews;
+DROP TABLE IF EXISTS weekly_reviews;
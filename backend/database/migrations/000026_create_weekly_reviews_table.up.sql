@@ -0,0 +1,22 @@
+-- Migration: 000026_create_weekly_reviews_table.up.sql
+
+-- Weekly Reviews Table (generated at the end of each week, Monday to Sunday in the user's timezone)
+CREATE TABLE weekly_reviews (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    week_start DATE NOT NULL, -- Monday
+    week_end DATE NOT NULL, -- Sunday
+    report JSONB NOT NULL, -- Sessions, subject hours, assignments, exam prep, attendance and suggested focus
+
+    emailed_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(user_id, week_start)
+);
+
+-- Apply the auto-update trigger to the new weekly_reviews table
+CREATE TRIGGER update_weekly_reviews_updated_at BEFORE UPDATE ON weekly_reviews
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+-- Migration: 000027_create_goals_tables.down.sql
+
+DROP TRIGGER IF EXISTS update_goals_updated_at ON goals;
+DROP TABLE IF EXISTS goal_results;
+DROP TABLE IF EXISTS goals;
@@ -0,0 +1,51 @@
+-- Migration: 000027_create_goals_tables.up.sql
+
+-- Goals Table (targets whose progress is computed from daily stats, question practice and attendance)
+CREATE TABLE goals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    title VARCHAR(255) NOT NULL,
+    metric VARCHAR(30) NOT NULL CHECK (metric IN (
+        'study_hours', 'questions_practised', 'attendance_rate'
+    )),
+    target_value DECIMAL(10,2) NOT NULL CHECK (target_value > 0), -- Hours, questions or a percentage
+    period VARCHAR(10) NOT NULL CHECK (period IN ('weekly', 'once')), -- Every week, or once between the start and end dates
+
+    -- Optional scope of the metric
+    subject_id UUID REFERENCES subjects(id) ON DELETE CASCADE,
+    exam_id UUID REFERENCES exams(id) ON DELETE CASCADE,
+
+    start_date DATE NOT NULL, -- In the user's timezone
+    end_date DATE, -- Inclusive; required for one-off goals, optional for weekly ones
+
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN (
+        'active', 'achieved', 'missed', 'ended', 'archived'
+    )),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_goals_user_status ON goals(user_id, status);
+
+-- Goal Results Table (attainment of each finished period of a goal)
+CREATE TABLE goal_results (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    goal_id UUID NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    target_value DECIMAL(10,2) NOT NULL, -- The goal's target at the time
+    actual_value DECIMAL(10,2) NOT NULL,
+    achieved BOOLEAN NOT NULL,
+
+    recorded_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(goal_id, period_start)
+);
+
+-- Apply the auto-update trigger to the new goals table
+CREATE TRIGGER update_goals_updated_at BEFORE UPDATE ON goals
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
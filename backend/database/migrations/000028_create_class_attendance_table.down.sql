@@ -0,0 +1,4 @@
+-- Migration: 000028_create_class_attendance_table.down.sql
+
+DROP TRIGGER IF EXISTS update_class_attendance_updated_at ON class_attendance;
+DROP TABLE IF EXISTS class_attendance;
@@ -0,0 +1,22 @@
+-- Migration: 000028_create_class_attendance_table.up.sql
+
+-- Class Attendance Table (whether a user attended the class of a timetable slot on a date)
+CREATE TABLE class_attendance (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    slot_id UUID NOT NULL REFERENCES timetable_slots(id) ON DELETE CASCADE,
+
+    class_date DATE NOT NULL, -- In the user's timezone
+    attended BOOLEAN NOT NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE(slot_id, class_date)
+);
+
+CREATE INDEX idx_class_attendance_user_date ON class_attendance(user_id, class_date);
+
+-- Apply the auto-update trigger to the new class_attendance table
+CREATE TRIGGER update_class_attendance_updated_at BEFORE UPDATE ON class_attendance
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
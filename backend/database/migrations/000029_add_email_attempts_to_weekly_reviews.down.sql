@@ -0,0 +1,3 @@
+-- Migration: 000029_add_email_attempts_to_weekly_reviews.down.sql
+
+ALTER TABLE weekly_reviews DROP COLUMN IF EXISTS email_attempts;
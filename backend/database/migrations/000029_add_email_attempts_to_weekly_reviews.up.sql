@@ -0,0 +1,4 @@
+-- Migration: 000029_add_email_attempts_to_weekly_reviews.up.sql
+
+-- Counts failed sends so the scheduler stops retrying an email the SMTP server keeps rejecting
+ALTER TABLE weekly_reviews ADD COLUMN email_attempts INTEGER NOT NULL DEFAULT 0;
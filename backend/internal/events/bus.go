@@ -0,0 +1,166 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Event types published by the services.
+const (
+	AssignmentCreated   = "assignment_created"
+	AssignmentUpdated   = "assignment_updated"
+	AssignmentCompleted = "assignment_completed"
+	AssignmentDeleted   = "assignment_deleted"
+
+	ExamCreated = "exam_created"
+	ExamUpdated = "exam_updated"
+	ExamDeleted = "exam_deleted"
+
+	StudyPlanCreated = "study_plan_created"
+	StudyPlanUpdated = "study_plan_updated"
+	StudyPlanDeleted = "study_plan_deleted"
+
+	StudySessionCreated   = "study_session_created"
+	StudySessionUpdated   = "study_session_updated"
+	StudySessionCompleted = "study_session_completed"
+	StudySessionDeleted   = "study_session_deleted"
+
+	QuestionPractised       = "question_practised"
+	QuizCompleted           = "quiz_completed"
+	ClassAttendanceRecorded = "class_attendance_recorded"
+
+	// AllEvents subscribes a handler to every event type.
+	AllEvents = "*"
+)
+
+// Event is something that happened to one of a user's entities.
+type Event struct {
+	Type        string
+	UserID      string
+	EntityType  string // 'assignment', 'exam', 'study_plan', 'study_session', 'important_question', 'quiz', 'class_attendance'
+	EntityID    string
+	Description string
+	Metadata    map[string]interface{} // Event details, e.g. the changed fields of an update
+	Request     RequestInfo            // Filled from the publishing context
+	OccurredAt  time.Time
+}
+
+// RequestInfo identifies the client whose request caused an event.
+type RequestInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// FieldChange is the old and new value of a changed field.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Handler reacts to a published event.
+type Handler func(ctx context.Context, event Event)
+
+// Bus delivers domain events from the services to their subscribers.
+type Bus interface {
+	Subscribe(eventType string, handler Handler)
+	Publish(ctx context.Context, event Event)
+}
+
+// bus is an in-process Bus that delivers events asynchronously.
+type bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewBus creates a new in-process event bus.
+func NewBus() Bus {
+	return &bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers a handler for an event type, or for every type with AllEvents.
+func (b *bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish hands an event to its subscribers in the background, so a slow or failing subscriber never
+// holds up or fails the request that caused it. The request info stored on ctx is attached to the event.
+func (b *bus) Publish(ctx context.Context, event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	if info, ok := RequestInfoFromContext(ctx); ok {
+		event.Request = info
+	}
+
+	b.mu.RLock()
+	handlers := append(append([]Handler{}, b.handlers[event.Type]...), b.handlers[AllEvents]...)
+	b.mu.RUnlock()
+	if len(handlers) == 0 {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		for _, handler := range handlers {
+			deliver(ctx, handler, event)
+		}
+	}()
+}
+
+// deliver calls a handler, recovering from panics so one subscriber cannot stop the others.
+func deliver(ctx context.Context, handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Warning: Event handler for %s panicked: %v", event.Type, r)
+		}
+	}()
+	handler(ctx, event)
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx carrying the client's request info.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request info stored on ctx, if any.
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// Changes compares two versions of an entity through their JSON form and returns the fields that differ,
+// keyed by JSON field name. Timestamps maintained by the repositories are ignored.
+func Changes(before, after interface{}) map[string]FieldChange {
+	var from, to map[string]interface{}
+	if !toMap(before, &from) || !toMap(after, &to) {
+		return nil
+	}
+
+	changes := make(map[string]FieldChange)
+	for key, newValue := range to {
+		if key == "createdAt" || key == "updatedAt" {
+			continue
+		}
+		if oldValue := from[key]; !reflect.DeepEqual(oldValue, newValue) {
+			changes[key] = FieldChange{From: oldValue, To: newValue}
+		}
+	}
+	return changes
+}
+
+// toMap converts a value to a generic map through JSON.
+func toMap(value interface{}, out *map[string]interface{}) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, out) == nil
+}
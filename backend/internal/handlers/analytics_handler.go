@@ -1,8 +1,10 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -13,15 +15,17 @@ import (
 
 // AnalyticsHandler handles HTTP requests related to analytics.
 type AnalyticsHandler struct {
-	analyticsService services.AnalyticsService
-	validator        *validator.Validate
+	analyticsService     services.AnalyticsService
+	dailyStatsAggregator services.DailyStatsAggregator
+	validator            *validator.Validate
 }
 
 // NewAnalyticsHandler creates a new AnalyticsHandler.
-func NewAnalyticsHandler(analyticsService services.AnalyticsService) *AnalyticsHandler {
+func NewAnalyticsHandler(analyticsService services.AnalyticsService, dailyStatsAggregator services.DailyStatsAggregator) *AnalyticsHandler {
 	return &AnalyticsHandler{
-		analyticsService: analyticsService,
-		validator:        validator.New(),
+		analyticsService:     analyticsService,
+		dailyStatsAggregator: dailyStatsAggregator,
+		validator:            validator.New(),
 	}
 }
 
@@ -53,20 +57,28 @@ func (h *AnalyticsHandler) CreateActivityLog(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	logEntry, err := h.analyticsService.CreateActivityLog(context.Background(), userID, &input)
+	logEntry, err := h.analyticsService.CreateActivityLog(c.UserContext(), userID, &input)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create activity log: " + err.Error()})
 	}
 	return c.Status(fiber.StatusCreated).JSON(logEntry)
 }
 
-// GetActivityLogs handles retrieving all activity logs for the authenticated user.
-// @Summary Get all activity logs
-// @Description Retrieve a list of all activity logs for the authenticated user.
+// GetActivityLogs handles retrieving a page of activity logs for the authenticated user.
+// @Summary Get activity logs
+// @Description Retrieve activity logs for the authenticated user, newest first, optionally filtered by activity type, entity and time range.
 // @Tags Analytics
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} models.ActivityLog
+// @Param type query string false "Comma-separated activity types"
+// @Param entityType query string false "Entity type (assignment, exam, study_session, ...)"
+// @Param entityId query string false "Entity ID"
+// @Param from query string false "Start date (YYYY-MM-DD), inclusive"
+// @Param to query string false "End date (YYYY-MM-DD), inclusive"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param offset query int false "Number of logs to skip"
+// @Success 200 {object} models.ActivityLogPage
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /analytics/activity-logs [get]
@@ -76,20 +88,64 @@ func (h *AnalyticsHandler) GetActivityLogs(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	logs, err := h.analyticsService.GetActivityLogsByUserID(context.Background(), userID)
+	filter := models.ActivityLogFilter{}
+	if types := c.Query("type"); types != "" {
+		for _, activityType := range strings.Split(types, ",") {
+			if activityType = strings.TrimSpace(activityType); activityType != "" {
+				filter.ActivityTypes = append(filter.ActivityTypes, activityType)
+			}
+		}
+	}
+	if entityType := c.Query("entityType"); entityType != "" {
+		filter.EntityType = &entityType
+	}
+	if entityID := c.Query("entityId"); entityID != "" {
+		filter.EntityID = &entityID
+	}
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid from date format. Use YYYY-MM-DD."})
+		}
+		filter.From = &from
+	}
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid to date format. Use YYYY-MM-DD."})
+		}
+		// Include the whole end day
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	var err error
+	if filter.Limit, err = nonNegativeQueryInt(c, "limit"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if filter.Offset, err = nonNegativeQueryInt(c, "offset"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, err := h.analyticsService.GetActivityLogs(c.UserContext(), userID, &filter)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidDateRange) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve activity logs: " + err.Error()})
 	}
-	return c.Status(fiber.StatusOK).JSON(logs)
+	return c.Status(fiber.StatusOK).JSON(page)
 }
 
-// GetDailyStats handles retrieving all daily stats for the authenticated user.
-// @Summary Get all daily stats
-// @Description Retrieve a list of all daily statistics for the authenticated user.
+// GetDailyStats handles retrieving daily stats for the authenticated user.
+// @Summary Get daily stats
+// @Description Retrieve daily statistics for the authenticated user. With a date range only the days in it are returned, oldest first; otherwise all days, newest first.
 // @Tags Analytics
 // @Produce json
 // @Security BearerAuth
+// @Param from query string false "Start date (YYYY-MM-DD), inclusive; requires to"
+// @Param to query string false "End date (YYYY-MM-DD), inclusive; requires from"
 // @Success 200 {array} models.DailyStats
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /analytics/daily-stats [get]
@@ -99,9 +155,38 @@ func (h *AnalyticsHandler) GetDailyStats(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	stats, err := h.analyticsService.GetDailyStatsByUserID(context.Background(), userID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve daily stats: " + err.Error()})
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+
+	var stats []models.DailyStats
+	if fromStr != "" || toStr != "" {
+		if fromStr == "" || toStr == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "from and to must be provided together"})
+		}
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid from date format. Use YYYY-MM-DD."})
+		}
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid to date format. Use YYYY-MM-DD."})
+		}
+		stats, err = h.analyticsService.GetDailyStatsByUserIDAndDateRange(c.UserContext(), userID, from, to)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidDateRange) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve daily stats: " + err.Error()})
+		}
+	} else {
+		var err error
+		stats, err = h.analyticsService.GetDailyStatsByUserID(c.UserContext(), userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve daily stats: " + err.Error()})
+		}
+	}
+	if stats == nil {
+		stats = []models.DailyStats{}
 	}
 	return c.Status(fiber.StatusOK).JSON(stats)
 }
@@ -131,9 +216,9 @@ func (h *AnalyticsHandler) GetDailyStatsByDate(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid date format. Use YYYY-MM-DD."})
 	}
 
-	stats, err := h.analyticsService.GetDailyStatsByUserIDAndDate(context.Background(), userID, date)
+	stats, err := h.analyticsService.GetDailyStatsByUserIDAndDate(c.UserContext(), userID, date)
 	if err != nil {
-		if err.Error() == "failed to get daily stats by user ID and date: no rows in result set" {
+		if errors.Is(err, services.ErrDailyStatsNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Daily stats not found for date"})
 		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve daily stats: " + err.Error()})
@@ -143,7 +228,7 @@ func (h *AnalyticsHandler) GetDailyStatsByDate(c *fiber.Ctx) error {
 
 // UpsertDailyStats handles creating or updating daily stats.
 // @Summary Create or update daily stats
-// @Description Create or update daily statistics for the authenticated user for a specific date.
+// @Description Admin only. Create or update daily statistics for a user (the caller by default) for a specific date. Daily stats are derived by the server, so values other than XP are overwritten the next time the day is recomputed. Students record attendance per class through PUT /timetable/attendance.
 // @Tags Analytics
 // @Accept json
 // @Produce json
@@ -152,6 +237,7 @@ func (h *AnalyticsHandler) GetDailyStatsByDate(c *fiber.Ctx) error {
 // @Success 200 {object} models.DailyStats
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /analytics/daily-stats [put]
 func (h *AnalyticsHandler) UpsertDailyStats(c *fiber.Ctx) error {
@@ -169,9 +255,202 @@ func (h *AnalyticsHandler) UpsertDailyStats(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	stats, err := h.analyticsService.UpsertDailyStats(context.Background(), userID, &input)
+	targetUserID := userID
+	if input.UserID != nil {
+		targetUserID = *input.UserID
+	}
+
+	stats, err := h.analyticsService.UpsertDailyStats(c.UserContext(), targetUserID, &input)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to upsert daily stats: " + err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(stats)
 }
+
+// BackfillDailyStats handles recomputing daily stats from the source data over a date range.
+// @Summary Backfill daily stats
+// @Description Admin only. Recompute the daily stats of one user, or of every active user when no user is given, for each date in a range of at most 366 days.
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param backfill body models.DailyStatsBackfillInput true "Users and date range to recompute"
+// @Success 200 {object} models.DailyStatsBackfillResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /analytics/daily-stats/backfill [post]
+func (h *AnalyticsHandler) BackfillDailyStats(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.DailyStatsBackfillInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := h.dailyStatsAggregator.Backfill(c.UserContext(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidDateRange), strings.HasPrefix(err.Error(), "invalid"):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case strings.HasPrefix(err.Error(), "user not found"):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to backfill daily stats: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// GetWeeklyTotals handles retrieving weekly totals of the daily stats.
+// @Summary Get weekly totals
+// @Description Sum the authenticated user's daily stats per week (Monday to Sunday), oldest first and ending with the current week. Weeks without activity are included.
+// @Tags Analytics
+// @Produce json
+// @Security BearerAuth
+// @Param weeks query int false "Number of weeks (default 8, max 52)"
+// @Success 200 {array} models.DailyStatsTotals
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /analytics/summary/weekly [get]
+func (h *AnalyticsHandler) GetWeeklyTotals(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	weeks, err := nonNegativeQueryInt(c, "weeks")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	totals, err := h.analyticsService.GetWeeklyTotals(c.UserContext(), userID, weeks)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve weekly totals: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(totals)
+}
+
+// GetMonthlyTotals handles retrieving monthly totals of the daily stats.
+// @Summary Get monthly totals
+// @Description Sum the authenticated user's daily stats per calendar month, oldest first and ending with the current month. Months without activity are included.
+// @Tags Analytics
+// @Produce json
+// @Security BearerAuth
+// @Param months query int false "Number of months (default 6, max 52)"
+// @Success 200 {array} models.DailyStatsTotals
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /analytics/summary/monthly [get]
+func (h *AnalyticsHandler) GetMonthlyTotals(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	months, err := nonNegativeQueryInt(c, "months")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	totals, err := h.analyticsService.GetMonthlyTotals(c.UserContext(), userID, months)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve monthly totals: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(totals)
+}
+
+// GetStudyStreak handles retrieving the study streak.
+// @Summary Get study streak
+// @Description Get the authenticated user's current and longest runs of consecutive days with study time or completed sessions.
+// @Tags Analytics
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.StudyStreak
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /analytics/streaks [get]
+func (h *AnalyticsHandler) GetStudyStreak(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	streak, err := h.analyticsService.GetStudyStreak(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve study streak: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(streak)
+}
+
+// GetTimeSeries handles retrieving bucketed activity metrics for heatmaps and trend charts.
+// @Summary Get an activity time series
+// @Description Bucket the authenticated user's study minutes, XP, completed tasks (study sessions and assignments) and attendance by day, week (Monday to Sunday) or calendar month, oldest first. Buckets follow the calendar of the user's timezone and every bucket of the range is included, with zeros where nothing happened. Each bucket has a heatmap level from 0 to 4 relative to the busiest one. The range is widened to whole buckets; without one it covers the last 365 days, 26 weeks or 12 months.
+// @Tags Analytics
+// @Produce json
+// @Security BearerAuth
+// @Param bucket query string false "Bucket size: day, week or month (default day)"
+// @Param from query string false "Start date (YYYY-MM-DD), inclusive; requires to"
+// @Param to query string false "End date (YYYY-MM-DD), inclusive; requires from"
+// @Success 200 {object} models.TimeSeries
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /analytics/timeseries [get]
+func (h *AnalyticsHandler) GetTimeSeries(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+
+	var from, to time.Time
+	if fromStr != "" || toStr != "" {
+		if fromStr == "" || toStr == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "from and to must be provided together"})
+		}
+		var err error
+		from, err = time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid from date format. Use YYYY-MM-DD."})
+		}
+		to, err = time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid to date format. Use YYYY-MM-DD."})
+		}
+	}
+
+	series, err := h.analyticsService.GetTimeSeries(c.UserContext(), userID, c.Query("bucket"), from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDateRange) || errors.Is(err, services.ErrInvalidTimeSeriesBucket) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve time series: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(series)
+}
+
+// nonNegativeQueryInt parses an optional non-negative integer query parameter, returning 0 when absent.
+func nonNegativeQueryInt(c *fiber.Ctx, key string) (int, error) {
+	str := c.Query(key)
+	if str == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(str)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return value, nil
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -54,7 +53,7 @@ func (h *AssignmentHandler) CreateAssignment(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	assignment, err := h.assignmentService.CreateAssignment(context.Background(), userID, &input)
+	assignment, err := h.assignmentService.CreateAssignment(c.UserContext(), userID, &input)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create assignment: " + err.Error()})
 	}
@@ -67,6 +66,7 @@ func (h *AssignmentHandler) CreateAssignment(c *fiber.Ctx) error {
 // @Tags Assignments
 // @Produce json
 // @Security BearerAuth
+// @Param sortBy query string false "Set to urgencyScore to return scored assignments, most urgent first"
 // @Success 200 {array} models.Assignment
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -77,13 +77,55 @@ func (h *AssignmentHandler) GetAssignments(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	assignments, err := h.assignmentService.GetAssignmentsByUserID(context.Background(), userID)
+	if c.Query("sortBy") == "urgencyScore" {
+		scored, err := h.assignmentService.GetScoredAssignmentsByUserID(c.UserContext(), userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve assignments: " + err.Error()})
+		}
+		return c.Status(fiber.StatusOK).JSON(scored)
+	}
+
+	assignments, err := h.assignmentService.GetAssignmentsByUserID(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve assignments: " + err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(assignments)
 }
 
+// GetFocusAssignments handles retrieving the assignments to work on now.
+// @Summary Get focus assignments
+// @Description Retrieve the most urgent open assignments for the authenticated user, each with an explanation of its urgency score.
+// @Tags Assignments
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Number of assignments to return (default 5)"
+// @Success 200 {array} models.ScoredAssignment
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /assignments/focus [get]
+func (h *AssignmentHandler) GetFocusAssignments(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	limit := 5
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "limit must be a positive integer"})
+		}
+		limit = parsed
+	}
+
+	focus, err := h.assignmentService.GetFocusAssignments(c.UserContext(), userID, limit)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve focus assignments: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(focus)
+}
+
 // GetAssignmentByID handles retrieving a single assignment by ID.
 // @Summary Get assignment by ID
 // @Description Retrieve a single assignment by its ID for the authenticated user.
@@ -103,7 +145,7 @@ func (h *AssignmentHandler) GetAssignmentByID(c *fiber.Ctx) error {
 	}
 
 	id := c.Params("id")
-	assignment, err := h.assignmentService.GetAssignmentByID(context.Background(), id)
+	assignment, err := h.assignmentService.GetAssignmentByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Assignment not found or not owned by user"})
 	}
@@ -129,7 +171,7 @@ func (h *AssignmentHandler) GetPendingAssignments(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	assignments, err := h.assignmentService.GetPendingAssignmentsByUserID(context.Background(), userID)
+	assignments, err := h.assignmentService.GetPendingAssignmentsByUserID(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve pending assignments: " + err.Error()})
 	}
@@ -152,7 +194,7 @@ func (h *AssignmentHandler) GetOverdueAssignments(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	assignments, err := h.assignmentService.GetOverdueAssignmentsByUserID(context.Background(), userID)
+	assignments, err := h.assignmentService.GetOverdueAssignmentsByUserID(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve overdue assignments: " + err.Error()})
 	}
@@ -191,7 +233,7 @@ func (h *AssignmentHandler) UpdateAssignment(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	assignment, err := h.assignmentService.UpdateAssignment(context.Background(), userID, id, &input)
+	assignment, err := h.assignmentService.UpdateAssignment(c.UserContext(), userID, id, &input)
 	if err != nil {
 		if err.Error() == "assignment does not belong to user" {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
@@ -247,7 +289,7 @@ func (h *AssignmentHandler) UpdateAssignmentStatus(c *fiber.Ctx) error {
 	}
 
 	// Check ownership before updating
-	assignment, err := h.assignmentService.GetAssignmentByID(context.Background(), id)
+	assignment, err := h.assignmentService.GetAssignmentByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Assignment not found"})
 	}
@@ -255,7 +297,7 @@ func (h *AssignmentHandler) UpdateAssignmentStatus(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Assignment does not belong to user"})
 	}
 
-	if err := h.assignmentService.UpdateAssignmentStatus(context.Background(), id, body.Status); err != nil {
+	if err := h.assignmentService.UpdateAssignmentStatus(c.UserContext(), id, body.Status); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update assignment status: " + err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Assignment status updated successfully"})
@@ -282,7 +324,7 @@ func (h *AssignmentHandler) DeleteAssignment(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	// Check ownership before deleting
-	assignment, err := h.assignmentService.GetAssignmentByID(context.Background(), id)
+	assignment, err := h.assignmentService.GetAssignmentByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Assignment not found"})
 	}
@@ -290,7 +332,7 @@ func (h *AssignmentHandler) DeleteAssignment(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Assignment does not belong to user"})
 	}
 
-	if err := h.assignmentService.DeleteAssignment(context.Background(), id); err != nil {
+	if err := h.assignmentService.DeleteAssignment(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete assignment: " + err.Error()})
 	}
 	return c.Status(fiber.StatusNoContent).Send(nil)
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"context"
-
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
@@ -45,7 +43,7 @@ func (h *AuthHandler) RegisterUser(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	user, err := h.authService.RegisterUser(context.Background(), &input)
+	user, err := h.authService.RegisterUser(c.UserContext(), &input)
 	if err != nil {
 		// Differentiate between known errors (e.g., duplicate email) and internal errors
 		if err.Error() == "user with this email already exists" {
@@ -79,7 +77,7 @@ func (h *AuthHandler) LoginUser(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	token, err := h.authService.Login(context.Background(), &input)
+	token, err := h.authService.Login(c.UserContext(), &input)
 	if err != nil {
 		if err.Error() == "invalid credentials" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
@@ -106,7 +104,7 @@ func (h *AuthHandler) GetUserProfile(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized: Invalid user ID in token"})
 	}
 
-	user, err := h.authService.GetUserProfile(context.Background(), userID)
+	user, err := h.authService.GetUserProfile(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve user profile"})
 	}
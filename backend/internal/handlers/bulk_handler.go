@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// BulkHandler handles HTTP requests for bulk operations on assignments, exams and lab records.
+type BulkHandler struct {
+	bulkService services.BulkService
+	validator   *validator.Validate
+}
+
+// NewBulkHandler creates a new BulkHandler.
+func NewBulkHandler(bulkService services.BulkService) *BulkHandler {
+	return &BulkHandler{
+		bulkService: bulkService,
+		validator:   validator.New(),
+	}
+}
+
+// BulkAssignments handles a bulk operation on assignments.
+// @Summary Bulk update or delete assignments
+// @Description Apply a status change, tag change, subject reassignment or deletion to assignments selected by ID or filter.
+// @Tags Assignments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param operation body models.BulkOperationInput true "Bulk operation"
+// @Success 200 {object} models.BulkOperationResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /assignments/bulk [post]
+func (h *BulkHandler) BulkAssignments(c *fiber.Ctx) error {
+	return h.applyBulkOperation(c, "assignment")
+}
+
+// BulkExams handles a bulk operation on exams.
+// @Summary Bulk update or delete exams
+// @Description Apply a prep status change, tag change, subject reassignment or deletion to exams selected by ID or filter.
+// @Tags Exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param operation body models.BulkOperationInput true "Bulk operation"
+// @Success 200 {object} models.BulkOperationResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/bulk [post]
+func (h *BulkHandler) BulkExams(c *fiber.Ctx) error {
+	return h.applyBulkOperation(c, "exam")
+}
+
+// BulkLabRecords handles a bulk operation on lab records.
+// @Summary Bulk update or delete lab records
+// @Description Apply a status change, tag change, subject reassignment or deletion to lab records selected by ID or filter.
+// @Tags Lab Records
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param operation body models.BulkOperationInput true "Bulk operation"
+// @Success 200 {object} models.BulkOperationResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /lab-records/bulk [post]
+func (h *BulkHandler) BulkLabRecords(c *fiber.Ctx) error {
+	return h.applyBulkOperation(c, "lab_record")
+}
+
+func (h *BulkHandler) applyBulkOperation(c *fiber.Ctx, entityType string) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.BulkOperationInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := h.bulkService.ApplyBulkOperation(c.UserContext(), userID, entityType, &input)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBulkOperation) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to apply bulk operation: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
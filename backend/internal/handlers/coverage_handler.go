@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// CoverageHandler handles HTTP requests related to syllabus coverage.
+type CoverageHandler struct {
+	coverageService services.CoverageService
+}
+
+// NewCoverageHandler creates a new CoverageHandler.
+func NewCoverageHandler(coverageService services.CoverageService) *CoverageHandler {
+	return &CoverageHandler{coverageService: coverageService}
+}
+
+// GetUpcomingExamCoverage handles retrieving syllabus coverage for all upcoming exams.
+// @Summary Get syllabus coverage
+// @Description Compute covered, revised and untouched syllabus topics for every upcoming exam. Prep statuses that are not set manually advance as study sessions are completed and questions practised.
+// @Tags Exams
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.ExamCoverage
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/coverage [get]
+func (h *CoverageHandler) GetUpcomingExamCoverage(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	coverage, err := h.coverageService.GetUpcomingExamCoverage(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to compute syllabus coverage: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(coverage)
+}
+
+// GetExamCoverage handles retrieving syllabus coverage for a single exam.
+// @Summary Get exam syllabus coverage
+// @Description Compute covered, revised and untouched syllabus topics for an exam, with the prep status it suggests. Prep statuses that are not set manually advance as study sessions are completed and questions practised.
+// @Tags Exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Exam ID"
+// @Success 200 {object} models.ExamCoverage
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/{id}/coverage [get]
+func (h *CoverageHandler) GetExamCoverage(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	coverage, err := h.coverageService.GetExamCoverage(c.UserContext(), userID, id)
+	if err != nil {
+		return coverageErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(coverage)
+}
+
+// ClearPrepStatusOverride handles handing an exam's prep status back to coverage tracking.
+// @Summary Clear manual prep status
+// @Description Remove a manually set prep status so it is derived from syllabus coverage again.
+// @Tags Exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Exam ID"
+// @Success 200 {object} models.ExamCoverage
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/{id}/prep-status/override [delete]
+func (h *CoverageHandler) ClearPrepStatusOverride(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	coverage, err := h.coverageService.ClearPrepStatusOverride(c.UserContext(), userID, id)
+	if err != nil {
+		return coverageErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(coverage)
+}
+
+// coverageErrorResponse maps coverage service errors to HTTP responses.
+func coverageErrorResponse(c *fiber.Ctx, err error) error {
+	if err.Error() == "exam does not belong to user" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	}
+	if strings.HasPrefix(err.Error(), "exam not found") {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Exam not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to compute syllabus coverage: " + err.Error()})
+}
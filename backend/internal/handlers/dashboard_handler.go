@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// DashboardHandler handles HTTP requests related to the dashboard summary.
+type DashboardHandler struct {
+	dashboardService services.DashboardService
+}
+
+// NewDashboardHandler creates a new DashboardHandler.
+func NewDashboardHandler(dashboardService services.DashboardService) *DashboardHandler {
+	return &DashboardHandler{dashboardService: dashboardService}
+}
+
+// GetDashboard handles retrieving the dashboard summary of the authenticated user.
+// @Summary Get dashboard
+// @Description Get today's timetable, a countdown to the next exam with its prep status, pending and overdue assignment counts, lab records by status, this week's study minutes against the goal, recent documents and streak info in one response. The summary is cached for up to 30 seconds and rebuilt as soon as any of the user's data changes.
+// @Tags Dashboard
+// @Produce json
+// @Security BearerAuth
+// @Param refresh query bool false "Skip the cache and rebuild the summary"
+// @Success 200 {object} models.Dashboard
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /dashboard [get]
+func (h *DashboardHandler) GetDashboard(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	dashboard, err := h.dashboardService.GetDashboard(c.UserContext(), userID, c.QueryBool("refresh", false))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve dashboard: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(dashboard)
+}
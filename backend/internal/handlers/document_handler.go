@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 
@@ -53,7 +52,7 @@ func (h *DocumentHandler) CreateDocument(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	document, err := h.documentService.CreateDocument(context.Background(), userID, &input)
+	document, err := h.documentService.CreateDocument(c.UserContext(), userID, &input)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create document: " + err.Error()})
 	}
@@ -76,7 +75,7 @@ func (h *DocumentHandler) GetDocuments(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	documents, err := h.documentService.GetDocumentsByUserID(context.Background(), userID)
+	documents, err := h.documentService.GetDocumentsByUserID(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve documents: " + err.Error()})
 	}
@@ -102,7 +101,7 @@ func (h *DocumentHandler) GetDocumentByID(c *fiber.Ctx) error {
 	}
 
 	id := c.Params("id")
-	document, err := h.documentService.GetDocumentByID(context.Background(), id)
+	document, err := h.documentService.GetDocumentByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Document not found or not owned by user"})
 	}
@@ -130,7 +129,7 @@ func (h *DocumentHandler) GetDocumentsBySubjectID(c *fiber.Ctx) error {
 	}
 
 	subjectID := c.Params("subjectId")
-	documents, err := h.documentService.GetDocumentsByUserIDAndSubjectID(context.Background(), userID, subjectID)
+	documents, err := h.documentService.GetDocumentsByUserIDAndSubjectID(c.UserContext(), userID, subjectID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve documents: " + err.Error()})
 	}
@@ -169,7 +168,7 @@ func (h *DocumentHandler) UpdateDocument(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	document, err := h.documentService.UpdateDocument(context.Background(), userID, id, &input)
+	document, err := h.documentService.UpdateDocument(c.UserContext(), userID, id, &input)
 	if err != nil {
 		if err.Error() == "document does not belong to user" {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
@@ -203,7 +202,7 @@ func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	// Check ownership before deleting
-	document, err := h.documentService.GetDocumentByID(context.Background(), id)
+	document, err := h.documentService.GetDocumentByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Document not found"})
 	}
@@ -211,7 +210,7 @@ func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Document does not belong to user"})
 	}
 
-	if err := h.documentService.DeleteDocument(context.Background(), id); err != nil {
+	if err := h.documentService.DeleteDocument(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete document: " + err.Error()})
 	}
 	return c.Status(fiber.StatusNoContent).Send(nil)
@@ -230,7 +229,7 @@ func (h *DocumentHandler) DeleteDocument(c *fiber.Ctx) error {
 // @Router /documents/{id}/view [post]
 func (h *DocumentHandler) IncrementViewCount(c *fiber.Ctx) error {
 	id := c.Params("id")
-	if err := h.documentService.IncrementViewCount(context.Background(), id); err != nil {
+	if err := h.documentService.IncrementViewCount(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to increment view count: " + err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "View count incremented"})
@@ -249,7 +248,7 @@ func (h *DocumentHandler) IncrementViewCount(c *fiber.Ctx) error {
 // @Router /documents/{id}/download-count [post]
 func (h *DocumentHandler) IncrementDownloadCount(c *fiber.Ctx) error {
 	id := c.Params("id")
-	if err := h.documentService.IncrementDownloadCount(context.Background(), id); err != nil {
+	if err := h.documentService.IncrementDownloadCount(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to increment download count: " + err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Download count incremented"})
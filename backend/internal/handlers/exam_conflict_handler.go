@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// ExamConflictHandler handles HTTP requests for exam clash and crunch detection.
+type ExamConflictHandler struct {
+	examConflictService services.ExamConflictService
+}
+
+// NewExamConflictHandler creates a new ExamConflictHandler.
+func NewExamConflictHandler(examConflictService services.ExamConflictService) *ExamConflictHandler {
+	return &ExamConflictHandler{examConflictService: examConflictService}
+}
+
+// GetExamConflicts handles analysing the authenticated user's exam schedule.
+// @Summary Detect exam clashes and crunch weeks
+// @Description Flag overlapping exams, days with more than maxPerDay exams, and weeks where assignment and exam prep hours exceed free time. Each exam's prep hours are spread over the two weeks before it. An hourly check raises the findings for the default limits as notifications.
+// @Tags Exams
+// @Produce json
+// @Security BearerAuth
+// @Param maxPerDay query int false "Maximum exams allowed on one day before it is flagged (default 1)"
+// @Param weeks query int false "Number of weeks ahead to check for crunches (default 4, max 26)"
+// @Success 200 {object} models.ExamConflictReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/conflicts [get]
+func (h *ExamConflictHandler) GetExamConflicts(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	maxPerDay := c.QueryInt("maxPerDay", 1)
+	if maxPerDay < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "maxPerDay must be at least 1"})
+	}
+	weeks := c.QueryInt("weeks", 4)
+	if weeks < 1 || weeks > 26 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "weeks must be between 1 and 26"})
+	}
+
+	report, err := h.examConflictService.AnalyzeExamConflicts(c.UserContext(), userID, maxPerDay, weeks)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to analyse exam conflicts: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(report)
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -54,7 +53,7 @@ func (h *ExamHandler) CreateExam(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	exam, err := h.examService.CreateExam(context.Background(), userID, &input)
+	exam, err := h.examService.CreateExam(c.UserContext(), userID, &input)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create exam: " + err.Error()})
 	}
@@ -77,7 +76,7 @@ func (h *ExamHandler) GetExams(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	exams, err := h.examService.GetExamsByUserID(context.Background(), userID)
+	exams, err := h.examService.GetExamsByUserID(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve exams: " + err.Error()})
 	}
@@ -103,7 +102,7 @@ func (h *ExamHandler) GetExamByID(c *fiber.Ctx) error {
 	}
 
 	id := c.Params("id")
-	exam, err := h.examService.GetExamByID(context.Background(), id)
+	exam, err := h.examService.GetExamByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Exam not found or not owned by user"})
 	}
@@ -129,7 +128,7 @@ func (h *ExamHandler) GetUpcomingExams(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	exams, err := h.examService.GetUpcomingExamsByUserID(context.Background(), userID)
+	exams, err := h.examService.GetUpcomingExamsByUserID(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve upcoming exams: " + err.Error()})
 	}
@@ -168,7 +167,7 @@ func (h *ExamHandler) UpdateExam(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	exam, err := h.examService.UpdateExam(context.Background(), userID, id, &input)
+	exam, err := h.examService.UpdateExam(c.UserContext(), userID, id, &input)
 	if err != nil {
 		if err.Error() == "exam does not belong to user" {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
@@ -183,7 +182,7 @@ func (h *ExamHandler) UpdateExam(c *fiber.Ctx) error {
 
 // UpdateExamPrepStatus handles updating the prep status of an exam.
 // @Summary Update exam prep status
-// @Description Update the preparation status of an exam for the authenticated user.
+// @Description Manually set the preparation status of an exam for the authenticated user. Coverage tracking stops advancing it until the override is cleared.
 // @Tags Exams
 // @Accept json
 // @Produce json
@@ -223,7 +222,7 @@ func (h *ExamHandler) UpdateExamPrepStatus(c *fiber.Ctx) error {
 	}
 
 	// Check ownership before updating
-	exam, err := h.examService.GetExamByID(context.Background(), id)
+	exam, err := h.examService.GetExamByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Exam not found"})
 	}
@@ -231,7 +230,7 @@ func (h *ExamHandler) UpdateExamPrepStatus(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Exam does not belong to user"})
 	}
 
-	if err := h.examService.UpdateExamPrepStatus(context.Background(), id, body.PrepStatus); err != nil {
+	if err := h.examService.UpdateExamPrepStatus(c.UserContext(), id, body.PrepStatus); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update exam prep status: " + err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Exam prep status updated successfully"})
@@ -258,7 +257,7 @@ func (h *ExamHandler) DeleteExam(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	// Check ownership before deleting
-	exam, err := h.examService.GetExamByID(context.Background(), id)
+	exam, err := h.examService.GetExamByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Exam not found"})
 	}
@@ -266,7 +265,7 @@ func (h *ExamHandler) DeleteExam(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Exam does not belong to user"})
 	}
 
-	if err := h.examService.DeleteExam(context.Background(), id); err != nil {
+	if err := h.examService.DeleteExam(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete exam: " + err.Error()})
 	}
 	return c.Status(fiber.StatusNoContent).Send(nil)
@@ -300,7 +299,7 @@ func (h *ExamHandler) CreateImportantQuestion(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	question, err := h.examService.CreateImportantQuestion(context.Background(), userID, &input)
+	question, err := h.examService.CreateImportantQuestion(c.UserContext(), userID, &input)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create important question: " + err.Error()})
 	}
@@ -325,7 +324,7 @@ func (h *ExamHandler) GetImportantQuestionsByExamID(c *fiber.Ctx) error {
 	}
 
 	examID := c.Params("examId")
-	questions, err := h.examService.GetImportantQuestionsByExamID(context.Background(), examID)
+	questions, err := h.examService.GetImportantQuestionsByExamID(c.UserContext(), examID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve important questions: " + err.Error()})
 	}
@@ -350,7 +349,7 @@ func (h *ExamHandler) GetImportantQuestionsBySubjectID(c *fiber.Ctx) error {
 	}
 
 	subjectID := c.Params("subjectId")
-	questions, err := h.examService.GetImportantQuestionsBySubjectID(context.Background(), subjectID)
+	questions, err := h.examService.GetImportantQuestionsBySubjectID(c.UserContext(), subjectID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve important questions: " + err.Error()})
 	}
@@ -389,7 +388,7 @@ func (h *ExamHandler) UpdateImportantQuestion(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	question, err := h.examService.UpdateImportantQuestion(context.Background(), userID, id, &input)
+	question, err := h.examService.UpdateImportantQuestion(c.UserContext(), userID, id, &input)
 	if err != nil {
 		if err.Error() == "important question does not belong to user" {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
@@ -423,7 +422,7 @@ func (h *ExamHandler) DeleteImportantQuestion(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	// Check ownership before deleting
-	question, err := h.examService.GetImportantQuestionByID(context.Background(), id)
+	question, err := h.examService.GetImportantQuestionByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Important question not found"})
 	}
@@ -431,7 +430,7 @@ func (h *ExamHandler) DeleteImportantQuestion(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Important question does not belong to user"})
 	}
 
-	if err := h.examService.DeleteImportantQuestion(context.Background(), id); err != nil {
+	if err := h.examService.DeleteImportantQuestion(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete important question: " + err.Error()})
 	}
 	return c.Status(fiber.StatusNoContent).Send(nil)
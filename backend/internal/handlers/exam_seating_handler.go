@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// ExamSeatingHandler handles HTTP requests related to exam seating and hall ticket details.
+type ExamSeatingHandler struct {
+	examSeatingService services.ExamSeatingService
+	validator          *validator.Validate
+}
+
+// NewExamSeatingHandler creates a new ExamSeatingHandler.
+func NewExamSeatingHandler(examSeatingService services.ExamSeatingService) *ExamSeatingHandler {
+	return &ExamSeatingHandler{
+		examSeatingService: examSeatingService,
+		validator:          validator.New(),
+	}
+}
+
+// GetExamSeating handles retrieving the seating of an exam.
+// @Summary Get exam seating
+// @Description Retrieve the venue, room, seat, reporting time and items allowed for an exam.
+// @Tags Exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Exam ID"
+// @Success 200 {object} models.ExamSeating
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/{id}/seating [get]
+func (h *ExamSeatingHandler) GetExamSeating(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	seating, err := h.examSeatingService.GetExamSeating(c.UserContext(), userID, id)
+	if err != nil {
+		return examSeatingErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(seating)
+}
+
+// SetExamSeating handles entering the seating of an exam by hand.
+// @Summary Set exam seating
+// @Description Record the venue, room, seat, reporting time and items allowed for an exam, replacing any imported seating.
+// @Tags Exams
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Exam ID"
+// @Param seating body models.ExamSeatingInput true "Seating details"
+// @Success 200 {object} models.ExamSeating
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/{id}/seating [put]
+func (h *ExamSeatingHandler) SetExamSeating(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.ExamSeatingInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed: " + err.Error()})
+	}
+
+	id := c.Params("id")
+	seating, err := h.examSeatingService.SetExamSeating(c.UserContext(), userID, id, &input)
+	if err != nil {
+		return examSeatingErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(seating)
+}
+
+// ImportSeatingChart handles finding the user's seat in a seating chart published by the exam cell.
+// @Summary Import a seating chart
+// @Description Find the user's seat in an uploaded or pasted CSV seating chart by register number, either exactly or within a register number range.
+// @Tags Exams
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Exam ID"
+// @Param file formData file false "CSV seating chart"
+// @Param content formData string false "Pasted CSV content, used when no file is uploaded"
+// @Param mapping formData string false "JSON object mapping fields to column headers or 1-based column numbers"
+// @Param dryRun formData boolean false "Find the seat without saving it"
+// @Success 200 {object} models.ExamSeatingImportResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/{id}/seating/import [post]
+func (h *ExamSeatingHandler) ImportSeatingChart(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.ExamSeatingImportInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+
+	var data []byte
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to read uploaded file: " + err.Error()})
+		}
+		defer file.Close()
+		data, err = io.ReadAll(file)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to read uploaded file: " + err.Error()})
+		}
+	} else if input.Content != nil && strings.TrimSpace(*input.Content) != "" {
+		data = []byte(*input.Content)
+	} else {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Upload a file or provide the seating chart content"})
+	}
+
+	id := c.Params("id")
+	result, err := h.examSeatingService.ImportSeatingChart(c.UserContext(), userID, id, data, &input)
+	if err != nil {
+		return examSeatingErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// DeleteExamSeating handles removing the seating of an exam.
+// @Summary Delete exam seating
+// @Description Remove the seating details recorded for an exam.
+// @Tags Exams
+// @Security BearerAuth
+// @Param id path string true "Exam ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/{id}/seating [delete]
+func (h *ExamSeatingHandler) DeleteExamSeating(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	if err := h.examSeatingService.DeleteExamSeating(c.UserContext(), userID, id); err != nil {
+		return examSeatingErrorResponse(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetExamBriefing handles retrieving the hall ticket briefing for upcoming exams.
+// @Summary Get exam briefing
+// @Description List exams in the next days with their venue, room, seat, items allowed and when to report.
+// @Tags Exams
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Number of days to include, starting today (default 1)"
+// @Success 200 {array} models.ExamBriefing
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/briefing [get]
+func (h *ExamSeatingHandler) GetExamBriefing(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	days := c.QueryInt("days", 1)
+	if days < 1 || days > 31 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "days must be between 1 and 31"})
+	}
+
+	briefings, err := h.examSeatingService.GetExamBriefing(c.UserContext(), userID, days)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to build exam briefing: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(briefings)
+}
+
+// examSeatingErrorResponse maps exam seating service errors to HTTP responses.
+func examSeatingErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidSeatingImport), errors.Is(err, services.ErrNoRegisterNumber):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, services.ErrSeatNotInChart):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case err.Error() == "exam does not belong to user":
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "exam not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Exam not found"})
+	case strings.Contains(err.Error(), "not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Exam seating not found"})
+	case strings.HasPrefix(err.Error(), "invalid reporting time"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to process exam seating: " + err.Error()})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// ExportHandler handles HTTP requests related to exporting a user's history.
+type ExportHandler struct {
+	exportService services.ExportService
+}
+
+// NewExportHandler creates a new ExportHandler.
+func NewExportHandler(exportService services.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// ExportAnalytics handles streaming one of the user's datasets as a file download.
+// @Summary Export analytics
+// @Description Download the authenticated user's daily stats, activity logs, study sessions or graded marks over a date range as CSV or NDJSON. Rows are streamed as they are read, so large ranges are fine. Dates are calendar days in the user's timezone and timestamps are RFC 3339 in that timezone.
+// @Tags Analytics
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Param dataset path string true "daily-stats, activity-logs, study-sessions or marks"
+// @Param format query string false "csv (default) or ndjson"
+// @Param from query string false "Start date (YYYY-MM-DD); defaults to 30 days before the end date"
+// @Param to query string false "End date (YYYY-MM-DD), inclusive; defaults to today"
+// @Param columns query string false "Comma-separated columns to include, in order; defaults to all"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /analytics/export/{dataset} [get]
+func (h *ExportHandler) ExportAnalytics(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	input := models.AnalyticsExportInput{
+		Dataset:  c.Params("dataset"),
+		Format:   strings.ToLower(c.Query("format", "csv")),
+		FromDate: c.Query("from"),
+		ToDate:   c.Query("to"),
+	}
+	for _, column := range strings.Split(c.Query("columns"), ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			input.Columns = append(input.Columns, column)
+		}
+	}
+
+	ctx := c.UserContext()
+	export, err := h.exportService.PrepareAnalyticsExport(ctx, userID, &input)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAnalyticsExport) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to export analytics: " + err.Error()})
+	}
+
+	c.Set("Content-Type", export.ContentType)
+	c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName))
+	// The body is written after the handler returns, so a failure part-way can only cut the download short.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := export.WriteTo(ctx, w); err != nil {
+			log.Printf("Warning: Analytics export %s for user %s stopped early: %v", export.FileName, userID, err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// GamificationHandler handles HTTP requests related to XP, levels, streaks and achievements.
+type GamificationHandler struct {
+	gamificationService services.GamificationService
+	validator           *validator.Validate
+}
+
+// NewGamificationHandler creates a new GamificationHandler.
+func NewGamificationHandler(gamificationService services.GamificationService) *GamificationHandler {
+	return &GamificationHandler{
+		gamificationService: gamificationService,
+		validator:           validator.New(),
+	}
+}
+
+// GetProgress handles retrieving the gamification progress of the authenticated user.
+// @Summary Get progress
+// @Description Get the authenticated user's XP, level, daily study streak with freeze tokens, achievements and latest XP awards. XP is earned automatically from completed study sessions, assignments, practised questions, quizzes and attended classes.
+// @Tags Gamification
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ProgressOverview
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /me/progress [get]
+func (h *GamificationHandler) GetProgress(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	progress, err := h.gamificationService.GetProgress(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve progress: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(progress)
+}
+
+// GetXPRules handles listing the XP rules.
+// @Summary Get XP rules
+// @Description List how much XP each kind of activity earns and its daily cap.
+// @Tags Gamification
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.XPRule
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /xp-rules [get]
+func (h *GamificationHandler) GetXPRules(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	rules, err := h.gamificationService.GetXPRules(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve XP rules: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(rules)
+}
+
+// UpdateXPRule handles tuning an XP rule.
+// @Summary Update an XP rule
+// @Description Admin only. Change the XP an activity earns, its daily cap (0 removes it) or whether it earns XP at all. Past awards are kept.
+// @Tags Gamification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rule path string true "XP rule, e.g. study_session_completed"
+// @Param input body models.XPRuleUpdateInput true "New rule settings"
+// @Success 200 {object} models.XPRule
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /xp-rules/{rule} [put]
+func (h *GamificationHandler) UpdateXPRule(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.XPRuleUpdateInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	rule, err := h.gamificationService.UpdateXPRule(c.UserContext(), c.Params("rule"), &input)
+	if err != nil {
+		if errors.Is(err, services.ErrXPRuleNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update XP rule: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(rule)
+}
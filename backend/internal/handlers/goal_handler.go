@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// GoalHandler handles HTTP requests for goals.
+type GoalHandler struct {
+	goalService services.GoalService
+	validator   *validator.Validate
+}
+
+// NewGoalHandler creates a new GoalHandler.
+func NewGoalHandler(goalService services.GoalService) *GoalHandler {
+	return &GoalHandler{
+		goalService: goalService,
+		validator:   validator.New(),
+	}
+}
+
+// CreateGoal handles creating a new goal.
+// @Summary Create a goal
+// @Description Set a target such as study hours per week, important questions practised before an exam, or an attendance percentage to stay above. Progress is computed from daily stats, question practice and attendance. Study hours, questions and attendance can be limited to a subject the user takes (on their timetable or linked to their exams, assignments or study sessions), questions also to an exam.
+// @Tags Goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param goal body models.GoalCreationInput true "Goal details"
+// @Success 201 {object} models.GoalWithProgress
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /goals [post]
+func (h *GoalHandler) CreateGoal(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.GoalCreationInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed: " + err.Error()})
+	}
+
+	goal, err := h.goalService.CreateGoal(c.UserContext(), userID, &input)
+	if err != nil {
+		return goalErrorResponse(c, err, "Failed to create goal: ")
+	}
+	return c.Status(fiber.StatusCreated).JSON(goal)
+}
+
+// GetGoals handles retrieving all goals of the user.
+// @Summary Get all goals
+// @Description Get the authenticated user's goals, newest first, with the progress of active goals in their current period.
+// @Tags Goals
+// @Produce json
+// @Security BearerAuth
+// @Param includeArchived query bool false "Include archived goals"
+// @Success 200 {array} models.GoalWithProgress
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /goals [get]
+func (h *GoalHandler) GetGoals(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	goals, err := h.goalService.GetGoals(c.UserContext(), userID, c.QueryBool("includeArchived", false))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve goals: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(goals)
+}
+
+// GetGoal handles retrieving a single goal.
+// @Summary Get a goal by ID
+// @Description Get a single goal with its progress in the current period: the value so far, the value expected by now at an even pace, days left and whether it is falling behind.
+// @Tags Goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} models.GoalWithProgress
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /goals/{id} [get]
+func (h *GoalHandler) GetGoal(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	goal, err := h.goalService.GetGoal(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return goalErrorResponse(c, err, "Failed to retrieve goal: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(goal)
+}
+
+// UpdateGoal handles updating a goal.
+// @Summary Update a goal
+// @Description Change a goal's title, target or end date, or archive it. The target and end date can only change while the goal is active.
+// @Tags Goals
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Param goal body models.GoalUpdateInput true "Fields to update"
+// @Success 200 {object} models.GoalWithProgress
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /goals/{id} [put]
+func (h *GoalHandler) UpdateGoal(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.GoalUpdateInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed: " + err.Error()})
+	}
+
+	goal, err := h.goalService.UpdateGoal(c.UserContext(), userID, c.Params("id"), &input)
+	if err != nil {
+		return goalErrorResponse(c, err, "Failed to update goal: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(goal)
+}
+
+// DeleteGoal handles deleting a goal.
+// @Summary Delete a goal
+// @Description Delete a goal together with its history.
+// @Tags Goals
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 204 "Goal deleted"
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /goals/{id} [delete]
+func (h *GoalHandler) DeleteGoal(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	if err := h.goalService.DeleteGoal(c.UserContext(), userID, c.Params("id")); err != nil {
+		return goalErrorResponse(c, err, "Failed to delete goal: ")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetGoalHistory handles retrieving the attainment history of a goal.
+// @Summary Get a goal's history
+// @Description Get the result of each finished period of a goal, newest first, with its attainment rate and current streak of achieved periods.
+// @Tags Goals
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Goal ID"
+// @Success 200 {object} models.GoalHistory
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /goals/{id}/history [get]
+func (h *GoalHandler) GetGoalHistory(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	history, err := h.goalService.GetGoalHistory(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return goalErrorResponse(c, err, "Failed to retrieve goal history: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(history)
+}
+
+// goalErrorResponse maps goal service errors to HTTP responses.
+func goalErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, services.ErrGoalNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Goal not found"})
+	case errors.Is(err, services.ErrInvalidGoal):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case strings.HasSuffix(err.Error(), "does not belong to user"):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "subject not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Subject not found"})
+	case strings.HasPrefix(err.Error(), "exam not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Exam not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": message + err.Error()})
+}
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// GradeBookHandler handles HTTP requests related to the grade book and grade schemes.
+type GradeBookHandler struct {
+	gradeBookService services.GradeBookService
+	validator        *validator.Validate
+}
+
+// NewGradeBookHandler creates a new GradeBookHandler.
+func NewGradeBookHandler(gradeBookService services.GradeBookService) *GradeBookHandler {
+	return &GradeBookHandler{
+		gradeBookService: gradeBookService,
+		validator:        validator.New(),
+	}
+}
+
+// GetGradeBook handles retrieving the grade book for the authenticated user.
+// @Summary Get the grade book
+// @Description Compute internal marks and grades per subject, SGPA per semester and CGPA for the authenticated user.
+// @Tags Grade Book
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.GradeBook
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /grade-book [get]
+func (h *GradeBookHandler) GetGradeBook(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	book, err := h.gradeBookService.GetGradeBook(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to compute grade book: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(book)
+}
+
+// GetSubjectGrade handles retrieving the grade-book entry for a single subject.
+// @Summary Get subject grade
+// @Description Compute the internal marks and grade of a single subject for the authenticated user.
+// @Tags Grade Book
+// @Produce json
+// @Security BearerAuth
+// @Param subjectId path string true "Subject ID"
+// @Success 200 {object} models.SubjectGrade
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /grade-book/subjects/{subjectId} [get]
+func (h *GradeBookHandler) GetSubjectGrade(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	subjectID := c.Params("subjectId")
+	grade, err := h.gradeBookService.GetSubjectGrade(c.UserContext(), userID, subjectID)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "subject not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Subject not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to compute subject grade: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(grade)
+}
+
+// ProjectGrade handles projecting what is needed in the remaining components to reach a target grade.
+// @Summary Project marks needed for a grade
+// @Description Work out the percentage (and final exam marks, when known) needed in the remaining components of a subject to reach a target grade.
+// @Tags Grade Book
+// @Produce json
+// @Security BearerAuth
+// @Param subjectId path string true "Subject ID"
+// @Param targetGrade query string true "Target grade (S, A, B, C, D, E)"
+// @Success 200 {object} models.GradeProjection
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /grade-book/subjects/{subjectId}/projection [get]
+func (h *GradeBookHandler) ProjectGrade(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	subjectID := c.Params("subjectId")
+	targetGrade := c.Query("targetGrade")
+	if targetGrade == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "targetGrade query parameter is required"})
+	}
+
+	projection, err := h.gradeBookService.ProjectGrade(c.UserContext(), userID, subjectID, targetGrade)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "unknown target grade") {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		if strings.HasPrefix(err.Error(), "subject not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Subject not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to project grade: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(projection)
+}
+
+// CreateGradeScheme handles creating a new grade scheme.
+// @Summary Create a grade scheme
+// @Description Create a weightage scheme for a subject, or the default scheme when no subject is given. Weights must add up to 100.
+// @Tags Grade Book
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param scheme body models.GradeSchemeInput true "Grade scheme details"
+// @Success 201 {object} models.GradeScheme
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /grade-book/schemes [post]
+func (h *GradeBookHandler) CreateGradeScheme(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.GradeSchemeInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	scheme, err := h.gradeBookService.CreateGradeScheme(c.UserContext(), userID, &input)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to create grade scheme: " + err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(scheme)
+}
+
+// GetGradeSchemes handles retrieving all grade schemes for the authenticated user.
+// @Summary Get grade schemes
+// @Description Retrieve all grade schemes configured by the authenticated user.
+// @Tags Grade Book
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.GradeScheme
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /grade-book/schemes [get]
+func (h *GradeBookHandler) GetGradeSchemes(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	schemes, err := h.gradeBookService.GetGradeSchemesByUserID(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve grade schemes: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(schemes)
+}
+
+// GetGradeSchemeByID handles retrieving a single grade scheme by ID.
+// @Summary Get grade scheme by ID
+// @Description Retrieve a single grade scheme by its ID for the authenticated user.
+// @Tags Grade Book
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Grade scheme ID"
+// @Success 200 {object} models.GradeScheme
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /grade-book/schemes/{id} [get]
+func (h *GradeBookHandler) GetGradeSchemeByID(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	scheme, err := h.gradeBookService.GetGradeSchemeByID(c.UserContext(), userID, id)
+	if err != nil {
+		if err.Error() == "grade scheme does not belong to user" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Grade scheme not found"})
+	}
+	return c.Status(fiber.StatusOK).JSON(scheme)
+}
+
+// UpdateGradeScheme handles updating an existing grade scheme.
+// @Summary Update a grade scheme
+// @Description Replace the name, subject and components of a grade scheme for the authenticated user.
+// @Tags Grade Book
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Grade scheme ID"
+// @Param scheme body models.GradeSchemeInput true "Grade scheme details"
+// @Success 200 {object} models.GradeScheme
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /grade-book/schemes/{id} [put]
+func (h *GradeBookHandler) UpdateGradeScheme(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	var input models.GradeSchemeInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	scheme, err := h.gradeBookService.UpdateGradeScheme(c.UserContext(), userID, id, &input)
+	if err != nil {
+		if err.Error() == "grade scheme does not belong to user" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
+		if strings.HasPrefix(err.Error(), "grade scheme not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Grade scheme not found"})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to update grade scheme: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(scheme)
+}
+
+// DeleteGradeScheme handles deleting a grade scheme.
+// @Summary Delete a grade scheme
+// @Description Delete a grade scheme for the authenticated user.
+// @Tags Grade Book
+// @Security BearerAuth
+// @Param id path string true "Grade scheme ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /grade-book/schemes/{id} [delete]
+func (h *GradeBookHandler) DeleteGradeScheme(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	if err := h.gradeBookService.DeleteGradeScheme(c.UserContext(), userID, id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Grade scheme not found or not owned by user"})
+	}
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// InsightsHandler handles HTTP requests related to study productivity insights.
+type InsightsHandler struct {
+	insightsService services.InsightsService
+}
+
+// NewInsightsHandler creates a new InsightsHandler.
+func NewInsightsHandler(insightsService services.InsightsService) *InsightsHandler {
+	return &InsightsHandler{insightsService: insightsService}
+}
+
+// GetProductivityInsights handles retrieving study productivity insights for the authenticated user.
+// @Summary Get productivity insights
+// @Description Analyse finished study sessions and assignment estimates: productivity and study time by hour of the day with the best hours, plan adherence (planned vs actual minutes and start times), average productivity by subject and session type, the most common blockers and how accurate assignment hour estimates were. Without a range the last 90 days are covered.
+// @Tags Analytics
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Start date (YYYY-MM-DD), inclusive; requires to"
+// @Param to query string false "End date (YYYY-MM-DD), inclusive; requires from"
+// @Success 200 {object} models.ProductivityInsights
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /analytics/insights [get]
+func (h *InsightsHandler) GetProductivityInsights(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	fromStr := c.Query("from")
+	toStr := c.Query("to")
+
+	var from, to time.Time
+	if fromStr != "" || toStr != "" {
+		if fromStr == "" || toStr == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "from and to must be provided together"})
+		}
+		var err error
+		from, err = time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid from date format. Use YYYY-MM-DD."})
+		}
+		to, err = time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid to date format. Use YYYY-MM-DD."})
+		}
+	}
+
+	insights, err := h.insightsService.GetProductivityInsights(c.UserContext(), userID, from, to)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidDateRange) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve productivity insights: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(insights)
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"fmt"
 	"strconv"
 	"time"
@@ -54,7 +53,7 @@ func (h *LabRecordHandler) CreateLabRecord(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	record, err := h.labRecordService.CreateLabRecord(context.Background(), userID, &input)
+	record, err := h.labRecordService.CreateLabRecord(c.UserContext(), userID, &input)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create lab record: " + err.Error()})
 	}
@@ -77,7 +76,7 @@ func (h *LabRecordHandler) GetLabRecords(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	records, err := h.labRecordService.GetLabRecordsByUserID(context.Background(), userID)
+	records, err := h.labRecordService.GetLabRecordsByUserID(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve lab records: " + err.Error()})
 	}
@@ -103,7 +102,7 @@ func (h *LabRecordHandler) GetLabRecordByID(c *fiber.Ctx) error {
 	}
 
 	id := c.Params("id")
-	record, err := h.labRecordService.GetLabRecordByID(context.Background(), id)
+	record, err := h.labRecordService.GetLabRecordByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Lab record not found or not owned by user"})
 	}
@@ -131,7 +130,7 @@ func (h *LabRecordHandler) GetLabRecordsBySubjectID(c *fiber.Ctx) error {
 	}
 
 	subjectID := c.Params("subjectId")
-	records, err := h.labRecordService.GetLabRecordsByUserIDAndSubjectID(context.Background(), userID, subjectID)
+	records, err := h.labRecordService.GetLabRecordsByUserIDAndSubjectID(c.UserContext(), userID, subjectID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve lab records: " + err.Error()})
 	}
@@ -170,7 +169,7 @@ func (h *LabRecordHandler) UpdateLabRecord(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	record, err := h.labRecordService.UpdateLabRecord(context.Background(), userID, id, &input)
+	record, err := h.labRecordService.UpdateLabRecord(c.UserContext(), userID, id, &input)
 	if err != nil {
 		if err.Error() == "lab record does not belong to user" {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
@@ -226,7 +225,7 @@ func (h *LabRecordHandler) UpdateLabRecordStatus(c *fiber.Ctx) error {
 	}
 
 	// Check ownership before updating
-	record, err := h.labRecordService.GetLabRecordByID(context.Background(), id)
+	record, err := h.labRecordService.GetLabRecordByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Lab record not found"})
 	}
@@ -234,7 +233,7 @@ func (h *LabRecordHandler) UpdateLabRecordStatus(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Lab record does not belong to user"})
 	}
 
-	if err := h.labRecordService.UpdateLabRecordStatus(context.Background(), id, body.Status); err != nil {
+	if err := h.labRecordService.UpdateLabRecordStatus(c.UserContext(), id, body.Status); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update lab record status: " + err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Lab record status updated successfully"})
@@ -261,7 +260,7 @@ func (h *LabRecordHandler) DeleteLabRecord(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	// Check ownership before deleting
-	record, err := h.labRecordService.GetLabRecordByID(context.Background(), id)
+	record, err := h.labRecordService.GetLabRecordByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Lab record not found"})
 	}
@@ -269,7 +268,7 @@ func (h *LabRecordHandler) DeleteLabRecord(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Lab record does not belong to user"})
 	}
 
-	if err := h.labRecordService.DeleteLabRecord(context.Background(), id); err != nil {
+	if err := h.labRecordService.DeleteLabRecord(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete lab record: " + err.Error()})
 	}
 	return c.Status(fiber.StatusNoContent).Send(nil)
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// NotificationHandler handles HTTP requests related to notifications.
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler.
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// GetNotifications handles retrieving notifications for the authenticated user.
+// @Summary Get notifications
+// @Description Retrieve notifications for the authenticated user, newest first.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Only return unread notifications"
+// @Success 200 {array} models.Notification
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	notifications, err := h.notificationService.GetNotificationsByUserID(c.UserContext(), userID, c.QueryBool("unread", false))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve notifications: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(notifications)
+}
+
+// MarkNotificationRead handles marking a notification as read.
+// @Summary Mark a notification as read
+// @Description Mark a single notification as read for the authenticated user.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} map[string]string "Notification marked as read"
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /notifications/{id}/read [patch]
+func (h *NotificationHandler) MarkNotificationRead(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	if err := h.notificationService.MarkNotificationRead(c.UserContext(), userID, id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Notification not found or not owned by user"})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Notification marked as read"})
+}
+
+// MarkAllNotificationsRead handles marking all notifications as read.
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification as read for the authenticated user.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Notifications marked as read"
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	if err := h.notificationService.MarkAllNotificationsRead(c.UserContext(), userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to mark notifications as read: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Notifications marked as read"})
+}
+
+// DeleteNotification handles deleting a notification.
+// @Summary Delete a notification
+// @Description Delete a notification for the authenticated user.
+// @Tags Notifications
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /notifications/{id} [delete]
+func (h *NotificationHandler) DeleteNotification(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	if err := h.notificationService.DeleteNotification(c.UserContext(), userID, id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Notification not found or not owned by user"})
+	}
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// PomodoroHandler handles HTTP requests related to pomodoros on study sessions.
+type PomodoroHandler struct {
+	pomodoroService services.PomodoroService
+	validator       *validator.Validate
+}
+
+// NewPomodoroHandler creates a new PomodoroHandler.
+func NewPomodoroHandler(pomodoroService services.PomodoroService) *PomodoroHandler {
+	return &PomodoroHandler{
+		pomodoroService: pomodoroService,
+		validator:       validator.New(),
+	}
+}
+
+// StartPomodoro handles starting a pomodoro on a study session.
+// @Summary Start a pomodoro
+// @Description Start work/break cycles on a study session. Without a target, enough cycles are run to fill the session's planned duration. Only one pomodoro may be active at a time, and not while a timer is running.
+// @Tags Pomodoro
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Study Session ID"
+// @Param pomodoro body models.PomodoroStartInput false "Work and break lengths"
+// @Success 201 {object} models.PomodoroState
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "A pomodoro or timer is already running"
+// @Failure 500 {object} map[string]string
+// @Router /study-sessions/{id}/pomodoro [post]
+func (h *PomodoroHandler) StartPomodoro(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.PomodoroStartInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	state, err := h.pomodoroService.StartPomodoro(c.UserContext(), userID, c.Params("id"), &input)
+	if err != nil {
+		return h.pomodoroError(c, err, "Failed to start pomodoro: ")
+	}
+	return c.Status(fiber.StatusCreated).JSON(state)
+}
+
+// GetCurrentPomodoro handles retrieving the active pomodoro.
+// @Summary Get the current pomodoro
+// @Description Get the running or paused pomodoro with the time left in its phase. Phases that ended while the client was away are applied first, so this is safe to call after reconnecting.
+// @Tags Pomodoro
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PomodoroState
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string "No active pomodoro"
+// @Failure 500 {object} map[string]string
+// @Router /pomodoro/current [get]
+func (h *PomodoroHandler) GetCurrentPomodoro(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	state, err := h.pomodoroService.GetCurrentPomodoro(c.UserContext(), userID)
+	if err != nil {
+		return h.pomodoroError(c, err, "Failed to retrieve pomodoro: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(state)
+}
+
+// PausePomodoro handles pausing the running pomodoro.
+// @Summary Pause the pomodoro
+// @Description Pause the running pomodoro. Focus time so far is added to the day's study minutes.
+// @Tags Pomodoro
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PomodoroState
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string "No active pomodoro"
+// @Failure 409 {object} map[string]string "The pomodoro is not running"
+// @Failure 500 {object} map[string]string
+// @Router /pomodoro/pause [post]
+func (h *PomodoroHandler) PausePomodoro(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	state, err := h.pomodoroService.PausePomodoro(c.UserContext(), userID)
+	if err != nil {
+		return h.pomodoroError(c, err, "Failed to pause pomodoro: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(state)
+}
+
+// ResumePomodoro handles resuming the paused pomodoro.
+// @Summary Resume the pomodoro
+// @Description Resume the paused pomodoro where it left off.
+// @Tags Pomodoro
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PomodoroState
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string "No active pomodoro"
+// @Failure 409 {object} map[string]string "The pomodoro is not paused"
+// @Failure 500 {object} map[string]string
+// @Router /pomodoro/resume [post]
+func (h *PomodoroHandler) ResumePomodoro(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	state, err := h.pomodoroService.ResumePomodoro(c.UserContext(), userID)
+	if err != nil {
+		return h.pomodoroError(c, err, "Failed to resume pomodoro: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(state)
+}
+
+// SkipBreak handles skipping the current break.
+// @Summary Skip the break
+// @Description End the current break and start the next work phase right away.
+// @Tags Pomodoro
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PomodoroState
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string "No active pomodoro"
+// @Failure 409 {object} map[string]string "There is no break to skip"
+// @Failure 500 {object} map[string]string
+// @Router /pomodoro/skip-break [post]
+func (h *PomodoroHandler) SkipBreak(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	state, err := h.pomodoroService.SkipBreak(c.UserContext(), userID)
+	if err != nil {
+		return h.pomodoroError(c, err, "Failed to skip break: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(state)
+}
+
+// RecordInterruption handles recording an interruption of the active pomodoro.
+// @Summary Record an interruption
+// @Description Log an internal or external interruption of the active pomodoro, optionally pausing it.
+// @Tags Pomodoro
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param interruption body models.PomodoroInterruptionInput true "Interruption details"
+// @Success 201 {object} models.PomodoroState
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string "No active pomodoro"
+// @Failure 500 {object} map[string]string
+// @Router /pomodoro/interruptions [post]
+func (h *PomodoroHandler) RecordInterruption(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.PomodoroInterruptionInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	state, err := h.pomodoroService.RecordInterruption(c.UserContext(), userID, &input)
+	if err != nil {
+		return h.pomodoroError(c, err, "Failed to record interruption: ")
+	}
+	return c.Status(fiber.StatusCreated).JSON(state)
+}
+
+// StopPomodoro handles finishing the active pomodoro.
+// @Summary Stop the pomodoro
+// @Description Finish the active pomodoro and write its actual start and end time, duration, covered topics and completion percentage to the study session. The completion is derived from the topics or the focus time when omitted.
+// @Tags Pomodoro
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param pomodoro body models.PomodoroStopInput false "Topics covered and completion"
+// @Success 200 {object} models.PomodoroState
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string "No active pomodoro"
+// @Failure 500 {object} map[string]string
+// @Router /pomodoro/stop [post]
+func (h *PomodoroHandler) StopPomodoro(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.PomodoroStopInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	state, err := h.pomodoroService.StopPomodoro(c.UserContext(), userID, &input)
+	if err != nil {
+		return h.pomodoroError(c, err, "Failed to stop pomodoro: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(state)
+}
+
+// GetPomodorosByStudySession handles retrieving the pomodoros run on a study session.
+// @Summary Get pomodoros of a study session
+// @Description Get all pomodoros run on one of the user's study sessions, newest first.
+// @Tags Pomodoro
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Study Session ID"
+// @Success 200 {array} models.PomodoroSession
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /study-sessions/{id}/pomodoros [get]
+func (h *PomodoroHandler) GetPomodorosByStudySession(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	pomodoros, err := h.pomodoroService.GetPomodorosByStudySession(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return h.pomodoroError(c, err, "Failed to retrieve pomodoros: ")
+	}
+	if pomodoros == nil {
+		pomodoros = []models.PomodoroSession{}
+	}
+	return c.Status(fiber.StatusOK).JSON(pomodoros)
+}
+
+// pomodoroError maps pomodoro service errors to HTTP responses.
+func (h *PomodoroHandler) pomodoroError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, services.ErrNoActivePomodoro):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, services.ErrPomodoroActive), errors.Is(err, services.ErrTimerAlreadyRunning), errors.Is(err, services.ErrInvalidPomodoroAction), errors.Is(err, services.ErrPomodoroChanged):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "study session not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Study session not found"})
+	case strings.HasPrefix(err.Error(), "study session does not belong"):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": message + err.Error()})
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// QuestionBankHandler handles importing and exporting important questions.
+type QuestionBankHandler struct {
+	questionBankService services.QuestionBankService
+}
+
+// NewQuestionBankHandler creates a new QuestionBankHandler.
+func NewQuestionBankHandler(questionBankService services.QuestionBankService) *QuestionBankHandler {
+	return &QuestionBankHandler{questionBankService: questionBankService}
+}
+
+// ImportQuestions handles importing important questions from a CSV file or Markdown table.
+// @Summary Import important questions
+// @Description Import important questions from an uploaded or pasted CSV file or Markdown table. Columns are matched by header name or by the mapping, e.g. {"question": "Question Text", "marks": "3"}.
+// @Tags Important Questions
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file false "CSV or Markdown file"
+// @Param content formData string false "Pasted file content, used when no file is uploaded"
+// @Param format formData string false "csv or markdown (inferred when omitted)"
+// @Param mapping formData string false "JSON object mapping fields to column headers or 1-based column numbers"
+// @Param hasHeader formData boolean false "Whether the first row holds column headers (default true)"
+// @Param subjectId formData string false "Subject ID applied to every question"
+// @Param examId formData string false "Exam ID applied to every question"
+// @Param dryRun formData boolean false "Parse and validate without saving"
+// @Success 201 {object} models.QuestionImportResult
+// @Success 200 {object} models.QuestionImportResult "Dry run"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /important-questions/import [post]
+func (h *QuestionBankHandler) ImportQuestions(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.QuestionImportInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+
+	var data []byte
+	fileName := ""
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to read uploaded file: " + err.Error()})
+		}
+		defer file.Close()
+		data, err = io.ReadAll(file)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to read uploaded file: " + err.Error()})
+		}
+		fileName = fileHeader.Filename
+	} else if input.Content != nil && strings.TrimSpace(*input.Content) != "" {
+		data = []byte(*input.Content)
+	} else {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Upload a file or provide the content to import"})
+	}
+
+	result, err := h.questionBankService.ImportQuestions(c.UserContext(), userID, data, fileName, &input)
+	if err != nil {
+		return questionBankErrorResponse(c, err)
+	}
+	if result.DryRun {
+		return c.Status(fiber.StatusOK).JSON(result)
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// ExportQuestions handles exporting important questions as a file download.
+// @Summary Export important questions
+// @Description Download important questions, optionally filtered by exam or subject, as CSV, a Markdown table or tab-separated Anki notes.
+// @Tags Important Questions
+// @Produce text/csv
+// @Produce text/markdown
+// @Produce text/plain
+// @Security BearerAuth
+// @Param format query string false "csv (default), markdown or anki"
+// @Param examId query string false "Exam ID"
+// @Param subjectId query string false "Subject ID"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /important-questions/export [get]
+func (h *QuestionBankHandler) ExportQuestions(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var examID, subjectID *string
+	if v := c.Query("examId"); v != "" {
+		examID = &v
+	}
+	if v := c.Query("subjectId"); v != "" {
+		subjectID = &v
+	}
+
+	export, err := h.questionBankService.ExportQuestions(c.UserContext(), userID, strings.ToLower(c.Query("format", "csv")), examID, subjectID)
+	if err != nil {
+		return questionBankErrorResponse(c, err)
+	}
+
+	c.Set("Content-Type", export.ContentType)
+	c.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName))
+	return c.Send(export.Content)
+}
+
+// questionBankErrorResponse maps question bank service errors to HTTP responses.
+func questionBankErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidQuestionImport), errors.Is(err, services.ErrUnsupportedExportFormat):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case err.Error() == "exam does not belong to user":
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "exam not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Exam not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to process question bank: " + err.Error()})
+}
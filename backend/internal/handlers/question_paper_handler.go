@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// QuestionPaperHandler handles HTTP requests related to previous question papers.
+type QuestionPaperHandler struct {
+	questionPaperService services.QuestionPaperService
+}
+
+// NewQuestionPaperHandler creates a new QuestionPaperHandler.
+func NewQuestionPaperHandler(questionPaperService services.QuestionPaperService) *QuestionPaperHandler {
+	return &QuestionPaperHandler{questionPaperService: questionPaperService}
+}
+
+// IngestPaper handles ingesting an uploaded or pasted question paper.
+// @Summary Ingest a question paper
+// @Description Extract questions from an uploaded text/PDF paper or pasted text, merging near-duplicates into the question bank and raising their frequency.
+// @Tags Question Papers
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file false "Question paper (text or PDF)"
+// @Param text formData string false "Pasted paper text, used when no file is uploaded"
+// @Param subjectId formData string false "Subject ID"
+// @Param examId formData string false "Exam ID"
+// @Param source formData string false "Paper source, e.g. FAT Nov 2023"
+// @Param dryRun formData boolean false "Extract and match without saving"
+// @Success 201 {object} models.QuestionPaperIngestResult
+// @Success 200 {object} models.QuestionPaperIngestResult "Dry run"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /question-papers/ingest [post]
+func (h *QuestionPaperHandler) IngestPaper(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.QuestionPaperIngestInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+
+	var data []byte
+	fileName := ""
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to read uploaded file: " + err.Error()})
+		}
+		defer file.Close()
+		data, err = io.ReadAll(file)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Failed to read uploaded file: " + err.Error()})
+		}
+		fileName = fileHeader.Filename
+	} else if input.Text != nil && strings.TrimSpace(*input.Text) != "" {
+		data = []byte(*input.Text)
+	} else {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Upload a file or provide the paper text"})
+	}
+
+	result, err := h.questionPaperService.IngestPaper(c.UserContext(), userID, data, fileName, &input)
+	if err != nil {
+		return questionPaperErrorResponse(c, err)
+	}
+	if result.DryRun {
+		return c.Status(fiber.StatusOK).JSON(result)
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// IngestDocument handles ingesting a previous paper stored in the document vault.
+// @Summary Ingest a vault document
+// @Description Extract questions from a previous or FAT paper in the document vault into the question bank. The document's file URL must point to a public address.
+// @Tags Question Papers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param documentId path string true "Document ID"
+// @Param input body models.QuestionPaperIngestInput false "Ingestion options"
+// @Success 201 {object} models.QuestionPaperIngestResult
+// @Success 200 {object} models.QuestionPaperIngestResult "Dry run"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /question-papers/documents/{documentId}/ingest [post]
+func (h *QuestionPaperHandler) IngestDocument(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.QuestionPaperIngestInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+		}
+	}
+
+	documentID := c.Params("documentId")
+	result, err := h.questionPaperService.IngestDocument(c.UserContext(), userID, documentID, &input)
+	if err != nil {
+		return questionPaperErrorResponse(c, err)
+	}
+	if result.DryRun {
+		return c.Status(fiber.StatusOK).JSON(result)
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// GetQuestionPapers handles retrieving all ingested question papers for the user.
+// @Summary Get ingested question papers
+// @Description Retrieve all question papers ingested by the authenticated user, newest first.
+// @Tags Question Papers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.QuestionPaper
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /question-papers [get]
+func (h *QuestionPaperHandler) GetQuestionPapers(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	papers, err := h.questionPaperService.GetQuestionPapersByUserID(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve question papers: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(papers)
+}
+
+// DeleteQuestionPaper handles deleting an ingested question paper record.
+// @Summary Delete a question paper
+// @Description Delete an ingested question paper record. Questions added to the bank are kept.
+// @Tags Question Papers
+// @Security BearerAuth
+// @Param id path string true "Question paper ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /question-papers/{id} [delete]
+func (h *QuestionPaperHandler) DeleteQuestionPaper(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	if err := h.questionPaperService.DeleteQuestionPaper(c.UserContext(), userID, id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Question paper not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete question paper: " + err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetExamQuestionSuggestions handles retrieving high-frequency questions per unit for an exam.
+// @Summary Get suggested questions for an exam
+// @Description List the most frequently asked questions per syllabus unit for an exam.
+// @Tags Exams
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Exam ID"
+// @Param perUnit query int false "Questions per unit (default 5)"
+// @Param minFrequency query int false "Minimum frequency count (default 2)"
+// @Success 200 {object} models.ExamQuestionSuggestions
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /exams/{id}/question-suggestions [get]
+func (h *QuestionPaperHandler) GetExamQuestionSuggestions(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	perUnit := c.QueryInt("perUnit", 5)
+	if perUnit < 1 || perUnit > 50 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "perUnit must be between 1 and 50"})
+	}
+	minFrequency := c.QueryInt("minFrequency", 2)
+	if minFrequency < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "minFrequency must be at least 1"})
+	}
+
+	id := c.Params("id")
+	suggestions, err := h.questionPaperService.GetExamQuestionSuggestions(c.UserContext(), userID, id, perUnit, minFrequency)
+	if err != nil {
+		return questionPaperErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(suggestions)
+}
+
+// questionPaperErrorResponse maps question paper service errors to HTTP responses.
+func questionPaperErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrUnsupportedPaperFormat), errors.Is(err, services.ErrNoQuestionsExtracted), errors.Is(err, services.ErrPaperTooLarge):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, services.ErrNotAQuestionPaper), errors.Is(err, services.ErrPaperURLNotAllowed):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, services.ErrPaperAlreadyIngested):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case strings.HasSuffix(err.Error(), "does not belong to user"):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "document not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Document not found"})
+	case strings.HasPrefix(err.Error(), "exam not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Exam not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to process question paper: " + err.Error()})
+}
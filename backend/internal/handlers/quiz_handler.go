@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// QuizHandler handles HTTP requests related to practice quizzes.
+type QuizHandler struct {
+	quizService services.QuizService
+	validator   *validator.Validate
+}
+
+// NewQuizHandler creates a new QuizHandler.
+func NewQuizHandler(quizService services.QuizService) *QuizHandler {
+	return &QuizHandler{
+		quizService: quizService,
+		validator:   validator.New(),
+	}
+}
+
+// CreateQuiz handles starting a new quiz.
+// @Summary Start a quiz
+// @Description Assemble a practice quiz from important questions filtered by exam, subject, unit, topic or marks.
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param quiz body models.QuizCreationInput true "Quiz filters"
+// @Success 201 {object} models.Quiz
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /quizzes [post]
+func (h *QuizHandler) CreateQuiz(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.QuizCreationInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	quiz, err := h.quizService.CreateQuiz(c.UserContext(), userID, &input)
+	if err != nil {
+		if errors.Is(err, services.ErrNoQuizQuestions) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create quiz: " + err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(quiz)
+}
+
+// GetQuizzes handles retrieving all quizzes for the authenticated user.
+// @Summary Get quizzes
+// @Description Retrieve all quizzes and their results for the authenticated user, newest first.
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Quiz
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /quizzes [get]
+func (h *QuizHandler) GetQuizzes(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	quizzes, err := h.quizService.GetQuizzesByUserID(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve quizzes: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(quizzes)
+}
+
+// GetQuizByID handles retrieving a quiz with its questions and responses.
+// @Summary Get quiz by ID
+// @Description Retrieve a quiz with every question and the recorded responses.
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Quiz ID"
+// @Success 200 {object} models.Quiz
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /quizzes/{id} [get]
+func (h *QuizHandler) GetQuizByID(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	quiz, err := h.quizService.GetQuizByID(c.UserContext(), userID, id)
+	if err != nil {
+		return quizErrorResponse(c, err, "Failed to retrieve quiz: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(quiz)
+}
+
+// GetCurrentQuestion handles retrieving the next unanswered question of a quiz.
+// @Summary Get the current quiz question
+// @Description Retrieve the next unanswered question of a quiz, without its answer.
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Quiz ID"
+// @Success 200 {object} models.QuizQuestionView
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /quizzes/{id}/current [get]
+func (h *QuizHandler) GetCurrentQuestion(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	question, err := h.quizService.GetCurrentQuestion(c.UserContext(), userID, id)
+	if err != nil {
+		return quizErrorResponse(c, err, "Failed to retrieve quiz question: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(question)
+}
+
+// AnswerQuestion handles recording the response to a quiz question.
+// @Summary Answer a quiz question
+// @Description Record a self grade and/or confidence level for a quiz question. Returns the model answer and the next question.
+// @Tags Quizzes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Quiz ID"
+// @Param position path int true "Question position (1-based)"
+// @Param answer body models.QuizAnswerInput true "Response"
+// @Success 200 {object} models.QuizAnswerResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /quizzes/{id}/questions/{position}/answer [post]
+func (h *QuizHandler) AnswerQuestion(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	position, err := c.ParamsInt("position")
+	if err != nil || position < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid question position"})
+	}
+
+	var input models.QuizAnswerInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result, err := h.quizService.AnswerQuestion(c.UserContext(), userID, id, position, &input)
+	if err != nil {
+		return quizErrorResponse(c, err, "Failed to record answer: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// CompleteQuiz handles ending a quiz early.
+// @Summary Complete a quiz
+// @Description End a quiz and store its results. Unanswered questions count against the score.
+// @Tags Quizzes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Quiz ID"
+// @Success 200 {object} models.Quiz
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /quizzes/{id}/complete [post]
+func (h *QuizHandler) CompleteQuiz(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	quiz, err := h.quizService.CompleteQuiz(c.UserContext(), userID, id)
+	if err != nil {
+		return quizErrorResponse(c, err, "Failed to complete quiz: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(quiz)
+}
+
+// DeleteQuiz handles deleting a quiz.
+// @Summary Delete a quiz
+// @Description Delete a quiz and its responses for the authenticated user.
+// @Tags Quizzes
+// @Security BearerAuth
+// @Param id path string true "Quiz ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /quizzes/{id} [delete]
+func (h *QuizHandler) DeleteQuiz(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	if err := h.quizService.DeleteQuiz(c.UserContext(), userID, id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Quiz not found or not owned by user"})
+	}
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// quizErrorResponse maps quiz service errors to HTTP responses.
+func quizErrorResponse(c *fiber.Ctx, err error, prefix string) error {
+	switch {
+	case errors.Is(err, services.ErrQuizCompleted), errors.Is(err, services.ErrQuizQuestionAnswered):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, services.ErrQuizAnswerRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case err.Error() == "quiz does not belong to user":
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "quiz not found"), err.Error() == "quiz question not found":
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": prefix + err.Error()})
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// SpacedRepetitionHandler handles HTTP requests related to reviewing important questions.
+type SpacedRepetitionHandler struct {
+	spacedRepetitionService services.SpacedRepetitionService
+	validator               *validator.Validate
+}
+
+// NewSpacedRepetitionHandler creates a new SpacedRepetitionHandler.
+func NewSpacedRepetitionHandler(spacedRepetitionService services.SpacedRepetitionService) *SpacedRepetitionHandler {
+	return &SpacedRepetitionHandler{
+		spacedRepetitionService: spacedRepetitionService,
+		validator:               validator.New(),
+	}
+}
+
+// GetDueQuestions handles retrieving the important questions due for review.
+// @Summary Get due important questions
+// @Description Retrieve important questions due for review, prioritising those linked to the nearest upcoming exam.
+// @Tags Important Questions
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Maximum number of questions to return"
+// @Success 200 {array} models.DueQuestion
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /important-questions/due [get]
+func (h *SpacedRepetitionHandler) GetDueQuestions(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	questions, err := h.spacedRepetitionService.GetDueQuestions(c.UserContext(), userID, c.QueryInt("limit", 0))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve due questions: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(questions)
+}
+
+// ReviewQuestion handles recording a single practice attempt of an important question.
+// @Summary Review an important question
+// @Description Record a practice attempt with an SM-2 recall quality (0-5) and schedule the next review.
+// @Tags Important Questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Important Question ID"
+// @Param review body models.QuestionReviewInput true "Review details"
+// @Success 201 {object} models.QuestionReview
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /important-questions/{id}/review [post]
+func (h *SpacedRepetitionHandler) ReviewQuestion(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	var input models.QuestionReviewInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	review, err := h.spacedRepetitionService.ReviewQuestion(c.UserContext(), userID, id, &input)
+	if err != nil {
+		return reviewErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(review)
+}
+
+// GetQuestionReviews handles retrieving the review history of an important question.
+// @Summary Get review history
+// @Description Retrieve every recorded practice attempt of an important question, newest first.
+// @Tags Important Questions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Important Question ID"
+// @Success 200 {array} models.QuestionReview
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /important-questions/{id}/reviews [get]
+func (h *SpacedRepetitionHandler) GetQuestionReviews(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	id := c.Params("id")
+	reviews, err := h.spacedRepetitionService.GetQuestionReviews(c.UserContext(), userID, id)
+	if err != nil {
+		return reviewErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(reviews)
+}
+
+// GetReviewSession handles building a review session from due questions.
+// @Summary Start a review session
+// @Description Pick a batch of due questions to practice, optionally limited to an exam or subject.
+// @Tags Important Questions
+// @Produce json
+// @Security BearerAuth
+// @Param size query int false "Number of questions in the session (default 20)"
+// @Param examId query string false "Only include questions for this exam"
+// @Param subjectId query string false "Only include questions for this subject"
+// @Success 200 {object} models.ReviewSession
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /important-questions/review-session [get]
+func (h *SpacedRepetitionHandler) GetReviewSession(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	session, err := h.spacedRepetitionService.GetReviewSession(c.UserContext(), userID, c.QueryInt("size", 20), c.Query("examId"), c.Query("subjectId"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to build review session: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(session)
+}
+
+// SubmitReviewSession handles recording the results of a review session.
+// @Summary Submit a review session
+// @Description Record a practice attempt for every question in a review session and reschedule them.
+// @Tags Important Questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param session body models.ReviewSessionInput true "Session results"
+// @Success 200 {object} models.ReviewSessionResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /important-questions/review-session [post]
+func (h *SpacedRepetitionHandler) SubmitReviewSession(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.ReviewSessionInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	for _, review := range input.Reviews {
+		if review.QuestionID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "questionId is required for every review"})
+		}
+	}
+
+	result, err := h.spacedRepetitionService.SubmitReviewSession(c.UserContext(), userID, &input)
+	if err != nil {
+		return reviewErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusOK).JSON(result)
+}
+
+// reviewErrorResponse maps spaced repetition service errors to HTTP responses.
+func reviewErrorResponse(c *fiber.Ctx, err error) error {
+	if err.Error() == "important question does not belong to user" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	}
+	if strings.HasPrefix(err.Error(), "important question not found") {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Important question not found"})
+	}
+	if strings.HasPrefix(err.Error(), "quality must be") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to record review: " + err.Error()})
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// StudyCarryOverHandler handles HTTP requests for carrying over unfinished study sessions.
+type StudyCarryOverHandler struct {
+	studyCarryOverService services.StudyCarryOverService
+}
+
+// NewStudyCarryOverHandler creates a new StudyCarryOverHandler.
+func NewStudyCarryOverHandler(studyCarryOverService services.StudyCarryOverService) *StudyCarryOverHandler {
+	return &StudyCarryOverHandler{studyCarryOverService: studyCarryOverService}
+}
+
+// CarryOverSessions handles carrying over the unfinished topics of skipped and partial sessions.
+// @Summary Carry over unfinished study sessions
+// @Description Move the uncovered topics of skipped and partial sessions into free time before their exam, assignment or plan deadline, including today's sessions. Topics that no longer fit are added to later sessions for the same exam, assignment or subject, and the report says whether the plan is still feasible. This also runs automatically once each day has ended.
+// @Tags Study Sessions
+// @Produce json
+// @Security BearerAuth
+// @Param dryRun query boolean false "Report the carry-over without saving it"
+// @Success 200 {object} models.StudyCarryOverReport
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /study-sessions/carry-over [post]
+func (h *StudyCarryOverHandler) CarryOverSessions(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	report, err := h.studyCarryOverService.CarryOverSessions(c.UserContext(), userID, c.QueryBool("dryRun", false))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to carry over study sessions: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(report)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// StudyPlanGeneratorHandler handles HTTP requests for generating study plans.
+type StudyPlanGeneratorHandler struct {
+	studyPlanGeneratorService services.StudyPlanGeneratorService
+	validator                 *validator.Validate
+}
+
+// NewStudyPlanGeneratorHandler creates a new StudyPlanGeneratorHandler.
+func NewStudyPlanGeneratorHandler(studyPlanGeneratorService services.StudyPlanGeneratorService) *StudyPlanGeneratorHandler {
+	return &StudyPlanGeneratorHandler{
+		studyPlanGeneratorService: studyPlanGeneratorService,
+		validator:                 validator.New(),
+	}
+}
+
+// GenerateStudyPlan handles generating a multi-day study plan from exams, assignments and free time.
+// @Summary Generate a study plan
+// @Description Generate a multi-day study plan from upcoming exams, pending assignments and free time around the timetable. Syllabus topics are spread across preparation sessions, revision passes are placed in the days before each exam, and the daily limit and preferred hours are respected.
+// @Tags Study Plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param settings body models.StudyPlanGenerationInput true "Generation settings"
+// @Success 201 {object} models.StudyPlanGenerationResult
+// @Success 200 {object} models.StudyPlanGenerationResult "Dry run"
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /study-plans/generate [post]
+func (h *StudyPlanGeneratorHandler) GenerateStudyPlan(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.StudyPlanGenerationInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+		}
+	}
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed: " + err.Error()})
+	}
+
+	result, err := h.studyPlanGeneratorService.GenerateStudyPlan(c.UserContext(), userID, &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPlanSettings):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, services.ErrNothingToPlan), errors.Is(err, services.ErrNoFreeTime):
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+		case err.Error() == "exam does not belong to user":
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		case strings.HasPrefix(err.Error(), "exam not found"):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Exam not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate study plan: " + err.Error()})
+	}
+	if result.DryRun {
+		return c.Status(fiber.StatusOK).JSON(result)
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
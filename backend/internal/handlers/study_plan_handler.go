@@ -1,8 +1,8 @@
 package handlers
 
 import (
-	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -53,7 +53,7 @@ func (h *StudyPlanHandler) CreateStudyPlan(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	plan, err := h.studyPlanService.CreateStudyPlan(context.Background(), userID, &input)
+	plan, err := h.studyPlanService.CreateStudyPlan(c.UserContext(), userID, &input)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create study plan: " + err.Error()})
 	}
@@ -76,7 +76,7 @@ func (h *StudyPlanHandler) GetStudyPlans(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	plans, err := h.studyPlanService.GetStudyPlansByUserID(context.Background(), userID)
+	plans, err := h.studyPlanService.GetStudyPlansByUserID(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve study plans: " + err.Error()})
 	}
@@ -102,7 +102,7 @@ func (h *StudyPlanHandler) GetStudyPlanByID(c *fiber.Ctx) error {
 	}
 
 	id := c.Params("id")
-	plan, err := h.studyPlanService.GetStudyPlanByID(context.Background(), id)
+	plan, err := h.studyPlanService.GetStudyPlanByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Study plan not found or not owned by user"})
 	}
@@ -136,7 +136,7 @@ func (h *StudyPlanHandler) GetStudyPlansByDate(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid date format. Use YYYY-MM-DD."})
 	}
 
-	plans, err := h.studyPlanService.GetStudyPlansByUserIDAndDate(context.Background(), userID, date)
+	plans, err := h.studyPlanService.GetStudyPlansByUserIDAndDate(c.UserContext(), userID, date)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve study plans: " + err.Error()})
 	}
@@ -175,7 +175,7 @@ func (h *StudyPlanHandler) UpdateStudyPlan(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	plan, err := h.studyPlanService.UpdateStudyPlan(context.Background(), userID, id, &input)
+	plan, err := h.studyPlanService.UpdateStudyPlan(c.UserContext(), userID, id, &input)
 	if err != nil {
 		if err.Error() == "study plan does not belong to user" {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
@@ -209,7 +209,7 @@ func (h *StudyPlanHandler) DeleteStudyPlan(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	// Check ownership before deleting
-	plan, err := h.studyPlanService.GetStudyPlanByID(context.Background(), id)
+	plan, err := h.studyPlanService.GetStudyPlanByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Study plan not found"})
 	}
@@ -217,7 +217,7 @@ func (h *StudyPlanHandler) DeleteStudyPlan(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Study plan does not belong to user"})
 	}
 
-	if err := h.studyPlanService.DeleteStudyPlan(context.Background(), id); err != nil {
+	if err := h.studyPlanService.DeleteStudyPlan(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete study plan: " + err.Error()})
 	}
 	return c.Status(fiber.StatusNoContent).Send(nil)
@@ -251,8 +251,14 @@ func (h *StudyPlanHandler) CreateStudySession(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	session, err := h.studyPlanService.CreateStudySession(context.Background(), userID, &input)
+	session, err := h.studyPlanService.CreateStudySession(c.UserContext(), userID, &input)
 	if err != nil {
+		if strings.HasSuffix(err.Error(), "does not belong to user") {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
+		if strings.HasPrefix(err.Error(), "exam not found") || strings.HasPrefix(err.Error(), "assignment not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create study session: " + err.Error()})
 	}
 	return c.Status(fiber.StatusCreated).JSON(session)
@@ -274,7 +280,7 @@ func (h *StudyPlanHandler) GetStudySessions(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
 	}
 
-	sessions, err := h.studyPlanService.GetStudySessionsByUserID(context.Background(), userID)
+	sessions, err := h.studyPlanService.GetStudySessionsByUserID(c.UserContext(), userID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve study sessions: " + err.Error()})
 	}
@@ -300,7 +306,7 @@ func (h *StudyPlanHandler) GetStudySessionByID(c *fiber.Ctx) error {
 	}
 
 	id := c.Params("id")
-	session, err := h.studyPlanService.GetStudySessionByID(context.Background(), id)
+	session, err := h.studyPlanService.GetStudySessionByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Study session not found or not owned by user"})
 	}
@@ -328,7 +334,7 @@ func (h *StudyPlanHandler) GetStudySessionsByStudyPlanID(c *fiber.Ctx) error {
 	}
 
 	studyPlanID := c.Params("studyPlanId")
-	sessions, err := h.studyPlanService.GetStudySessionsByStudyPlanID(context.Background(), studyPlanID)
+	sessions, err := h.studyPlanService.GetStudySessionsByStudyPlanID(c.UserContext(), studyPlanID)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve study sessions: " + err.Error()})
 	}
@@ -367,12 +373,12 @@ func (h *StudyPlanHandler) UpdateStudySession(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	session, err := h.studyPlanService.UpdateStudySession(context.Background(), userID, id, &input)
+	session, err := h.studyPlanService.UpdateStudySession(c.UserContext(), userID, id, &input)
 	if err != nil {
-		if err.Error() == "study session does not belong to user" {
+		if strings.HasSuffix(err.Error(), "does not belong to user") {
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
 		}
-		if err.Error() == "study session not found" {
+		if err.Error() == "study session not found" || strings.HasPrefix(err.Error(), "exam not found") || strings.HasPrefix(err.Error(), "assignment not found") {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update study session: " + err.Error()})
@@ -401,7 +407,7 @@ func (h *StudyPlanHandler) DeleteStudySession(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	// Check ownership before deleting
-	session, err := h.studyPlanService.GetStudySessionByID(context.Background(), id)
+	session, err := h.studyPlanService.GetStudySessionByID(c.UserContext(), id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Study session not found"})
 	}
@@ -409,7 +415,7 @@ func (h *StudyPlanHandler) DeleteStudySession(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Study session does not belong to user"})
 	}
 
-	if err := h.studyPlanService.DeleteStudySession(context.Background(), id); err != nil {
+	if err := h.studyPlanService.DeleteStudySession(c.UserContext(), id); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to delete study session: " + err.Error()})
 	}
 	return c.Status(fiber.StatusNoContent).Send(nil)
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// StudyPlanTemplateHandler handles HTTP requests for study plan templates and plan cloning.
+type StudyPlanTemplateHandler struct {
+	studyPlanTemplateService services.StudyPlanTemplateService
+	validator                *validator.Validate
+}
+
+// NewStudyPlanTemplateHandler creates a new StudyPlanTemplateHandler.
+func NewStudyPlanTemplateHandler(studyPlanTemplateService services.StudyPlanTemplateService) *StudyPlanTemplateHandler {
+	return &StudyPlanTemplateHandler{
+		studyPlanTemplateService: studyPlanTemplateService,
+		validator:                validator.New(),
+	}
+}
+
+// CreateStudyPlanTemplate handles creating a new study plan template.
+// @Summary Create a study plan template
+// @Description Create a reusable study plan made of session blueprints with day offsets, start times, and fixed or slotted subjects and topics.
+// @Tags Study Plan Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param template body models.StudyPlanTemplateInput true "Template details"
+// @Success 201 {object} models.StudyPlanTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /study-plan-templates [post]
+func (h *StudyPlanTemplateHandler) CreateStudyPlanTemplate(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.StudyPlanTemplateInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed: " + err.Error()})
+	}
+
+	template, err := h.studyPlanTemplateService.CreateStudyPlanTemplate(c.UserContext(), userID, &input)
+	if err != nil {
+		return h.templateError(c, err, "Failed to create study plan template: ")
+	}
+	return c.Status(fiber.StatusCreated).JSON(template)
+}
+
+// GetStudyPlanTemplates handles retrieving all study plan templates of the user.
+// @Summary Get all study plan templates
+// @Description Get all study plan templates for the authenticated user.
+// @Tags Study Plan Templates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.StudyPlanTemplate
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /study-plan-templates [get]
+func (h *StudyPlanTemplateHandler) GetStudyPlanTemplates(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	templates, err := h.studyPlanTemplateService.GetStudyPlanTemplatesByUserID(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve study plan templates: " + err.Error()})
+	}
+	if templates == nil {
+		templates = []models.StudyPlanTemplate{}
+	}
+	return c.Status(fiber.StatusOK).JSON(templates)
+}
+
+// GetStudyPlanTemplateByID handles retrieving a single study plan template.
+// @Summary Get a study plan template by ID
+// @Description Get a single study plan template for the authenticated user.
+// @Tags Study Plan Templates
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Study Plan Template ID"
+// @Success 200 {object} models.StudyPlanTemplate
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /study-plan-templates/{id} [get]
+func (h *StudyPlanTemplateHandler) GetStudyPlanTemplateByID(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	template, err := h.studyPlanTemplateService.GetStudyPlanTemplateByID(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return h.templateError(c, err, "Failed to retrieve study plan template: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(template)
+}
+
+// UpdateStudyPlanTemplate handles replacing the contents of a study plan template.
+// @Summary Update a study plan template
+// @Description Replace the title, type, description, cycle length and session blueprints of a study plan template. Plans already created from it are not changed.
+// @Tags Study Plan Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Study Plan Template ID"
+// @Param template body models.StudyPlanTemplateInput true "Template details"
+// @Success 200 {object} models.StudyPlanTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /study-plan-templates/{id} [put]
+func (h *StudyPlanTemplateHandler) UpdateStudyPlanTemplate(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.StudyPlanTemplateInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed: " + err.Error()})
+	}
+
+	template, err := h.studyPlanTemplateService.UpdateStudyPlanTemplate(c.UserContext(), userID, c.Params("id"), &input)
+	if err != nil {
+		return h.templateError(c, err, "Failed to update study plan template: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(template)
+}
+
+// DeleteStudyPlanTemplate handles deleting a study plan template.
+// @Summary Delete a study plan template
+// @Description Delete a study plan template for the authenticated user. Plans created from it are kept.
+// @Tags Study Plan Templates
+// @Security BearerAuth
+// @Param id path string true "Study Plan Template ID"
+// @Success 204 "Study plan template deleted"
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /study-plan-templates/{id} [delete]
+func (h *StudyPlanTemplateHandler) DeleteStudyPlanTemplate(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	if err := h.studyPlanTemplateService.DeleteStudyPlanTemplate(c.UserContext(), userID, c.Params("id")); err != nil {
+		return h.templateError(c, err, "Failed to delete study plan template: ")
+	}
+	return c.Status(fiber.StatusNoContent).Send(nil)
+}
+
+// InstantiateStudyPlanTemplate handles creating a study plan from a template.
+// @Summary Create a study plan from a template
+// @Description Lay a template out from a start date, once or repeated every cycle until an end date. Subject slots take the given subjects, and the topics of each topic slot are dealt out in order across the sessions sharing the slot.
+// @Tags Study Plan Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Study Plan Template ID"
+// @Param instantiation body models.StudyPlanTemplateInstantiationInput true "Dates and slot values"
+// @Success 201 {object} models.StudyPlanWithSessions
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /study-plan-templates/{id}/instantiate [post]
+func (h *StudyPlanTemplateHandler) InstantiateStudyPlanTemplate(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.StudyPlanTemplateInstantiationInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed: " + err.Error()})
+	}
+
+	result, err := h.studyPlanTemplateService.InstantiateStudyPlanTemplate(c.UserContext(), userID, c.Params("id"), &input)
+	if err != nil {
+		return h.templateError(c, err, "Failed to create study plan from template: ")
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// CloneStudyPlan handles copying a study plan to another date.
+// @Summary Clone a study plan
+// @Description Copy a study plan and its sessions to a new first day. Session times keep their local time of day and the copies start over as planned, with no actual times, covered topics or completion.
+// @Tags Study Plans
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Study Plan ID"
+// @Param clone body models.StudyPlanCloneInput true "Target date"
+// @Success 201 {object} models.StudyPlanWithSessions
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /study-plans/{id}/clone [post]
+func (h *StudyPlanTemplateHandler) CloneStudyPlan(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.StudyPlanCloneInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed: " + err.Error()})
+	}
+
+	result, err := h.studyPlanTemplateService.CloneStudyPlan(c.UserContext(), userID, c.Params("id"), &input)
+	if err != nil {
+		return h.templateError(c, err, "Failed to clone study plan: ")
+	}
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
+
+// templateError maps study plan template service errors to HTTP responses.
+func (h *StudyPlanTemplateHandler) templateError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidStudyPlanTemplate), errors.Is(err, services.ErrUnfilledTemplateSlot), errors.Is(err, services.ErrInvalidPlanDate):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case strings.HasSuffix(err.Error(), "does not belong to user"):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case strings.HasPrefix(err.Error(), "study plan template not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Study plan template not found"})
+	case strings.HasPrefix(err.Error(), "study plan not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Study plan not found"})
+	case strings.HasPrefix(err.Error(), "subject not found"):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Subject not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": message + err.Error()})
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// TimerHandler handles HTTP requests related to time tracking timers.
+type TimerHandler struct {
+	timerService services.TimerService
+	validator    *validator.Validate
+}
+
+// NewTimerHandler creates a new TimerHandler.
+func NewTimerHandler(timerService services.TimerService) *TimerHandler {
+	return &TimerHandler{
+		timerService: timerService,
+		validator:    validator.New(),
+	}
+}
+
+// StartTimer handles starting a new timer.
+// @Summary Start a timer
+// @Description Start a timer against an assignment, exam or study session. Only one timer may run at a time, and not during a pomodoro.
+// @Tags Timers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param timer body models.TimerStartInput true "Timer details"
+// @Success 201 {object} models.TimeEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "A timer or pomodoro is already running"
+// @Failure 500 {object} map[string]string
+// @Router /timers/start [post]
+func (h *TimerHandler) StartTimer(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.TimerStartInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	entry, err := h.timerService.StartTimer(c.UserContext(), userID, &input)
+	if err != nil {
+		if errors.Is(err, services.ErrTimerAlreadyRunning) || errors.Is(err, services.ErrPomodoroActive) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		if strings.HasSuffix(err.Error(), "does not belong to user") {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
+		if strings.Contains(err.Error(), "not found") {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to start timer: " + err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+// PauseTimer handles pausing the running timer.
+// @Summary Pause the running timer
+// @Description Pause the running timer for the authenticated user. Tracked time is rolled up immediately.
+// @Tags Timers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TimeEntry
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string "No active timer"
+// @Failure 500 {object} map[string]string
+// @Router /timers/pause [post]
+func (h *TimerHandler) PauseTimer(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	entry, err := h.timerService.PauseTimer(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrNoActiveTimer) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to pause timer: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(entry)
+}
+
+// ResumeTimer handles resuming the paused timer.
+// @Summary Resume the paused timer
+// @Description Resume the most recently paused timer for the authenticated user.
+// @Tags Timers
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} models.TimeEntry
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string "No active timer"
+// @Failure 409 {object} map[string]string "A timer or pomodoro is already running"
+// @Failure 500 {object} map[string]string
+// @Router /timers/resume [post]
+func (h *TimerHandler) ResumeTimer(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	entry, err := h.timerService.ResumeTimer(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrNoActiveTimer) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		if errors.Is(err, services.ErrTimerAlreadyRunning) || errors.Is(err, services.ErrPomodoroActive) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resume timer: " + err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(entry)
+}
+
+// StopTimer handles stopping the running or paused timer.
+// @Summary Stop the timer
+// @Description Stop the running or paused timer for the authenticated user. A timer left running overnight or for over 12 hours is auto-stopped where it was abandoned and returned with autoStopped set.
+// @Tags Timers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TimeEntry
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string "No active timer"
+// @Failure 500 {object} map[string]string
+// @Router /timers/stop [post]
+func (h *TimerHandler) StopTimer(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	entry, err := h.timerService.StopTimer(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrNoActiveTimer) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to stop timer: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(entry)
+}
+
+// GetCurrentTimer handles retrieving the running or paused timer.
+// @Summary Get the current timer
+// @Description Retrieve the running or paused timer for the authenticated user.
+// @Tags Timers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TimeEntry
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string "No active timer"
+// @Failure 500 {object} map[string]string
+// @Router /timers/current [get]
+func (h *TimerHandler) GetCurrentTimer(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	entry, err := h.timerService.GetCurrentTimer(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrNoActiveTimer) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve timer: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(entry)
+}
+
+// GetTimeEntries handles retrieving time entries for the authenticated user.
+// @Summary Get time entries
+// @Description Retrieve time entries for the authenticated user, optionally for a single entity.
+// @Tags Timers
+// @Produce json
+// @Security BearerAuth
+// @Param entityType query string false "Entity type (assignment, exam, study_session)"
+// @Param entityId query string false "Entity ID"
+// @Success 200 {array} models.TimeEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /timers/entries [get]
+func (h *TimerHandler) GetTimeEntries(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	entityType := c.Query("entityType")
+	entityID := c.Query("entityId")
+
+	var entries []models.TimeEntry
+	var err error
+	if entityType != "" || entityID != "" {
+		if entityType == "" || entityID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "entityType and entityId must be provided together"})
+		}
+		entries, err = h.timerService.GetTimeEntriesByEntity(c.UserContext(), userID, entityType, entityID)
+	} else {
+		entries, err = h.timerService.GetTimeEntriesByUserID(c.UserContext(), userID)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve time entries: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
@@ -1,8 +1,9 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -45,7 +46,7 @@ func (h *TimetableHandler) CreateSubject(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	if err := h.timetableService.CreateSubject(context.Background(), &subject); err != nil {
+	if err := h.timetableService.CreateSubject(c.UserContext(), &subject); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create subject"})
 	}
 	return c.Status(fiber.StatusCreated).JSON(subject)
@@ -60,7 +61,7 @@ func (h *TimetableHandler) CreateSubject(c *fiber.Ctx) error {
 // @Failure 500 {object} map[string]string
 // @Router /timetable/subjects [get]
 func (h *TimetableHandler) GetAllSubjects(c *fiber.Ctx) error {
-	subjects, err := h.timetableService.GetAllSubjects(context.Background())
+	subjects, err := h.timetableService.GetAllSubjects(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve subjects"})
 	}
@@ -88,7 +89,7 @@ func (h *TimetableHandler) CreateStaff(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	if err := h.timetableService.CreateStaff(context.Background(), &staff); err != nil {
+	if err := h.timetableService.CreateStaff(c.UserContext(), &staff); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create staff"})
 	}
 	return c.Status(fiber.StatusCreated).JSON(staff)
@@ -103,7 +104,7 @@ func (h *TimetableHandler) CreateStaff(c *fiber.Ctx) error {
 // @Failure 500 {object} map[string]string
 // @Router /timetable/staff [get]
 func (h *TimetableHandler) GetAllStaff(c *fiber.Ctx) error {
-	staffMembers, err := h.timetableService.GetAllStaff(context.Background())
+	staffMembers, err := h.timetableService.GetAllStaff(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve staff"})
 	}
@@ -131,7 +132,7 @@ func (h *TimetableHandler) CreateVenue(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	if err := h.timetableService.CreateVenue(context.Background(), &venue); err != nil {
+	if err := h.timetableService.CreateVenue(c.UserContext(), &venue); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create venue"})
 	}
 	return c.Status(fiber.StatusCreated).JSON(venue)
@@ -146,7 +147,7 @@ func (h *TimetableHandler) CreateVenue(c *fiber.Ctx) error {
 // @Failure 500 {object} map[string]string
 // @Router /timetable/venues [get]
 func (h *TimetableHandler) GetAllVenues(c *fiber.Ctx) error {
-	venues, err := h.timetableService.GetAllVenues(context.Background())
+	venues, err := h.timetableService.GetAllVenues(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve venues"})
 	}
@@ -182,7 +183,7 @@ func (h *TimetableHandler) CreateTimetableSlot(c *fiber.Ctx) error {
 	}
 
 	slot.UserID = userID // Assign the authenticated user's ID
-	if err := h.timetableService.CreateTimetableSlot(context.Background(), &slot); err != nil {
+	if err := h.timetableService.CreateTimetableSlot(c.UserContext(), &slot); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to create timetable slot"})
 	}
 	return c.Status(fiber.StatusCreated).JSON(slot)
@@ -212,7 +213,7 @@ func (h *TimetableHandler) GetUserTimetableByDay(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid day of week. Must be 0-6."})
 	}
 
-	slots, err := h.timetableService.GetUserTimetableByDay(context.Background(), userID, int32(dayOfWeek))
+	slots, err := h.timetableService.GetUserTimetableByDay(c.UserContext(), userID, int32(dayOfWeek))
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve timetable slots"})
 	}
@@ -253,7 +254,7 @@ func (h *TimetableHandler) GetUserTimetableByDateRange(c *fiber.Ctx) error {
 	// Adjust end date to include the whole day
 	end = end.Add(24*time.Hour - time.Nanosecond)
 
-	slots, err := h.timetableService.GetUserTimetableByDateRange(context.Background(), userID, start, end)
+	slots, err := h.timetableService.GetUserTimetableByDateRange(c.UserContext(), userID, start, end)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve timetable slots"})
 	}
@@ -294,7 +295,7 @@ func (h *TimetableHandler) ExportICSCalendar(c *fiber.Ctx) error {
 	// Adjust end date to include the whole day
 	end = end.Add(24*time.Hour - time.Nanosecond)
 
-	icsContent, err := h.timetableService.GenerateICSCalendar(context.Background(), userID, start, end)
+	icsContent, err := h.timetableService.GenerateICSCalendar(c.UserContext(), userID, start, end)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to generate ICS calendar: " + err.Error()})
 	}
@@ -303,3 +304,85 @@ func (h *TimetableHandler) ExportICSCalendar(c *fiber.Ctx) error {
 	c.Set("Content-Disposition", `attachment; filename="timetable.ics"`)
 	return c.SendString(icsContent)
 }
+
+// RecordClassAttendance handles recording whether the user attended a class.
+// @Summary Record class attendance
+// @Description Record whether the authenticated user attended the class of one of their lecture, lab or tutorial slots on a date. Recording the same class again replaces the earlier record. Daily stats, attendance goals and XP are derived from these records.
+// @Tags Timetable
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param attendance body models.ClassAttendanceInput true "Slot, date and whether the class was attended"
+// @Success 200 {object} models.ClassAttendance
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /timetable/attendance [put]
+func (h *TimetableHandler) RecordClassAttendance(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.ClassAttendanceInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+	}
+	if err := h.validator.Struct(input); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Validation failed: " + err.Error()})
+	}
+
+	attendance, err := h.timetableService.RecordClassAttendance(c.UserContext(), userID, &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidClassAttendance):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		case strings.HasSuffix(err.Error(), "does not belong to user"):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		case strings.HasPrefix(err.Error(), "timetable slot not found"):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Timetable slot not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to record class attendance: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(attendance)
+}
+
+// GetClassAttendance handles retrieving the user's class attendance for a date range.
+// @Summary Get class attendance by date range
+// @Description Retrieve the authenticated user's attendance records, with the subject of each class, between two dates (inclusive).
+// @Tags Timetable
+// @Produce json
+// @Security BearerAuth
+// @Param start query string true "Start date (YYYY-MM-DD)"
+// @Param end query string true "End date (YYYY-MM-DD)"
+// @Success 200 {array} models.ClassAttendance
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /timetable/attendance [get]
+func (h *TimetableHandler) GetClassAttendance(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	start, err := time.Parse("2006-01-02", c.Query("start"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid start date format. Use YYYY-MM-DD."})
+	}
+	end, err := time.Parse("2006-01-02", c.Query("end"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid end date format. Use YYYY-MM-DD."})
+	}
+
+	records, err := h.timetableService.GetClassAttendance(c.UserContext(), userID, start, end)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve class attendance"})
+	}
+	if records == nil {
+		records = []models.ClassAttendance{}
+	}
+	return c.Status(fiber.StatusOK).JSON(records)
+}
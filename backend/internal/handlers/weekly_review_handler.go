@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/mailer"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/services"
+)
+
+// WeeklyReviewHandler handles HTTP requests for weekly review reports.
+type WeeklyReviewHandler struct {
+	weeklyReviewService services.WeeklyReviewService
+}
+
+// NewWeeklyReviewHandler creates a new WeeklyReviewHandler.
+func NewWeeklyReviewHandler(weeklyReviewService services.WeeklyReviewService) *WeeklyReviewHandler {
+	return &WeeklyReviewHandler{weeklyReviewService: weeklyReviewService}
+}
+
+// GenerateWeeklyReview handles generating the review of a week on demand.
+// @Summary Generate a weekly review
+// @Description Build, or rebuild, the review of a week: study sessions planned versus completed, hours per subject compared with the week before, assignments completed and missed, exam prep status changes, attendance change and suggested focus areas for the next week. Any date of the week can be given; without one the current week is reviewed so far. Exam prep statuses are only known when they are taken, so a past week keeps the prep snapshot taken during that week.
+// @Tags Weekly Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.WeeklyReviewGenerateInput false "Week to review"
+// @Success 200 {object} models.WeeklyReview
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /weekly-reviews/generate [post]
+func (h *WeeklyReviewHandler) GenerateWeeklyReview(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var input models.WeeklyReviewGenerateInput
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&input); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body: " + err.Error()})
+		}
+	}
+
+	review, err := h.weeklyReviewService.GenerateWeeklyReview(c.UserContext(), userID, &input)
+	if err != nil {
+		return weeklyReviewErrorResponse(c, err, "Failed to generate weekly review: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(review)
+}
+
+// GetWeeklyReviews handles retrieving all weekly reviews of the user.
+// @Summary Get all weekly reviews
+// @Description Get all weekly reviews for the authenticated user, newest week first.
+// @Tags Weekly Reviews
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.WeeklyReview
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /weekly-reviews [get]
+func (h *WeeklyReviewHandler) GetWeeklyReviews(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	reviews, err := h.weeklyReviewService.GetWeeklyReviews(c.UserContext(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve weekly reviews: " + err.Error()})
+	}
+	return c.Status(fiber.StatusOK).JSON(reviews)
+}
+
+// GetWeeklyReview handles retrieving a single weekly review.
+// @Summary Get a weekly review by ID
+// @Description Get a single weekly review for the authenticated user.
+// @Tags Weekly Reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Weekly Review ID"
+// @Success 200 {object} models.WeeklyReview
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /weekly-reviews/{id} [get]
+func (h *WeeklyReviewHandler) GetWeeklyReview(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	review, err := h.weeklyReviewService.GetWeeklyReview(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return weeklyReviewErrorResponse(c, err, "Failed to retrieve weekly review: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(review)
+}
+
+// GetWeeklyReviewHTML handles rendering a weekly review as an HTML page.
+// @Summary Get a weekly review as HTML
+// @Description Render a weekly review as the standalone HTML page that is also emailed.
+// @Tags Weekly Reviews
+// @Produce html
+// @Security BearerAuth
+// @Param id path string true "Weekly Review ID"
+// @Success 200 {string} string "HTML page"
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /weekly-reviews/{id}/html [get]
+func (h *WeeklyReviewHandler) GetWeeklyReviewHTML(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	page, err := h.weeklyReviewService.RenderWeeklyReviewHTML(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return weeklyReviewErrorResponse(c, err, "Failed to render weekly review: ")
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(fiber.StatusOK).SendString(page)
+}
+
+// EmailWeeklyReview handles emailing a weekly review to the user.
+// @Summary Email a weekly review
+// @Description Email a weekly review to the authenticated user's address and record when it was sent.
+// @Tags Weekly Reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Weekly Review ID"
+// @Success 200 {object} models.WeeklyReview
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /weekly-reviews/{id}/email [post]
+func (h *WeeklyReviewHandler) EmailWeeklyReview(c *fiber.Ctx) error {
+	userID := c.Locals("userID").(string)
+	if userID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	review, err := h.weeklyReviewService.EmailWeeklyReview(c.UserContext(), userID, c.Params("id"))
+	if err != nil {
+		return weeklyReviewErrorResponse(c, err, "Failed to email weekly review: ")
+	}
+	return c.Status(fiber.StatusOK).JSON(review)
+}
+
+// weeklyReviewErrorResponse maps weekly review service errors to HTTP responses.
+func weeklyReviewErrorResponse(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, services.ErrWeeklyReviewNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Weekly review not found"})
+	case strings.HasSuffix(err.Error(), "does not belong to user"):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidDateRange), strings.HasPrefix(err.Error(), "invalid week start format"):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, mailer.ErrNotConfigured):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": message + err.Error()})
+}
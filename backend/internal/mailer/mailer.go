@@ -0,0 +1,167 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// ErrNotConfigured is returned when sending an email without an SMTP server configured.
+var ErrNotConfigured = errors.New("email delivery is not configured")
+
+// sendTimeout bounds how long connecting to the SMTP server and sending a message may take, so a
+// stalled server cannot hold up the caller.
+const sendTimeout = 30 * time.Second
+
+// Mailer sends emails.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, textBody, htmlBody string) error
+	Enabled() bool
+}
+
+// SMTPMailer implements Mailer over SMTP, with STARTTLS when the server offers it.
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPMailer creates a new SMTP mailer. from may include a display name, as in "Campus Pilot <no-reply@example.com>".
+// With an empty host the mailer is disabled and Send returns ErrNotConfigured; without a username no
+// authentication is attempted.
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	if port == "" {
+		port = "587"
+	}
+	return &SMTPMailer{host: host, port: port, username: username, password: password, from: from}
+}
+
+// Enabled reports whether an SMTP server is configured.
+func (m *SMTPMailer) Enabled() bool {
+	return m.host != "" && m.from != ""
+}
+
+// Send emails a message with both a plain-text and an HTML part.
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, textBody, htmlBody string) error {
+	if !m.Enabled() {
+		return ErrNotConfigured
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	message, err := buildMessage(from.String(), to, subject, textBody, htmlBody)
+	if err != nil {
+		return err
+	}
+
+	if err := m.deliver(ctx, from.Address, to, message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// deliver sends message over a new SMTP connection. The connection is closed once sendTimeout passes or
+// ctx is done, whichever comes first, which fails the SMTP command in progress.
+func (m *SMTPMailer) deliver(ctx context.Context, from, to string, message []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	dialer := net.Dialer{Timeout: sendTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.host, m.port))
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage renders a multipart/alternative email.
+func buildMessage(from, to, subject, textBody, htmlBody string) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	parts := []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", textBody},
+		{"text/html; charset=utf-8", htmlBody},
+	}
+	for _, part := range parts {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"8bit"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build email: %w", err)
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", writer.Boundary())
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
@@ -41,7 +41,21 @@ func Protected(jwtSecret string) fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid user ID in token"})
 		}
 
+		// Tokens issued before roles existed carry none and are treated as students
+		role, _ := claims["role"].(string)
+
 		c.Locals("userID", userID)
+		c.Locals("role", role)
+		return c.Next()
+	}
+}
+
+// AdminOnly restricts routes to admins. It must run after Protected.
+func AdminOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if role, _ := c.Locals("role").(string); role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Admin access required"})
+		}
 		return c.Next()
 	}
 }
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+)
+
+// RequestInfo stores the client's IP address and user agent on the request's user context, where the
+// event bus picks them up for the domain events published while handling the request. The values are
+// copied because events are delivered after the handler returns, when fasthttp may reuse its buffers.
+func RequestInfo() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(events.WithRequestInfo(c.UserContext(), events.RequestInfo{
+			IPAddress: strings.Clone(c.IP()),
+			UserAgent: strings.Clone(c.Get(fiber.HeaderUserAgent)),
+		}))
+		return c.Next()
+	}
+}
@@ -2,28 +2,27 @@ package models
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
-
-	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // ActivityLog represents a user activity entry.
 type ActivityLog struct {
-	ID           string         `json:"id"`
-	UserID       string         `json:"userId"`
+	ID           string          `json:"id"`
+	UserID       string          `json:"userId"`
 	
-	ActivityType string         `json:"activityType"` // 'assignment_created', 'exam_updated', etc.
-	Description  sql.NullString `json:"description"`
+	ActivityType string          `json:"activityType"` // 'assignment_created', 'exam_updated', etc.
+	Description  sql.NullString  `json:"description"`
 	
-	EntityType   sql.NullString `json:"entityType"` // 'assignment', 'exam', 'study_session', etc.
-	EntityID     sql.NullString `json:"entityId"`
+	EntityType   sql.NullString  `json:"entityType"` // 'assignment', 'exam', 'study_session', etc.
+	EntityID     sql.NullString  `json:"entityId"`
 	
-	Metadata     pgtype.JSONB   `json:"metadata"` // Additional JSON data
+	Metadata     json.RawMessage `json:"metadata"` // Additional JSON data; for updates, the changed fields
 	
-	IPAddress    sql.NullString `json:"ipAddress"` // INET type might need specific pgtype handling, using string for simplicity
-	UserAgent    sql.NullString `json:"userAgent"`
+	IPAddress    sql.NullString  `json:"ipAddress"` // INET type might need specific pgtype handling, using string for simplicity
+	UserAgent    sql.NullString  `json:"userAgent"`
 	
-	CreatedAt    time.Time      `json:"createdAt"`
+	CreatedAt    time.Time       `json:"createdAt"`
 }
 
 // DailyStats represents pre-aggregated daily statistics for a user.
@@ -58,13 +57,114 @@ type ActivityLogCreationInput struct {
 
 // DailyStatsCreationInput defines the expected input for creating/updating daily stats.
 type DailyStatsUpdateInput struct {
-	StatDate             string `json:"statDate" validate:"required"` // YYYY-MM-DD
-	StudyMinutes         *int32 `json:"studyMinutes"`
-	SessionsCompleted    *int32 `json:"sessionsCompleted"`
-	TopicsCovered        *int32 `json:"topicsCovered"`
-	AssignmentsCompleted *int32 `json:"assignmentsCompleted"`
-	AssignmentsAdded     *int32 `json:"assignmentsAdded"`
-	ClassesAttended      *int32 `json:"classesAttended"`
-	TotalClasses         *int32 `json:"totalClasses"`
-	XPEarned             *int32 `json:"xpEarned"`
+	UserID               *string `json:"userId"` // Defaults to the caller
+	StatDate             string  `json:"statDate" validate:"required"` // YYYY-MM-DD
+	StudyMinutes         *int32  `json:"studyMinutes"`
+	SessionsCompleted    *int32  `json:"sessionsCompleted"`
+	TopicsCovered        *int32  `json:"topicsCovered"`
+	AssignmentsCompleted *int32  `json:"assignmentsCompleted"`
+	AssignmentsAdded     *int32  `json:"assignmentsAdded"`
+	ClassesAttended      *int32  `json:"classesAttended"`
+	TotalClasses         *int32  `json:"totalClasses"`
+	XPEarned             *int32  `json:"xpEarned"`
+}
+
+// ActivityLogFilter narrows down a page of activity logs.
+type ActivityLogFilter struct {
+	ActivityTypes []string   // Any of these types; empty for all
+	EntityType    *string
+	EntityID      *string
+	From          *time.Time // Inclusive
+	To            *time.Time // Exclusive
+	Limit         int
+	Offset        int
+}
+
+// ActivityLogPage is one page of a user's activity logs, newest first.
+type ActivityLogPage struct {
+	Logs    []ActivityLog `json:"logs"`
+	Total   int           `json:"total"` // Logs matching the filter across all pages
+	Limit   int           `json:"limit"`
+	Offset  int           `json:"offset"`
+	HasMore bool          `json:"hasMore"`
+}
+
+// DailyStatsTotals sums a user's daily stats over a week or month.
+type DailyStatsTotals struct {
+	Period               string          `json:"period"`    // 'week', 'month'
+	StartDate            time.Time       `json:"startDate"` // DATE; Monday of the week or first of the month
+	EndDate              time.Time       `json:"endDate"`   // DATE, inclusive
+
+	StudyMinutes         int32           `json:"studyMinutes"`
+	SessionsCompleted    int32           `json:"sessionsCompleted"`
+	TopicsCovered        int32           `json:"topicsCovered"`
+
+	AssignmentsCompleted int32           `json:"assignmentsCompleted"`
+	AssignmentsAdded     int32           `json:"assignmentsAdded"`
+
+	ClassesAttended      int32           `json:"classesAttended"`
+	TotalClasses         int32           `json:"totalClasses"`
+	AttendanceRate       sql.NullFloat64 `json:"attendanceRate"` // Percentage; NULL without classes
+
+	XPEarned             int32           `json:"xpEarned"`
+
+	ActiveDays           int32           `json:"activeDays"`          // Days with study time or completed sessions
+	AverageStudyMinutes  float64         `json:"averageStudyMinutes"` // Per elapsed day of the period
+}
+
+// StudyStreak describes a user's run of consecutive active study days.
+type StudyStreak struct {
+	CurrentDays    int32        `json:"currentDays"` // Still alive when the last active day was yesterday
+	ActiveToday    bool         `json:"activeToday"`
+	LastActiveDate sql.NullTime `json:"lastActiveDate"`
+
+	LongestDays    int32        `json:"longestDays"`
+	LongestStart   sql.NullTime `json:"longestStart"`
+	LongestEnd     sql.NullTime `json:"longestEnd"`
+}
+
+// DailyStatsBackfillInput defines the expected input for recomputing daily stats over a date range.
+type DailyStatsBackfillInput struct {
+	UserID   *string `json:"userId"` // All active users when omitted
+	FromDate string  `json:"fromDate" validate:"required"` // YYYY-MM-DD
+	ToDate   string  `json:"toDate" validate:"required"`   // YYYY-MM-DD, inclusive
+}
+
+// DailyStatsBackfillResult reports what a backfill recomputed.
+type DailyStatsBackfillResult struct {
+	FromDate time.Time `json:"fromDate"`
+	ToDate   time.Time `json:"toDate"`
+	Users    int       `json:"users"`
+	Days     int       `json:"days"`   // User-days recomputed
+	Failed   int       `json:"failed"` // User-days that could not be recomputed
+}
+
+// TimeSeries is a user's activity bucketed by day, week or month in their timezone, oldest first. Every
+// bucket of the range is present, with zeros where nothing happened.
+type TimeSeries struct {
+	Bucket   string            `json:"bucket"` // 'day', 'week', 'month'
+	Timezone string            `json:"timezone"`
+	FromDate time.Time         `json:"fromDate"` // DATE; first day of the first bucket
+	ToDate   time.Time         `json:"toDate"`   // DATE; last day of the last bucket
+	Points   []TimeSeriesPoint `json:"points"`
+}
+
+// TimeSeriesPoint holds the metrics of one bucket of a time series.
+type TimeSeriesPoint struct {
+	Date                 time.Time       `json:"date"`  // DATE; first day of the bucket
+	Start                time.Time       `json:"start"` // Midnight starting the bucket, in the user's timezone
+	End                  time.Time       `json:"end"`   // Midnight ending the bucket (exclusive)
+
+	StudyMinutes         int32           `json:"studyMinutes"`
+	XPEarned             int32           `json:"xpEarned"`
+
+	CompletedTasks       int32           `json:"completedTasks"` // Study sessions and assignments completed
+	SessionsCompleted    int32           `json:"sessionsCompleted"`
+	AssignmentsCompleted int32           `json:"assignmentsCompleted"`
+
+	ClassesAttended      int32           `json:"classesAttended"`
+	TotalClasses         int32           `json:"totalClasses"`
+	AttendanceRate       sql.NullFloat64 `json:"attendanceRate"` // Percentage; NULL without classes
+
+	Level                int32           `json:"level"` // Heatmap intensity from 0 (no study time) to 4, relative to the busiest bucket
 }
@@ -92,4 +92,25 @@ type AssignmentCreationInput struct {
 	Tags               []string `json:"tags"`
 	IsRecurring        *bool    `json:"isRecurring"`
 	RecurrencePattern  *string  `json:"recurrencePattern"`
-}
\ No newline at end of file
+}
+
+// ScoredAssignment is an assignment annotated with its computed urgency score.
+type ScoredAssignment struct {
+	Assignment
+
+	UrgencyScore   float64                  `json:"urgencyScore"` // 0-100, higher means work on it sooner
+	ScoreBreakdown AssignmentScoreBreakdown `json:"scoreBreakdown"`
+	Explanation    []string                 `json:"explanation"`
+}
+
+// AssignmentScoreBreakdown lists the weighted components that make up an urgency score.
+type AssignmentScoreBreakdown struct {
+	DueProximity float64 `json:"dueProximity"`
+	Workload     float64 `json:"workload"`
+	MarksWeight  float64 `json:"marksWeight"`
+	Credits      float64 `json:"credits"`
+
+	HoursUntilDue  float64 `json:"hoursUntilDue"`
+	RemainingHours float64 `json:"remainingHours"`
+	FreeHours      float64 `json:"freeHours"`
+}
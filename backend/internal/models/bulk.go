@@ -0,0 +1,39 @@
+package models
+
+// BulkOperationInput defines the expected input for a bulk operation on assignments, exams or lab records.
+// Exactly one of IDs or Filter selects the items to operate on.
+type BulkOperationInput struct {
+	IDs    []string    `json:"ids"`
+	Filter *BulkFilter `json:"filter"`
+
+	Action string `json:"action" validate:"required,oneof=set_status add_tags remove_tags set_subject delete"`
+
+	Status    *string  `json:"status"`    // Required for 'set_status' (prep status for exams)
+	Tags      []string `json:"tags"`      // Required for 'add_tags' and 'remove_tags'
+	SubjectID *string  `json:"subjectId"` // Used by 'set_subject'; null clears the subject
+}
+
+// BulkFilter selects items by their attributes instead of by ID. All set fields must match.
+type BulkFilter struct {
+	Status    *string `json:"status"`
+	SubjectID *string `json:"subjectId"`
+	Tag       *string `json:"tag"`
+	DateFrom  *string `json:"dateFrom"` // Date string (YYYY-MM-DD), compared with due/exam/lab date
+	DateTo    *string `json:"dateTo"`   // Date string (YYYY-MM-DD), inclusive
+}
+
+// BulkItemResult reports the outcome of a bulk operation for a single item.
+type BulkItemResult struct {
+	ID      string `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkOperationResult summarises a bulk operation.
+type BulkOperationResult struct {
+	Action    string           `json:"action"`
+	Matched   int              `json:"matched"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
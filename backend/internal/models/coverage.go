@@ -0,0 +1,30 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ExamCoverage describes how much of an exam's syllabus has been studied, revised and practised.
+type ExamCoverage struct {
+	ExamID              string         `json:"examId"`
+	ExamTitle           string         `json:"examTitle"`
+	ExamDate            time.Time      `json:"examDate"`
+	SubjectID           sql.NullString `json:"subjectId"`
+
+	TotalTopics         int            `json:"totalTopics"`
+	CoveredTopics       []string       `json:"coveredTopics"`   // Studied once
+	RevisedTopics       []string       `json:"revisedTopics"`   // Studied again or in a revision/practice session
+	UntouchedTopics     []string       `json:"untouchedTopics"`
+	CoveragePercentage  float64        `json:"coveragePercentage"` // Covered or revised
+	RevisionPercentage  float64        `json:"revisionPercentage"`
+
+	TotalQuestions      int            `json:"totalQuestions"`
+	PracticedQuestions  int            `json:"practicedQuestions"`
+	PracticePercentage  float64        `json:"practicePercentage"`
+
+	PrepStatus          string         `json:"prepStatus"`
+	SuggestedPrepStatus string         `json:"suggestedPrepStatus"`
+	PrepStatusOverride  bool           `json:"prepStatusOverride"`
+	PrepStatusAdvanced  bool           `json:"prepStatusAdvanced"` // True when this computation moved the status forward
+}
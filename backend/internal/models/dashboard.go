@@ -0,0 +1,59 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Dashboard is everything the dashboard shows, gathered in one response.
+type Dashboard struct {
+	Date               time.Time           `json:"date"` // DATE; today in the user's timezone
+	TodayTimetable     []TimetableSlot     `json:"todayTimetable"`
+	NextExam           *ExamCountdown      `json:"nextExam"` // null without upcoming exams
+
+	Assignments        AssignmentCounts    `json:"assignments"`
+	LabRecordsByStatus map[string]int      `json:"labRecordsByStatus"` // e.g. 'pending': 2, 'submitted': 5
+
+	WeeklyStudy        WeeklyStudyProgress `json:"weeklyStudy"`
+	RecentDocuments    []Document          `json:"recentDocuments"`
+	Streak             DashboardStreak     `json:"streak"`
+
+	GeneratedAt        time.Time           `json:"generatedAt"` // Responses are cached briefly, so this may lag behind now
+}
+
+// ExamCountdown describes the next upcoming exam and how long is left until it.
+type ExamCountdown struct {
+	ExamID     string         `json:"examId"`
+	SubjectID  sql.NullString `json:"subjectId"`
+	Title      string         `json:"title"`
+	ExamType   string         `json:"examType"`
+	ExamDate   time.Time      `json:"examDate"`
+	StartsAt   sql.NullTime   `json:"startsAt"` // NULL when the exam has no start time
+	DaysLeft   int32          `json:"daysLeft"` // Calendar days until the exam date; 0 on the day
+	PrepStatus string         `json:"prepStatus"`
+}
+
+// AssignmentCounts counts a user's unfinished assignments.
+type AssignmentCounts struct {
+	Pending int `json:"pending"` // Not yet due
+	Overdue int `json:"overdue"`
+}
+
+// WeeklyStudyProgress compares this week's study time with the weekly goal.
+type WeeklyStudyProgress struct {
+	WeekStart    time.Time `json:"weekStart"` // DATE; Monday of the current week
+	StudyMinutes int32     `json:"studyMinutes"`
+	GoalMinutes  int32     `json:"goalMinutes"`
+	GoalSet      bool      `json:"goalSet"`    // False when measured against the default of 10 hours
+	Percentage   float64   `json:"percentage"` // May exceed 100
+}
+
+// DashboardStreak is the streak part of a user's gamification progress.
+type DashboardStreak struct {
+	CurrentStreak int32 `json:"currentStreak"`
+	LongestStreak int32 `json:"longestStreak"`
+	StudiedToday  bool  `json:"studiedToday"`
+	FreezeTokens  int32 `json:"freezeTokens"`
+	Level         int32 `json:"level"`
+	TotalXP       int32 `json:"totalXp"`
+}
@@ -31,11 +31,14 @@ type Exam struct {
 	Grade             sql.NullString       `json:"grade"`
 	
 	PrepStatus        string         `json:"prepStatus"` // 'not_started', 'in_progress', 'revision', 'ready'
+	PrepStatusOverride bool          `json:"prepStatusOverride"` // Set manually; not advanced by coverage tracking
 	PrepNotes         sql.NullString `json:"prepNotes"`
 	StudyHoursLogged  sql.NullFloat64 `json:"studyHoursLogged"`
 	
 	ReminderEnabled   bool           `json:"reminderEnabled"`
 	
+	Tags              pgtype.FlatTextArray `json:"tags"` // TEXT[]
+	
 	CreatedAt         time.Time      `json:"createdAt"`
 	UpdatedAt         time.Time      `json:"updatedAt"`
 }
@@ -60,6 +63,12 @@ type ImportantQuestion struct {
 	LastPracticedAt   sql.NullTime   `json:"lastPracticedAt"`
 	ConfidenceLevel   sql.NullInt32  `json:"confidenceLevel"`
 	
+	// Spaced repetition (SM-2) schedule
+	EaseFactor        float64        `json:"easeFactor"`
+	RepetitionCount   int32          `json:"repetitionCount"`
+	IntervalDays      int32          `json:"intervalDays"`
+	NextReviewAt      sql.NullTime   `json:"nextReviewAt"`
+	
 	Tags              pgtype.FlatTextArray `json:"tags"` // TEXT[]
 	CreatedAt         time.Time      `json:"createdAt"`
 }
@@ -90,6 +99,8 @@ type ExamCreationInput struct {
 	StudyHoursLogged  *float64  `json:"studyHoursLogged"`
 	
 	ReminderEnabled   *bool     `json:"reminderEnabled"` // defaults to true
+	
+	Tags              []string  `json:"tags"`
 }
 
 // ImportantQuestionCreationInput defines input for creating an important question.
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// ExamClash describes two exams whose scheduled times overlap.
+type ExamClash struct {
+	Date           string   `json:"date"` // YYYY-MM-DD
+	ExamIDs        []string `json:"examIds"`
+	ExamTitles     []string `json:"examTitles"`
+	OverlapMinutes int      `json:"overlapMinutes"`
+}
+
+// OverloadedDay describes a day with more exams than the configured limit.
+type OverloadedDay struct {
+	Date       string   `json:"date"` // YYYY-MM-DD
+	ExamCount  int      `json:"examCount"`
+	ExamIDs    []string `json:"examIds"`
+	ExamTitles []string `json:"examTitles"`
+}
+
+// CrunchWeek describes a week where the remaining assignment and exam prep work exceeds the free time.
+type CrunchWeek struct {
+	WeekStart     string   `json:"weekStart"` // YYYY-MM-DD (Monday)
+	WeekEnd       string   `json:"weekEnd"`   // YYYY-MM-DD (Sunday)
+	RequiredHours float64  `json:"requiredHours"`
+	FreeHours     float64  `json:"freeHours"`
+	DeficitHours  float64  `json:"deficitHours"`
+	AssignmentIDs []string `json:"assignmentIds"`
+	ExamIDs       []string `json:"examIds"`
+}
+
+// ExamConflictReport is the result of analysing a user's exam schedule for clashes and crunches.
+type ExamConflictReport struct {
+	MaxExamsPerDay int             `json:"maxExamsPerDay"`
+	Weeks          int             `json:"weeks"`
+
+	Clashes        []ExamClash     `json:"clashes"`
+	OverloadedDays []OverloadedDay `json:"overloadedDays"`
+	CrunchWeeks    []CrunchWeek    `json:"crunchWeeks"`
+
+	GeneratedAt    time.Time       `json:"generatedAt"`
+}
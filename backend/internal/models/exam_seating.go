@@ -0,0 +1,68 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ExamSeating holds the hall ticket details of a user's exam: where to sit and when to report.
+type ExamSeating struct {
+	ID             string         `json:"id"`
+	UserID         string         `json:"userId"`
+	ExamID         string         `json:"examId"`
+	VenueID        sql.NullString `json:"venueId"`
+
+	VenueName      sql.NullString `json:"venueName"` // As printed on the seating chart
+	Room           sql.NullString `json:"room"`
+	SeatNumber     sql.NullString `json:"seatNumber"`
+	RegisterNumber sql.NullString `json:"registerNumber"`
+
+	ReportingTime  sql.NullTime   `json:"reportingTime"`
+	ItemsAllowed   pgtype.FlatTextArray `json:"itemsAllowed"` // TEXT[]
+	Instructions   sql.NullString `json:"instructions"`
+
+	Source         string         `json:"source"` // 'manual', 'import'
+
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+}
+
+// ExamSeatingInput defines the expected input for setting an exam's seating by hand.
+type ExamSeatingInput struct {
+	VenueID        *string  `json:"venueId"`
+	VenueName      *string  `json:"venueName"`
+	Room           *string  `json:"room"`
+	SeatNumber     *string  `json:"seatNumber"`
+	ReportingTime  *string  `json:"reportingTime"` // Time string (HH:MM:SS)
+	ItemsAllowed   []string `json:"itemsAllowed"`
+	Instructions   *string  `json:"instructions"`
+}
+
+// ExamSeatingImportInput defines the form fields accepted when importing a seating chart.
+type ExamSeatingImportInput struct {
+	Content *string `form:"content"` // Pasted CSV content, used when no file is uploaded
+	Mapping *string `form:"mapping"` // JSON object mapping fields to column headers or 1-based column numbers
+	DryRun  bool    `form:"dryRun"`  // Find the seat without saving it
+}
+
+// ExamSeatingImportResult reports the seat found for the user in an imported seating chart.
+type ExamSeatingImportResult struct {
+	Seating     *ExamSeating      `json:"seating"`
+	DryRun      bool              `json:"dryRun"`
+	MatchedRow  int               `json:"matchedRow"` // 1-based row number in the chart, counting the header
+	MatchedBy   string            `json:"matchedBy"`  // 'register_number', 'register_range'
+	RowsScanned int               `json:"rowsScanned"`
+	Mapping     map[string]string `json:"mapping"` // Field to column header actually used
+}
+
+// ExamBriefing combines an upcoming exam with its hall ticket details.
+type ExamBriefing struct {
+	Exam               Exam          `json:"exam"`
+	SubjectName        string        `json:"subjectName,omitempty"`
+	Seating            *ExamSeating  `json:"seating"` // nil until seating is known
+	StartsAt           sql.NullTime  `json:"startsAt"`
+	ReportBy           sql.NullTime  `json:"reportBy"` // Reporting time, or a default lead before the start
+	MinutesUntilReport sql.NullInt32 `json:"minutesUntilReport"`
+}
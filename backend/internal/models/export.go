@@ -0,0 +1,33 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// GradedMark is one graded exam, assignment or lab record, as exported with a user's marks.
+type GradedMark struct {
+	Source        string          `json:"source"` // 'exam', 'assignment', 'lab_record'
+	SourceID      string          `json:"sourceId"`
+	SubjectID     sql.NullString  `json:"subjectId"`
+	SubjectCode   sql.NullString  `json:"subjectCode"`
+
+	Title         string          `json:"title"`
+	Kind          sql.NullString  `json:"kind"` // Exam type or assignment type; NULL for lab records
+
+	MaxMarks      sql.NullFloat64 `json:"maxMarks"`
+	ObtainedMarks float64         `json:"obtainedMarks"`
+	Grade         sql.NullString  `json:"grade"`
+
+	GradedOn      time.Time       `json:"gradedOn"` // Exam date, assignment submission (or due) time, or lab record submission date
+	UpdatedAt     time.Time       `json:"updatedAt"`
+}
+
+// AnalyticsExportInput defines the expected input for exporting a user's history.
+type AnalyticsExportInput struct {
+	Dataset  string   // 'daily-stats', 'activity-logs', 'study-sessions', 'marks'
+	Format   string   // 'csv' or 'ndjson'
+	FromDate string   // YYYY-MM-DD, in the user's timezone; defaults to 30 days before ToDate
+	ToDate   string   // YYYY-MM-DD, inclusive; defaults to today
+	Columns  []string // Columns to include, in order; empty for all
+}
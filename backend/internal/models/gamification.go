@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// XPRule defines how much XP one kind of activity earns.
+type XPRule struct {
+	Rule        string        `json:"rule"` // e.g. 'study_session_completed', 'assignment_on_time'
+	Description string        `json:"description"`
+	XP          int32         `json:"xp"`
+	DailyCap    sql.NullInt32 `json:"dailyCap"` // Most XP the rule awards a user per day; NULL for no cap
+	IsActive    bool          `json:"isActive"`
+
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+}
+
+// XPAward is one rewarded activity in a user's XP ledger.
+type XPAward struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"userId"`
+	Rule      string    `json:"rule"`
+	SourceKey string    `json:"sourceKey"` // What earned the XP, e.g. the completed session's ID
+	XP        int32     `json:"xp"`        // 0 once the rule's daily cap is reached
+	AwardDate time.Time `json:"awardDate"` // DATE, in the user's timezone
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UserProgress holds a user's XP, level and daily study streak.
+type UserProgress struct {
+	UserID         string       `json:"userId"`
+	TotalXP        int32        `json:"totalXp"`
+	Level          int32        `json:"level"`
+
+	CurrentStreak  int32        `json:"currentStreak"`
+	LongestStreak  int32        `json:"longestStreak"`
+	LastActiveDate sql.NullTime `json:"lastActiveDate"` // DATE, in the user's timezone
+
+	FreezeTokens   int32        `json:"freezeTokens"` // Each one covers a missed day without breaking the streak
+	FreezesUsed    int32        `json:"freezesUsed"`
+
+	CreatedAt      time.Time    `json:"createdAt"`
+	UpdatedAt      time.Time    `json:"updatedAt"`
+}
+
+// UserAchievement records when a user unlocked an achievement.
+type UserAchievement struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"userId"`
+	Achievement string    `json:"achievement"`
+	UnlockedAt  time.Time `json:"unlockedAt"`
+}
+
+// Achievement describes an unlockable achievement and how far a user is towards it.
+type Achievement struct {
+	Code        string       `json:"code"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Progress    int32        `json:"progress"`
+	Target      int32        `json:"target"`
+	Unlocked    bool         `json:"unlocked"`
+	UnlockedAt  sql.NullTime `json:"unlockedAt"`
+}
+
+// ProgressOverview is a user's gamification progress as returned by /me/progress.
+type ProgressOverview struct {
+	TotalXP          int32         `json:"totalXp"`
+	XPToday          int32         `json:"xpToday"`
+	Level            int32         `json:"level"`
+	LevelStartXP     int32         `json:"levelStartXp"` // Total XP at which the current level was reached
+	NextLevelXP      int32         `json:"nextLevelXp"`  // Total XP needed for the next level
+	XPToNextLevel    int32         `json:"xpToNextLevel"`
+
+	CurrentStreak    int32         `json:"currentStreak"` // 0 once missed days outnumber the freeze tokens
+	LongestStreak    int32         `json:"longestStreak"`
+	LastActiveDate   sql.NullTime  `json:"lastActiveDate"`
+	StudiedToday     bool          `json:"studiedToday"`
+	FreezeTokens     int32         `json:"freezeTokens"`
+	FreezesUsed      int32         `json:"freezesUsed"`
+
+	Achievements     []Achievement `json:"achievements"`
+	RecentAwards     []XPAward     `json:"recentAwards"`
+}
+
+// XPRuleUpdateInput defines the expected input for tuning an XP rule.
+type XPRuleUpdateInput struct {
+	XP       *int32 `json:"xp" validate:"omitempty,min=0"`
+	DailyCap *int32 `json:"dailyCap" validate:"omitempty,min=0"` // 0 removes the cap
+	IsActive *bool  `json:"isActive"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Goal is a target a user sets for their studies, such as a number of study hours per week.
+type Goal struct {
+	ID          string         `json:"id"`
+	UserID      string         `json:"userId"`
+
+	Title       string         `json:"title"`
+	Metric      string         `json:"metric"`      // 'study_hours', 'questions_practised', 'attendance_rate'
+	TargetValue float64        `json:"targetValue"` // Hours, questions or a percentage
+	Period      string         `json:"period"`      // 'weekly', 'once'
+
+	SubjectID   sql.NullString `json:"subjectId"` // Study hours, questions or attendance of one subject
+	ExamID      sql.NullString `json:"examId"`    // Questions linked to one exam
+
+	StartDate   time.Time      `json:"startDate"` // DATE, in the user's timezone
+	EndDate     sql.NullTime   `json:"endDate"`   // DATE, inclusive
+
+	Status      string         `json:"status"` // 'active', 'achieved', 'missed', 'ended', 'archived'
+
+	CreatedAt   time.Time      `json:"createdAt"`
+	UpdatedAt   time.Time      `json:"updatedAt"`
+}
+
+// GoalResult records whether a goal was attained in one of its finished periods.
+type GoalResult struct {
+	ID          string    `json:"id"`
+	GoalID      string    `json:"goalId"`
+	UserID      string    `json:"userId"`
+
+	PeriodStart time.Time `json:"periodStart"` // DATE
+	PeriodEnd   time.Time `json:"periodEnd"`   // DATE, inclusive
+	TargetValue float64   `json:"targetValue"` // The goal's target at the time
+	ActualValue float64   `json:"actualValue"`
+	Achieved    bool      `json:"achieved"`
+
+	RecordedAt  time.Time `json:"recordedAt"`
+}
+
+// GoalProgress is how far a goal has come in its current period.
+type GoalProgress struct {
+	PeriodStart   time.Time `json:"periodStart"` // DATE
+	PeriodEnd     time.Time `json:"periodEnd"`   // DATE, inclusive
+
+	CurrentValue  float64   `json:"currentValue"`
+	TargetValue   float64   `json:"targetValue"`
+	Percent       float64   `json:"percent"`       // Of the target, capped at 100
+	ExpectedValue float64   `json:"expectedValue"` // Where the goal should be by now at an even pace; the target for attendance
+
+	DaysLeft      int32     `json:"daysLeft"` // Including today
+	Attained      bool      `json:"attained"`
+	FallingBehind bool      `json:"fallingBehind"`
+}
+
+// GoalWithProgress is a goal together with its progress in the current period.
+type GoalWithProgress struct {
+	Goal
+	Progress *GoalProgress `json:"progress"` // NULL for goals that are no longer active
+}
+
+// GoalHistory lists the results of a goal's finished periods, newest first.
+type GoalHistory struct {
+	Goal            Goal            `json:"goal"`
+	Results         []GoalResult    `json:"results"`
+	PeriodsTotal    int             `json:"periodsTotal"`
+	PeriodsAchieved int             `json:"periodsAchieved"`
+	AttainmentRate  sql.NullFloat64 `json:"attainmentRate"` // Percentage; NULL without finished periods
+	CurrentStreak   int             `json:"currentStreak"`  // Consecutive achieved periods up to the latest
+}
+
+// GoalCreationInput defines the expected input for creating a goal.
+type GoalCreationInput struct {
+	Title       string   `json:"title" validate:"required"`
+	Metric      string   `json:"metric" validate:"required,oneof=study_hours questions_practised attendance_rate"`
+	TargetValue float64  `json:"targetValue" validate:"required,gt=0"`
+	Period      string   `json:"period" validate:"required,oneof=weekly once"`
+
+	SubjectID   *string  `json:"subjectId"`
+	ExamID      *string  `json:"examId"` // Only for questions practised
+
+	StartDate   *string  `json:"startDate"` // YYYY-MM-DD, defaults to today
+	EndDate     *string  `json:"endDate"`   // YYYY-MM-DD; for one-off goals defaults to the day before the exam
+}
+
+// GoalUpdateInput defines the expected input for updating a goal. Omitted fields are left unchanged.
+type GoalUpdateInput struct {
+	Title       *string  `json:"title"`
+	TargetValue *float64 `json:"targetValue" validate:"omitempty,gt=0"`
+	EndDate     *string  `json:"endDate"` // YYYY-MM-DD; an empty string removes the end date of a weekly goal
+	Archived    *bool    `json:"archived"`
+}
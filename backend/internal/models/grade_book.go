@@ -0,0 +1,118 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// GradeScheme defines how marks from exams, assignments and lab records are weighted for a subject.
+type GradeScheme struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"userId"`
+	SubjectID  sql.NullString `json:"subjectId"` // NULL for the user's default scheme
+
+	Name       string         `json:"name"`
+	Components []GradeSchemeComponent `json:"components"`
+
+	CreatedAt  time.Time      `json:"createdAt"`
+	UpdatedAt  time.Time      `json:"updatedAt"`
+}
+
+// GradeSchemeComponent is a single weighted component of a grade scheme.
+type GradeSchemeComponent struct {
+	ID           string          `json:"id"`
+	SchemeID     string          `json:"schemeId"`
+
+	Name         string          `json:"name"`
+	SourceType   string          `json:"sourceType"` // 'exam', 'assignment', 'lab_record'
+	ExamType     sql.NullString  `json:"examType"`   // e.g. 'cat1', only for 'exam' components
+
+	Weight       float64         `json:"weight"`
+	ItemMaxMarks sql.NullFloat64 `json:"itemMaxMarks"`
+	IsFinal      bool            `json:"isFinal"`
+
+	SortOrder    int32           `json:"sortOrder"`
+}
+
+// GradeSchemeInput defines the expected input for creating or updating a grade scheme.
+type GradeSchemeInput struct {
+	SubjectID  *string                     `json:"subjectId"` // Omit for the default scheme
+	Name       string                      `json:"name" validate:"required"`
+	Components []GradeSchemeComponentInput `json:"components" validate:"required,min=1,dive"`
+}
+
+// GradeSchemeComponentInput defines the expected input for a grade scheme component.
+type GradeSchemeComponentInput struct {
+	Name         string   `json:"name" validate:"required"`
+	SourceType   string   `json:"sourceType" validate:"required,oneof=exam assignment lab_record"`
+	ExamType     *string  `json:"examType"`
+	Weight       float64  `json:"weight" validate:"required,gt=0"`
+	ItemMaxMarks *float64 `json:"itemMaxMarks"`
+	IsFinal      *bool    `json:"isFinal"`
+}
+
+// ComponentScore is the computed result of one grade scheme component.
+type ComponentScore struct {
+	Name         string          `json:"name"`
+	SourceType   string          `json:"sourceType"`
+	Weight       float64         `json:"weight"`
+	IsFinal      bool            `json:"isFinal"`
+
+	GradedItems  int             `json:"gradedItems"`
+	Percentage   sql.NullFloat64 `json:"percentage"` // NULL until at least one item is graded
+	Contribution float64         `json:"contribution"`
+}
+
+// SubjectGrade is the computed grade-book entry for a single subject.
+type SubjectGrade struct {
+	SubjectID        string          `json:"subjectId"`
+	SubjectCode      string          `json:"subjectCode"`
+	SubjectName      string          `json:"subjectName"`
+	Semester         sql.NullInt32   `json:"semester"`
+	Credits          sql.NullInt32   `json:"credits"`
+
+	SchemeName       string          `json:"schemeName"`
+	Components       []ComponentScore `json:"components"`
+
+	InternalMarks    float64         `json:"internalMarks"`    // Contribution of non-final components
+	InternalMaxMarks float64         `json:"internalMaxMarks"` // Weight of non-final components
+	SecuredMarks     float64         `json:"securedMarks"`     // Contribution of all graded components
+	GradedWeight     float64         `json:"gradedWeight"`
+	TotalWeight      float64         `json:"totalWeight"`
+
+	IsComplete       bool            `json:"isComplete"`
+	Percentage       sql.NullFloat64 `json:"percentage"`
+	Grade            sql.NullString  `json:"grade"`
+	GradePoint       sql.NullFloat64 `json:"gradePoint"`
+}
+
+// SemesterGPA groups subject grades for a semester with the resulting SGPA.
+type SemesterGPA struct {
+	Semester      int32           `json:"semester"`
+	SGPA          sql.NullFloat64 `json:"sgpa"` // NULL until a subject with credits is complete
+	GradedCredits int32           `json:"gradedCredits"`
+	Subjects      []SubjectGrade  `json:"subjects"`
+}
+
+// GradeBook is the computed grade book for a user.
+type GradeBook struct {
+	Semesters     []SemesterGPA   `json:"semesters"`
+	CGPA          sql.NullFloat64 `json:"cgpa"`
+	GradedCredits int32           `json:"gradedCredits"`
+}
+
+// GradeProjection describes what is needed in the remaining components to reach a target grade.
+type GradeProjection struct {
+	SubjectID          string          `json:"subjectId"`
+	TargetGrade        string          `json:"targetGrade"`
+	TargetPercentage   float64         `json:"targetPercentage"`
+
+	SecuredMarks       float64         `json:"securedMarks"`
+	RemainingWeight    float64         `json:"remainingWeight"`
+	RemainingComponents []string       `json:"remainingComponents"`
+
+	RequiredPercentage float64         `json:"requiredPercentage"` // Needed across the remaining components
+	RequiredFinalMarks sql.NullFloat64 `json:"requiredFinalMarks"` // Raw marks in the final exam, when its max marks are known
+	Achievable         bool            `json:"achievable"`
+	Message            string          `json:"message"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// ProductivityInsights analyses how a user's study sessions and assignment estimates went over a period.
+type ProductivityInsights struct {
+	FromDate         time.Time               `json:"fromDate"` // DATE, in the user's timezone
+	ToDate           time.Time               `json:"toDate"`   // DATE, inclusive
+	SessionsAnalysed int                     `json:"sessionsAnalysed"`
+
+	Hours            []HourProductivity      `json:"hours"`     // Hours of the day with at least one started session
+	BestHours        []int32                 `json:"bestHours"` // Up to three hours with the highest average productivity, best first
+
+	PlanAdherence    PlanAdherence           `json:"planAdherence"`
+
+	BySubject        []ProductivityBreakdown `json:"bySubject"`
+	BySessionType    []ProductivityBreakdown `json:"bySessionType"`
+	CommonBlockers   []BlockerCount          `json:"commonBlockers"`
+
+	EstimateAccuracy EstimateAccuracy        `json:"estimateAccuracy"`
+}
+
+// HourProductivity summarises the sessions started in one hour of the day.
+type HourProductivity struct {
+	Hour            int32           `json:"hour"` // 0-23, in the user's timezone
+	Sessions        int             `json:"sessions"`
+	ActualMinutes   int32           `json:"actualMinutes"`
+	RatedSessions   int             `json:"ratedSessions"`
+	AvgProductivity sql.NullFloat64 `json:"avgProductivity"` // 1-5; NULL without rated sessions
+	AvgCompletion   float64         `json:"avgCompletion"`   // Percentage
+}
+
+// PlanAdherence compares planned study time with what actually happened.
+type PlanAdherence struct {
+	PlannedSessions      int             `json:"plannedSessions"` // Sessions with a planned duration or planned times
+	CompletedSessions    int             `json:"completedSessions"`
+	PartialSessions      int             `json:"partialSessions"`
+	SkippedSessions      int             `json:"skippedSessions"`
+
+	PlannedMinutes       int32           `json:"plannedMinutes"` // Of planned sessions, skipped ones included
+	ActualMinutes        int32           `json:"actualMinutes"`
+	AdherencePercentage  sql.NullFloat64 `json:"adherencePercentage"` // Actual minutes as a percentage of planned; NULL without planned minutes
+
+	OnTimeStarts         int             `json:"onTimeStarts"` // Started within 15 minutes of the planned start
+	LateStarts           int             `json:"lateStarts"`
+	AvgStartDelayMinutes sql.NullFloat64 `json:"avgStartDelayMinutes"` // Negative when sessions start early
+}
+
+// ProductivityBreakdown summarises sessions grouped by subject or session type.
+type ProductivityBreakdown struct {
+	Key             string          `json:"key"`   // Subject ID or session type; empty for sessions without a subject
+	Label           string          `json:"label"` // Subject name or session type
+	Sessions        int             `json:"sessions"`
+	ActualMinutes   int32           `json:"actualMinutes"`
+	RatedSessions   int             `json:"ratedSessions"`
+	AvgProductivity sql.NullFloat64 `json:"avgProductivity"`
+	AvgCompletion   float64         `json:"avgCompletion"`
+}
+
+// BlockerCount is a blocker reported on study sessions and how often it came up.
+type BlockerCount struct {
+	Blocker string `json:"blocker"`
+	Count   int    `json:"count"`
+}
+
+// EstimateAccuracy compares assignment time estimates with the hours actually spent.
+type EstimateAccuracy struct {
+	Assignments      int             `json:"assignments"` // Assignments with both estimated and actual hours
+	EstimatedHours   float64         `json:"estimatedHours"`
+	ActualHours      float64         `json:"actualHours"`
+	ActualToEstimate sql.NullFloat64 `json:"actualToEstimate"` // 1.5 means work took 50% longer than estimated
+	MeanAbsErrorPct  sql.NullFloat64 `json:"meanAbsErrorPct"`  // Average size of the miss, as a percentage of the estimate
+	Underestimated   int             `json:"underestimated"`   // Took more than 10% longer than estimated
+	Overestimated    int             `json:"overestimated"`    // Took more than 10% less time than estimated
+	Accurate         int             `json:"accurate"`
+}
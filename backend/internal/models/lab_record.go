@@ -37,6 +37,8 @@ type LabRecord struct {
 	Marks             sql.NullFloat64 `json:"marks"`
 	StaffRemarks      sql.NullString `json:"staffRemarks"`
 	
+	Tags              pgtype.FlatTextArray `json:"tags"` // TEXT[]
+	
 	CreatedAt         time.Time      `json:"createdAt"`
 	UpdatedAt         time.Time      `json:"updatedAt"`
 }
@@ -83,4 +85,6 @@ type LabRecordCreationInput struct {
 	
 	Marks             *float64  `json:"marks"`
 	StaffRemarks      *string   `json:"staffRemarks"`
+	
+	Tags              []string  `json:"tags"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Notification represents an in-app notification for a user.
+type Notification struct {
+	ID               string         `json:"id"`
+	UserID           string         `json:"userId"`
+
+	NotificationType string         `json:"notificationType"` // e.g. 'exam_clash', 'exam_overload', 'crunch_week'
+	Title            string         `json:"title"`
+	Message          sql.NullString `json:"message"`
+
+	EntityType       sql.NullString `json:"entityType"`
+	EntityID         sql.NullString `json:"entityId"`
+
+	DedupeKey        sql.NullString `json:"-"` // Identifies the condition so it is only notified once
+
+	IsRead           bool           `json:"isRead"`
+	ReadAt           sql.NullTime   `json:"readAt"`
+
+	CreatedAt        time.Time      `json:"createdAt"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// PomodoroSession holds the server-side state of focused work/break cycles on a study session.
+type PomodoroSession struct {
+	ID                    string       `json:"id"`
+	UserID                string       `json:"userId"`
+	StudySessionID        string       `json:"studySessionId"`
+
+	WorkMinutes           int32        `json:"workMinutes"`
+	ShortBreakMinutes     int32        `json:"shortBreakMinutes"`
+	LongBreakMinutes      int32        `json:"longBreakMinutes"`
+	CyclesBeforeLongBreak int32        `json:"cyclesBeforeLongBreak"`
+	TargetCycles          int32        `json:"targetCycles"`
+
+	Phase                 string       `json:"phase"`  // 'work', 'short_break', 'long_break'
+	Status                string       `json:"status"` // 'running', 'paused', 'completed', 'stopped'
+	CompletedCycles       int32        `json:"completedCycles"`
+	PhaseStartedAt        time.Time    `json:"phaseStartedAt"`      // Start of the current stretch of the phase
+	PhaseElapsedSeconds   int32        `json:"phaseElapsedSeconds"` // Time spent in the phase before PhaseStartedAt
+
+	FocusSeconds          int32        `json:"focusSeconds"`
+	InterruptionCount     int32        `json:"interruptionCount"`
+
+	StartedAt             time.Time    `json:"startedAt"`
+	PausedAt              sql.NullTime `json:"pausedAt"`
+	EndedAt               sql.NullTime `json:"endedAt"`
+
+	CreatedAt             time.Time    `json:"createdAt"`
+	UpdatedAt             time.Time    `json:"updatedAt"`
+}
+
+// PomodoroInterruption records something that broke the user's focus during a pomodoro.
+type PomodoroInterruption struct {
+	ID         string         `json:"id"`
+	PomodoroID string         `json:"pomodoroId"`
+	OccurredAt time.Time      `json:"occurredAt"`
+	Phase      string         `json:"phase"`
+	Kind       string         `json:"kind"` // 'internal', 'external'
+	Reason     sql.NullString `json:"reason"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+// PomodoroState is a pomodoro as seen at ServerTime, with the time left in its current phase.
+type PomodoroState struct {
+	Pomodoro         PomodoroSession        `json:"pomodoro"`
+	RemainingSeconds int32                  `json:"remainingSeconds"` // Left in the current phase
+	PhaseEndsAt      sql.NullTime           `json:"phaseEndsAt"`      // NULL while paused or finished
+	ServerTime       time.Time              `json:"serverTime"`       // Lets clients correct for clock drift after reconnecting
+	Interruptions    []PomodoroInterruption `json:"interruptions"`
+	StudySession     *StudySession          `json:"studySession,omitempty"` // Set once the pomodoro has finished
+}
+
+// PomodoroStartInput defines the expected input for starting a pomodoro on a study session.
+type PomodoroStartInput struct {
+	WorkMinutes           *int `json:"workMinutes" validate:"omitempty,min=5,max=120"`           // defaults to 25
+	ShortBreakMinutes     *int `json:"shortBreakMinutes" validate:"omitempty,min=1,max=60"`      // defaults to 5
+	LongBreakMinutes      *int `json:"longBreakMinutes" validate:"omitempty,min=1,max=90"`       // defaults to 15
+	CyclesBeforeLongBreak *int `json:"cyclesBeforeLongBreak" validate:"omitempty,min=1,max=12"`  // defaults to 4
+	TargetCycles          *int `json:"targetCycles" validate:"omitempty,min=1,max=12"`           // defaults to fill the planned duration
+}
+
+// PomodoroInterruptionInput defines the expected input for recording an interruption.
+type PomodoroInterruptionInput struct {
+	Kind   string  `json:"kind" validate:"omitempty,oneof=internal external"` // defaults to 'external'
+	Reason *string `json:"reason"`
+	Pause  bool    `json:"pause"` // Pause the pomodoro as well
+}
+
+// PomodoroStopInput defines the expected input for finishing a pomodoro.
+type PomodoroStopInput struct {
+	TopicsCovered        []string `json:"topicsCovered"`
+	CompletionPercentage *int     `json:"completionPercentage" validate:"omitempty,min=0,max=100"` // Derived from topics or focus time when omitted
+}
@@ -0,0 +1,41 @@
+package models
+
+// QuestionImportInput defines the form fields accepted when importing important questions from a
+// CSV file or a Markdown table.
+type QuestionImportInput struct {
+	Format    *string `form:"format"`    // 'csv' or 'markdown'; inferred from the file name when omitted
+	Content   *string `form:"content"`   // Pasted file content, used when no file is uploaded
+	Mapping   *string `form:"mapping"`   // JSON object mapping fields to column headers or 1-based column numbers
+	HasHeader *bool   `form:"hasHeader"` // Whether the first row holds column headers (default true)
+
+	SubjectID *string `form:"subjectId"` // Applied to every imported question
+	ExamID    *string `form:"examId"`    // Applied to every imported question
+	DryRun    bool    `form:"dryRun"`    // Parse and validate without saving
+}
+
+// QuestionImportRow reports the outcome of importing a single row.
+type QuestionImportRow struct {
+	Row          int    `json:"row"` // 1-based row number in the file, counting the header
+	QuestionText string `json:"questionText,omitempty"`
+	Action       string `json:"action"` // 'created', 'duplicate', 'invalid'
+	QuestionID   string `json:"questionId,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// QuestionImportResult summarises an import of important questions.
+type QuestionImportResult struct {
+	Format     string              `json:"format"`
+	DryRun     bool                `json:"dryRun"`
+	Mapping    map[string]string   `json:"mapping"` // Field to column header actually used
+	Created    int                 `json:"created"`
+	Duplicates int                 `json:"duplicates"`
+	Invalid    int                 `json:"invalid"`
+	Rows       []QuestionImportRow `json:"rows"`
+}
+
+// QuestionExport is a rendered export of important questions.
+type QuestionExport struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// QuestionPaper records a previous paper whose questions were ingested into the question bank.
+type QuestionPaper struct {
+	ID             string         `json:"id"`
+	UserID         string         `json:"userId"`
+	DocumentID     sql.NullString `json:"documentId"`
+	SubjectID      sql.NullString `json:"subjectId"`
+	ExamID         sql.NullString `json:"examId"`
+
+	Source         string         `json:"source"` // e.g. 'FAT Nov 2023'
+	FileName       sql.NullString `json:"fileName"`
+
+	ExtractedCount int32          `json:"extractedCount"`
+	CreatedCount   int32          `json:"createdCount"`
+	MergedCount    int32          `json:"mergedCount"`
+
+	CreatedAt      time.Time      `json:"createdAt"`
+}
+
+// QuestionPaperOccurrence links an important question to a paper it appeared in.
+type QuestionPaperOccurrence struct {
+	ID            string  `json:"id"`
+	PaperID       string  `json:"paperId"`
+	QuestionID    string  `json:"questionId"`
+	ExtractedText string  `json:"extractedText"`
+	Similarity    float64 `json:"similarity"`
+}
+
+// QuestionPaperIngestInput defines the form fields accepted when ingesting a question paper.
+type QuestionPaperIngestInput struct {
+	SubjectID *string `form:"subjectId"`
+	ExamID    *string `form:"examId"`
+	Source    *string `form:"source"` // defaults to the document title or file name
+	Text      *string `form:"text"`   // Pasted paper text, used when no file is uploaded
+	DryRun    bool    `form:"dryRun"` // Extract and match without saving
+}
+
+// ExtractedQuestion is a question found in a paper and what ingestion did with it.
+type ExtractedQuestion struct {
+	Text           string         `json:"text"`
+	Unit           sql.NullString `json:"unit"`
+	Marks          sql.NullInt32  `json:"marks"`
+
+	Action         string         `json:"action"` // 'created', 'merged'
+	QuestionID     sql.NullString `json:"questionId"`
+	MatchedText    sql.NullString `json:"matchedText"`
+	Similarity     float64        `json:"similarity"`
+	FrequencyCount int32          `json:"frequencyCount"`
+}
+
+// QuestionPaperIngestResult summarises the ingestion of a question paper.
+type QuestionPaperIngestResult struct {
+	Paper     *QuestionPaper      `json:"paper"` // nil for a dry run
+	DryRun    bool                `json:"dryRun"`
+	Questions []ExtractedQuestion `json:"questions"`
+}
+
+// UnitQuestionSuggestions lists the most frequently asked questions of a unit.
+type UnitQuestionSuggestions struct {
+	Unit      string              `json:"unit"`
+	Questions []ImportantQuestion `json:"questions"`
+}
+
+// ExamQuestionSuggestions lists high-frequency questions per unit for an exam.
+type ExamQuestionSuggestions struct {
+	ExamID    string                    `json:"examId"`
+	ExamTitle string                    `json:"examTitle"`
+	ExamDate  time.Time                 `json:"examDate"`
+	Units     []UnitQuestionSuggestions `json:"units"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// QuestionReview records a single practice attempt of an important question and the schedule it produced.
+type QuestionReview struct {
+	ID               string        `json:"id"`
+	UserID           string        `json:"userId"`
+	QuestionID       string        `json:"questionId"`
+
+	Quality          int32         `json:"quality"` // 0 (blackout) to 5 (perfect recall)
+	ConfidenceLevel  sql.NullInt32 `json:"confidenceLevel"`
+	TimeSpentSeconds sql.NullInt32 `json:"timeSpentSeconds"`
+
+	EaseFactor       float64       `json:"easeFactor"`
+	IntervalDays     int32         `json:"intervalDays"`
+	NextReviewAt     time.Time     `json:"nextReviewAt"`
+
+	ReviewedAt       time.Time     `json:"reviewedAt"`
+}
+
+// QuestionReviewInput defines the expected input for recording a practice attempt.
+type QuestionReviewInput struct {
+	QuestionID       string `json:"questionId"` // Only used inside a review session
+	Quality          *int   `json:"quality" validate:"required,min=0,max=5"`
+	ConfidenceLevel  *int   `json:"confidenceLevel" validate:"omitempty,min=1,max=5"`
+	TimeSpentSeconds *int   `json:"timeSpentSeconds" validate:"omitempty,min=0"`
+}
+
+// ReviewSessionInput defines the expected input for submitting the results of a review session.
+type ReviewSessionInput struct {
+	Reviews []QuestionReviewInput `json:"reviews" validate:"required,min=1,dive"`
+}
+
+// DueQuestion is an important question that is due for review, with the reason it was prioritised.
+type DueQuestion struct {
+	ImportantQuestion
+
+	OverdueDays      int            `json:"overdueDays"`
+	LinkedExamID     sql.NullString `json:"linkedExamId"`
+	LinkedExamTitle  sql.NullString `json:"linkedExamTitle"`
+	LinkedExamDate   sql.NullTime   `json:"linkedExamDate"`
+	DaysUntilExam    sql.NullInt32  `json:"daysUntilExam"`
+}
+
+// ReviewSession is a batch of due questions to practice in one sitting.
+type ReviewSession struct {
+	Questions    []DueQuestion `json:"questions"`
+	TotalDue     int           `json:"totalDue"`
+	GeneratedAt  time.Time     `json:"generatedAt"`
+}
+
+// ReviewSessionResult summarises a submitted review session.
+type ReviewSessionResult struct {
+	Reviews        []QuestionReview `json:"reviews"`
+	ReviewedCount  int              `json:"reviewedCount"`
+	AverageQuality float64          `json:"averageQuality"`
+	RemainingDue   int              `json:"remainingDue"`
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Quiz represents a practice session assembled from important questions.
+type Quiz struct {
+	ID                string          `json:"id"`
+	UserID            string          `json:"userId"`
+	Title             string          `json:"title"`
+
+	ExamID            sql.NullString  `json:"examId"`
+	SubjectID         sql.NullString  `json:"subjectId"`
+	Unit              sql.NullString  `json:"unit"`
+	Topic             sql.NullString  `json:"topic"`
+	Marks             sql.NullInt32   `json:"marks"`
+
+	Status            string          `json:"status"` // 'in_progress', 'completed'
+
+	TotalQuestions    int32           `json:"totalQuestions"`
+	AnsweredCount     int32           `json:"answeredCount"`
+	CorrectCount      int32           `json:"correctCount"`
+	PartialCount      int32           `json:"partialCount"`
+	IncorrectCount    int32           `json:"incorrectCount"`
+	SkippedCount      int32           `json:"skippedCount"`
+	ScorePercentage   sql.NullFloat64 `json:"scorePercentage"`
+	AverageConfidence sql.NullFloat64 `json:"averageConfidence"`
+	TotalTimeSeconds  int32           `json:"totalTimeSeconds"`
+
+	StartedAt         time.Time       `json:"startedAt"`
+	CompletedAt       sql.NullTime    `json:"completedAt"`
+
+	Questions         []QuizQuestion  `json:"questions,omitempty"`
+
+	CreatedAt         time.Time       `json:"createdAt"`
+	UpdatedAt         time.Time       `json:"updatedAt"`
+}
+
+// QuizQuestion is a single question within a quiz and the user's response to it.
+type QuizQuestion struct {
+	ID               string         `json:"id"`
+	QuizID           string         `json:"quizId"`
+	QuestionID       sql.NullString `json:"questionId"`
+	Position         int32          `json:"position"`
+
+	QuestionText     string         `json:"questionText"`
+	Marks            sql.NullInt32  `json:"marks"`
+
+	SelfGrade        sql.NullString `json:"selfGrade"` // 'correct', 'partial', 'incorrect', 'skipped'
+	ConfidenceLevel  sql.NullInt32  `json:"confidenceLevel"`
+	TimeSpentSeconds sql.NullInt32  `json:"timeSpentSeconds"`
+	AnsweredAt       sql.NullTime   `json:"answeredAt"`
+}
+
+// QuizCreationInput defines the expected input for starting a quiz.
+type QuizCreationInput struct {
+	Title     *string `json:"title"`
+
+	ExamID    *string `json:"examId"`
+	SubjectID *string `json:"subjectId"`
+	Unit      *string `json:"unit"`
+	Topic     *string `json:"topic"`
+	Marks     *int    `json:"marks"`
+
+	Size      *int    `json:"size" validate:"omitempty,min=1,max=100"` // defaults to 10
+	Shuffle   *bool   `json:"shuffle"`                                 // defaults to true
+}
+
+// QuizAnswerInput defines the expected input for answering a quiz question. At least one of
+// selfGrade and confidenceLevel must be given.
+type QuizAnswerInput struct {
+	SelfGrade        *string `json:"selfGrade" validate:"omitempty,oneof=correct partial incorrect skipped"`
+	ConfidenceLevel  *int    `json:"confidenceLevel" validate:"omitempty,min=1,max=5"`
+	TimeSpentSeconds *int    `json:"timeSpentSeconds" validate:"omitempty,min=0"`
+}
+
+// QuizQuestionView is a quiz question as presented to the user, without its answer.
+type QuizQuestionView struct {
+	QuizID         string         `json:"quizId"`
+	Position       int32          `json:"position"`
+	TotalQuestions int32          `json:"totalQuestions"`
+	QuestionID     sql.NullString `json:"questionId"`
+	QuestionText   string         `json:"questionText"`
+	Marks          sql.NullInt32  `json:"marks"`
+	Unit           sql.NullString `json:"unit"`
+	Topic          sql.NullString `json:"topic"`
+}
+
+// QuizAnswerResult is returned after answering a question: the model answer, progress and the next question.
+type QuizAnswerResult struct {
+	Question     QuizQuestion      `json:"question"`
+	AnswerText   sql.NullString    `json:"answerText"`
+	Quiz         Quiz              `json:"quiz"`
+	NextQuestion *QuizQuestionView `json:"nextQuestion"`
+}
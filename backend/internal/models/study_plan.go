@@ -14,6 +14,7 @@ type StudyPlan struct {
 	
 	Title     string         `json:"title"`
 	PlanDate  time.Time      `json:"planDate"`
+	EndDate   sql.NullTime   `json:"endDate"` // Last day of a multi-day plan
 	
 	PlanType  string         `json:"planType"` // 'daily', 'weekly', 'weekend', 'exam_prep', 'revision', 'custom'
 	
@@ -21,6 +22,8 @@ type StudyPlan struct {
 	
 	Notes     sql.NullString `json:"notes"`
 	
+	GenerationSettings *StudyPlanGenerationSettings `json:"generationSettings"` // JSONB; nil for manual plans
+	
 	CreatedAt time.Time      `json:"createdAt"`
 	UpdatedAt time.Time      `json:"updatedAt"`
 }
@@ -31,6 +34,8 @@ type StudySession struct {
 	UserID                 string         `json:"userId"`
 	StudyPlanID            sql.NullString `json:"studyPlanId"`
 	SubjectID              sql.NullString `json:"subjectId"`
+	ExamID                 sql.NullString `json:"examId"`       // Exam the session prepares for
+	AssignmentID           sql.NullString `json:"assignmentId"` // Assignment the session works on
 	
 	PlannedStartTime       sql.NullTime   `json:"plannedStartTime"`
 	PlannedEndTime         sql.NullTime   `json:"plannedEndTime"`
@@ -51,6 +56,9 @@ type StudySession struct {
 	Notes                  sql.NullString `json:"notes"`
 	Blockers               sql.NullString `json:"blockers"`
 	
+	CarriedOverFrom        sql.NullString `json:"carriedOverFrom"` // Skipped or partial session this one continues
+	CarriedOverAt          sql.NullTime   `json:"carriedOverAt"`   // When this session's unfinished work was carried over
+	
 	CreatedAt              time.Time      `json:"createdAt"`
 	UpdatedAt              time.Time      `json:"updatedAt"`
 }
@@ -68,6 +76,8 @@ type StudyPlanCreationInput struct {
 type StudySessionCreationInput struct {
 	StudyPlanID            *string  `json:"studyPlanId"`
 	SubjectID              *string  `json:"subjectId"`
+	ExamID                 *string  `json:"examId"`
+	AssignmentID           *string  `json:"assignmentId"`
 
 	PlannedStartTime       *string  `json:"plannedStartTime"` // ISO string
 	PlannedEndTime         *string  `json:"plannedEndTime"`   // ISO string
@@ -88,3 +98,151 @@ type StudySessionCreationInput struct {
 	Notes                  *string  `json:"notes"`
 	Blockers               *string  `json:"blockers"`
 }
+
+// StudyPlanGenerationSettings records the settings a study plan was generated with.
+type StudyPlanGenerationSettings struct {
+	StartDate          string   `json:"startDate"` // YYYY-MM-DD
+	EndDate            string   `json:"endDate"`   // YYYY-MM-DD
+	Timezone           string   `json:"timezone"`
+
+	DailyLimitMinutes  int      `json:"dailyLimitMinutes"`
+	PreferredStartHour int      `json:"preferredStartHour"`
+	PreferredEndHour   int      `json:"preferredEndHour"`
+	SessionMinutes     int      `json:"sessionMinutes"`
+	BreakMinutes       int      `json:"breakMinutes"`
+	RevisionDays       int      `json:"revisionDays"` // Days before each exam reserved for revision
+
+	ExamIDs            []string `json:"examIds"`
+	IncludeAssignments bool     `json:"includeAssignments"`
+}
+
+// StudyPlanGenerationInput defines the expected input for generating a study plan.
+type StudyPlanGenerationInput struct {
+	Title              *string  `json:"title"`
+	StartDate          *string  `json:"startDate"` // YYYY-MM-DD, defaults to today
+	EndDate            *string  `json:"endDate"`   // YYYY-MM-DD, defaults to the day before the last deadline
+
+	DailyLimitMinutes  *int     `json:"dailyLimitMinutes" validate:"omitempty,min=30,max=720"`   // defaults to 240
+	PreferredStartHour *int     `json:"preferredStartHour" validate:"omitempty,min=0,max=23"`    // defaults to 8
+	PreferredEndHour   *int     `json:"preferredEndHour" validate:"omitempty,min=1,max=24"`      // defaults to 22
+	SessionMinutes     *int     `json:"sessionMinutes" validate:"omitempty,min=15,max=240"`      // defaults to 50
+	BreakMinutes       *int     `json:"breakMinutes" validate:"omitempty,min=0,max=120"`         // defaults to 10
+	RevisionDays       *int     `json:"revisionDays" validate:"omitempty,min=0,max=7"`           // defaults to 1
+
+	ExamIDs            []string `json:"examIds"`            // defaults to all upcoming exams
+	IncludeAssignments *bool    `json:"includeAssignments"` // defaults to true
+
+	DryRun             bool     `json:"dryRun"` // Preview the plan without saving it
+}
+
+// StudyPlanGenerationItem reports how much of an exam or assignment the generated plan covers.
+type StudyPlanGenerationItem struct {
+	Kind               string    `json:"kind"` // 'exam', 'assignment'
+	ID                 string    `json:"id"`
+	Title              string    `json:"title"`
+	Deadline           time.Time `json:"deadline"`
+
+	RequiredMinutes    int       `json:"requiredMinutes"`
+	ScheduledMinutes   int       `json:"scheduledMinutes"`
+	RevisionMinutes    int       `json:"revisionMinutes"` // Part of ScheduledMinutes spent on revision passes
+	UnscheduledMinutes int       `json:"unscheduledMinutes"`
+	Sessions           int       `json:"sessions"`
+}
+
+// StudyPlanGenerationResult holds a generated study plan and how well it fits the available time.
+type StudyPlanGenerationResult struct {
+	Plan                  *StudyPlan                `json:"plan"`
+	Sessions              []StudySession            `json:"sessions"`
+	Items                 []StudyPlanGenerationItem `json:"items"`
+	DryRun                bool                      `json:"dryRun"`
+	TotalScheduledMinutes int                       `json:"totalScheduledMinutes"`
+	Warnings              []string                  `json:"warnings"`
+}
+
+// StudySessionCarryOver describes where the unfinished work of a skipped or partial session went.
+type StudySessionCarryOver struct {
+	SourceSessionID      string         `json:"sourceSessionId"`
+	SourceStatus         string         `json:"sourceStatus"`
+	Topics               []string       `json:"topics"`  // Topics left uncovered
+	Minutes              int            `json:"minutes"` // Unfinished minutes
+	Deadline             sql.NullTime   `json:"deadline"` // Exam day, assignment due date or plan end
+
+	NewSessions          []StudySession `json:"newSessions"`          // Sessions created in free time before the deadline
+	RebalancedSessionIDs []string       `json:"rebalancedSessionIds"` // Later sessions that took over topics that did not fit
+	UnplacedMinutes      int            `json:"unplacedMinutes"`
+	UnplacedTopics       []string       `json:"unplacedTopics"` // Topics with no session left before the deadline
+
+	Feasible             bool           `json:"feasible"` // False when the unfinished work no longer fits before the deadline
+}
+
+// StudyCarryOverReport summarises a carry-over run for a user.
+type StudyCarryOverReport struct {
+	DryRun     bool                    `json:"dryRun"`
+	Processed  int                     `json:"processed"`
+	CarryOvers []StudySessionCarryOver `json:"carryOvers"`
+	Feasible   bool                    `json:"feasible"` // False when any carried-over work no longer fits before its deadline
+	Warnings   []string                `json:"warnings"`
+}
+
+// StudyPlanTemplate is a reusable study plan that can be instantiated onto a date or date range.
+type StudyPlanTemplate struct {
+	ID              string                  `json:"id"`
+	UserID          string                  `json:"userId"`
+
+	Title           string                  `json:"title"`
+	PlanType        string                  `json:"planType"` // 'daily', 'weekly', 'weekend', 'exam_prep', 'revision', 'custom'
+	Description     sql.NullString          `json:"description"`
+
+	RepeatEveryDays int32                   `json:"repeatEveryDays"` // Cycle length when instantiated onto a date range
+	Sessions        []StudySessionBlueprint `json:"sessions"`        // JSONB
+
+	CreatedAt       time.Time               `json:"createdAt"`
+	UpdatedAt       time.Time               `json:"updatedAt"`
+}
+
+// StudySessionBlueprint describes a session of a template relative to the day the template is instantiated on.
+// Subject and topic slots are named placeholders filled in on instantiation.
+type StudySessionBlueprint struct {
+	DayOffset       int      `json:"dayOffset" validate:"min=0,max=27"`  // Days after the first day of the cycle
+	StartTime       string   `json:"startTime" validate:"required"`      // HH:MM in the user's timezone
+	DurationMinutes int      `json:"durationMinutes" validate:"required,min=5,max=720"`
+	SessionType     string   `json:"sessionType" validate:"required,oneof=study revision practice assignment lab_prep exam_prep"`
+
+	SubjectID       *string  `json:"subjectId"`   // Fixed subject
+	SubjectSlot     string   `json:"subjectSlot"` // Subject chosen on instantiation; overrides SubjectID when filled
+	Topics          []string `json:"topics"`      // Fixed topics
+	TopicSlot       string   `json:"topicSlot"`   // Topics chosen on instantiation, dealt out across the sessions sharing the slot
+
+	Notes           string   `json:"notes"`
+}
+
+// StudyPlanTemplateInput defines the expected input for creating or updating a study plan template.
+type StudyPlanTemplateInput struct {
+	Title           string                  `json:"title" validate:"required"`
+	PlanType        string                  `json:"planType" validate:"required,oneof=daily weekly weekend exam_prep revision custom"`
+	Description     *string                 `json:"description"`
+	RepeatEveryDays *int                    `json:"repeatEveryDays" validate:"omitempty,min=1,max=28"` // defaults to 7
+	Sessions        []StudySessionBlueprint `json:"sessions" validate:"required,min=1,dive"`
+}
+
+// StudyPlanTemplateInstantiationInput defines the expected input for creating a study plan from a template.
+type StudyPlanTemplateInstantiationInput struct {
+	StartDate string              `json:"startDate" validate:"required"` // YYYY-MM-DD
+	EndDate   *string             `json:"endDate"`                       // YYYY-MM-DD; repeats the template every RepeatEveryDays until this day
+	Title     *string             `json:"title"`                         // defaults to the template title
+
+	Subjects  map[string]string   `json:"subjects"` // Subject slot -> subject ID
+	Topics    map[string][]string `json:"topics"`   // Topic slot -> topics
+}
+
+// StudyPlanCloneInput defines the expected input for cloning a study plan to another date.
+type StudyPlanCloneInput struct {
+	PlanDate string  `json:"planDate" validate:"required"` // YYYY-MM-DD, the clone's first day
+	Title    *string `json:"title"`                        // defaults to the source plan title
+}
+
+// StudyPlanWithSessions is a study plan together with its sessions.
+type StudyPlanWithSessions struct {
+	Plan     *StudyPlan     `json:"plan"`
+	Sessions []StudySession `json:"sessions"`
+}
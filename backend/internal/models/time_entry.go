@@ -0,0 +1,34 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// TimeEntry represents a block of tracked time against an assignment, exam or study session.
+type TimeEntry struct {
+	ID              string         `json:"id"`
+	UserID          string         `json:"userId"`
+
+	EntityType      string         `json:"entityType"` // 'assignment', 'exam', 'study_session'
+	EntityID        string         `json:"entityId"`
+
+	StartedAt       time.Time      `json:"startedAt"`
+	EndedAt         sql.NullTime   `json:"endedAt"`
+	DurationMinutes sql.NullInt32  `json:"durationMinutes"`
+
+	Status          string         `json:"status"` // 'running', 'paused', 'stopped'
+	AutoStopped     bool           `json:"autoStopped"`
+
+	Notes           sql.NullString `json:"notes"`
+
+	CreatedAt       time.Time      `json:"createdAt"`
+	UpdatedAt       time.Time      `json:"updatedAt"`
+}
+
+// TimerStartInput defines the expected input for starting a timer.
+type TimerStartInput struct {
+	EntityType string  `json:"entityType" validate:"required,oneof=assignment exam study_session"`
+	EntityID   string  `json:"entityId" validate:"required"`
+	Notes      *string `json:"notes"`
+}
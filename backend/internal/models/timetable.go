@@ -66,3 +66,22 @@ type TimetableSlot struct {
 	CreatedAt    time.Time      `json:"createdAt"`
 	UpdatedAt    time.Time      `json:"updatedAt"`
 }
+
+// ClassAttendance records whether a user attended the class of a timetable slot on a date.
+type ClassAttendance struct {
+	ID        string         `json:"id"`
+	UserID    string         `json:"userId"`
+	SlotID    string         `json:"slotId"`
+	SubjectID sql.NullString `json:"subjectId"` // The slot's subject
+	ClassDate time.Time      `json:"classDate"` // DATE, in the user's timezone
+	Attended  bool           `json:"attended"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// ClassAttendanceInput defines the expected input for recording attendance of a class.
+type ClassAttendanceInput struct {
+	SlotID    string `json:"slotId" validate:"required"`
+	ClassDate string `json:"classDate" validate:"required"` // YYYY-MM-DD
+	Attended  *bool  `json:"attended" validate:"required"`
+}
@@ -36,6 +36,7 @@ type User struct {
 	GithubID               *string        `json:"githubId"`
 
 	// Metadata
+	Role                   string         `json:"role"` // 'student', 'admin'
 	IsActive               bool           `json:"isActive"`
 	IsVerified             bool           `json:"isVerified"`
 	LastLoginAt            *time.Time     `json:"lastLoginAt"`
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// WeeklyReview is the review generated for a user at the end of a week.
+type WeeklyReview struct {
+	ID        string             `json:"id"`
+	UserID    string             `json:"userId"`
+
+	WeekStart time.Time          `json:"weekStart"` // DATE; Monday, in the user's timezone
+	WeekEnd   time.Time          `json:"weekEnd"`   // DATE; Sunday
+	Report    WeeklyReviewReport `json:"report"`    // JSONB
+
+	EmailedAt     sql.NullTime `json:"emailedAt"`
+	EmailAttempts int32        `json:"emailAttempts"` // Failed sends; scheduled retries stop after a few
+
+	CreatedAt time.Time          `json:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt"` // Changes when the review is regenerated
+}
+
+// WeeklyReviewReport holds the sections of a weekly review.
+type WeeklyReviewReport struct {
+	Sessions       WeeklySessionSummary    `json:"sessions"`
+	SubjectHours   []WeeklySubjectHours    `json:"subjectHours"`
+	Assignments    WeeklyAssignmentSummary `json:"assignments"`
+	ExamPrep       []WeeklyExamPrep        `json:"examPrep"`
+	ExamPrepAsOf   sql.NullTime            `json:"examPrepAsOf"` // DATE the prep statuses were taken; NULL when no snapshot was taken in the week
+	Attendance     WeeklyAttendance        `json:"attendance"`
+	SuggestedFocus []FocusSuggestion       `json:"suggestedFocus"`
+}
+
+// WeeklySessionSummary compares the study sessions planned for a week with how they went.
+type WeeklySessionSummary struct {
+	Planned        int             `json:"planned"` // All sessions of the week
+	Completed      int             `json:"completed"`
+	Partial        int             `json:"partial"`
+	Skipped        int             `json:"skipped"`
+	NotStarted     int             `json:"notStarted"` // Still planned or in progress when the review was generated
+
+	PlannedMinutes int32           `json:"plannedMinutes"`
+	ActualMinutes  int32           `json:"actualMinutes"`
+	CompletionRate sql.NullFloat64 `json:"completionRate"` // Completed sessions as a percentage of planned; NULL without sessions
+}
+
+// WeeklySubjectHours is the study time spent on one subject in a week and the week before.
+type WeeklySubjectHours struct {
+	SubjectID       string  `json:"subjectId"` // Empty for sessions without a subject
+	SubjectName     string  `json:"subjectName"`
+	Minutes         int32   `json:"minutes"`
+	Hours           float64 `json:"hours"`
+	PreviousMinutes int32   `json:"previousMinutes"`
+}
+
+// WeeklyAssignmentSummary lists the assignments completed and missed in a week.
+type WeeklyAssignmentSummary struct {
+	Completed       []WeeklyReviewAssignment `json:"completed"`
+	CompletedOnTime int                      `json:"completedOnTime"`
+	Missed          []WeeklyReviewAssignment `json:"missed"` // Past due in the week and not completed by its end
+}
+
+// WeeklyReviewAssignment is an assignment as listed in a weekly review.
+type WeeklyReviewAssignment struct {
+	ID          string         `json:"id"`
+	SubjectID   sql.NullString `json:"subjectId"`
+	Title       string         `json:"title"`
+	Status      string         `json:"status"`
+	DueDate     time.Time      `json:"dueDate"`
+	CompletedAt sql.NullTime   `json:"completedAt"`
+}
+
+// WeeklyExamPrep is the prep status of an upcoming exam and what it was at the previous review.
+type WeeklyExamPrep struct {
+	ExamID         string         `json:"examId"`
+	Title          string         `json:"title"`
+	ExamDate       time.Time      `json:"examDate"`
+	DaysLeft       int32          `json:"daysLeft"` // From the day the prep statuses were taken, at most the week's last day
+	PrepStatus     string         `json:"prepStatus"`
+	PreviousStatus sql.NullString `json:"previousStatus"` // NULL when the exam was not in the previous review
+	Changed        bool           `json:"changed"`
+}
+
+// WeeklyAttendance compares a week's class attendance with the week before.
+type WeeklyAttendance struct {
+	ClassesAttended int32           `json:"classesAttended"`
+	TotalClasses    int32           `json:"totalClasses"`
+	AttendanceRate  sql.NullFloat64 `json:"attendanceRate"` // Percentage; NULL without classes
+	PreviousRate    sql.NullFloat64 `json:"previousRate"`
+	Change          sql.NullFloat64 `json:"change"` // Percentage points; NULL unless both weeks had classes
+}
+
+// FocusSuggestion is something the user should focus on the following week.
+type FocusSuggestion struct {
+	Kind       string         `json:"kind"` // 'exam_prep', 'assignment', 'missed_assignment', 'attendance', 'plan_adherence', 'neglected_subject'
+	Message    string         `json:"message"`
+	EntityType sql.NullString `json:"entityType"`
+	EntityID   sql.NullString `json:"entityId"`
+}
+
+// WeeklyReviewGenerateInput defines the expected input for generating a weekly review on demand.
+type WeeklyReviewGenerateInput struct {
+	WeekStart *string `json:"weekStart"` // YYYY-MM-DD, any day of the week; defaults to the current week
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -16,6 +17,7 @@ type ActivityLogRepository interface {
 	CreateActivityLog(ctx context.Context, log *models.ActivityLog) error
 	GetActivityLogsByUserID(ctx context.Context, userID string) ([]models.ActivityLog, error)
 	GetActivityLogsByUserIDAndType(ctx context.Context, userID, activityType string) ([]models.ActivityLog, error)
+	GetActivityLogs(ctx context.Context, userID string, filter *models.ActivityLogFilter) ([]models.ActivityLog, int, error)
 }
 
 // PGActivityLogRepository implements ActivityLogRepository for PostgreSQL.
@@ -113,13 +115,83 @@ func (r *PGActivityLogRepository) GetActivityLogsByUserIDAndType(ctx context.Con
 	return logs, nil
 }
 
+// GetActivityLogs retrieves one page of a user's activity logs matching a filter, newest first,
+// together with the number of matching logs across all pages.
+func (r *PGActivityLogRepository) GetActivityLogs(ctx context.Context, userID string, filter *models.ActivityLogFilter) ([]models.ActivityLog, int, error) {
+	conditions := []string{"user_id = $1"}
+	args := []interface{}{userID}
+
+	if len(filter.ActivityTypes) > 0 {
+		args = append(args, filter.ActivityTypes)
+		conditions = append(conditions, fmt.Sprintf("activity_type = ANY($%d)", len(args)))
+	}
+	if filter.EntityType != nil {
+		args = append(args, *filter.EntityType)
+		conditions = append(conditions, fmt.Sprintf("entity_type = $%d", len(args)))
+	}
+	if filter.EntityID != nil {
+		args = append(args, *filter.EntityID)
+		conditions = append(conditions, fmt.Sprintf("entity_id::text = $%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM activity_logs WHERE %s`, where)
+	if err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count activity logs: %w", err)
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		SELECT
+			id, user_id, activity_type, description, entity_type, entity_id,
+			metadata, ip_address, user_agent, created_at
+		FROM activity_logs
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get activity logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []models.ActivityLog
+	for rows.Next() {
+		log := models.ActivityLog{}
+		err := rows.Scan(
+			&log.ID, &log.UserID, &log.ActivityType, &log.Description, &log.EntityType, &log.EntityID,
+			&log.Metadata, &log.IPAddress, &log.UserAgent, &log.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan activity log row: %w", err)
+		}
+		logs = append(logs, log)
+	}
+	return logs, total, nil
+}
+
 // --- DailyStats Repository ---
 
 // DailyStatsRepository defines the interface for daily stats data operations.
 type DailyStatsRepository interface {
 	GetDailyStatsByUserIDAndDate(ctx context.Context, userID string, date time.Time) (*models.DailyStats, error)
 	GetDailyStatsByUserID(ctx context.Context, userID string) ([]models.DailyStats, error)
+	GetDailyStatsByUserIDAndDateRange(ctx context.Context, userID string, from, to time.Time) ([]models.DailyStats, error)
 	UpsertDailyStats(ctx context.Context, stats *models.DailyStats) error
+	AddStudyMinutes(ctx context.Context, userID string, date time.Time, minutes int32) error
+	AddSessionProgress(ctx context.Context, userID string, date time.Time, sessionsCompleted, topicsCovered int32) error
+	AggregateDailyStats(ctx context.Context, userID string, date, dayStart, dayEnd time.Time) (*models.DailyStats, error)
+	SaveAggregatedDailyStats(ctx context.Context, stats *models.DailyStats) error
 }
 
 // PGDailyStatsRepository implements DailyStatsRepository for PostgreSQL.
@@ -183,6 +255,37 @@ func (r *PGDailyStatsRepository) GetDailyStatsByUserID(ctx context.Context, user
 	return statsList, nil
 }
 
+// GetDailyStatsByUserIDAndDateRange retrieves a user's daily stats between two dates (inclusive), oldest first.
+func (r *PGDailyStatsRepository) GetDailyStatsByUserIDAndDateRange(ctx context.Context, userID string, from, to time.Time) ([]models.DailyStats, error) {
+	var statsList []models.DailyStats
+	query := `
+		SELECT
+			id, user_id, stat_date, study_minutes, sessions_completed, topics_covered,
+			assignments_completed, assignments_added, classes_attended, total_classes, xp_earned
+		FROM daily_stats
+		WHERE user_id = $1 AND stat_date BETWEEN $2 AND $3
+		ORDER BY stat_date ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily stats by date range: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		stats := models.DailyStats{}
+		err := rows.Scan(
+			&stats.ID, &stats.UserID, &stats.StatDate, &stats.StudyMinutes, &stats.SessionsCompleted, &stats.TopicsCovered,
+			&stats.AssignmentsCompleted, &stats.AssignmentsAdded, &stats.ClassesAttended, &stats.TotalClasses, &stats.XPEarned,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan daily stats row: %w", err)
+		}
+		statsList = append(statsList, stats)
+	}
+	return statsList, nil
+}
+
 // UpsertDailyStats inserts or updates daily stats for a user on a specific date.
 func (r *PGDailyStatsRepository) UpsertDailyStats(ctx context.Context, stats *models.DailyStats) error {
 	query := `
@@ -217,3 +320,138 @@ func (r *PGDailyStatsRepository) UpsertDailyStats(ctx context.Context, stats *mo
 	}
 	return nil
 }
+
+// AddStudyMinutes increments study_minutes for a user on a specific date, creating the row if needed.
+func (r *PGDailyStatsRepository) AddStudyMinutes(ctx context.Context, userID string, date time.Time, minutes int32) error {
+	query := `
+		INSERT INTO daily_stats (id, user_id, stat_date, study_minutes)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, stat_date) DO UPDATE SET
+			study_minutes = daily_stats.study_minutes + EXCLUDED.study_minutes
+	`
+	_, err := r.db.Exec(ctx, query, models.NewUUID(), userID, date, minutes)
+	if err != nil {
+		return fmt.Errorf("failed to add study minutes: %w", err)
+	}
+	return nil
+}
+
+// AddSessionProgress increments sessions_completed and topics_covered for a user on a specific date,
+// creating the row if needed.
+func (r *PGDailyStatsRepository) AddSessionProgress(ctx context.Context, userID string, date time.Time, sessionsCompleted, topicsCovered int32) error {
+	query := `
+		INSERT INTO daily_stats (id, user_id, stat_date, sessions_completed, topics_covered)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, stat_date) DO UPDATE SET
+			sessions_completed = daily_stats.sessions_completed + EXCLUDED.sessions_completed,
+			topics_covered = daily_stats.topics_covered + EXCLUDED.topics_covered
+	`
+	_, err := r.db.Exec(ctx, query, models.NewUUID(), userID, date, sessionsCompleted, topicsCovered)
+	if err != nil {
+		return fmt.Errorf("failed to add session progress: %w", err)
+	}
+	return nil
+}
+
+// AggregateDailyStats derives a user's stats for one day from the source tables. The day runs from
+// dayStart to dayEnd in the user's timezone and date is its calendar date. Study minutes come from
+// stopped timers (split at the day's bounds) and pomodoro focus time, sessions and topics from completed
+// study sessions, and assignments from their creation and completion times. Total classes count the
+// lectures, labs and tutorials on the user's timetable for that day, and classes attended those of them
+// recorded as attended. XP has no source table and is left at zero.
+func (r *PGDailyStatsRepository) AggregateDailyStats(ctx context.Context, userID string, date, dayStart, dayEnd time.Time) (*models.DailyStats, error) {
+	query := `
+		SELECT
+			COALESCE((
+				SELECT SUM(ROUND(EXTRACT(EPOCH FROM LEAST(ended_at, $3) - GREATEST(started_at, $2)) / 60))
+				FROM time_entries
+				WHERE user_id = $1 AND ended_at IS NOT NULL AND started_at < $3 AND ended_at > $2
+			), 0)::INT + COALESCE((
+				SELECT SUM(ROUND(focus_seconds / 60.0))
+				FROM pomodoro_sessions
+				WHERE user_id = $1 AND started_at >= $2 AND started_at < $3
+			), 0)::INT,
+			(
+				SELECT COUNT(*)
+				FROM study_sessions
+				WHERE user_id = $1 AND status = 'completed'
+					AND COALESCE(actual_end_time, updated_at) >= $2 AND COALESCE(actual_end_time, updated_at) < $3
+			),
+			COALESCE((
+				SELECT SUM(cardinality(topics_covered))
+				FROM study_sessions
+				WHERE user_id = $1 AND status = 'completed'
+					AND COALESCE(actual_end_time, updated_at) >= $2 AND COALESCE(actual_end_time, updated_at) < $3
+			), 0),
+			(
+				SELECT COUNT(*)
+				FROM assignments
+				WHERE user_id = $1 AND completed_at >= $2 AND completed_at < $3
+			),
+			(
+				SELECT COUNT(*)
+				FROM assignments
+				WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+			),
+			(
+				SELECT COUNT(*)
+				FROM timetable_slots ts
+				JOIN users u ON u.id = ts.user_id
+				WHERE ts.user_id = $1 AND ts.is_active = true
+					AND ts.slot_type IN ('lecture', 'lab', 'tutorial')
+					AND (
+						(COALESCE(ts.is_recurring, true) AND ts.day_of_week = $4)
+						OR (NOT COALESCE(ts.is_recurring, true) AND ts.specific_date = $5)
+					)
+					AND (ts.batch_filter IS NULL OR ts.batch_filter = u.batch)
+			),
+			(
+				SELECT COUNT(*)
+				FROM class_attendance ca
+				JOIN timetable_slots ts ON ts.id = ca.slot_id
+				JOIN users u ON u.id = ts.user_id
+				WHERE ca.user_id = $1 AND ca.class_date = $5 AND ca.attended
+					AND ts.is_active = true
+					AND ts.slot_type IN ('lecture', 'lab', 'tutorial')
+					AND (ts.batch_filter IS NULL OR ts.batch_filter = u.batch)
+			)
+	`
+	stats := &models.DailyStats{UserID: userID, StatDate: date}
+	err := r.db.QueryRow(ctx, query, userID, dayStart, dayEnd, int(date.Weekday()), date).Scan(
+		&stats.StudyMinutes, &stats.SessionsCompleted, &stats.TopicsCovered,
+		&stats.AssignmentsCompleted, &stats.AssignmentsAdded, &stats.TotalClasses, &stats.ClassesAttended,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate daily stats: %w", err)
+	}
+	return stats, nil
+}
+
+// SaveAggregatedDailyStats writes derived daily stats, creating the row if needed. XP earned is not
+// derived and keeps its stored value.
+func (r *PGDailyStatsRepository) SaveAggregatedDailyStats(ctx context.Context, stats *models.DailyStats) error {
+	query := `
+		INSERT INTO daily_stats (
+			id, user_id, stat_date, study_minutes, sessions_completed, topics_covered,
+			assignments_completed, assignments_added, classes_attended, total_classes
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		) ON CONFLICT (user_id, stat_date) DO UPDATE SET
+			study_minutes = EXCLUDED.study_minutes,
+			sessions_completed = EXCLUDED.sessions_completed,
+			topics_covered = EXCLUDED.topics_covered,
+			assignments_completed = EXCLUDED.assignments_completed,
+			assignments_added = EXCLUDED.assignments_added,
+			classes_attended = EXCLUDED.classes_attended,
+			total_classes = EXCLUDED.total_classes
+		RETURNING id, xp_earned
+	`
+	err := r.db.QueryRow(ctx, query,
+		models.NewUUID(), stats.UserID, stats.StatDate, stats.StudyMinutes, stats.SessionsCompleted, stats.TopicsCovered,
+		stats.AssignmentsCompleted, stats.AssignmentsAdded, stats.ClassesAttended, stats.TotalClasses,
+	).Scan(&stats.ID, &stats.XPEarned)
+	if err != nil {
+		return fmt.Errorf("failed to save aggregated daily stats: %w", err)
+	}
+	return nil
+}
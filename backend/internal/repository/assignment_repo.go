@@ -21,6 +21,7 @@ type AssignmentRepository interface {
 	UpdateAssignment(ctx context.Context, assignment *models.Assignment) error
 	DeleteAssignment(ctx context.Context, id string) error
 	UpdateAssignmentStatus(ctx context.Context, id string, status string) error
+	AddActualHours(ctx context.Context, id string, hours float64) error
 }
 
 // PGAssignmentRepository implements AssignmentRepository for PostgreSQL.
@@ -245,6 +246,20 @@ func (r *PGAssignmentRepository) UpdateAssignmentStatus(ctx context.Context, id
 	return nil
 }
 
+// AddActualHours adds tracked hours to the actual_hours of an assignment.
+func (r *PGAssignmentRepository) AddActualHours(ctx context.Context, id string, hours float64) error {
+	query := `
+		UPDATE assignments SET
+			actual_hours = COALESCE(actual_hours, 0) + $1, updated_at = $2
+		WHERE id = $3
+	`
+	_, err := r.db.Exec(ctx, query, hours, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to add assignment actual hours: %w", err)
+	}
+	return nil
+}
+
 // DeleteAssignment deletes an assignment from the database.
 func (r *PGAssignmentRepository) DeleteAssignment(ctx context.Context, id string) error {
 	query := `DELETE FROM assignments WHERE id = $1`
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- Bulk Repository ---
+
+// bulkTable describes how a bulk operation maps onto an entity's table.
+type bulkTable struct {
+	name           string
+	statusColumn   string
+	overrideColumn string // Flags a status as set manually, if the entity tracks that
+	dateColumn     string
+}
+
+// bulkTables lists the entity types that support bulk operations.
+var bulkTables = map[string]bulkTable{
+	"assignment": {name: "assignments", statusColumn: "status", dateColumn: "due_date"},
+	"exam":       {name: "exams", statusColumn: "prep_status", overrideColumn: "prep_status_override", dateColumn: "exam_date"},
+	"lab_record": {name: "lab_records", statusColumn: "status", dateColumn: "lab_date"},
+}
+
+// BulkRepository defines the interface for bulk data operations on assignments, exams and lab records.
+type BulkRepository interface {
+	ApplyBulkOperation(ctx context.Context, userID, entityType string, input *models.BulkOperationInput) ([]models.BulkItemResult, error)
+}
+
+// PGBulkRepository implements BulkRepository for PostgreSQL.
+type PGBulkRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGBulkRepository creates a new PostgreSQL bulk repository.
+func NewPGBulkRepository(db *pgxpool.Pool) *PGBulkRepository {
+	return &PGBulkRepository{db: db}
+}
+
+// ApplyBulkOperation resolves the targeted items, checks ownership and applies the action to every
+// owned item in a single transaction. Items that are missing or owned by another user are reported
+// as failed and left untouched; a database error rolls back the whole operation.
+func (r *PGBulkRepository) ApplyBulkOperation(ctx context.Context, userID, entityType string, input *models.BulkOperationInput) ([]models.BulkItemResult, error) {
+	table, ok := bulkTables[entityType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported bulk entity type: %s", entityType)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin bulk transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var results []models.BulkItemResult
+	var ownedIDs []string
+
+	if input.Filter != nil {
+		query, args := bulkFilterQuery(table, userID, input.Filter)
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve bulk filter: %w", err)
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan bulk item row: %w", err)
+			}
+			ownedIDs = append(ownedIDs, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to resolve bulk filter: %w", err)
+		}
+	} else {
+		owners := make(map[string]string)
+		query := fmt.Sprintf(`SELECT id, user_id FROM %s WHERE id::text = ANY($1) FOR UPDATE`, table.name)
+		rows, err := tx.Query(ctx, query, input.IDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up bulk items: %w", err)
+		}
+		for rows.Next() {
+			var id, ownerID string
+			if err := rows.Scan(&id, &ownerID); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan bulk item row: %w", err)
+			}
+			owners[id] = ownerID
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to look up bulk items: %w", err)
+		}
+
+		seen := make(map[string]bool)
+		for _, id := range input.IDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			ownerID, found := owners[id]
+			switch {
+			case !found:
+				results = append(results, models.BulkItemResult{ID: id, Error: "not found"})
+			case ownerID != userID:
+				results = append(results, models.BulkItemResult{ID: id, Error: "does not belong to user"})
+			default:
+				ownedIDs = append(ownedIDs, id)
+			}
+		}
+	}
+
+	if len(ownedIDs) > 0 {
+		query, args := bulkActionQuery(table, userID, ownedIDs, input)
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply bulk %s: %w", input.Action, err)
+		}
+		applied := make(map[string]bool)
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan bulk result row: %w", err)
+			}
+			applied[id] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to apply bulk %s: %w", input.Action, err)
+		}
+
+		for _, id := range ownedIDs {
+			if applied[id] {
+				results = append(results, models.BulkItemResult{ID: id, Success: true})
+			} else {
+				results = append(results, models.BulkItemResult{ID: id, Error: "not found"})
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit bulk transaction: %w", err)
+	}
+	return results, nil
+}
+
+// bulkFilterQuery builds the query selecting the IDs of a user's items that match a filter.
+func bulkFilterQuery(table bulkTable, userID string, filter *models.BulkFilter) (string, []interface{}) {
+	conditions := []string{"user_id = $1"}
+	args := []interface{}{userID}
+
+	if filter.Status != nil {
+		args = append(args, *filter.Status)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", table.statusColumn, len(args)))
+	}
+	if filter.SubjectID != nil {
+		args = append(args, *filter.SubjectID)
+		conditions = append(conditions, fmt.Sprintf("subject_id::text = $%d", len(args)))
+	}
+	if filter.Tag != nil {
+		args = append(args, *filter.Tag)
+		conditions = append(conditions, fmt.Sprintf("$%d = ANY(tags)", len(args)))
+	}
+	if filter.DateFrom != nil {
+		args = append(args, *filter.DateFrom)
+		conditions = append(conditions, fmt.Sprintf("%s::date >= $%d::date", table.dateColumn, len(args)))
+	}
+	if filter.DateTo != nil {
+		args = append(args, *filter.DateTo)
+		conditions = append(conditions, fmt.Sprintf("%s::date <= $%d::date", table.dateColumn, len(args)))
+	}
+
+	query := fmt.Sprintf(`SELECT id FROM %s WHERE %s FOR UPDATE`, table.name, strings.Join(conditions, " AND "))
+	return query, args
+}
+
+// bulkActionQuery builds the statement applying a bulk action to the given owned IDs.
+// Every statement returns the IDs it touched.
+func bulkActionQuery(table bulkTable, userID string, ids []string, input *models.BulkOperationInput) (string, []interface{}) {
+	switch input.Action {
+	case "set_status":
+		// A status set by hand is kept from then on, as when it is set on a single item.
+		set := table.statusColumn + " = $1"
+		if table.overrideColumn != "" {
+			set += ", " + table.overrideColumn + " = true"
+		}
+		return fmt.Sprintf(`
+			UPDATE %s SET %s, updated_at = $2
+			WHERE id::text = ANY($3) AND user_id = $4
+			RETURNING id
+		`, table.name, set), []interface{}{*input.Status, time.Now(), ids, userID}
+	case "add_tags":
+		return fmt.Sprintf(`
+			UPDATE %s SET
+				tags = ARRAY(SELECT DISTINCT t FROM unnest(COALESCE(tags, '{}') || $1::text[]) AS t ORDER BY t),
+				updated_at = $2
+			WHERE id::text = ANY($3) AND user_id = $4
+			RETURNING id
+		`, table.name), []interface{}{input.Tags, time.Now(), ids, userID}
+	case "remove_tags":
+		return fmt.Sprintf(`
+			UPDATE %s SET
+				tags = ARRAY(SELECT t FROM unnest(COALESCE(tags, '{}')) AS t WHERE t <> ALL($1::text[])),
+				updated_at = $2
+			WHERE id::text = ANY($3) AND user_id = $4
+			RETURNING id
+		`, table.name), []interface{}{input.Tags, time.Now(), ids, userID}
+	case "set_subject":
+		return fmt.Sprintf(`
+			UPDATE %s SET subject_id = $1, updated_at = $2
+			WHERE id::text = ANY($3) AND user_id = $4
+			RETURNING id
+		`, table.name), []interface{}{input.SubjectID, time.Now(), ids, userID}
+	default: // "delete"
+		return fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE id::text = ANY($1) AND user_id = $2
+			RETURNING id
+		`, table.name), []interface{}{ids, userID}
+	}
+}
@@ -19,7 +19,8 @@ type ExamRepository interface {
 	GetUpcomingExamsByUserID(ctx context.Context, userID string) ([]models.Exam, error)
 	UpdateExam(ctx context.Context, exam *models.Exam) error
 	DeleteExam(ctx context.Context, id string) error
-	UpdateExamPrepStatus(ctx context.Context, id string, status string) error
+	UpdateExamPrepStatus(ctx context.Context, id string, status string, override bool) error
+	AddStudyHours(ctx context.Context, id string, hours float64) error
 }
 
 // PGExamRepository implements ExamRepository for PostgreSQL.
@@ -38,11 +39,11 @@ func (r *PGExamRepository) CreateExam(ctx context.Context, exam *models.Exam) er
 		INSERT INTO exams (
 			id, user_id, subject_id, title, exam_type, exam_date, start_time, end_time,
 			duration_minutes, venue_id, syllabus_units, syllabus_topics, syllabus_notes,
-			max_marks, obtained_marks, grade, prep_status, prep_notes, study_hours_logged,
-			reminder_enabled, created_at, updated_at
+			max_marks, obtained_marks, grade, prep_status, prep_status_override, prep_notes, study_hours_logged,
+			reminder_enabled, tags, created_at, updated_at
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
-			$17, $18, $19, $20, $21, $22
+			$17, $18, $19, $20, $21, $22, $23, $24
 		) RETURNING id, created_at, updated_at
 	`
 	exam.ID = models.NewUUID()
@@ -52,8 +53,8 @@ func (r *PGExamRepository) CreateExam(ctx context.Context, exam *models.Exam) er
 	_, err := r.db.Exec(ctx, query,
 		exam.ID, exam.UserID, exam.SubjectID, exam.Title, exam.ExamType, exam.ExamDate, exam.StartTime, exam.EndTime,
 		exam.DurationMinutes, exam.VenueID, exam.SyllabusUnits, exam.SyllabusTopics, exam.SyllabusNotes,
-		exam.MaxMarks, exam.ObtainedMarks, exam.Grade, exam.PrepStatus, exam.PrepNotes, exam.StudyHoursLogged,
-		exam.ReminderEnabled, exam.CreatedAt, exam.UpdatedAt,
+		exam.MaxMarks, exam.ObtainedMarks, exam.Grade, exam.PrepStatus, exam.PrepStatusOverride, exam.PrepNotes, exam.StudyHoursLogged,
+		exam.ReminderEnabled, exam.Tags, exam.CreatedAt, exam.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create exam: %w", err)
@@ -68,16 +69,16 @@ func (r *PGExamRepository) GetExamByID(ctx context.Context, id string) (*models.
 		SELECT
 			id, user_id, subject_id, title, exam_type, exam_date, start_time, end_time,
 			duration_minutes, venue_id, syllabus_units, syllabus_topics, syllabus_notes,
-			max_marks, obtained_marks, grade, prep_status, prep_notes, study_hours_logged,
-			reminder_enabled, created_at, updated_at
+			max_marks, obtained_marks, grade, prep_status, prep_status_override, prep_notes, study_hours_logged,
+			reminder_enabled, tags, created_at, updated_at
 		FROM exams
 		WHERE id = $1
 	`
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&exam.ID, &exam.UserID, &exam.SubjectID, &exam.Title, &exam.ExamType, &exam.ExamDate, &exam.StartTime, &exam.EndTime,
 		&exam.DurationMinutes, &exam.VenueID, &exam.SyllabusUnits, &exam.SyllabusTopics, &exam.SyllabusNotes,
-		&exam.MaxMarks, &exam.ObtainedMarks, &exam.Grade, &exam.PrepStatus, &exam.PrepNotes, &exam.StudyHoursLogged,
-		&exam.ReminderEnabled, &exam.CreatedAt, &exam.UpdatedAt,
+		&exam.MaxMarks, &exam.ObtainedMarks, &exam.Grade, &exam.PrepStatus, &exam.PrepStatusOverride, &exam.PrepNotes, &exam.StudyHoursLogged,
+		&exam.ReminderEnabled, &exam.Tags, &exam.CreatedAt, &exam.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get exam by ID: %w", err)
@@ -92,8 +93,8 @@ func (r *PGExamRepository) GetExamsByUserID(ctx context.Context, userID string)
 		SELECT
 			id, user_id, subject_id, title, exam_type, exam_date, start_time, end_time,
 			duration_minutes, venue_id, syllabus_units, syllabus_topics, syllabus_notes,
-			max_marks, obtained_marks, grade, prep_status, prep_notes, study_hours_logged,
-			reminder_enabled, created_at, updated_at
+			max_marks, obtained_marks, grade, prep_status, prep_status_override, prep_notes, study_hours_logged,
+			reminder_enabled, tags, created_at, updated_at
 		FROM exams
 		WHERE user_id = $1
 		ORDER BY exam_date ASC, start_time ASC
@@ -109,8 +110,8 @@ func (r *PGExamRepository) GetExamsByUserID(ctx context.Context, userID string)
 		err := rows.Scan(
 			&exam.ID, &exam.UserID, &exam.SubjectID, &exam.Title, &exam.ExamType, &exam.ExamDate, &exam.StartTime, &exam.EndTime,
 			&exam.DurationMinutes, &exam.VenueID, &exam.SyllabusUnits, &exam.SyllabusTopics, &exam.SyllabusNotes,
-			&exam.MaxMarks, &exam.ObtainedMarks, &exam.Grade, &exam.PrepStatus, &exam.PrepNotes, &exam.StudyHoursLogged,
-			&exam.ReminderEnabled, &exam.CreatedAt, &exam.UpdatedAt,
+			&exam.MaxMarks, &exam.ObtainedMarks, &exam.Grade, &exam.PrepStatus, &exam.PrepStatusOverride, &exam.PrepNotes, &exam.StudyHoursLogged,
+			&exam.ReminderEnabled, &exam.Tags, &exam.CreatedAt, &exam.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan exam row: %w", err)
@@ -127,8 +128,8 @@ func (r *PGExamRepository) GetUpcomingExamsByUserID(ctx context.Context, userID
 		SELECT
 			id, user_id, subject_id, title, exam_type, exam_date, start_time, end_time,
 			duration_minutes, venue_id, syllabus_units, syllabus_topics, syllabus_notes,
-			max_marks, obtained_marks, grade, prep_status, prep_notes, study_hours_logged,
-			reminder_enabled, created_at, updated_at
+			max_marks, obtained_marks, grade, prep_status, prep_status_override, prep_notes, study_hours_logged,
+			reminder_enabled, tags, created_at, updated_at
 		FROM exams
 		WHERE user_id = $1 AND exam_date >= CURRENT_DATE
 		ORDER BY exam_date ASC, start_time ASC
@@ -144,8 +145,8 @@ func (r *PGExamRepository) GetUpcomingExamsByUserID(ctx context.Context, userID
 		err := rows.Scan(
 			&exam.ID, &exam.UserID, &exam.SubjectID, &exam.Title, &exam.ExamType, &exam.ExamDate, &exam.StartTime, &exam.EndTime,
 			&exam.DurationMinutes, &exam.VenueID, &exam.SyllabusUnits, &exam.SyllabusTopics, &exam.SyllabusNotes,
-			&exam.MaxMarks, &exam.ObtainedMarks, &exam.Grade, &exam.PrepStatus, &exam.PrepNotes, &exam.StudyHoursLogged,
-			&exam.ReminderEnabled, &exam.CreatedAt, &exam.UpdatedAt,
+			&exam.MaxMarks, &exam.ObtainedMarks, &exam.Grade, &exam.PrepStatus, &exam.PrepStatusOverride, &exam.PrepNotes, &exam.StudyHoursLogged,
+			&exam.ReminderEnabled, &exam.Tags, &exam.CreatedAt, &exam.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan upcoming exam row: %w", err)
@@ -162,8 +163,9 @@ func (r *PGExamRepository) UpdateExam(ctx context.Context, exam *models.Exam) er
 			subject_id = $1, title = $2, exam_type = $3, exam_date = $4, start_time = $5, end_time = $6,
 			duration_minutes = $7, venue_id = $8, syllabus_units = $9, syllabus_topics = $10, syllabus_notes = $11,
 			max_marks = $12, obtained_marks = $13, grade = $14, prep_status = $15, prep_notes = $16,
-			study_hours_logged = $17, reminder_enabled = $18, updated_at = $19
-		WHERE id = $20 AND user_id = $21
+			study_hours_logged = $17, reminder_enabled = $18, tags = $19, updated_at = $20,
+			prep_status_override = $21
+		WHERE id = $22 AND user_id = $23
 	`
 	exam.UpdatedAt = time.Now()
 
@@ -171,7 +173,8 @@ func (r *PGExamRepository) UpdateExam(ctx context.Context, exam *models.Exam) er
 		exam.SubjectID, exam.Title, exam.ExamType, exam.ExamDate, exam.StartTime, exam.EndTime,
 		exam.DurationMinutes, exam.VenueID, exam.SyllabusUnits, exam.SyllabusTopics, exam.SyllabusNotes,
 		exam.MaxMarks, exam.ObtainedMarks, exam.Grade, exam.PrepStatus, exam.PrepNotes,
-		exam.StudyHoursLogged, exam.ReminderEnabled, exam.UpdatedAt,
+		exam.StudyHoursLogged, exam.ReminderEnabled, exam.Tags, exam.UpdatedAt,
+		exam.PrepStatusOverride,
 		exam.ID, exam.UserID,
 	)
 	if err != nil {
@@ -183,20 +186,35 @@ func (r *PGExamRepository) UpdateExam(ctx context.Context, exam *models.Exam) er
 	return nil
 }
 
-// UpdateExamPrepStatus updates the preparation status of an exam.
-func (r *PGExamRepository) UpdateExamPrepStatus(ctx context.Context, id string, status string) error {
+// UpdateExamPrepStatus updates the preparation status of an exam. override records whether the status
+// was chosen by the user rather than derived from syllabus coverage.
+func (r *PGExamRepository) UpdateExamPrepStatus(ctx context.Context, id string, status string, override bool) error {
 	query := `
 		UPDATE exams SET
-			prep_status = $1, updated_at = $2
-		WHERE id = $3
+			prep_status = $1, prep_status_override = $2, updated_at = $3
+		WHERE id = $4
 	`
-	_, err := r.db.Exec(ctx, query, status, time.Now(), id)
+	_, err := r.db.Exec(ctx, query, status, override, time.Now(), id)
 	if err != nil {
 		return fmt.Errorf("failed to update exam prep status: %w", err)
 	}
 	return nil
 }
 
+// AddStudyHours adds tracked hours to the study_hours_logged of an exam.
+func (r *PGExamRepository) AddStudyHours(ctx context.Context, id string, hours float64) error {
+	query := `
+		UPDATE exams SET
+			study_hours_logged = COALESCE(study_hours_logged, 0) + $1, updated_at = $2
+		WHERE id = $3
+	`
+	_, err := r.db.Exec(ctx, query, hours, time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("failed to add exam study hours: %w", err)
+	}
+	return nil
+}
+
 // DeleteExam deletes an exam from the database.
 func (r *PGExamRepository) DeleteExam(ctx context.Context, id string) error {
 	query := `DELETE FROM exams WHERE id = $1`
@@ -215,9 +233,12 @@ func (r *PGExamRepository) DeleteExam(ctx context.Context, id string) error {
 // ImportantQuestionRepository defines the interface for important question data operations.
 type ImportantQuestionRepository interface {
 	CreateImportantQuestion(ctx context.Context, question *models.ImportantQuestion) error
+	CreateImportantQuestions(ctx context.Context, questions []*models.ImportantQuestion) error
 	GetImportantQuestionByID(ctx context.Context, id string) (*models.ImportantQuestion, error)
 	GetImportantQuestionsByExamID(ctx context.Context, examID string) ([]models.ImportantQuestion, error)
 	GetImportantQuestionsBySubjectID(ctx context.Context, subjectID string) ([]models.ImportantQuestion, error)
+	GetImportantQuestionsByUserID(ctx context.Context, userID string) ([]models.ImportantQuestion, error)
+	GetDueImportantQuestionsByUserID(ctx context.Context, userID string, asOf time.Time) ([]models.ImportantQuestion, error)
 	UpdateImportantQuestion(ctx context.Context, question *models.ImportantQuestion) error
 	DeleteImportantQuestion(ctx context.Context, id string) error
 }
@@ -232,31 +253,64 @@ func NewPGImportantQuestionRepository(db *pgxpool.Pool) *PGImportantQuestionRepo
 	return &PGImportantQuestionRepository{db: db}
 }
 
-// CreateImportantQuestion inserts a new important question into the database.
-func (r *PGImportantQuestionRepository) CreateImportantQuestion(ctx context.Context, question *models.ImportantQuestion) error {
-	query := `
-		INSERT INTO important_questions (
-			id, user_id, subject_id, exam_id, question_text, answer_text, source,
-			unit, topic, marks, frequency_count, is_practiced, last_practiced_at,
-			confidence_level, tags, created_at
-		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16
-		) RETURNING id, created_at
-	`
+// createImportantQuestionQuery inserts an important question; see importantQuestionInsertArgs.
+const createImportantQuestionQuery = `
+	INSERT INTO important_questions (
+		id, user_id, subject_id, exam_id, question_text, answer_text, source,
+		unit, topic, marks, frequency_count, is_practiced, last_practiced_at,
+		confidence_level, ease_factor, repetition_count, interval_days, next_review_at,
+		tags, created_at
+	) VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+		$17, $18, $19, $20
+	)
+`
+
+// importantQuestionInsertArgs assigns a new question its ID, creation time and default ease factor, and
+// returns the arguments of createImportantQuestionQuery.
+func importantQuestionInsertArgs(question *models.ImportantQuestion) []interface{} {
 	question.ID = models.NewUUID()
 	question.CreatedAt = time.Now()
-
-	_, err := r.db.Exec(ctx, query,
+	if question.EaseFactor == 0 {
+		question.EaseFactor = 2.5
+	}
+	return []interface{}{
 		question.ID, question.UserID, question.SubjectID, question.ExamID, question.QuestionText, question.AnswerText, question.Source,
 		question.Unit, question.Topic, question.Marks, question.FrequencyCount, question.IsPracticed, question.LastPracticedAt,
-		question.ConfidenceLevel, question.Tags, question.CreatedAt,
-	)
-	if err != nil {
+		question.ConfidenceLevel, question.EaseFactor, question.RepetitionCount, question.IntervalDays, question.NextReviewAt,
+		question.Tags, question.CreatedAt,
+	}
+}
+
+// CreateImportantQuestion inserts a new important question into the database.
+func (r *PGImportantQuestionRepository) CreateImportantQuestion(ctx context.Context, question *models.ImportantQuestion) error {
+	if _, err := r.db.Exec(ctx, createImportantQuestionQuery, importantQuestionInsertArgs(question)...); err != nil {
 		return fmt.Errorf("failed to create important question: %w", err)
 	}
 	return nil
 }
 
+// CreateImportantQuestions inserts several important questions in a single transaction, so either all
+// of them are added or none are.
+func (r *PGImportantQuestionRepository) CreateImportantQuestions(ctx context.Context, questions []*models.ImportantQuestion) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin important question transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, question := range questions {
+		if _, err := tx.Exec(ctx, createImportantQuestionQuery, importantQuestionInsertArgs(question)...); err != nil {
+			return fmt.Errorf("failed to create important question: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit important question transaction: %w", err)
+	}
+	return nil
+}
+
 // GetImportantQuestionByID retrieves an important question by its ID.
 func (r *PGImportantQuestionRepository) GetImportantQuestionByID(ctx context.Context, id string) (*models.ImportantQuestion, error) {
 	question := &models.ImportantQuestion{}
@@ -264,14 +318,16 @@ func (r *PGImportantQuestionRepository) GetImportantQuestionByID(ctx context.Con
 		SELECT
 			id, user_id, subject_id, exam_id, question_text, answer_text, source,
 			unit, topic, marks, frequency_count, is_practiced, last_practiced_at,
-			confidence_level, tags, created_at
+			confidence_level, ease_factor, repetition_count, interval_days, next_review_at,
+			tags, created_at
 		FROM important_questions
 		WHERE id = $1
 	`
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&question.ID, &question.UserID, &question.SubjectID, &question.ExamID, &question.QuestionText, &question.AnswerText, &question.Source,
 		&question.Unit, &question.Topic, &question.Marks, &question.FrequencyCount, &question.IsPracticed, &question.LastPracticedAt,
-		&question.ConfidenceLevel, &question.Tags, &question.CreatedAt,
+		&question.ConfidenceLevel, &question.EaseFactor, &question.RepetitionCount, &question.IntervalDays, &question.NextReviewAt,
+		&question.Tags, &question.CreatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get important question by ID: %w", err)
@@ -286,7 +342,8 @@ func (r *PGImportantQuestionRepository) GetImportantQuestionsByExamID(ctx contex
 		SELECT
 			id, user_id, subject_id, exam_id, question_text, answer_text, source,
 			unit, topic, marks, frequency_count, is_practiced, last_practiced_at,
-			confidence_level, tags, created_at
+			confidence_level, ease_factor, repetition_count, interval_days, next_review_at,
+			tags, created_at
 		FROM important_questions
 		WHERE exam_id = $1
 		ORDER BY created_at ASC
@@ -302,7 +359,8 @@ func (r *PGImportantQuestionRepository) GetImportantQuestionsByExamID(ctx contex
 		err := rows.Scan(
 			&question.ID, &question.UserID, &question.SubjectID, &question.ExamID, &question.QuestionText, &question.AnswerText, &question.Source,
 			&question.Unit, &question.Topic, &question.Marks, &question.FrequencyCount, &question.IsPracticed, &question.LastPracticedAt,
-			&question.ConfidenceLevel, &question.Tags, &question.CreatedAt,
+			&question.ConfidenceLevel, &question.EaseFactor, &question.RepetitionCount, &question.IntervalDays, &question.NextReviewAt,
+			&question.Tags, &question.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan important question row: %w", err)
@@ -319,7 +377,8 @@ func (r *PGImportantQuestionRepository) GetImportantQuestionsBySubjectID(ctx con
 		SELECT
 			id, user_id, subject_id, exam_id, question_text, answer_text, source,
 			unit, topic, marks, frequency_count, is_practiced, last_practiced_at,
-			confidence_level, tags, created_at
+			confidence_level, ease_factor, repetition_count, interval_days, next_review_at,
+			tags, created_at
 		FROM important_questions
 		WHERE subject_id = $1
 		ORDER BY created_at ASC
@@ -335,7 +394,79 @@ func (r *PGImportantQuestionRepository) GetImportantQuestionsBySubjectID(ctx con
 		err := rows.Scan(
 			&question.ID, &question.UserID, &question.SubjectID, &question.ExamID, &question.QuestionText, &question.AnswerText, &question.Source,
 			&question.Unit, &question.Topic, &question.Marks, &question.FrequencyCount, &question.IsPracticed, &question.LastPracticedAt,
-			&question.ConfidenceLevel, &question.Tags, &question.CreatedAt,
+			&question.ConfidenceLevel, &question.EaseFactor, &question.RepetitionCount, &question.IntervalDays, &question.NextReviewAt,
+			&question.Tags, &question.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan important question row: %w", err)
+		}
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
+
+// GetImportantQuestionsByUserID retrieves all important questions for a given user.
+func (r *PGImportantQuestionRepository) GetImportantQuestionsByUserID(ctx context.Context, userID string) ([]models.ImportantQuestion, error) {
+	var questions []models.ImportantQuestion
+	query := `
+		SELECT
+			id, user_id, subject_id, exam_id, question_text, answer_text, source,
+			unit, topic, marks, frequency_count, is_practiced, last_practiced_at,
+			confidence_level, ease_factor, repetition_count, interval_days, next_review_at,
+			tags, created_at
+		FROM important_questions
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get important questions by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		question := models.ImportantQuestion{}
+		err := rows.Scan(
+			&question.ID, &question.UserID, &question.SubjectID, &question.ExamID, &question.QuestionText, &question.AnswerText, &question.Source,
+			&question.Unit, &question.Topic, &question.Marks, &question.FrequencyCount, &question.IsPracticed, &question.LastPracticedAt,
+			&question.ConfidenceLevel, &question.EaseFactor, &question.RepetitionCount, &question.IntervalDays, &question.NextReviewAt,
+			&question.Tags, &question.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan important question row: %w", err)
+		}
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
+
+// GetDueImportantQuestionsByUserID retrieves a user's questions that have never been reviewed or whose
+// next review is due at or before asOf, most overdue first.
+func (r *PGImportantQuestionRepository) GetDueImportantQuestionsByUserID(ctx context.Context, userID string, asOf time.Time) ([]models.ImportantQuestion, error) {
+	var questions []models.ImportantQuestion
+	query := `
+		SELECT
+			id, user_id, subject_id, exam_id, question_text, answer_text, source,
+			unit, topic, marks, frequency_count, is_practiced, last_practiced_at,
+			confidence_level, ease_factor, repetition_count, interval_days, next_review_at,
+			tags, created_at
+		FROM important_questions
+		WHERE user_id = $1 AND (next_review_at IS NULL OR next_review_at <= $2)
+		ORDER BY next_review_at ASC NULLS FIRST, created_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due important questions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		question := models.ImportantQuestion{}
+		err := rows.Scan(
+			&question.ID, &question.UserID, &question.SubjectID, &question.ExamID, &question.QuestionText, &question.AnswerText, &question.Source,
+			&question.Unit, &question.Topic, &question.Marks, &question.FrequencyCount, &question.IsPracticed, &question.LastPracticedAt,
+			&question.ConfidenceLevel, &question.EaseFactor, &question.RepetitionCount, &question.IntervalDays, &question.NextReviewAt,
+			&question.Tags, &question.CreatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan important question row: %w", err)
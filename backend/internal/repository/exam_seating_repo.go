@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- ExamSeating Repository ---
+
+// ExamSeatingRepository defines the interface for exam seating data operations.
+type ExamSeatingRepository interface {
+	UpsertExamSeating(ctx context.Context, seating *models.ExamSeating) error
+	GetExamSeatingByExamID(ctx context.Context, examID string) (*models.ExamSeating, error)
+	GetExamSeatingsByUserID(ctx context.Context, userID string) ([]models.ExamSeating, error)
+	DeleteExamSeating(ctx context.Context, examID string, userID string) error
+}
+
+// PGExamSeatingRepository implements ExamSeatingRepository for PostgreSQL.
+type PGExamSeatingRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGExamSeatingRepository creates a new PostgreSQL exam seating repository.
+func NewPGExamSeatingRepository(db *pgxpool.Pool) *PGExamSeatingRepository {
+	return &PGExamSeatingRepository{db: db}
+}
+
+// UpsertExamSeating inserts the seating of an exam or replaces the existing one.
+func (r *PGExamSeatingRepository) UpsertExamSeating(ctx context.Context, seating *models.ExamSeating) error {
+	query := `
+		INSERT INTO exam_seatings (
+			id, user_id, exam_id, venue_id, venue_name, room, seat_number, register_number,
+			reporting_time, items_allowed, instructions, source, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+		)
+		ON CONFLICT (exam_id) DO UPDATE SET
+			venue_id = EXCLUDED.venue_id, venue_name = EXCLUDED.venue_name, room = EXCLUDED.room,
+			seat_number = EXCLUDED.seat_number, register_number = EXCLUDED.register_number,
+			reporting_time = EXCLUDED.reporting_time, items_allowed = EXCLUDED.items_allowed,
+			instructions = EXCLUDED.instructions, source = EXCLUDED.source, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at, updated_at
+	`
+	seating.ID = models.NewUUID()
+	seating.CreatedAt = time.Now()
+	seating.UpdatedAt = time.Now()
+
+	err := r.db.QueryRow(ctx, query,
+		seating.ID, seating.UserID, seating.ExamID, seating.VenueID, seating.VenueName, seating.Room, seating.SeatNumber, seating.RegisterNumber,
+		seating.ReportingTime, seating.ItemsAllowed, seating.Instructions, seating.Source, seating.CreatedAt, seating.UpdatedAt,
+	).Scan(&seating.ID, &seating.CreatedAt, &seating.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert exam seating: %w", err)
+	}
+	return nil
+}
+
+// GetExamSeatingByExamID retrieves the seating of an exam.
+func (r *PGExamSeatingRepository) GetExamSeatingByExamID(ctx context.Context, examID string) (*models.ExamSeating, error) {
+	seating := &models.ExamSeating{}
+	query := `
+		SELECT
+			id, user_id, exam_id, venue_id, venue_name, room, seat_number, register_number,
+			reporting_time, items_allowed, instructions, source, created_at, updated_at
+		FROM exam_seatings
+		WHERE exam_id = $1
+	`
+	err := r.db.QueryRow(ctx, query, examID).Scan(
+		&seating.ID, &seating.UserID, &seating.ExamID, &seating.VenueID, &seating.VenueName, &seating.Room, &seating.SeatNumber, &seating.RegisterNumber,
+		&seating.ReportingTime, &seating.ItemsAllowed, &seating.Instructions, &seating.Source, &seating.CreatedAt, &seating.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exam seating by exam ID: %w", err)
+	}
+	return seating, nil
+}
+
+// GetExamSeatingsByUserID retrieves the seatings of all of a user's exams.
+func (r *PGExamSeatingRepository) GetExamSeatingsByUserID(ctx context.Context, userID string) ([]models.ExamSeating, error) {
+	var seatings []models.ExamSeating
+	query := `
+		SELECT
+			id, user_id, exam_id, venue_id, venue_name, room, seat_number, register_number,
+			reporting_time, items_allowed, instructions, source, created_at, updated_at
+		FROM exam_seatings
+		WHERE user_id = $1
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exam seatings by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		seating := models.ExamSeating{}
+		err := rows.Scan(
+			&seating.ID, &seating.UserID, &seating.ExamID, &seating.VenueID, &seating.VenueName, &seating.Room, &seating.SeatNumber, &seating.RegisterNumber,
+			&seating.ReportingTime, &seating.ItemsAllowed, &seating.Instructions, &seating.Source, &seating.CreatedAt, &seating.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan exam seating row: %w", err)
+		}
+		seatings = append(seatings, seating)
+	}
+	return seatings, nil
+}
+
+// DeleteExamSeating deletes the seating of an exam.
+func (r *PGExamSeatingRepository) DeleteExamSeating(ctx context.Context, examID string, userID string) error {
+	query := `DELETE FROM exam_seatings WHERE exam_id = $1 AND user_id = $2`
+	cmdTag, err := r.db.Exec(ctx, query, examID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete exam seating: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("exam seating for exam %s not found or not owned by user", examID)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- Export Repository ---
+
+// ExportRepository defines the interface for streaming a user's history for export. Rows are handed
+// to fn one at a time as they are read, so an export never holds more than one row in memory; an
+// error returned by fn stops the export.
+type ExportRepository interface {
+	StreamDailyStats(ctx context.Context, userID string, fromDate, toDate time.Time, fn func(*models.DailyStats) error) error
+	StreamActivityLogs(ctx context.Context, userID string, start, end time.Time, fn func(*models.ActivityLog) error) error
+	StreamStudySessions(ctx context.Context, userID string, start, end time.Time, fn func(*models.StudySession) error) error
+	StreamGradedMarks(ctx context.Context, userID string, start, end time.Time, timezone string, fn func(*models.GradedMark) error) error
+}
+
+// PGExportRepository implements ExportRepository for PostgreSQL.
+type PGExportRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGExportRepository creates a new PostgreSQL export repository.
+func NewPGExportRepository(db *pgxpool.Pool) *PGExportRepository {
+	return &PGExportRepository{db: db}
+}
+
+// StreamDailyStats streams a user's daily stats between two dates (inclusive), oldest first.
+func (r *PGExportRepository) StreamDailyStats(ctx context.Context, userID string, fromDate, toDate time.Time, fn func(*models.DailyStats) error) error {
+	query := `
+		SELECT
+			id, user_id, stat_date, study_minutes, sessions_completed, topics_covered,
+			assignments_completed, assignments_added, classes_attended, total_classes, xp_earned
+		FROM daily_stats
+		WHERE user_id = $1 AND stat_date BETWEEN $2 AND $3
+		ORDER BY stat_date ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID, fromDate, toDate)
+	if err != nil {
+		return fmt.Errorf("failed to export daily stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		stats := models.DailyStats{}
+		err := rows.Scan(
+			&stats.ID, &stats.UserID, &stats.StatDate, &stats.StudyMinutes, &stats.SessionsCompleted, &stats.TopicsCovered,
+			&stats.AssignmentsCompleted, &stats.AssignmentsAdded, &stats.ClassesAttended, &stats.TotalClasses, &stats.XPEarned,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan daily stats row: %w", err)
+		}
+		if err := fn(&stats); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to export daily stats: %w", err)
+	}
+	return nil
+}
+
+// StreamActivityLogs streams a user's activity logs created in [start, end), oldest first.
+func (r *PGExportRepository) StreamActivityLogs(ctx context.Context, userID string, start, end time.Time, fn func(*models.ActivityLog) error) error {
+	query := `
+		SELECT
+			id, user_id, activity_type, description, entity_type, entity_id,
+			metadata, ip_address, user_agent, created_at
+		FROM activity_logs
+		WHERE user_id = $1 AND created_at >= $2 AND created_at < $3
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to export activity logs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		log := models.ActivityLog{}
+		err := rows.Scan(
+			&log.ID, &log.UserID, &log.ActivityType, &log.Description, &log.EntityType, &log.EntityID,
+			&log.Metadata, &log.IPAddress, &log.UserAgent, &log.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan activity log row: %w", err)
+		}
+		if err := fn(&log); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to export activity logs: %w", err)
+	}
+	return nil
+}
+
+// StreamStudySessions streams a user's study sessions that started, or were planned to start, in
+// [start, end), oldest first. Sessions without either time count from when they were created.
+func (r *PGExportRepository) StreamStudySessions(ctx context.Context, userID string, start, end time.Time, fn func(*models.StudySession) error) error {
+	query := `
+		SELECT
+			id, user_id, study_plan_id, subject_id, planned_start_time, planned_end_time,
+			planned_duration_minutes, actual_start_time, actual_end_time, actual_duration_minutes,
+			session_type, topics_to_cover, topics_covered, status, completion_percentage,
+			productivity_rating, notes, blockers, created_at, updated_at,
+			exam_id, assignment_id, carried_over_from, carried_over_at
+		FROM study_sessions
+		WHERE user_id = $1
+		  AND COALESCE(actual_start_time, planned_start_time, created_at) >= $2
+		  AND COALESCE(actual_start_time, planned_start_time, created_at) < $3
+		ORDER BY COALESCE(actual_start_time, planned_start_time, created_at) ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to export study sessions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		session := models.StudySession{}
+		err := rows.Scan(
+			&session.ID, &session.UserID, &session.StudyPlanID, &session.SubjectID, &session.PlannedStartTime, &session.PlannedEndTime,
+			&session.PlannedDurationMinutes, &session.ActualStartTime, &session.ActualEndTime, &session.ActualDurationMinutes,
+			&session.SessionType, &session.TopicsToCover, &session.TopicsCovered, &session.Status, &session.CompletionPercentage,
+			&session.ProductivityRating, &session.Notes, &session.Blockers, &session.CreatedAt, &session.UpdatedAt,
+			&session.ExamID, &session.AssignmentID, &session.CarriedOverFrom, &session.CarriedOverAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan study session row: %w", err)
+		}
+		if err := fn(&session); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to export study sessions: %w", err)
+	}
+	return nil
+}
+
+// StreamGradedMarks streams a user's graded exams, assignments and lab records graded in [start, end),
+// oldest first. Exam and lab record dates are taken as midnight in the given timezone.
+func (r *PGExportRepository) StreamGradedMarks(ctx context.Context, userID string, start, end time.Time, timezone string, fn func(*models.GradedMark) error) error {
+	query := `
+		SELECT
+			m.source, m.source_id, m.subject_id, s.code, m.title, m.kind,
+			m.max_marks, m.obtained_marks, m.grade, m.graded_on, m.updated_at
+		FROM (
+			SELECT
+				'exam' AS source, id AS source_id, subject_id, title, exam_type AS kind,
+				max_marks, obtained_marks, grade, exam_date::timestamp AT TIME ZONE $4 AS graded_on, updated_at
+			FROM exams
+			WHERE user_id = $1 AND obtained_marks IS NOT NULL
+			UNION ALL
+			SELECT
+				'assignment', id, subject_id, title, assignment_type,
+				max_marks, obtained_marks, NULL, COALESCE(submitted_at, due_date), updated_at
+			FROM assignments
+			WHERE user_id = $1 AND obtained_marks IS NOT NULL
+			UNION ALL
+			SELECT
+				'lab_record', id, subject_id, title, NULL,
+				NULL, marks, NULL, COALESCE(submitted_date, lab_date, created_at::date)::timestamp AT TIME ZONE $4, updated_at
+			FROM lab_records
+			WHERE user_id = $1 AND marks IS NOT NULL
+		) m
+		LEFT JOIN subjects s ON s.id = m.subject_id
+		WHERE m.graded_on >= $2 AND m.graded_on < $3
+		ORDER BY m.graded_on ASC, m.source ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID, start, end, timezone)
+	if err != nil {
+		return fmt.Errorf("failed to export graded marks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		mark := models.GradedMark{}
+		err := rows.Scan(
+			&mark.Source, &mark.SourceID, &mark.SubjectID, &mark.SubjectCode, &mark.Title, &mark.Kind,
+			&mark.MaxMarks, &mark.ObtainedMarks, &mark.Grade, &mark.GradedOn, &mark.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan graded mark row: %w", err)
+		}
+		if err := fn(&mark); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to export graded marks: %w", err)
+	}
+	return nil
+}
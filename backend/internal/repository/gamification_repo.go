@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- Gamification Repository ---
+
+// GamificationRepository defines the interface for XP, level, streak and achievement data operations.
+type GamificationRepository interface {
+	GetXPRules(ctx context.Context) ([]models.XPRule, error)
+	GetXPRule(ctx context.Context, rule string) (*models.XPRule, error)
+	UpdateXPRule(ctx context.Context, rule *models.XPRule) error
+
+	GetUserProgress(ctx context.Context, userID string) (*models.UserProgress, error)
+	RecordXPAward(ctx context.Context, award *models.XPAward, progress *models.UserProgress) (bool, error)
+	GetXPAwardedOnDate(ctx context.Context, userID string, date time.Time, rule string) (int32, error)
+	CountXPAwardsByRule(ctx context.Context, userID string) (map[string]int32, error)
+	GetRecentXPAwards(ctx context.Context, userID string, limit int) ([]models.XPAward, error)
+
+	GetUserAchievements(ctx context.Context, userID string) ([]models.UserAchievement, error)
+	CreateUserAchievement(ctx context.Context, achievement *models.UserAchievement) (bool, error)
+}
+
+// PGGamificationRepository implements GamificationRepository for PostgreSQL.
+type PGGamificationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGGamificationRepository creates a new PostgreSQL gamification repository.
+func NewPGGamificationRepository(db *pgxpool.Pool) *PGGamificationRepository {
+	return &PGGamificationRepository{db: db}
+}
+
+// GetXPRules retrieves every XP rule.
+func (r *PGGamificationRepository) GetXPRules(ctx context.Context) ([]models.XPRule, error) {
+	var rules []models.XPRule
+	query := `
+		SELECT rule, description, xp, daily_cap, is_active, created_at, updated_at
+		FROM xp_rules
+		ORDER BY rule
+	`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get XP rules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		rule := models.XPRule{}
+		err := rows.Scan(&rule.Rule, &rule.Description, &rule.XP, &rule.DailyCap, &rule.IsActive, &rule.CreatedAt, &rule.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan XP rule row: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// GetXPRule retrieves one XP rule.
+func (r *PGGamificationRepository) GetXPRule(ctx context.Context, rule string) (*models.XPRule, error) {
+	xpRule := &models.XPRule{}
+	query := `
+		SELECT rule, description, xp, daily_cap, is_active, created_at, updated_at
+		FROM xp_rules
+		WHERE rule = $1
+	`
+	err := r.db.QueryRow(ctx, query, rule).Scan(
+		&xpRule.Rule, &xpRule.Description, &xpRule.XP, &xpRule.DailyCap, &xpRule.IsActive, &xpRule.CreatedAt, &xpRule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get XP rule: %w", err)
+	}
+	return xpRule, nil
+}
+
+// UpdateXPRule updates the XP, daily cap and active flag of an XP rule.
+func (r *PGGamificationRepository) UpdateXPRule(ctx context.Context, rule *models.XPRule) error {
+	query := `
+		UPDATE xp_rules SET
+			xp = $1, daily_cap = $2, is_active = $3, updated_at = $4
+		WHERE rule = $5
+	`
+	rule.UpdatedAt = time.Now()
+	cmdTag, err := r.db.Exec(ctx, query, rule.XP, rule.DailyCap, rule.IsActive, rule.UpdatedAt, rule.Rule)
+	if err != nil {
+		return fmt.Errorf("failed to update XP rule: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("XP rule %s not found", rule.Rule)
+	}
+	return nil
+}
+
+// GetUserProgress retrieves a user's progress.
+func (r *PGGamificationRepository) GetUserProgress(ctx context.Context, userID string) (*models.UserProgress, error) {
+	progress := &models.UserProgress{}
+	query := `
+		SELECT
+			user_id, total_xp, level, current_streak, longest_streak, last_active_date,
+			freeze_tokens, freezes_used, created_at, updated_at
+		FROM user_progress
+		WHERE user_id = $1
+	`
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&progress.UserID, &progress.TotalXP, &progress.Level, &progress.CurrentStreak, &progress.LongestStreak, &progress.LastActiveDate,
+		&progress.FreezeTokens, &progress.FreezesUsed, &progress.CreatedAt, &progress.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user progress: %w", err)
+	}
+	return progress, nil
+}
+
+// RecordXPAward adds an award to the XP ledger and, in the same transaction, saves the user's updated
+// progress and adds the XP to their daily stats. An award whose source was already rewarded under the
+// rule is skipped, nothing is saved and false is returned.
+func (r *PGGamificationRepository) RecordXPAward(ctx context.Context, award *models.XPAward, progress *models.UserProgress) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	award.ID = models.NewUUID()
+	award.CreatedAt = time.Now()
+	cmdTag, err := tx.Exec(ctx, `
+		INSERT INTO xp_awards (id, user_id, rule, source_key, xp, award_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, rule, source_key) DO NOTHING
+	`, award.ID, award.UserID, award.Rule, award.SourceKey, award.XP, award.AwardDate, award.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create XP award: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	progress.UpdatedAt = time.Now()
+	if progress.CreatedAt.IsZero() {
+		progress.CreatedAt = progress.UpdatedAt
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_progress (
+			user_id, total_xp, level, current_streak, longest_streak, last_active_date,
+			freeze_tokens, freezes_used, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		) ON CONFLICT (user_id) DO UPDATE SET
+			total_xp = EXCLUDED.total_xp,
+			level = EXCLUDED.level,
+			current_streak = EXCLUDED.current_streak,
+			longest_streak = EXCLUDED.longest_streak,
+			last_active_date = EXCLUDED.last_active_date,
+			freeze_tokens = EXCLUDED.freeze_tokens,
+			freezes_used = EXCLUDED.freezes_used,
+			updated_at = EXCLUDED.updated_at
+	`,
+		progress.UserID, progress.TotalXP, progress.Level, progress.CurrentStreak, progress.LongestStreak, progress.LastActiveDate,
+		progress.FreezeTokens, progress.FreezesUsed, progress.CreatedAt, progress.UpdatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to save user progress: %w", err)
+	}
+
+	if award.XP > 0 {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO daily_stats (id, user_id, stat_date, xp_earned)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, stat_date) DO UPDATE SET
+				xp_earned = daily_stats.xp_earned + EXCLUDED.xp_earned
+		`, models.NewUUID(), award.UserID, award.AwardDate, award.XP)
+		if err != nil {
+			return false, fmt.Errorf("failed to add XP to daily stats: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetXPAwardedOnDate sums the XP a user was awarded on a date, under one rule or, with an empty rule,
+// under all of them.
+func (r *PGGamificationRepository) GetXPAwardedOnDate(ctx context.Context, userID string, date time.Time, rule string) (int32, error) {
+	var total int32
+	query := `
+		SELECT COALESCE(SUM(xp), 0)
+		FROM xp_awards
+		WHERE user_id = $1 AND award_date = $2 AND ($3 = '' OR rule = $3)
+	`
+	if err := r.db.QueryRow(ctx, query, userID, date, rule).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to get XP awarded on date: %w", err)
+	}
+	return total, nil
+}
+
+// CountXPAwardsByRule counts a user's rewarded activities per rule, including those past a daily cap.
+func (r *PGGamificationRepository) CountXPAwardsByRule(ctx context.Context, userID string) (map[string]int32, error) {
+	counts := make(map[string]int32)
+	query := `
+		SELECT rule, COUNT(*)
+		FROM xp_awards
+		WHERE user_id = $1
+		GROUP BY rule
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count XP awards: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rule string
+		var count int32
+		if err := rows.Scan(&rule, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan XP award count row: %w", err)
+		}
+		counts[rule] = count
+	}
+	return counts, nil
+}
+
+// GetRecentXPAwards retrieves a user's latest XP awards, newest first.
+func (r *PGGamificationRepository) GetRecentXPAwards(ctx context.Context, userID string, limit int) ([]models.XPAward, error) {
+	var awards []models.XPAward
+	query := `
+		SELECT id, user_id, rule, source_key, xp, award_date, created_at
+		FROM xp_awards
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent XP awards: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		award := models.XPAward{}
+		err := rows.Scan(&award.ID, &award.UserID, &award.Rule, &award.SourceKey, &award.XP, &award.AwardDate, &award.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan XP award row: %w", err)
+		}
+		awards = append(awards, award)
+	}
+	return awards, nil
+}
+
+// GetUserAchievements retrieves the achievements a user has unlocked, oldest first.
+func (r *PGGamificationRepository) GetUserAchievements(ctx context.Context, userID string) ([]models.UserAchievement, error) {
+	var achievements []models.UserAchievement
+	query := `
+		SELECT id, user_id, achievement, unlocked_at
+		FROM user_achievements
+		WHERE user_id = $1
+		ORDER BY unlocked_at
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user achievements: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		achievement := models.UserAchievement{}
+		if err := rows.Scan(&achievement.ID, &achievement.UserID, &achievement.Achievement, &achievement.UnlockedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user achievement row: %w", err)
+		}
+		achievements = append(achievements, achievement)
+	}
+	return achievements, nil
+}
+
+// CreateUserAchievement records an unlocked achievement. It returns false when the user had already
+// unlocked it.
+func (r *PGGamificationRepository) CreateUserAchievement(ctx context.Context, achievement *models.UserAchievement) (bool, error) {
+	query := `
+		INSERT INTO user_achievements (id, user_id, achievement, unlocked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, achievement) DO NOTHING
+	`
+	achievement.ID = models.NewUUID()
+	cmdTag, err := r.db.Exec(ctx, query, achievement.ID, achievement.UserID, achievement.Achievement, achievement.UnlockedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create user achievement: %w", err)
+	}
+	return cmdTag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- Goal Repository ---
+
+// GoalRepository defines the interface for goal data operations.
+type GoalRepository interface {
+	CreateGoal(ctx context.Context, goal *models.Goal) error
+	GetGoalByID(ctx context.Context, id string) (*models.Goal, error)
+	GetGoalsByUserID(ctx context.Context, userID string) ([]models.Goal, error)
+	UpdateGoal(ctx context.Context, goal *models.Goal) error
+	UpdateGoalStatus(ctx context.Context, id, status string) error
+	DeleteGoal(ctx context.Context, id, userID string) error
+
+	RecordGoalResult(ctx context.Context, result *models.GoalResult) (bool, error)
+	GetGoalResultsByGoalID(ctx context.Context, goalID string) ([]models.GoalResult, error)
+
+	SumDailyStudyMinutes(ctx context.Context, userID string, fromDate, toDate time.Time) (int32, error)
+	SumSubjectStudyMinutes(ctx context.Context, userID, subjectID string, start, end time.Time) (int32, error)
+	CountQuestionsPractised(ctx context.Context, userID, subjectID, examID string, start, end time.Time) (int32, error)
+	SumAttendance(ctx context.Context, userID string, fromDate, toDate time.Time) (int32, int32, error)
+	SumSubjectAttendance(ctx context.Context, userID, subjectID string, fromDate, toDate time.Time) (int32, int32, error)
+}
+
+// PGGoalRepository implements GoalRepository for PostgreSQL.
+type PGGoalRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGGoalRepository creates a new PostgreSQL goal repository.
+func NewPGGoalRepository(db *pgxpool.Pool) *PGGoalRepository {
+	return &PGGoalRepository{db: db}
+}
+
+// CreateGoal inserts a new goal into the database.
+func (r *PGGoalRepository) CreateGoal(ctx context.Context, goal *models.Goal) error {
+	query := `
+		INSERT INTO goals (
+			id, user_id, title, metric, target_value, period, subject_id, exam_id,
+			start_date, end_date, status
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		) RETURNING created_at, updated_at
+	`
+	goal.ID = models.NewUUID()
+	err := r.db.QueryRow(ctx, query,
+		goal.ID, goal.UserID, goal.Title, goal.Metric, goal.TargetValue, goal.Period, goal.SubjectID, goal.ExamID,
+		goal.StartDate, goal.EndDate, goal.Status,
+	).Scan(&goal.CreatedAt, &goal.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create goal: %w", err)
+	}
+	return nil
+}
+
+// GetGoalByID retrieves a goal by its ID.
+func (r *PGGoalRepository) GetGoalByID(ctx context.Context, id string) (*models.Goal, error) {
+	goal := &models.Goal{}
+	query := `
+		SELECT id, user_id, title, metric, target_value, period, subject_id, exam_id,
+			start_date, end_date, status, created_at, updated_at
+		FROM goals
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&goal.ID, &goal.UserID, &goal.Title, &goal.Metric, &goal.TargetValue, &goal.Period, &goal.SubjectID, &goal.ExamID,
+		&goal.StartDate, &goal.EndDate, &goal.Status, &goal.CreatedAt, &goal.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal by ID: %w", err)
+	}
+	return goal, nil
+}
+
+// GetGoalsByUserID retrieves all of a user's goals, newest first.
+func (r *PGGoalRepository) GetGoalsByUserID(ctx context.Context, userID string) ([]models.Goal, error) {
+	var goals []models.Goal
+	query := `
+		SELECT id, user_id, title, metric, target_value, period, subject_id, exam_id,
+			start_date, end_date, status, created_at, updated_at
+		FROM goals
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goals by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		goal := models.Goal{}
+		err := rows.Scan(
+			&goal.ID, &goal.UserID, &goal.Title, &goal.Metric, &goal.TargetValue, &goal.Period, &goal.SubjectID, &goal.ExamID,
+			&goal.StartDate, &goal.EndDate, &goal.Status, &goal.CreatedAt, &goal.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan goal row: %w", err)
+		}
+		goals = append(goals, goal)
+	}
+	return goals, nil
+}
+
+// UpdateGoal updates the title, target, end date and status of a goal. Its metric, period and scope are fixed.
+func (r *PGGoalRepository) UpdateGoal(ctx context.Context, goal *models.Goal) error {
+	query := `
+		UPDATE goals SET
+			title = $1, target_value = $2, end_date = $3, status = $4
+		WHERE id = $5 AND user_id = $6
+		RETURNING updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		goal.Title, goal.TargetValue, goal.EndDate, goal.Status, goal.ID, goal.UserID,
+	).Scan(&goal.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update goal: %w", err)
+	}
+	return nil
+}
+
+// UpdateGoalStatus sets the status of a goal.
+func (r *PGGoalRepository) UpdateGoalStatus(ctx context.Context, id, status string) error {
+	query := `UPDATE goals SET status = $1 WHERE id = $2`
+	result, err := r.db.Exec(ctx, query, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update goal status: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("goal with ID %s not found", id)
+	}
+	return nil
+}
+
+// DeleteGoal deletes a goal and its results.
+func (r *PGGoalRepository) DeleteGoal(ctx context.Context, id, userID string) error {
+	query := `DELETE FROM goals WHERE id = $1 AND user_id = $2`
+	result, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("goal with ID %s not found or not owned by user", id)
+	}
+	return nil
+}
+
+// RecordGoalResult stores the result of one of a goal's periods. It returns false, leaving the stored
+// result unchanged, when the period was already recorded.
+func (r *PGGoalRepository) RecordGoalResult(ctx context.Context, result *models.GoalResult) (bool, error) {
+	query := `
+		INSERT INTO goal_results (
+			id, goal_id, user_id, period_start, period_end, target_value, actual_value, achieved
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		) ON CONFLICT (goal_id, period_start) DO NOTHING
+	`
+	result.ID = models.NewUUID()
+	tag, err := r.db.Exec(ctx, query,
+		result.ID, result.GoalID, result.UserID, result.PeriodStart, result.PeriodEnd,
+		result.TargetValue, result.ActualValue, result.Achieved,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record goal result: %w", err)
+	}
+	result.RecordedAt = time.Now()
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetGoalResultsByGoalID retrieves the recorded results of a goal, newest period first.
+func (r *PGGoalRepository) GetGoalResultsByGoalID(ctx context.Context, goalID string) ([]models.GoalResult, error) {
+	var results []models.GoalResult
+	query := `
+		SELECT id, goal_id, user_id, period_start, period_end, target_value, actual_value, achieved, recorded_at
+		FROM goal_results
+		WHERE goal_id = $1
+		ORDER BY period_start DESC
+	`
+	rows, err := r.db.Query(ctx, query, goalID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get goal results: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		result := models.GoalResult{}
+		err := rows.Scan(
+			&result.ID, &result.GoalID, &result.UserID, &result.PeriodStart, &result.PeriodEnd,
+			&result.TargetValue, &result.ActualValue, &result.Achieved, &result.RecordedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan goal result row: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// SumDailyStudyMinutes sums a user's study minutes from their daily stats between two dates, inclusive.
+func (r *PGGoalRepository) SumDailyStudyMinutes(ctx context.Context, userID string, fromDate, toDate time.Time) (int32, error) {
+	var minutes int32
+	query := `
+		SELECT COALESCE(SUM(study_minutes), 0)::INT
+		FROM daily_stats
+		WHERE user_id = $1 AND stat_date BETWEEN $2 AND $3
+	`
+	if err := r.db.QueryRow(ctx, query, userID, fromDate, toDate).Scan(&minutes); err != nil {
+		return 0, fmt.Errorf("failed to sum study minutes: %w", err)
+	}
+	return minutes, nil
+}
+
+// SumSubjectStudyMinutes sums a user's study minutes on one subject in [start, end), counted the same way
+// as the study minutes of the daily stats: stopped timers on the subject's assignments, exams and study
+// sessions (split at the bounds), and pomodoro focus time in the subject's study sessions.
+func (r *PGGoalRepository) SumSubjectStudyMinutes(ctx context.Context, userID, subjectID string, start, end time.Time) (int32, error) {
+	var minutes int32
+	query := `
+		SELECT
+			COALESCE((
+				SELECT SUM(ROUND(EXTRACT(EPOCH FROM LEAST(te.ended_at, $4) - GREATEST(te.started_at, $3)) / 60))
+				FROM time_entries te
+				WHERE te.user_id = $1 AND te.ended_at IS NOT NULL AND te.started_at < $4 AND te.ended_at > $3
+					AND (
+						(te.entity_type = 'assignment' AND te.entity_id IN (SELECT id FROM assignments WHERE subject_id = $2))
+						OR (te.entity_type = 'exam' AND te.entity_id IN (SELECT id FROM exams WHERE subject_id = $2))
+						OR (te.entity_type = 'study_session' AND te.entity_id IN (SELECT id FROM study_sessions WHERE subject_id = $2))
+					)
+			), 0)::INT + COALESCE((
+				SELECT SUM(ROUND(ps.focus_seconds / 60.0))
+				FROM pomodoro_sessions ps
+				JOIN study_sessions ss ON ss.id = ps.study_session_id
+				WHERE ps.user_id = $1 AND ss.subject_id = $2 AND ps.started_at >= $3 AND ps.started_at < $4
+			), 0)::INT
+	`
+	if err := r.db.QueryRow(ctx, query, userID, subjectID, start, end).Scan(&minutes); err != nil {
+		return 0, fmt.Errorf("failed to sum subject study minutes: %w", err)
+	}
+	return minutes, nil
+}
+
+// CountQuestionsPractised counts the distinct important questions a user practised in [start, end), through
+// a review or by marking them practised, optionally only those of one subject or linked to one exam.
+func (r *PGGoalRepository) CountQuestionsPractised(ctx context.Context, userID, subjectID, examID string, start, end time.Time) (int32, error) {
+	var count int32
+	query := `
+		SELECT COUNT(*)::INT
+		FROM important_questions iq
+		WHERE iq.user_id = $1
+			AND ($2 = '' OR iq.subject_id::TEXT = $2)
+			AND ($3 = '' OR iq.exam_id::TEXT = $3)
+			AND (
+				(iq.last_practiced_at >= $4 AND iq.last_practiced_at < $5)
+				OR EXISTS (
+					SELECT 1 FROM question_reviews qr
+					WHERE qr.question_id = iq.id AND qr.reviewed_at >= $4 AND qr.reviewed_at < $5
+				)
+			)
+	`
+	if err := r.db.QueryRow(ctx, query, userID, subjectID, examID, start, end).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count practised questions: %w", err)
+	}
+	return count, nil
+}
+
+// SumAttendance sums the classes a user attended and the classes they had from their daily stats between
+// two dates, inclusive.
+func (r *PGGoalRepository) SumAttendance(ctx context.Context, userID string, fromDate, toDate time.Time) (int32, int32, error) {
+	var attended, total int32
+	query := `
+		SELECT COALESCE(SUM(classes_attended), 0)::INT, COALESCE(SUM(total_classes), 0)::INT
+		FROM daily_stats
+		WHERE user_id = $1 AND stat_date BETWEEN $2 AND $3
+	`
+	if err := r.db.QueryRow(ctx, query, userID, fromDate, toDate).Scan(&attended, &total); err != nil {
+		return 0, 0, fmt.Errorf("failed to sum attendance: %w", err)
+	}
+	return attended, total, nil
+}
+
+// SumSubjectAttendance counts the classes of one subject a user attended and the classes of it on their
+// timetable between two dates, inclusive. Classes are the subject's active lectures, labs and tutorials
+// for the user's batch, as in the daily stats.
+func (r *PGGoalRepository) SumSubjectAttendance(ctx context.Context, userID, subjectID string, fromDate, toDate time.Time) (int32, int32, error) {
+	var attended, total int32
+	query := `
+		SELECT
+			(
+				SELECT COUNT(*)::INT
+				FROM class_attendance ca
+				JOIN timetable_slots ts ON ts.id = ca.slot_id
+				JOIN users u ON u.id = ts.user_id
+				WHERE ca.user_id = $1 AND ts.subject_id = $2 AND ca.attended
+					AND ca.class_date BETWEEN $3 AND $4
+					AND ts.is_active = true
+					AND ts.slot_type IN ('lecture', 'lab', 'tutorial')
+					AND (ts.batch_filter IS NULL OR ts.batch_filter = u.batch)
+			),
+			(
+				SELECT COUNT(*)::INT
+				FROM generate_series($3::date, $4::date, INTERVAL '1 day') AS day
+				JOIN timetable_slots ts ON ts.user_id = $1 AND ts.subject_id = $2
+				JOIN users u ON u.id = ts.user_id
+				WHERE ts.is_active = true
+					AND ts.slot_type IN ('lecture', 'lab', 'tutorial')
+					AND (
+						(COALESCE(ts.is_recurring, true) AND ts.day_of_week = EXTRACT(DOW FROM day)::INT)
+						OR (NOT COALESCE(ts.is_recurring, true) AND ts.specific_date = day::date)
+					)
+					AND (ts.batch_filter IS NULL OR ts.batch_filter = u.batch)
+			)
+	`
+	if err := r.db.QueryRow(ctx, query, userID, subjectID, fromDate, toDate).Scan(&attended, &total); err != nil {
+		return 0, 0, fmt.Errorf("failed to sum subject attendance: %w", err)
+	}
+	return attended, total, nil
+}
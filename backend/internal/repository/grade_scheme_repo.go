@@ -0,0 +1,242 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- GradeScheme Repository ---
+
+// GradeSchemeRepository defines the interface for grade scheme data operations.
+type GradeSchemeRepository interface {
+	CreateGradeScheme(ctx context.Context, scheme *models.GradeScheme) error
+	GetGradeSchemeByID(ctx context.Context, id string) (*models.GradeScheme, error)
+	GetGradeSchemesByUserID(ctx context.Context, userID string) ([]models.GradeScheme, error)
+	UpdateGradeScheme(ctx context.Context, scheme *models.GradeScheme) error
+	DeleteGradeScheme(ctx context.Context, id string, userID string) error
+}
+
+// PGGradeSchemeRepository implements GradeSchemeRepository for PostgreSQL.
+type PGGradeSchemeRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGGradeSchemeRepository creates a new PostgreSQL grade scheme repository.
+func NewPGGradeSchemeRepository(db *pgxpool.Pool) *PGGradeSchemeRepository {
+	return &PGGradeSchemeRepository{db: db}
+}
+
+// CreateGradeScheme inserts a new grade scheme and its components in a single transaction.
+func (r *PGGradeSchemeRepository) CreateGradeScheme(ctx context.Context, scheme *models.GradeScheme) error {
+	query := `
+		INSERT INTO grade_schemes (
+			id, user_id, subject_id, name, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)
+	`
+	scheme.ID = models.NewUUID()
+	scheme.CreatedAt = time.Now()
+	scheme.UpdatedAt = time.Now()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, query,
+		scheme.ID, scheme.UserID, scheme.SubjectID, scheme.Name, scheme.CreatedAt, scheme.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create grade scheme: %w", err)
+	}
+	if err := insertGradeSchemeComponents(ctx, tx, scheme); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit grade scheme: %w", err)
+	}
+	return nil
+}
+
+// GetGradeSchemeByID retrieves a grade scheme and its components by ID.
+func (r *PGGradeSchemeRepository) GetGradeSchemeByID(ctx context.Context, id string) (*models.GradeScheme, error) {
+	scheme := &models.GradeScheme{}
+	query := `
+		SELECT
+			id, user_id, subject_id, name, created_at, updated_at
+		FROM grade_schemes
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&scheme.ID, &scheme.UserID, &scheme.SubjectID, &scheme.Name, &scheme.CreatedAt, &scheme.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grade scheme by ID: %w", err)
+	}
+
+	components, err := r.getComponentsBySchemeIDs(ctx, []string{scheme.ID})
+	if err != nil {
+		return nil, err
+	}
+	scheme.Components = components[scheme.ID]
+	return scheme, nil
+}
+
+// GetGradeSchemesByUserID retrieves all grade schemes and their components for a given user.
+func (r *PGGradeSchemeRepository) GetGradeSchemesByUserID(ctx context.Context, userID string) ([]models.GradeScheme, error) {
+	var schemes []models.GradeScheme
+	query := `
+		SELECT
+			id, user_id, subject_id, name, created_at, updated_at
+		FROM grade_schemes
+		WHERE user_id = $1
+		ORDER BY subject_id NULLS FIRST, name ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grade schemes by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		scheme := models.GradeScheme{}
+		err := rows.Scan(
+			&scheme.ID, &scheme.UserID, &scheme.SubjectID, &scheme.Name, &scheme.CreatedAt, &scheme.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan grade scheme row: %w", err)
+		}
+		schemes = append(schemes, scheme)
+		ids = append(ids, scheme.ID)
+	}
+	rows.Close()
+
+	if len(ids) == 0 {
+		return schemes, nil
+	}
+	components, err := r.getComponentsBySchemeIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range schemes {
+		schemes[i].Components = components[schemes[i].ID]
+	}
+	return schemes, nil
+}
+
+// UpdateGradeScheme updates a grade scheme and replaces its components in a single transaction.
+func (r *PGGradeSchemeRepository) UpdateGradeScheme(ctx context.Context, scheme *models.GradeScheme) error {
+	query := `
+		UPDATE grade_schemes SET
+			subject_id = $1, name = $2, updated_at = $3
+		WHERE id = $4 AND user_id = $5
+	`
+	scheme.UpdatedAt = time.Now()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	cmdTag, err := tx.Exec(ctx, query,
+		scheme.SubjectID, scheme.Name, scheme.UpdatedAt,
+		scheme.ID, scheme.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update grade scheme: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("grade scheme with ID %s not found or not owned by user", scheme.ID)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM grade_scheme_components WHERE scheme_id = $1`, scheme.ID); err != nil {
+		return fmt.Errorf("failed to clear grade scheme components: %w", err)
+	}
+	if err := insertGradeSchemeComponents(ctx, tx, scheme); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit grade scheme: %w", err)
+	}
+	return nil
+}
+
+// DeleteGradeScheme deletes a grade scheme. Its components are removed by cascade.
+func (r *PGGradeSchemeRepository) DeleteGradeScheme(ctx context.Context, id string, userID string) error {
+	query := `DELETE FROM grade_schemes WHERE id = $1 AND user_id = $2`
+	cmdTag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete grade scheme: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("grade scheme with ID %s not found or not owned by user", id)
+	}
+	return nil
+}
+
+// getComponentsBySchemeIDs retrieves the components of the given schemes, keyed by scheme ID.
+func (r *PGGradeSchemeRepository) getComponentsBySchemeIDs(ctx context.Context, schemeIDs []string) (map[string][]models.GradeSchemeComponent, error) {
+	components := make(map[string][]models.GradeSchemeComponent)
+	query := `
+		SELECT
+			id, scheme_id, name, source_type, exam_type, weight, item_max_marks, is_final, sort_order
+		FROM grade_scheme_components
+		WHERE scheme_id::text = ANY($1)
+		ORDER BY sort_order ASC
+	`
+	rows, err := r.db.Query(ctx, query, schemeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grade scheme components: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		component := models.GradeSchemeComponent{}
+		err := rows.Scan(
+			&component.ID, &component.SchemeID, &component.Name, &component.SourceType, &component.ExamType,
+			&component.Weight, &component.ItemMaxMarks, &component.IsFinal, &component.SortOrder,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan grade scheme component row: %w", err)
+		}
+		components[component.SchemeID] = append(components[component.SchemeID], component)
+	}
+	return components, nil
+}
+
+// insertGradeSchemeComponents inserts the components of a scheme within an open transaction.
+func insertGradeSchemeComponents(ctx context.Context, tx pgx.Tx, scheme *models.GradeScheme) error {
+	query := `
+		INSERT INTO grade_scheme_components (
+			id, scheme_id, name, source_type, exam_type, weight, item_max_marks, is_final, sort_order
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`
+	for i := range scheme.Components {
+		component := &scheme.Components[i]
+		component.ID = models.NewUUID()
+		component.SchemeID = scheme.ID
+		component.SortOrder = int32(i)
+
+		_, err := tx.Exec(ctx, query,
+			component.ID, component.SchemeID, component.Name, component.SourceType, component.ExamType,
+			component.Weight, component.ItemMaxMarks, component.IsFinal, component.SortOrder,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create grade scheme component: %w", err)
+		}
+	}
+	return nil
+}
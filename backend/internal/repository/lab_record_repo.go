@@ -39,10 +39,10 @@ func (r *PGLabRecordRepository) CreateLabRecord(ctx context.Context, record *mod
 			id, user_id, subject_id, experiment_number, title, lab_date, record_written_date,
 			submitted_date, status, aim, algorithm, code, output, observations, result,
 			viva_questions, print_required, pages_to_print, printed_at, marks,
-			staff_remarks, created_at, updated_at
+			staff_remarks, tags, created_at, updated_at
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
-			$17, $18, $19, $20, $21, $22, $23
+			$17, $18, $19, $20, $21, $22, $23, $24
 		) RETURNING id, created_at, updated_at
 	`
 	record.ID = models.NewUUID()
@@ -53,7 +53,7 @@ func (r *PGLabRecordRepository) CreateLabRecord(ctx context.Context, record *mod
 		record.ID, record.UserID, record.SubjectID, record.ExperimentNumber, record.Title, record.LabDate, record.RecordWrittenDate,
 		record.SubmittedDate, record.Status, record.Aim, record.Algorithm, record.Code, record.Output, record.Observations, record.Result,
 		record.VivaQuestions, record.PrintRequired, record.PagesToPrint, record.PrintedAt, record.Marks,
-		record.StaffRemarks, record.CreatedAt, record.UpdatedAt,
+		record.StaffRemarks, record.Tags, record.CreatedAt, record.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create lab record: %w", err)
@@ -69,7 +69,7 @@ func (r *PGLabRecordRepository) GetLabRecordByID(ctx context.Context, id string)
 			id, user_id, subject_id, experiment_number, title, lab_date, record_written_date,
 			submitted_date, status, aim, algorithm, code, output, observations, result,
 			viva_questions, print_required, pages_to_print, printed_at, marks,
-			staff_remarks, created_at, updated_at
+			staff_remarks, tags, created_at, updated_at
 		FROM lab_records
 		WHERE id = $1
 	`
@@ -77,7 +77,7 @@ func (r *PGLabRecordRepository) GetLabRecordByID(ctx context.Context, id string)
 		&record.ID, &record.UserID, &record.SubjectID, &record.ExperimentNumber, &record.Title, &record.LabDate, &record.RecordWrittenDate,
 		&record.SubmittedDate, &record.Status, &record.Aim, &record.Algorithm, &record.Code, &record.Output, &record.Observations, &record.Result,
 		&record.VivaQuestions, &record.PrintRequired, &record.PagesToPrint, &record.PrintedAt, &record.Marks,
-		&record.StaffRemarks, &record.CreatedAt, &record.UpdatedAt,
+		&record.StaffRemarks, &record.Tags, &record.CreatedAt, &record.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get lab record by ID: %w", err)
@@ -93,7 +93,7 @@ func (r *PGLabRecordRepository) GetLabRecordsByUserID(ctx context.Context, userI
 			id, user_id, subject_id, experiment_number, title, lab_date, record_written_date,
 			submitted_date, status, aim, algorithm, code, output, observations, result,
 			viva_questions, print_required, pages_to_print, printed_at, marks,
-			staff_remarks, created_at, updated_at
+			staff_remarks, tags, created_at, updated_at
 		FROM lab_records
 		WHERE user_id = $1
 		ORDER BY lab_date DESC, experiment_number ASC
@@ -110,7 +110,7 @@ func (r *PGLabRecordRepository) GetLabRecordsByUserID(ctx context.Context, userI
 			&record.ID, &record.UserID, &record.SubjectID, &record.ExperimentNumber, &record.Title, &record.LabDate, &record.RecordWrittenDate,
 			&record.SubmittedDate, &record.Status, &record.Aim, &record.Algorithm, &record.Code, &record.Output, &record.Observations, &record.Result,
 			&record.VivaQuestions, &record.PrintRequired, &record.PagesToPrint, &record.PrintedAt, &record.Marks,
-			&record.StaffRemarks, &record.CreatedAt, &record.UpdatedAt,
+			&record.StaffRemarks, &record.Tags, &record.CreatedAt, &record.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan lab record row: %w", err)
@@ -128,7 +128,7 @@ func (r *PGLabRecordRepository) GetLabRecordsByUserIDAndSubjectID(ctx context.Co
 			id, user_id, subject_id, experiment_number, title, lab_date, record_written_date,
 			submitted_date, status, aim, algorithm, code, output, observations, result,
 			viva_questions, print_required, pages_to_print, printed_at, marks,
-			staff_remarks, created_at, updated_at
+			staff_remarks, tags, created_at, updated_at
 		FROM lab_records
 		WHERE user_id = $1 AND subject_id = $2
 		ORDER BY experiment_number ASC
@@ -145,7 +145,7 @@ func (r *PGLabRecordRepository) GetLabRecordsByUserIDAndSubjectID(ctx context.Co
 			&record.ID, &record.UserID, &record.SubjectID, &record.ExperimentNumber, &record.Title, &record.LabDate, &record.RecordWrittenDate,
 			&record.SubmittedDate, &record.Status, &record.Aim, &record.Algorithm, &record.Code, &record.Output, &record.Observations, &record.Result,
 			&record.VivaQuestions, &record.PrintRequired, &record.PagesToPrint, &record.PrintedAt, &record.Marks,
-			&record.StaffRemarks, &record.CreatedAt, &record.UpdatedAt,
+			&record.StaffRemarks, &record.Tags, &record.CreatedAt, &record.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan lab record row: %w", err)
@@ -163,8 +163,8 @@ func (r *PGLabRecordRepository) UpdateLabRecord(ctx context.Context, record *mod
 			record_written_date = $5, submitted_date = $6, status = $7, aim = $8,
 			algorithm = $9, code = $10, output = $11, observations = $12, result = $13,
 			viva_questions = $14, print_required = $15, pages_to_print = $16, printed_at = $17,
-			marks = $18, staff_remarks = $19, updated_at = $20
-		WHERE id = $21 AND user_id = $22
+			marks = $18, staff_remarks = $19, tags = $20, updated_at = $21
+		WHERE id = $22 AND user_id = $23
 	`
 	record.UpdatedAt = time.Now()
 
@@ -173,7 +173,7 @@ func (r *PGLabRecordRepository) UpdateLabRecord(ctx context.Context, record *mod
 		record.RecordWrittenDate, record.SubmittedDate, record.Status, record.Aim,
 		record.Algorithm, record.Code, record.Output, record.Observations, record.Result,
 		record.VivaQuestions, record.PrintRequired, record.PagesToPrint, record.PrintedAt,
-		record.Marks, record.StaffRemarks, record.UpdatedAt,
+		record.Marks, record.StaffRemarks, record.Tags, record.UpdatedAt,
 		record.ID, record.UserID,
 	)
 	if err != nil {
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- Notification Repository ---
+
+// NotificationRepository defines the interface for notification data operations.
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, notification *models.Notification) (bool, error)
+	GetNotificationsByUserID(ctx context.Context, userID string, unreadOnly bool) ([]models.Notification, error)
+	MarkNotificationRead(ctx context.Context, id string, userID string) error
+	MarkAllNotificationsRead(ctx context.Context, userID string) error
+	DeleteNotification(ctx context.Context, id string, userID string) error
+}
+
+// PGNotificationRepository implements NotificationRepository for PostgreSQL.
+type PGNotificationRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGNotificationRepository creates a new PostgreSQL notification repository.
+func NewPGNotificationRepository(db *pgxpool.Pool) *PGNotificationRepository {
+	return &PGNotificationRepository{db: db}
+}
+
+// CreateNotification inserts a new notification. A notification whose dedupe key already exists for
+// the user is skipped, in which case false is returned.
+func (r *PGNotificationRepository) CreateNotification(ctx context.Context, notification *models.Notification) (bool, error) {
+	query := `
+		INSERT INTO notifications (
+			id, user_id, notification_type, title, message, entity_type, entity_id,
+			dedupe_key, is_read, read_at, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)
+		ON CONFLICT (user_id, dedupe_key) DO NOTHING
+	`
+	notification.ID = models.NewUUID()
+	notification.CreatedAt = time.Now()
+
+	cmdTag, err := r.db.Exec(ctx, query,
+		notification.ID, notification.UserID, notification.NotificationType, notification.Title, notification.Message,
+		notification.EntityType, notification.EntityID, notification.DedupeKey, notification.IsRead, notification.ReadAt,
+		notification.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create notification: %w", err)
+	}
+	return cmdTag.RowsAffected() > 0, nil
+}
+
+// GetNotificationsByUserID retrieves notifications for a given user, newest first.
+func (r *PGNotificationRepository) GetNotificationsByUserID(ctx context.Context, userID string, unreadOnly bool) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := `
+		SELECT
+			id, user_id, notification_type, title, message, entity_type, entity_id,
+			dedupe_key, is_read, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND ($2 = FALSE OR is_read = FALSE)
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID, unreadOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		notification := models.Notification{}
+		err := rows.Scan(
+			&notification.ID, &notification.UserID, &notification.NotificationType, &notification.Title, &notification.Message,
+			&notification.EntityType, &notification.EntityID, &notification.DedupeKey, &notification.IsRead, &notification.ReadAt,
+			&notification.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification row: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, nil
+}
+
+// MarkNotificationRead marks a single notification as read.
+func (r *PGNotificationRepository) MarkNotificationRead(ctx context.Context, id string, userID string) error {
+	query := `
+		UPDATE notifications SET
+			is_read = TRUE, read_at = $1
+		WHERE id = $2 AND user_id = $3
+	`
+	cmdTag, err := r.db.Exec(ctx, query, time.Now(), id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("notification with ID %s not found or not owned by user", id)
+	}
+	return nil
+}
+
+// MarkAllNotificationsRead marks all of a user's unread notifications as read.
+func (r *PGNotificationRepository) MarkAllNotificationsRead(ctx context.Context, userID string) error {
+	query := `
+		UPDATE notifications SET
+			is_read = TRUE, read_at = $1
+		WHERE user_id = $2 AND is_read = FALSE
+	`
+	_, err := r.db.Exec(ctx, query, time.Now(), userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return nil
+}
+
+// DeleteNotification deletes a notification from the database.
+func (r *PGNotificationRepository) DeleteNotification(ctx context.Context, id string, userID string) error {
+	query := `DELETE FROM notifications WHERE id = $1 AND user_id = $2`
+	cmdTag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("notification with ID %s not found or not owned by user", id)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- Pomodoro Repository ---
+
+// PomodoroRepository defines the interface for pomodoro data operations.
+type PomodoroRepository interface {
+	CreatePomodoro(ctx context.Context, pomodoro *models.PomodoroSession) error
+	GetActivePomodoroByUserID(ctx context.Context, userID string) (*models.PomodoroSession, error)
+	GetPomodorosByStudySessionID(ctx context.Context, studySessionID string) ([]models.PomodoroSession, error)
+	UpdatePomodoro(ctx context.Context, pomodoro, prev *models.PomodoroSession) (bool, error)
+	CreatePomodoroInterruption(ctx context.Context, interruption *models.PomodoroInterruption) error
+	GetPomodoroInterruptions(ctx context.Context, pomodoroID string) ([]models.PomodoroInterruption, error)
+}
+
+// PGPomodoroRepository implements PomodoroRepository for PostgreSQL.
+type PGPomodoroRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGPomodoroRepository creates a new PostgreSQL pomodoro repository.
+func NewPGPomodoroRepository(db *pgxpool.Pool) *PGPomodoroRepository {
+	return &PGPomodoroRepository{db: db}
+}
+
+// CreatePomodoro inserts a new pomodoro into the database.
+func (r *PGPomodoroRepository) CreatePomodoro(ctx context.Context, pomodoro *models.PomodoroSession) error {
+	query := `
+		INSERT INTO pomodoro_sessions (
+			id, user_id, study_session_id, work_minutes, short_break_minutes, long_break_minutes,
+			cycles_before_long_break, target_cycles, phase, status, completed_cycles,
+			phase_started_at, phase_elapsed_seconds, focus_seconds, interruption_count,
+			started_at, paused_at, ended_at, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+		)
+	`
+	pomodoro.ID = models.NewUUID()
+	pomodoro.PhaseStartedAt = pomodoro.PhaseStartedAt.Truncate(time.Microsecond)
+	pomodoro.CreatedAt = time.Now()
+	pomodoro.UpdatedAt = time.Now()
+
+	_, err := r.db.Exec(ctx, query,
+		pomodoro.ID, pomodoro.UserID, pomodoro.StudySessionID, pomodoro.WorkMinutes, pomodoro.ShortBreakMinutes, pomodoro.LongBreakMinutes,
+		pomodoro.CyclesBeforeLongBreak, pomodoro.TargetCycles, pomodoro.Phase, pomodoro.Status, pomodoro.CompletedCycles,
+		pomodoro.PhaseStartedAt, pomodoro.PhaseElapsedSeconds, pomodoro.FocusSeconds, pomodoro.InterruptionCount,
+		pomodoro.StartedAt, pomodoro.PausedAt, pomodoro.EndedAt, pomodoro.CreatedAt, pomodoro.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pomodoro: %w", err)
+	}
+	return nil
+}
+
+// GetActivePomodoroByUserID retrieves the running or paused pomodoro for a user.
+func (r *PGPomodoroRepository) GetActivePomodoroByUserID(ctx context.Context, userID string) (*models.PomodoroSession, error) {
+	pomodoro := &models.PomodoroSession{}
+	query := `
+		SELECT
+			id, user_id, study_session_id, work_minutes, short_break_minutes, long_break_minutes,
+			cycles_before_long_break, target_cycles, phase, status, completed_cycles,
+			phase_started_at, phase_elapsed_seconds, focus_seconds, interruption_count,
+			started_at, paused_at, ended_at, created_at, updated_at
+		FROM pomodoro_sessions
+		WHERE user_id = $1 AND status IN ('running', 'paused')
+	`
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&pomodoro.ID, &pomodoro.UserID, &pomodoro.StudySessionID, &pomodoro.WorkMinutes, &pomodoro.ShortBreakMinutes, &pomodoro.LongBreakMinutes,
+		&pomodoro.CyclesBeforeLongBreak, &pomodoro.TargetCycles, &pomodoro.Phase, &pomodoro.Status, &pomodoro.CompletedCycles,
+		&pomodoro.PhaseStartedAt, &pomodoro.PhaseElapsedSeconds, &pomodoro.FocusSeconds, &pomodoro.InterruptionCount,
+		&pomodoro.StartedAt, &pomodoro.PausedAt, &pomodoro.EndedAt, &pomodoro.CreatedAt, &pomodoro.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active pomodoro: %w", err)
+	}
+	return pomodoro, nil
+}
+
+// GetPomodorosByStudySessionID retrieves all pomodoros run on a study session, newest first.
+func (r *PGPomodoroRepository) GetPomodorosByStudySessionID(ctx context.Context, studySessionID string) ([]models.PomodoroSession, error) {
+	var pomodoros []models.PomodoroSession
+	query := `
+		SELECT
+			id, user_id, study_session_id, work_minutes, short_break_minutes, long_break_minutes,
+			cycles_before_long_break, target_cycles, phase, status, completed_cycles,
+			phase_started_at, phase_elapsed_seconds, focus_seconds, interruption_count,
+			started_at, paused_at, ended_at, created_at, updated_at
+		FROM pomodoro_sessions
+		WHERE study_session_id = $1
+		ORDER BY started_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, studySessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pomodoros by study session ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		pomodoro := models.PomodoroSession{}
+		err := rows.Scan(
+			&pomodoro.ID, &pomodoro.UserID, &pomodoro.StudySessionID, &pomodoro.WorkMinutes, &pomodoro.ShortBreakMinutes, &pomodoro.LongBreakMinutes,
+			&pomodoro.CyclesBeforeLongBreak, &pomodoro.TargetCycles, &pomodoro.Phase, &pomodoro.Status, &pomodoro.CompletedCycles,
+			&pomodoro.PhaseStartedAt, &pomodoro.PhaseElapsedSeconds, &pomodoro.FocusSeconds, &pomodoro.InterruptionCount,
+			&pomodoro.StartedAt, &pomodoro.PausedAt, &pomodoro.EndedAt, &pomodoro.CreatedAt, &pomodoro.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pomodoro row: %w", err)
+		}
+		pomodoros = append(pomodoros, pomodoro)
+	}
+	return pomodoros, nil
+}
+
+// UpdatePomodoro saves the state of a pomodoro, provided it is still in the state prev it was read in.
+// It returns false when another request changed the pomodoro in the meantime.
+func (r *PGPomodoroRepository) UpdatePomodoro(ctx context.Context, pomodoro, prev *models.PomodoroSession) (bool, error) {
+	query := `
+		UPDATE pomodoro_sessions SET
+			phase = $1, status = $2, completed_cycles = $3, phase_started_at = $4, phase_elapsed_seconds = $5,
+			focus_seconds = $6, interruption_count = $7, paused_at = $8, ended_at = $9, updated_at = $10
+		WHERE id = $11 AND user_id = $12
+			AND status = $13 AND phase = $14 AND phase_started_at = $15 AND completed_cycles = $16 AND interruption_count = $17
+	`
+	// Postgres keeps microseconds, so the next update has to compare against the value as stored.
+	pomodoro.PhaseStartedAt = pomodoro.PhaseStartedAt.Truncate(time.Microsecond)
+	pomodoro.UpdatedAt = time.Now()
+
+	cmdTag, err := r.db.Exec(ctx, query,
+		pomodoro.Phase, pomodoro.Status, pomodoro.CompletedCycles, pomodoro.PhaseStartedAt, pomodoro.PhaseElapsedSeconds,
+		pomodoro.FocusSeconds, pomodoro.InterruptionCount, pomodoro.PausedAt, pomodoro.EndedAt, pomodoro.UpdatedAt,
+		pomodoro.ID, pomodoro.UserID,
+		prev.Status, prev.Phase, prev.PhaseStartedAt, prev.CompletedCycles, prev.InterruptionCount,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to update pomodoro: %w", err)
+	}
+	return cmdTag.RowsAffected() > 0, nil
+}
+
+// CreatePomodoroInterruption inserts a new interruption into the database.
+func (r *PGPomodoroRepository) CreatePomodoroInterruption(ctx context.Context, interruption *models.PomodoroInterruption) error {
+	query := `
+		INSERT INTO pomodoro_interruptions (id, pomodoro_id, occurred_at, phase, kind, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	interruption.ID = models.NewUUID()
+	interruption.CreatedAt = time.Now()
+
+	_, err := r.db.Exec(ctx, query,
+		interruption.ID, interruption.PomodoroID, interruption.OccurredAt, interruption.Phase, interruption.Kind, interruption.Reason, interruption.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create pomodoro interruption: %w", err)
+	}
+	return nil
+}
+
+// GetPomodoroInterruptions retrieves the interruptions of a pomodoro in the order they happened.
+func (r *PGPomodoroRepository) GetPomodoroInterruptions(ctx context.Context, pomodoroID string) ([]models.PomodoroInterruption, error) {
+	var interruptions []models.PomodoroInterruption
+	query := `
+		SELECT id, pomodoro_id, occurred_at, phase, kind, reason, created_at
+		FROM pomodoro_interruptions
+		WHERE pomodoro_id = $1
+		ORDER BY occurred_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, pomodoroID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pomodoro interruptions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		interruption := models.PomodoroInterruption{}
+		err := rows.Scan(
+			&interruption.ID, &interruption.PomodoroID, &interruption.OccurredAt, &interruption.Phase, &interruption.Kind, &interruption.Reason, &interruption.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pomodoro interruption row: %w", err)
+		}
+		interruptions = append(interruptions, interruption)
+	}
+	return interruptions, nil
+}
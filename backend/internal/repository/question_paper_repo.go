@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- QuestionPaper Repository ---
+
+// QuestionPaperRepository defines the interface for question paper data operations.
+type QuestionPaperRepository interface {
+	IngestQuestionPaper(ctx context.Context, paper *models.QuestionPaper, created, merged []*models.ImportantQuestion, occurrences []models.QuestionPaperOccurrence, occurrenceQuestions []*models.ImportantQuestion) error
+	GetQuestionPapersByUserID(ctx context.Context, userID string) ([]models.QuestionPaper, error)
+	GetQuestionPaperByDocumentID(ctx context.Context, userID, documentID string) (*models.QuestionPaper, error)
+	DeleteQuestionPaper(ctx context.Context, id string, userID string) error
+}
+
+// PGQuestionPaperRepository implements QuestionPaperRepository for PostgreSQL.
+type PGQuestionPaperRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGQuestionPaperRepository creates a new PostgreSQL question paper repository.
+func NewPGQuestionPaperRepository(db *pgxpool.Pool) *PGQuestionPaperRepository {
+	return &PGQuestionPaperRepository{db: db}
+}
+
+// IngestQuestionPaper saves an ingested paper in a single transaction: it adds the created questions to the
+// bank, stores the merged questions' new frequency counts, inserts the paper and links it to the question
+// each extracted question matched. occurrenceQuestions[i] is the question occurrences[i] matched, which may
+// be one of the created questions.
+func (r *PGQuestionPaperRepository) IngestQuestionPaper(ctx context.Context, paper *models.QuestionPaper, created, merged []*models.ImportantQuestion, occurrences []models.QuestionPaperOccurrence, occurrenceQuestions []*models.ImportantQuestion) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin question paper transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	createQuestionQuery := `
+		INSERT INTO important_questions (
+			id, user_id, subject_id, exam_id, question_text, source, unit, marks, frequency_count,
+			ease_factor, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)
+	`
+	for _, question := range created {
+		question.ID = models.NewUUID()
+		question.CreatedAt = time.Now()
+		if question.EaseFactor == 0 {
+			question.EaseFactor = 2.5
+		}
+		_, err := tx.Exec(ctx, createQuestionQuery,
+			question.ID, question.UserID, question.SubjectID, question.ExamID, question.QuestionText, question.Source,
+			question.Unit, question.Marks, question.FrequencyCount, question.EaseFactor, question.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create important question: %w", err)
+		}
+	}
+
+	mergeQuestionQuery := `
+		UPDATE important_questions SET
+			frequency_count = $1, unit = $2, marks = $3, source = $4
+		WHERE id = $5 AND user_id = $6
+	`
+	for _, question := range merged {
+		_, err := tx.Exec(ctx, mergeQuestionQuery,
+			question.FrequencyCount, question.Unit, question.Marks, question.Source, question.ID, question.UserID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update question frequency: %w", err)
+		}
+	}
+
+	paperQuery := `
+		INSERT INTO question_papers (
+			id, user_id, document_id, subject_id, exam_id, source, file_name,
+			extracted_count, created_count, merged_count, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)
+	`
+	paper.ID = models.NewUUID()
+	paper.CreatedAt = time.Now()
+	_, err = tx.Exec(ctx, paperQuery,
+		paper.ID, paper.UserID, paper.DocumentID, paper.SubjectID, paper.ExamID, paper.Source, paper.FileName,
+		paper.ExtractedCount, paper.CreatedCount, paper.MergedCount, paper.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create question paper: %w", err)
+	}
+
+	occurrenceQuery := `
+		INSERT INTO question_paper_occurrences (
+			id, paper_id, question_id, extracted_text, similarity
+		) VALUES (
+			$1, $2, $3, $4, $5
+		)
+		ON CONFLICT (paper_id, question_id) DO NOTHING
+	`
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		occurrence.ID = models.NewUUID()
+		occurrence.PaperID = paper.ID
+		occurrence.QuestionID = occurrenceQuestions[i].ID
+		_, err := tx.Exec(ctx, occurrenceQuery,
+			occurrence.ID, occurrence.PaperID, occurrence.QuestionID, occurrence.ExtractedText, occurrence.Similarity,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create question paper occurrence: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit question paper transaction: %w", err)
+	}
+	return nil
+}
+
+// GetQuestionPapersByUserID retrieves all ingested question papers for a given user, newest first.
+func (r *PGQuestionPaperRepository) GetQuestionPapersByUserID(ctx context.Context, userID string) ([]models.QuestionPaper, error) {
+	var papers []models.QuestionPaper
+	query := `
+		SELECT
+			id, user_id, document_id, subject_id, exam_id, source, file_name,
+			extracted_count, created_count, merged_count, created_at
+		FROM question_papers
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question papers by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		paper := models.QuestionPaper{}
+		err := rows.Scan(
+			&paper.ID, &paper.UserID, &paper.DocumentID, &paper.SubjectID, &paper.ExamID, &paper.Source, &paper.FileName,
+			&paper.ExtractedCount, &paper.CreatedCount, &paper.MergedCount, &paper.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan question paper row: %w", err)
+		}
+		papers = append(papers, paper)
+	}
+	return papers, nil
+}
+
+// GetQuestionPaperByDocumentID retrieves the paper ingested from a vault document.
+func (r *PGQuestionPaperRepository) GetQuestionPaperByDocumentID(ctx context.Context, userID, documentID string) (*models.QuestionPaper, error) {
+	paper := &models.QuestionPaper{}
+	query := `
+		SELECT
+			id, user_id, document_id, subject_id, exam_id, source, file_name,
+			extracted_count, created_count, merged_count, created_at
+		FROM question_papers
+		WHERE user_id = $1 AND document_id = $2
+	`
+	err := r.db.QueryRow(ctx, query, userID, documentID).Scan(
+		&paper.ID, &paper.UserID, &paper.DocumentID, &paper.SubjectID, &paper.ExamID, &paper.Source, &paper.FileName,
+		&paper.ExtractedCount, &paper.CreatedCount, &paper.MergedCount, &paper.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question paper by document ID: %w", err)
+	}
+	return paper, nil
+}
+
+// DeleteQuestionPaper deletes a question paper record. Questions created from it are kept.
+func (r *PGQuestionPaperRepository) DeleteQuestionPaper(ctx context.Context, id string, userID string) error {
+	query := `DELETE FROM question_papers WHERE id = $1 AND user_id = $2`
+	cmdTag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete question paper: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("question paper with ID %s not found or not owned by user", id)
+	}
+	return nil
+}
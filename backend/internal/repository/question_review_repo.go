@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- QuestionReview Repository ---
+
+// QuestionReviewRepository defines the interface for question review data operations.
+type QuestionReviewRepository interface {
+	RecordQuestionReviews(ctx context.Context, reviews []*models.QuestionReview, questions []*models.ImportantQuestion) error
+	GetQuestionReviewsByQuestionID(ctx context.Context, questionID string) ([]models.QuestionReview, error)
+}
+
+// PGQuestionReviewRepository implements QuestionReviewRepository for PostgreSQL.
+type PGQuestionReviewRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGQuestionReviewRepository creates a new PostgreSQL question review repository.
+func NewPGQuestionReviewRepository(db *pgxpool.Pool) *PGQuestionReviewRepository {
+	return &PGQuestionReviewRepository{db: db}
+}
+
+// RecordQuestionReviews inserts reviews and stores their questions' new schedules in a single transaction.
+// reviews[i] belongs to questions[i]; either all of them are recorded or none is.
+func (r *PGQuestionReviewRepository) RecordQuestionReviews(ctx context.Context, reviews []*models.QuestionReview, questions []*models.ImportantQuestion) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	insertQuery := `
+		INSERT INTO question_reviews (
+			id, user_id, question_id, quality, confidence_level, time_spent_seconds,
+			ease_factor, interval_days, next_review_at, reviewed_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)
+	`
+	updateQuery := `
+		UPDATE important_questions SET
+			is_practiced = $1, last_practiced_at = $2, confidence_level = $3,
+			ease_factor = $4, repetition_count = $5, interval_days = $6, next_review_at = $7
+		WHERE id = $8 AND user_id = $9
+	`
+	for i, review := range reviews {
+		question := questions[i]
+
+		review.ID = models.NewUUID()
+		if review.ReviewedAt.IsZero() {
+			review.ReviewedAt = time.Now()
+		}
+		_, err = tx.Exec(ctx, insertQuery,
+			review.ID, review.UserID, review.QuestionID, review.Quality, review.ConfidenceLevel, review.TimeSpentSeconds,
+			review.EaseFactor, review.IntervalDays, review.NextReviewAt, review.ReviewedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create question review: %w", err)
+		}
+
+		cmdTag, err := tx.Exec(ctx, updateQuery,
+			question.IsPracticed, question.LastPracticedAt, question.ConfidenceLevel,
+			question.EaseFactor, question.RepetitionCount, question.IntervalDays, question.NextReviewAt,
+			question.ID, question.UserID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update question schedule: %w", err)
+		}
+		if cmdTag.RowsAffected() == 0 {
+			return fmt.Errorf("important question with ID %s not found or not owned by user", question.ID)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit question reviews: %w", err)
+	}
+	return nil
+}
+
+// GetQuestionReviewsByQuestionID retrieves the review history of a question, newest first.
+func (r *PGQuestionReviewRepository) GetQuestionReviewsByQuestionID(ctx context.Context, questionID string) ([]models.QuestionReview, error) {
+	var reviews []models.QuestionReview
+	query := `
+		SELECT
+			id, user_id, question_id, quality, confidence_level, time_spent_seconds,
+			ease_factor, interval_days, next_review_at, reviewed_at
+		FROM question_reviews
+		WHERE question_id = $1
+		ORDER BY reviewed_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get question reviews: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		review := models.QuestionReview{}
+		err := rows.Scan(
+			&review.ID, &review.UserID, &review.QuestionID, &review.Quality, &review.ConfidenceLevel, &review.TimeSpentSeconds,
+			&review.EaseFactor, &review.IntervalDays, &review.NextReviewAt, &review.ReviewedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan question review row: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
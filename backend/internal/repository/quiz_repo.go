@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- Quiz Repository ---
+
+// QuizRepository defines the interface for quiz data operations.
+type QuizRepository interface {
+	CreateQuiz(ctx context.Context, quiz *models.Quiz) error
+	GetQuizByID(ctx context.Context, id string) (*models.Quiz, error)
+	GetQuizzesByUserID(ctx context.Context, userID string) ([]models.Quiz, error)
+	GetQuizQuestions(ctx context.Context, quizID string) ([]models.QuizQuestion, error)
+	AnswerQuizQuestion(ctx context.Context, quiz *models.Quiz, question *models.QuizQuestion) (bool, error)
+	UpdateQuiz(ctx context.Context, quiz *models.Quiz) error
+	DeleteQuiz(ctx context.Context, id string, userID string) error
+}
+
+// PGQuizRepository implements QuizRepository for PostgreSQL.
+type PGQuizRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGQuizRepository creates a new PostgreSQL quiz repository.
+func NewPGQuizRepository(db *pgxpool.Pool) *PGQuizRepository {
+	return &PGQuizRepository{db: db}
+}
+
+// CreateQuiz inserts a quiz together with its questions in a single transaction.
+func (r *PGQuizRepository) CreateQuiz(ctx context.Context, quiz *models.Quiz) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO quizzes (
+			id, user_id, title, exam_id, subject_id, unit, topic, marks, status,
+			total_questions, answered_count, correct_count, partial_count, incorrect_count, skipped_count,
+			score_percentage, average_confidence, total_time_seconds, started_at, completed_at,
+			created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22
+		)
+	`
+	quiz.ID = models.NewUUID()
+	quiz.StartedAt = time.Now()
+	quiz.CreatedAt = time.Now()
+	quiz.UpdatedAt = time.Now()
+
+	_, err = tx.Exec(ctx, query,
+		quiz.ID, quiz.UserID, quiz.Title, quiz.ExamID, quiz.SubjectID, quiz.Unit, quiz.Topic, quiz.Marks, quiz.Status,
+		quiz.TotalQuestions, quiz.AnsweredCount, quiz.CorrectCount, quiz.PartialCount, quiz.IncorrectCount, quiz.SkippedCount,
+		quiz.ScorePercentage, quiz.AverageConfidence, quiz.TotalTimeSeconds, quiz.StartedAt, quiz.CompletedAt,
+		quiz.CreatedAt, quiz.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create quiz: %w", err)
+	}
+
+	questionQuery := `
+		INSERT INTO quiz_questions (
+			id, quiz_id, question_id, position, question_text, marks,
+			self_grade, confidence_level, time_spent_seconds, answered_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)
+	`
+	for i := range quiz.Questions {
+		question := &quiz.Questions[i]
+		question.ID = models.NewUUID()
+		question.QuizID = quiz.ID
+		_, err = tx.Exec(ctx, questionQuery,
+			question.ID, question.QuizID, question.QuestionID, question.Position, question.QuestionText, question.Marks,
+			question.SelfGrade, question.ConfidenceLevel, question.TimeSpentSeconds, question.AnsweredAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create quiz question: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit quiz: %w", err)
+	}
+	return nil
+}
+
+// GetQuizByID retrieves a quiz by its ID, without its questions.
+func (r *PGQuizRepository) GetQuizByID(ctx context.Context, id string) (*models.Quiz, error) {
+	quiz := &models.Quiz{}
+	query := `
+		SELECT
+			id, user_id, title, exam_id, subject_id, unit, topic, marks, status,
+			total_questions, answered_count, correct_count, partial_count, incorrect_count, skipped_count,
+			score_percentage, average_confidence, total_time_seconds, started_at, completed_at,
+			created_at, updated_at
+		FROM quizzes
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&quiz.ID, &quiz.UserID, &quiz.Title, &quiz.ExamID, &quiz.SubjectID, &quiz.Unit, &quiz.Topic, &quiz.Marks, &quiz.Status,
+		&quiz.TotalQuestions, &quiz.AnsweredCount, &quiz.CorrectCount, &quiz.PartialCount, &quiz.IncorrectCount, &quiz.SkippedCount,
+		&quiz.ScorePercentage, &quiz.AverageConfidence, &quiz.TotalTimeSeconds, &quiz.StartedAt, &quiz.CompletedAt,
+		&quiz.CreatedAt, &quiz.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz by ID: %w", err)
+	}
+	return quiz, nil
+}
+
+// GetQuizzesByUserID retrieves all quizzes for a given user, newest first.
+func (r *PGQuizRepository) GetQuizzesByUserID(ctx context.Context, userID string) ([]models.Quiz, error) {
+	var quizzes []models.Quiz
+	query := `
+		SELECT
+			id, user_id, title, exam_id, subject_id, unit, topic, marks, status,
+			total_questions, answered_count, correct_count, partial_count, incorrect_count, skipped_count,
+			score_percentage, average_confidence, total_time_seconds, started_at, completed_at,
+			created_at, updated_at
+		FROM quizzes
+		WHERE user_id = $1
+		ORDER BY started_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quizzes by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		quiz := models.Quiz{}
+		err := rows.Scan(
+			&quiz.ID, &quiz.UserID, &quiz.Title, &quiz.ExamID, &quiz.SubjectID, &quiz.Unit, &quiz.Topic, &quiz.Marks, &quiz.Status,
+			&quiz.TotalQuestions, &quiz.AnsweredCount, &quiz.CorrectCount, &quiz.PartialCount, &quiz.IncorrectCount, &quiz.SkippedCount,
+			&quiz.ScorePercentage, &quiz.AverageConfidence, &quiz.TotalTimeSeconds, &quiz.StartedAt, &quiz.CompletedAt,
+			&quiz.CreatedAt, &quiz.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quiz row: %w", err)
+		}
+		quizzes = append(quizzes, quiz)
+	}
+	return quizzes, nil
+}
+
+// GetQuizQuestions retrieves the questions of a quiz in order.
+func (r *PGQuizRepository) GetQuizQuestions(ctx context.Context, quizID string) ([]models.QuizQuestion, error) {
+	var questions []models.QuizQuestion
+	query := `
+		SELECT
+			id, quiz_id, question_id, position, question_text, marks,
+			self_grade, confidence_level, time_spent_seconds, answered_at
+		FROM quiz_questions
+		WHERE quiz_id = $1
+		ORDER BY position ASC
+	`
+	rows, err := r.db.Query(ctx, query, quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz questions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		question := models.QuizQuestion{}
+		err := rows.Scan(
+			&question.ID, &question.QuizID, &question.QuestionID, &question.Position, &question.QuestionText, &question.Marks,
+			&question.SelfGrade, &question.ConfidenceLevel, &question.TimeSpentSeconds, &question.AnsweredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan quiz question row: %w", err)
+		}
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
+
+// AnswerQuizQuestion stores the response to a quiz question and the quiz's updated results in a
+// single transaction. It returns false, changing nothing, when the question was already answered.
+func (r *PGQuizRepository) AnswerQuizQuestion(ctx context.Context, quiz *models.Quiz, question *models.QuizQuestion) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	questionQuery := `
+		UPDATE quiz_questions SET
+			self_grade = $1, confidence_level = $2, time_spent_seconds = $3, answered_at = $4
+		WHERE id = $5 AND quiz_id = $6 AND answered_at IS NULL
+	`
+	cmdTag, err := tx.Exec(ctx, questionQuery,
+		question.SelfGrade, question.ConfidenceLevel, question.TimeSpentSeconds, question.AnsweredAt,
+		question.ID, question.QuizID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to answer quiz question: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if err := updateQuiz(ctx, tx, quiz); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit quiz answer: %w", err)
+	}
+	return true, nil
+}
+
+// UpdateQuiz updates the status and results of a quiz.
+func (r *PGQuizRepository) UpdateQuiz(ctx context.Context, quiz *models.Quiz) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := updateQuiz(ctx, tx, quiz); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit quiz: %w", err)
+	}
+	return nil
+}
+
+// DeleteQuiz deletes a quiz and its questions from the database.
+func (r *PGQuizRepository) DeleteQuiz(ctx context.Context, id string, userID string) error {
+	query := `DELETE FROM quizzes WHERE id = $1 AND user_id = $2`
+	cmdTag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete quiz: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("quiz with ID %s not found or not owned by user", id)
+	}
+	return nil
+}
+
+// updateQuiz writes a quiz's status and results within an existing transaction.
+func updateQuiz(ctx context.Context, tx pgx.Tx, quiz *models.Quiz) error {
+	query := `
+		UPDATE quizzes SET
+			status = $1, answered_count = $2, correct_count = $3, partial_count = $4, incorrect_count = $5,
+			skipped_count = $6, score_percentage = $7, average_confidence = $8, total_time_seconds = $9,
+			completed_at = $10, updated_at = $11
+		WHERE id = $12 AND user_id = $13
+	`
+	quiz.UpdatedAt = time.Now()
+
+	cmdTag, err := tx.Exec(ctx, query,
+		quiz.Status, quiz.AnsweredCount, quiz.CorrectCount, quiz.PartialCount, quiz.IncorrectCount,
+		quiz.SkippedCount, quiz.ScorePercentage, quiz.AverageConfidence, quiz.TotalTimeSeconds,
+		quiz.CompletedAt, quiz.UpdatedAt,
+		quiz.ID, quiz.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update quiz: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("quiz with ID %s not found or not owned by user", quiz.ID)
+	}
+	return nil
+}
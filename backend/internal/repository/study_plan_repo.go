@@ -14,6 +14,7 @@ import (
 // StudyPlanRepository defines the interface for study plan data operations.
 type StudyPlanRepository interface {
 	CreateStudyPlan(ctx context.Context, plan *models.StudyPlan) error
+	CreateStudyPlanWithSessions(ctx context.Context, plan *models.StudyPlan, sessions []models.StudySession) error
 	GetStudyPlanByID(ctx context.Context, id string) (*models.StudyPlan, error)
 	GetStudyPlansByUserID(ctx context.Context, userID string) ([]models.StudyPlan, error)
 	GetStudyPlansByUserIDAndDate(ctx context.Context, userID string, date time.Time) ([]models.StudyPlan, error)
@@ -35,9 +36,10 @@ func NewPGStudyPlanRepository(db *pgxpool.Pool) *PGStudyPlanRepository {
 func (r *PGStudyPlanRepository) CreateStudyPlan(ctx context.Context, plan *models.StudyPlan) error {
 	query := `
 		INSERT INTO study_plans (
-			id, user_id, title, plan_date, plan_type, status, notes, created_at, updated_at
+			id, user_id, title, plan_date, plan_type, status, notes, created_at, updated_at,
+			end_date, generation_settings
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 		) RETURNING id, created_at, updated_at
 	`
 	plan.ID = models.NewUUID()
@@ -46,6 +48,7 @@ func (r *PGStudyPlanRepository) CreateStudyPlan(ctx context.Context, plan *model
 
 	_, err := r.db.Exec(ctx, query,
 		plan.ID, plan.UserID, plan.Title, plan.PlanDate, plan.PlanType, plan.Status, plan.Notes, plan.CreatedAt, plan.UpdatedAt,
+		plan.EndDate, plan.GenerationSettings,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create study plan: %w", err)
@@ -53,17 +56,83 @@ func (r *PGStudyPlanRepository) CreateStudyPlan(ctx context.Context, plan *model
 	return nil
 }
 
+// CreateStudyPlanWithSessions inserts a study plan together with its sessions in a single transaction.
+func (r *PGStudyPlanRepository) CreateStudyPlanWithSessions(ctx context.Context, plan *models.StudyPlan, sessions []models.StudySession) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO study_plans (
+			id, user_id, title, plan_date, plan_type, status, notes, created_at, updated_at,
+			end_date, generation_settings
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)
+	`
+	plan.ID = models.NewUUID()
+	plan.CreatedAt = time.Now()
+	plan.UpdatedAt = time.Now()
+
+	_, err = tx.Exec(ctx, query,
+		plan.ID, plan.UserID, plan.Title, plan.PlanDate, plan.PlanType, plan.Status, plan.Notes, plan.CreatedAt, plan.UpdatedAt,
+		plan.EndDate, plan.GenerationSettings,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create study plan: %w", err)
+	}
+
+	sessionQuery := `
+		INSERT INTO study_sessions (
+			id, user_id, study_plan_id, subject_id, planned_start_time, planned_end_time,
+			planned_duration_minutes, actual_start_time, actual_end_time, actual_duration_minutes,
+			session_type, topics_to_cover, topics_covered, status, completion_percentage,
+			productivity_rating, notes, blockers, created_at, updated_at,
+			exam_id, assignment_id, carried_over_from, carried_over_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24
+		)
+	`
+	for i := range sessions {
+		session := &sessions[i]
+		session.ID = models.NewUUID()
+		session.StudyPlanID.String = plan.ID
+		session.StudyPlanID.Valid = true
+		session.CreatedAt = plan.CreatedAt
+		session.UpdatedAt = plan.UpdatedAt
+		_, err = tx.Exec(ctx, sessionQuery,
+			session.ID, session.UserID, session.StudyPlanID, session.SubjectID, session.PlannedStartTime, session.PlannedEndTime,
+			session.PlannedDurationMinutes, session.ActualStartTime, session.ActualEndTime, session.ActualDurationMinutes,
+			session.SessionType, session.TopicsToCover, session.TopicsCovered, session.Status, session.CompletionPercentage,
+			session.ProductivityRating, session.Notes, session.Blockers, session.CreatedAt, session.UpdatedAt,
+			session.ExamID, session.AssignmentID, session.CarriedOverFrom, session.CarriedOverAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create study session: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit study plan: %w", err)
+	}
+	return nil
+}
+
 // GetStudyPlanByID retrieves a study plan by its ID.
 func (r *PGStudyPlanRepository) GetStudyPlanByID(ctx context.Context, id string) (*models.StudyPlan, error) {
 	plan := &models.StudyPlan{}
 	query := `
 		SELECT
-			id, user_id, title, plan_date, plan_type, status, notes, created_at, updated_at
+			id, user_id, title, plan_date, plan_type, status, notes, created_at, updated_at,
+			end_date, generation_settings
 		FROM study_plans
 		WHERE id = $1
 	`
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&plan.ID, &plan.UserID, &plan.Title, &plan.PlanDate, &plan.PlanType, &plan.Status, &plan.Notes, &plan.CreatedAt, &plan.UpdatedAt,
+		&plan.EndDate, &plan.GenerationSettings,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get study plan by ID: %w", err)
@@ -76,7 +145,8 @@ func (r *PGStudyPlanRepository) GetStudyPlansByUserID(ctx context.Context, userI
 	var plans []models.StudyPlan
 	query := `
 		SELECT
-			id, user_id, title, plan_date, plan_type, status, notes, created_at, updated_at
+			id, user_id, title, plan_date, plan_type, status, notes, created_at, updated_at,
+			end_date, generation_settings
 		FROM study_plans
 		WHERE user_id = $1
 		ORDER BY plan_date DESC, created_at DESC
@@ -91,6 +161,7 @@ func (r *PGStudyPlanRepository) GetStudyPlansByUserID(ctx context.Context, userI
 		plan := models.StudyPlan{}
 		err := rows.Scan(
 			&plan.ID, &plan.UserID, &plan.Title, &plan.PlanDate, &plan.PlanType, &plan.Status, &plan.Notes, &plan.CreatedAt, &plan.UpdatedAt,
+			&plan.EndDate, &plan.GenerationSettings,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan study plan row: %w", err)
@@ -100,14 +171,16 @@ func (r *PGStudyPlanRepository) GetStudyPlansByUserID(ctx context.Context, userI
 	return plans, nil
 }
 
-// GetStudyPlansByUserIDAndDate retrieves all study plans for a given user on a specific date.
+// GetStudyPlansByUserIDAndDate retrieves all study plans for a given user on a specific date,
+// including multi-day plans whose range covers that date.
 func (r *PGStudyPlanRepository) GetStudyPlansByUserIDAndDate(ctx context.Context, userID string, date time.Time) ([]models.StudyPlan, error) {
 	var plans []models.StudyPlan
 	query := `
 		SELECT
-			id, user_id, title, plan_date, plan_type, status, notes, created_at, updated_at
+			id, user_id, title, plan_date, plan_type, status, notes, created_at, updated_at,
+			end_date, generation_settings
 		FROM study_plans
-		WHERE user_id = $1 AND plan_date = $2
+		WHERE user_id = $1 AND (plan_date = $2 OR $2 BETWEEN plan_date AND end_date)
 		ORDER BY created_at DESC
 	`
 	rows, err := r.db.Query(ctx, query, userID, date)
@@ -120,6 +193,7 @@ func (r *PGStudyPlanRepository) GetStudyPlansByUserIDAndDate(ctx context.Context
 		plan := models.StudyPlan{}
 		err := rows.Scan(
 			&plan.ID, &plan.UserID, &plan.Title, &plan.PlanDate, &plan.PlanType, &plan.Status, &plan.Notes, &plan.CreatedAt, &plan.UpdatedAt,
+			&plan.EndDate, &plan.GenerationSettings,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan study plan row: %w", err)
@@ -133,13 +207,15 @@ func (r *PGStudyPlanRepository) GetStudyPlansByUserIDAndDate(ctx context.Context
 func (r *PGStudyPlanRepository) UpdateStudyPlan(ctx context.Context, plan *models.StudyPlan) error {
 	query := `
 		UPDATE study_plans SET
-			title = $1, plan_date = $2, plan_type = $3, status = $4, notes = $5, updated_at = $6
-		WHERE id = $7 AND user_id = $8
+			title = $1, plan_date = $2, plan_type = $3, status = $4, notes = $5, updated_at = $6,
+			end_date = $7, generation_settings = $8
+		WHERE id = $9 AND user_id = $10
 	`
 	plan.UpdatedAt = time.Now()
 
 	cmdTag, err := r.db.Exec(ctx, query,
 		plan.Title, plan.PlanDate, plan.PlanType, plan.Status, plan.Notes, plan.UpdatedAt,
+		plan.EndDate, plan.GenerationSettings,
 		plan.ID, plan.UserID,
 	)
 	if err != nil {
@@ -172,6 +248,9 @@ type StudySessionRepository interface {
 	GetStudySessionByID(ctx context.Context, id string) (*models.StudySession, error)
 	GetStudySessionsByUserID(ctx context.Context, userID string) ([]models.StudySession, error)
 	GetStudySessionsByStudyPlanID(ctx context.Context, studyPlanID string) ([]models.StudySession, error)
+	GetCarryOverSessions(ctx context.Context, before time.Time) ([]models.StudySession, error)
+	GetCarryOverSessionsByUserID(ctx context.Context, userID string, before time.Time) ([]models.StudySession, error)
+	CarryOverStudySession(ctx context.Context, source *models.StudySession, newSessions []models.StudySession, rebalanced []models.StudySession) error
 	UpdateStudySession(ctx context.Context, session *models.StudySession) error
 	DeleteStudySession(ctx context.Context, id string) error
 }
@@ -193,9 +272,10 @@ func (r *PGStudySessionRepository) CreateStudySession(ctx context.Context, sessi
 			id, user_id, study_plan_id, subject_id, planned_start_time, planned_end_time,
 			planned_duration_minutes, actual_start_time, actual_end_time, actual_duration_minutes,
 			session_type, topics_to_cover, topics_covered, status, completion_percentage,
-			productivity_rating, notes, blockers, created_at, updated_at
+			productivity_rating, notes, blockers, created_at, updated_at,
+			exam_id, assignment_id, carried_over_from, carried_over_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24
 		) RETURNING id, created_at, updated_at
 	`
 	session.ID = models.NewUUID()
@@ -207,6 +287,7 @@ func (r *PGStudySessionRepository) CreateStudySession(ctx context.Context, sessi
 		session.PlannedDurationMinutes, session.ActualStartTime, session.ActualEndTime, session.ActualDurationMinutes,
 		session.SessionType, session.TopicsToCover, session.TopicsCovered, session.Status, session.CompletionPercentage,
 		session.ProductivityRating, session.Notes, session.Blockers, session.CreatedAt, session.UpdatedAt,
+		session.ExamID, session.AssignmentID, session.CarriedOverFrom, session.CarriedOverAt,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create study session: %w", err)
@@ -222,7 +303,8 @@ func (r *PGStudySessionRepository) GetStudySessionByID(ctx context.Context, id s
 			id, user_id, study_plan_id, subject_id, planned_start_time, planned_end_time,
 			planned_duration_minutes, actual_start_time, actual_end_time, actual_duration_minutes,
 			session_type, topics_to_cover, topics_covered, status, completion_percentage,
-			productivity_rating, notes, blockers, created_at, updated_at
+			productivity_rating, notes, blockers, created_at, updated_at,
+			exam_id, assignment_id, carried_over_from, carried_over_at
 		FROM study_sessions
 		WHERE id = $1
 	`
@@ -231,6 +313,7 @@ func (r *PGStudySessionRepository) GetStudySessionByID(ctx context.Context, id s
 		&session.PlannedDurationMinutes, &session.ActualStartTime, &session.ActualEndTime, &session.ActualDurationMinutes,
 		&session.SessionType, &session.TopicsToCover, &session.TopicsCovered, &session.Status, &session.CompletionPercentage,
 		&session.ProductivityRating, &session.Notes, &session.Blockers, &session.CreatedAt, &session.UpdatedAt,
+		&session.ExamID, &session.AssignmentID, &session.CarriedOverFrom, &session.CarriedOverAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get study session by ID: %w", err)
@@ -246,7 +329,8 @@ func (r *PGStudySessionRepository) GetStudySessionsByUserID(ctx context.Context,
 			id, user_id, study_plan_id, subject_id, planned_start_time, planned_end_time,
 			planned_duration_minutes, actual_start_time, actual_end_time, actual_duration_minutes,
 			session_type, topics_to_cover, topics_covered, status, completion_percentage,
-			productivity_rating, notes, blockers, created_at, updated_at
+			productivity_rating, notes, blockers, created_at, updated_at,
+			exam_id, assignment_id, carried_over_from, carried_over_at
 		FROM study_sessions
 		WHERE user_id = $1
 		ORDER BY planned_start_time DESC, created_at DESC
@@ -264,6 +348,7 @@ func (r *PGStudySessionRepository) GetStudySessionsByUserID(ctx context.Context,
 			&session.PlannedDurationMinutes, &session.ActualStartTime, &session.ActualEndTime, &session.ActualDurationMinutes,
 			&session.SessionType, &session.TopicsToCover, &session.TopicsCovered, &session.Status, &session.CompletionPercentage,
 			&session.ProductivityRating, &session.Notes, &session.Blockers, &session.CreatedAt, &session.UpdatedAt,
+			&session.ExamID, &session.AssignmentID, &session.CarriedOverFrom, &session.CarriedOverAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan study session row: %w", err)
@@ -281,7 +366,8 @@ func (r *PGStudySessionRepository) GetStudySessionsByStudyPlanID(ctx context.Con
 			id, user_id, study_plan_id, subject_id, planned_start_time, planned_end_time,
 			planned_duration_minutes, actual_start_time, actual_end_time, actual_duration_minutes,
 			session_type, topics_to_cover, topics_covered, status, completion_percentage,
-			productivity_rating, notes, blockers, created_at, updated_at
+			productivity_rating, notes, blockers, created_at, updated_at,
+			exam_id, assignment_id, carried_over_from, carried_over_at
 		FROM study_sessions
 		WHERE study_plan_id = $1
 		ORDER BY planned_start_time ASC, created_at ASC
@@ -299,6 +385,66 @@ func (r *PGStudySessionRepository) GetStudySessionsByStudyPlanID(ctx context.Con
 			&session.PlannedDurationMinutes, &session.ActualStartTime, &session.ActualEndTime, &session.ActualDurationMinutes,
 			&session.SessionType, &session.TopicsToCover, &session.TopicsCovered, &session.Status, &session.CompletionPercentage,
 			&session.ProductivityRating, &session.Notes, &session.Blockers, &session.CreatedAt, &session.UpdatedAt,
+			&session.ExamID, &session.AssignmentID, &session.CarriedOverFrom, &session.CarriedOverAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan study session row: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// GetCarryOverSessions retrieves skipped and partial sessions of all users, planned to start before the
+// given time, whose unfinished work has not been carried over yet.
+func (r *PGStudySessionRepository) GetCarryOverSessions(ctx context.Context, before time.Time) ([]models.StudySession, error) {
+	query := `
+		SELECT
+			id, user_id, study_plan_id, subject_id, planned_start_time, planned_end_time,
+			planned_duration_minutes, actual_start_time, actual_end_time, actual_duration_minutes,
+			session_type, topics_to_cover, topics_covered, status, completion_percentage,
+			productivity_rating, notes, blockers, created_at, updated_at,
+			exam_id, assignment_id, carried_over_from, carried_over_at
+		FROM study_sessions
+		WHERE status IN ('skipped', 'partial') AND carried_over_at IS NULL AND planned_start_time < $1
+		ORDER BY user_id, planned_start_time ASC
+	`
+	return r.queryCarryOverSessions(ctx, query, before)
+}
+
+// GetCarryOverSessionsByUserID retrieves a user's skipped and partial sessions, planned to start before
+// the given time, whose unfinished work has not been carried over yet.
+func (r *PGStudySessionRepository) GetCarryOverSessionsByUserID(ctx context.Context, userID string, before time.Time) ([]models.StudySession, error) {
+	query := `
+		SELECT
+			id, user_id, study_plan_id, subject_id, planned_start_time, planned_end_time,
+			planned_duration_minutes, actual_start_time, actual_end_time, actual_duration_minutes,
+			session_type, topics_to_cover, topics_covered, status, completion_percentage,
+			productivity_rating, notes, blockers, created_at, updated_at,
+			exam_id, assignment_id, carried_over_from, carried_over_at
+		FROM study_sessions
+		WHERE user_id = $1 AND status IN ('skipped', 'partial') AND carried_over_at IS NULL AND planned_start_time < $2
+		ORDER BY planned_start_time ASC
+	`
+	return r.queryCarryOverSessions(ctx, query, userID, before)
+}
+
+func (r *PGStudySessionRepository) queryCarryOverSessions(ctx context.Context, query string, args ...interface{}) ([]models.StudySession, error) {
+	var sessions []models.StudySession
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get carry-over study sessions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		session := models.StudySession{}
+		err := rows.Scan(
+			&session.ID, &session.UserID, &session.StudyPlanID, &session.SubjectID, &session.PlannedStartTime, &session.PlannedEndTime,
+			&session.PlannedDurationMinutes, &session.ActualStartTime, &session.ActualEndTime, &session.ActualDurationMinutes,
+			&session.SessionType, &session.TopicsToCover, &session.TopicsCovered, &session.Status, &session.CompletionPercentage,
+			&session.ProductivityRating, &session.Notes, &session.Blockers, &session.CreatedAt, &session.UpdatedAt,
+			&session.ExamID, &session.AssignmentID, &session.CarriedOverFrom, &session.CarriedOverAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan study session row: %w", err)
@@ -308,6 +454,76 @@ func (r *PGStudySessionRepository) GetStudySessionsByStudyPlanID(ctx context.Con
 	return sessions, nil
 }
 
+// CarryOverStudySession marks a session's unfinished work as carried over, inserts the sessions that
+// continue it and saves the topics of rebalanced later sessions, all in a single transaction.
+func (r *PGStudySessionRepository) CarryOverStudySession(ctx context.Context, source *models.StudySession, newSessions []models.StudySession, rebalanced []models.StudySession) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now()
+	cmdTag, err := tx.Exec(ctx,
+		`UPDATE study_sessions SET carried_over_at = $1, updated_at = $1 WHERE id = $2 AND carried_over_at IS NULL`,
+		now, source.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark study session as carried over: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("study session with ID %s not found or already carried over", source.ID)
+	}
+	source.CarriedOverAt.Time = now
+	source.CarriedOverAt.Valid = true
+	source.UpdatedAt = now
+
+	query := `
+		INSERT INTO study_sessions (
+			id, user_id, study_plan_id, subject_id, planned_start_time, planned_end_time,
+			planned_duration_minutes, actual_start_time, actual_end_time, actual_duration_minutes,
+			session_type, topics_to_cover, topics_covered, status, completion_percentage,
+			productivity_rating, notes, blockers, created_at, updated_at,
+			exam_id, assignment_id, carried_over_from, carried_over_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24
+		)
+	`
+	for i := range newSessions {
+		session := &newSessions[i]
+		session.ID = models.NewUUID()
+		session.CreatedAt = now
+		session.UpdatedAt = now
+		_, err = tx.Exec(ctx, query,
+			session.ID, session.UserID, session.StudyPlanID, session.SubjectID, session.PlannedStartTime, session.PlannedEndTime,
+			session.PlannedDurationMinutes, session.ActualStartTime, session.ActualEndTime, session.ActualDurationMinutes,
+			session.SessionType, session.TopicsToCover, session.TopicsCovered, session.Status, session.CompletionPercentage,
+			session.ProductivityRating, session.Notes, session.Blockers, session.CreatedAt, session.UpdatedAt,
+			session.ExamID, session.AssignmentID, session.CarriedOverFrom, session.CarriedOverAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create study session: %w", err)
+		}
+	}
+
+	for i := range rebalanced {
+		session := &rebalanced[i]
+		session.UpdatedAt = now
+		_, err = tx.Exec(ctx,
+			`UPDATE study_sessions SET topics_to_cover = $1, updated_at = $2 WHERE id = $3 AND user_id = $4`,
+			session.TopicsToCover, session.UpdatedAt, session.ID, session.UserID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to rebalance study session: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit study session carry-over: %w", err)
+	}
+	return nil
+}
+
 // UpdateStudySession updates an existing study session in the database.
 func (r *PGStudySessionRepository) UpdateStudySession(ctx context.Context, session *models.StudySession) error {
 	query := `
@@ -316,8 +532,9 @@ func (r *PGStudySessionRepository) UpdateStudySession(ctx context.Context, sessi
 			planned_duration_minutes = $5, actual_start_time = $6, actual_end_time = $7,
 			actual_duration_minutes = $8, session_type = $9, topics_to_cover = $10,
 			topics_covered = $11, status = $12, completion_percentage = $13,
-			productivity_rating = $14, notes = $15, blockers = $16, updated_at = $17
-		WHERE id = $18 AND user_id = $19
+			productivity_rating = $14, notes = $15, blockers = $16, updated_at = $17,
+			exam_id = $18, assignment_id = $19, carried_over_from = $20, carried_over_at = $21
+		WHERE id = $22 AND user_id = $23
 	`
 	session.UpdatedAt = time.Now()
 
@@ -327,6 +544,7 @@ func (r *PGStudySessionRepository) UpdateStudySession(ctx context.Context, sessi
 		session.ActualDurationMinutes, session.SessionType, session.TopicsToCover,
 		session.TopicsCovered, session.Status, session.CompletionPercentage,
 		session.ProductivityRating, session.Notes, session.Blockers, session.UpdatedAt,
+		session.ExamID, session.AssignmentID, session.CarriedOverFrom, session.CarriedOverAt,
 		session.ID, session.UserID,
 	)
 	if err != nil {
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- StudyPlanTemplate Repository ---
+
+// StudyPlanTemplateRepository defines the interface for study plan template data operations.
+type StudyPlanTemplateRepository interface {
+	CreateStudyPlanTemplate(ctx context.Context, template *models.StudyPlanTemplate) error
+	GetStudyPlanTemplateByID(ctx context.Context, id string) (*models.StudyPlanTemplate, error)
+	GetStudyPlanTemplatesByUserID(ctx context.Context, userID string) ([]models.StudyPlanTemplate, error)
+	UpdateStudyPlanTemplate(ctx context.Context, template *models.StudyPlanTemplate) error
+	DeleteStudyPlanTemplate(ctx context.Context, id string, userID string) error
+}
+
+// PGStudyPlanTemplateRepository implements StudyPlanTemplateRepository for PostgreSQL.
+type PGStudyPlanTemplateRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGStudyPlanTemplateRepository creates a new PostgreSQL study plan template repository.
+func NewPGStudyPlanTemplateRepository(db *pgxpool.Pool) *PGStudyPlanTemplateRepository {
+	return &PGStudyPlanTemplateRepository{db: db}
+}
+
+// CreateStudyPlanTemplate inserts a new study plan template into the database.
+func (r *PGStudyPlanTemplateRepository) CreateStudyPlanTemplate(ctx context.Context, template *models.StudyPlanTemplate) error {
+	query := `
+		INSERT INTO study_plan_templates (
+			id, user_id, title, plan_type, description, repeat_every_days, sessions, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`
+	template.ID = models.NewUUID()
+	template.CreatedAt = time.Now()
+	template.UpdatedAt = time.Now()
+
+	_, err := r.db.Exec(ctx, query,
+		template.ID, template.UserID, template.Title, template.PlanType, template.Description, template.RepeatEveryDays, template.Sessions,
+		template.CreatedAt, template.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create study plan template: %w", err)
+	}
+	return nil
+}
+
+// GetStudyPlanTemplateByID retrieves a study plan template by its ID.
+func (r *PGStudyPlanTemplateRepository) GetStudyPlanTemplateByID(ctx context.Context, id string) (*models.StudyPlanTemplate, error) {
+	template := &models.StudyPlanTemplate{}
+	query := `
+		SELECT id, user_id, title, plan_type, description, repeat_every_days, sessions, created_at, updated_at
+		FROM study_plan_templates
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&template.ID, &template.UserID, &template.Title, &template.PlanType, &template.Description, &template.RepeatEveryDays, &template.Sessions,
+		&template.CreatedAt, &template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get study plan template by ID: %w", err)
+	}
+	return template, nil
+}
+
+// GetStudyPlanTemplatesByUserID retrieves all study plan templates for a given user.
+func (r *PGStudyPlanTemplateRepository) GetStudyPlanTemplatesByUserID(ctx context.Context, userID string) ([]models.StudyPlanTemplate, error) {
+	var templates []models.StudyPlanTemplate
+	query := `
+		SELECT id, user_id, title, plan_type, description, repeat_every_days, sessions, created_at, updated_at
+		FROM study_plan_templates
+		WHERE user_id = $1
+		ORDER BY title ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get study plan templates by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		template := models.StudyPlanTemplate{}
+		err := rows.Scan(
+			&template.ID, &template.UserID, &template.Title, &template.PlanType, &template.Description, &template.RepeatEveryDays, &template.Sessions,
+			&template.CreatedAt, &template.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan study plan template row: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+// UpdateStudyPlanTemplate updates an existing study plan template in the database.
+func (r *PGStudyPlanTemplateRepository) UpdateStudyPlanTemplate(ctx context.Context, template *models.StudyPlanTemplate) error {
+	query := `
+		UPDATE study_plan_templates SET
+			title = $1, plan_type = $2, description = $3, repeat_every_days = $4, sessions = $5, updated_at = $6
+		WHERE id = $7 AND user_id = $8
+	`
+	template.UpdatedAt = time.Now()
+
+	cmdTag, err := r.db.Exec(ctx, query,
+		template.Title, template.PlanType, template.Description, template.RepeatEveryDays, template.Sessions, template.UpdatedAt,
+		template.ID, template.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update study plan template: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("study plan template with ID %s not found or not owned by user", template.ID)
+	}
+	return nil
+}
+
+// DeleteStudyPlanTemplate deletes a study plan template from the database.
+func (r *PGStudyPlanTemplateRepository) DeleteStudyPlanTemplate(ctx context.Context, id string, userID string) error {
+	query := `DELETE FROM study_plan_templates WHERE id = $1 AND user_id = $2`
+	cmdTag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete study plan template: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("study plan template with ID %s not found or not owned by user", id)
+	}
+	return nil
+}
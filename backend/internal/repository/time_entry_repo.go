@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- TimeEntry Repository ---
+
+// TimeEntryRepository defines the interface for time entry data operations.
+type TimeEntryRepository interface {
+	CreateTimeEntry(ctx context.Context, entry *models.TimeEntry) error
+	GetTimeEntryByID(ctx context.Context, id string) (*models.TimeEntry, error)
+	GetRunningTimeEntryByUserID(ctx context.Context, userID string) (*models.TimeEntry, error)
+	GetLatestTimeEntryByUserID(ctx context.Context, userID string) (*models.TimeEntry, error)
+	GetTimeEntriesByUserID(ctx context.Context, userID string) ([]models.TimeEntry, error)
+	GetTimeEntriesByEntity(ctx context.Context, userID, entityType, entityID string) ([]models.TimeEntry, error)
+	UpdateTimeEntry(ctx context.Context, entry *models.TimeEntry) error
+	CloseTimeEntry(ctx context.Context, entry *models.TimeEntry, fromStatus string, dayMinutes map[time.Time]int32) (bool, error)
+	ReplacePausedTimeEntry(ctx context.Context, paused, entry *models.TimeEntry) (bool, error)
+}
+
+// PGTimeEntryRepository implements TimeEntryRepository for PostgreSQL.
+type PGTimeEntryRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGTimeEntryRepository creates a new PostgreSQL time entry repository.
+func NewPGTimeEntryRepository(db *pgxpool.Pool) *PGTimeEntryRepository {
+	return &PGTimeEntryRepository{db: db}
+}
+
+// CreateTimeEntry inserts a new time entry into the database.
+func (r *PGTimeEntryRepository) CreateTimeEntry(ctx context.Context, entry *models.TimeEntry) error {
+	query := `
+		INSERT INTO time_entries (
+			id, user_id, entity_type, entity_id, started_at, ended_at, duration_minutes,
+			status, auto_stopped, notes, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		) RETURNING id, created_at, updated_at
+	`
+	entry.ID = models.NewUUID()
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = time.Now()
+
+	_, err := r.db.Exec(ctx, query,
+		entry.ID, entry.UserID, entry.EntityType, entry.EntityID, entry.StartedAt, entry.EndedAt, entry.DurationMinutes,
+		entry.Status, entry.AutoStopped, entry.Notes, entry.CreatedAt, entry.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create time entry: %w", err)
+	}
+	return nil
+}
+
+// GetTimeEntryByID retrieves a time entry by its ID.
+func (r *PGTimeEntryRepository) GetTimeEntryByID(ctx context.Context, id string) (*models.TimeEntry, error) {
+	entry := &models.TimeEntry{}
+	query := `
+		SELECT
+			id, user_id, entity_type, entity_id, started_at, ended_at, duration_minutes,
+			status, auto_stopped, notes, created_at, updated_at
+		FROM time_entries
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&entry.ID, &entry.UserID, &entry.EntityType, &entry.EntityID, &entry.StartedAt, &entry.EndedAt, &entry.DurationMinutes,
+		&entry.Status, &entry.AutoStopped, &entry.Notes, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entry by ID: %w", err)
+	}
+	return entry, nil
+}
+
+// GetRunningTimeEntryByUserID retrieves the currently running time entry for a user.
+func (r *PGTimeEntryRepository) GetRunningTimeEntryByUserID(ctx context.Context, userID string) (*models.TimeEntry, error) {
+	entry := &models.TimeEntry{}
+	query := `
+		SELECT
+			id, user_id, entity_type, entity_id, started_at, ended_at, duration_minutes,
+			status, auto_stopped, notes, created_at, updated_at
+		FROM time_entries
+		WHERE user_id = $1 AND status = 'running'
+	`
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&entry.ID, &entry.UserID, &entry.EntityType, &entry.EntityID, &entry.StartedAt, &entry.EndedAt, &entry.DurationMinutes,
+		&entry.Status, &entry.AutoStopped, &entry.Notes, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running time entry: %w", err)
+	}
+	return entry, nil
+}
+
+// GetLatestTimeEntryByUserID retrieves the most recently started time entry for a user.
+func (r *PGTimeEntryRepository) GetLatestTimeEntryByUserID(ctx context.Context, userID string) (*models.TimeEntry, error) {
+	entry := &models.TimeEntry{}
+	query := `
+		SELECT
+			id, user_id, entity_type, entity_id, started_at, ended_at, duration_minutes,
+			status, auto_stopped, notes, created_at, updated_at
+		FROM time_entries
+		WHERE user_id = $1
+		ORDER BY started_at DESC
+		LIMIT 1
+	`
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&entry.ID, &entry.UserID, &entry.EntityType, &entry.EntityID, &entry.StartedAt, &entry.EndedAt, &entry.DurationMinutes,
+		&entry.Status, &entry.AutoStopped, &entry.Notes, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest time entry: %w", err)
+	}
+	return entry, nil
+}
+
+// GetTimeEntriesByUserID retrieves all time entries for a given user.
+func (r *PGTimeEntryRepository) GetTimeEntriesByUserID(ctx context.Context, userID string) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	query := `
+		SELECT
+			id, user_id, entity_type, entity_id, started_at, ended_at, duration_minutes,
+			status, auto_stopped, notes, created_at, updated_at
+		FROM time_entries
+		WHERE user_id = $1
+		ORDER BY started_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := models.TimeEntry{}
+		err := rows.Scan(
+			&entry.ID, &entry.UserID, &entry.EntityType, &entry.EntityID, &entry.StartedAt, &entry.EndedAt, &entry.DurationMinutes,
+			&entry.Status, &entry.AutoStopped, &entry.Notes, &entry.CreatedAt, &entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// GetTimeEntriesByEntity retrieves all time entries a user recorded against a single entity.
+func (r *PGTimeEntryRepository) GetTimeEntriesByEntity(ctx context.Context, userID, entityType, entityID string) ([]models.TimeEntry, error) {
+	var entries []models.TimeEntry
+	query := `
+		SELECT
+			id, user_id, entity_type, entity_id, started_at, ended_at, duration_minutes,
+			status, auto_stopped, notes, created_at, updated_at
+		FROM time_entries
+		WHERE user_id = $1 AND entity_type = $2 AND entity_id = $3
+		ORDER BY started_at ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID, entityType, entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries by entity: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := models.TimeEntry{}
+		err := rows.Scan(
+			&entry.ID, &entry.UserID, &entry.EntityType, &entry.EntityID, &entry.StartedAt, &entry.EndedAt, &entry.DurationMinutes,
+			&entry.Status, &entry.AutoStopped, &entry.Notes, &entry.CreatedAt, &entry.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// UpdateTimeEntry updates an existing time entry in the database.
+func (r *PGTimeEntryRepository) UpdateTimeEntry(ctx context.Context, entry *models.TimeEntry) error {
+	query := `
+		UPDATE time_entries SET
+			started_at = $1, ended_at = $2, duration_minutes = $3, status = $4,
+			auto_stopped = $5, notes = $6, updated_at = $7
+		WHERE id = $8 AND user_id = $9
+	`
+	entry.UpdatedAt = time.Now()
+
+	cmdTag, err := r.db.Exec(ctx, query,
+		entry.StartedAt, entry.EndedAt, entry.DurationMinutes, entry.Status,
+		entry.AutoStopped, entry.Notes, entry.UpdatedAt,
+		entry.ID, entry.UserID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update time entry: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return fmt.Errorf("time entry with ID %s not found or not owned by user", entry.ID)
+	}
+	return nil
+}
+
+// CloseTimeEntry saves a closed entry and, when dayMinutes is non-nil, rolls its duration into the tracked
+// entity and the user's daily stats, all in one transaction. The entry is only closed while it still has
+// fromStatus; it returns false, changing nothing, when another request got there first.
+func (r *PGTimeEntryRepository) CloseTimeEntry(ctx context.Context, entry *models.TimeEntry, fromStatus string, dayMinutes map[time.Time]int32) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin time entry transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE time_entries SET
+			ended_at = $1, duration_minutes = $2, status = $3, auto_stopped = $4, updated_at = $5
+		WHERE id = $6 AND user_id = $7 AND status = $8
+	`
+	entry.UpdatedAt = time.Now()
+	cmdTag, err := tx.Exec(ctx, query,
+		entry.EndedAt, entry.DurationMinutes, entry.Status, entry.AutoStopped, entry.UpdatedAt,
+		entry.ID, entry.UserID, fromStatus,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to close time entry: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if dayMinutes != nil && entry.DurationMinutes.Int32 > 0 {
+		if err := rollUpTimeEntry(ctx, tx, entry, dayMinutes); err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit time entry transaction: %w", err)
+	}
+	return true, nil
+}
+
+// ReplacePausedTimeEntry stops a paused entry, whose time was already rolled up when it was paused, and
+// starts the running entry that replaces it in one transaction. It returns false, changing nothing, when
+// the paused entry was resumed or stopped by another request in the meantime.
+func (r *PGTimeEntryRepository) ReplacePausedTimeEntry(ctx context.Context, paused, entry *models.TimeEntry) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin time entry transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	paused.Status = "stopped"
+	paused.UpdatedAt = time.Now()
+	cmdTag, err := tx.Exec(ctx,
+		`UPDATE time_entries SET status = $1, updated_at = $2 WHERE id = $3 AND user_id = $4 AND status = 'paused'`,
+		paused.Status, paused.UpdatedAt, paused.ID, paused.UserID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to stop paused time entry: %w", err)
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	query := `
+		INSERT INTO time_entries (
+			id, user_id, entity_type, entity_id, started_at, ended_at, duration_minutes,
+			status, auto_stopped, notes, created_at, updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+		)
+	`
+	entry.ID = models.NewUUID()
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = time.Now()
+	_, err = tx.Exec(ctx, query,
+		entry.ID, entry.UserID, entry.EntityType, entry.EntityID, entry.StartedAt, entry.EndedAt, entry.DurationMinutes,
+		entry.Status, entry.AutoStopped, entry.Notes, entry.CreatedAt, entry.UpdatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create time entry: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit time entry transaction: %w", err)
+	}
+	return true, nil
+}
+
+// rollUpTimeEntry adds a closed entry's duration to its tracked entity and its minutes per day to daily stats.
+func rollUpTimeEntry(ctx context.Context, tx pgx.Tx, entry *models.TimeEntry, dayMinutes map[time.Time]int32) error {
+	minutes := entry.DurationMinutes.Int32
+	now := time.Now()
+
+	var err error
+	switch entry.EntityType {
+	case "assignment":
+		_, err = tx.Exec(ctx,
+			`UPDATE assignments SET actual_hours = COALESCE(actual_hours, 0) + $1, updated_at = $2 WHERE id = $3`,
+			float64(minutes)/60, now, entry.EntityID,
+		)
+	case "exam":
+		_, err = tx.Exec(ctx,
+			`UPDATE exams SET study_hours_logged = COALESCE(study_hours_logged, 0) + $1, updated_at = $2 WHERE id = $3`,
+			float64(minutes)/60, now, entry.EntityID,
+		)
+	case "study_session":
+		_, err = tx.Exec(ctx, `
+			UPDATE study_sessions SET
+				actual_start_time = COALESCE(actual_start_time, $1), actual_end_time = $2,
+				actual_duration_minutes = COALESCE(actual_duration_minutes, 0) + $3, updated_at = $4
+			WHERE id = $5
+		`, entry.StartedAt, entry.EndedAt, minutes, now, entry.EntityID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to roll up time entry into %s: %w", entry.EntityType, err)
+	}
+
+	for day, dayTotal := range dayMinutes {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO daily_stats (id, user_id, stat_date, study_minutes)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, stat_date) DO UPDATE SET
+				study_minutes = daily_stats.study_minutes + EXCLUDED.study_minutes
+		`, models.NewUUID(), entry.UserID, day, dayTotal)
+		if err != nil {
+			return fmt.Errorf("failed to add study minutes: %w", err)
+		}
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ type SubjectRepository interface {
 	GetSubjectByID(ctx context.Context, id string) (*models.Subject, error)
 	GetSubjectByCode(ctx context.Context, code string) (*models.Subject, error)
 	GetAllSubjects(ctx context.Context) ([]models.Subject, error)
+	IsSubjectOfUser(ctx context.Context, subjectID, userID string) (bool, error)
 }
 
 // PGSubjectRepository implements SubjectRepository for PostgreSQL.
@@ -97,6 +98,22 @@ func (r *PGSubjectRepository) GetAllSubjects(ctx context.Context) ([]models.Subj
 	return subjects, nil
 }
 
+// IsSubjectOfUser reports whether a subject is one the user takes: it is on their timetable or linked to
+// one of their exams, assignments or study sessions. Subjects are shared, so this is what ties them to a user.
+func (r *PGSubjectRepository) IsSubjectOfUser(ctx context.Context, subjectID, userID string) (bool, error) {
+	var taken bool
+	query := `
+		SELECT EXISTS (SELECT 1 FROM timetable_slots WHERE subject_id = $1 AND user_id = $2)
+			OR EXISTS (SELECT 1 FROM exams WHERE subject_id = $1 AND user_id = $2)
+			OR EXISTS (SELECT 1 FROM assignments WHERE subject_id = $1 AND user_id = $2)
+			OR EXISTS (SELECT 1 FROM study_sessions WHERE subject_id = $1 AND user_id = $2)
+	`
+	if err := r.db.QueryRow(ctx, query, subjectID, userID).Scan(&taken); err != nil {
+		return false, fmt.Errorf("failed to check subject of user: %w", err)
+	}
+	return taken, nil
+}
+
 // --- Staff Repository ---
 
 // StaffRepository defines the interface for staff data operations.
@@ -362,3 +379,70 @@ func (r *PGTimetableSlotRepository) GetTimetableSlotsByUserIDAndDateRange(ctx co
 	}
 	return slots, nil
 }
+
+// --- ClassAttendance Repository ---
+
+// ClassAttendanceRepository defines the interface for class attendance data operations.
+type ClassAttendanceRepository interface {
+	UpsertClassAttendance(ctx context.Context, attendance *models.ClassAttendance) error
+	GetClassAttendanceByUserIDAndDateRange(ctx context.Context, userID string, fromDate, toDate time.Time) ([]models.ClassAttendance, error)
+}
+
+// PGClassAttendanceRepository implements ClassAttendanceRepository for PostgreSQL.
+type PGClassAttendanceRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGClassAttendanceRepository creates a new PostgreSQL class attendance repository.
+func NewPGClassAttendanceRepository(db *pgxpool.Pool) *PGClassAttendanceRepository {
+	return &PGClassAttendanceRepository{db: db}
+}
+
+// UpsertClassAttendance records the attendance of a slot's class on a date, replacing an earlier record.
+func (r *PGClassAttendanceRepository) UpsertClassAttendance(ctx context.Context, attendance *models.ClassAttendance) error {
+	query := `
+		INSERT INTO class_attendance (id, user_id, slot_id, class_date, attended, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		ON CONFLICT (slot_id, class_date) DO UPDATE SET
+			attended = EXCLUDED.attended,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query,
+		models.NewUUID(), attendance.UserID, attendance.SlotID, attendance.ClassDate, attendance.Attended, time.Now(),
+	).Scan(&attendance.ID, &attendance.CreatedAt, &attendance.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record class attendance: %w", err)
+	}
+	return nil
+}
+
+// GetClassAttendanceByUserIDAndDateRange retrieves a user's attendance records between two dates,
+// inclusive, with the subject of each slot, ordered by date.
+func (r *PGClassAttendanceRepository) GetClassAttendanceByUserIDAndDateRange(ctx context.Context, userID string, fromDate, toDate time.Time) ([]models.ClassAttendance, error) {
+	var records []models.ClassAttendance
+	query := `
+		SELECT ca.id, ca.user_id, ca.slot_id, ts.subject_id, ca.class_date, ca.attended, ca.created_at, ca.updated_at
+		FROM class_attendance ca
+		JOIN timetable_slots ts ON ts.id = ca.slot_id
+		WHERE ca.user_id = $1 AND ca.class_date BETWEEN $2::date AND $3::date
+		ORDER BY ca.class_date ASC, ts.start_time ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID, fromDate, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get class attendance: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record models.ClassAttendance
+		err := rows.Scan(
+			&record.ID, &record.UserID, &record.SlotID, &record.SubjectID, &record.ClassDate, &record.Attended, &record.CreatedAt, &record.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan class attendance row: %w", err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
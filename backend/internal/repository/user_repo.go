@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -13,6 +14,7 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	GetUserByID(ctx context.Context, id string) (*models.User, error)
+	GetActiveUserIDs(ctx context.Context) ([]string, error)
 }
 
 // PGUserRepository implements UserRepository for PostgreSQL.
@@ -33,19 +35,20 @@ func (r *PGUserRepository) CreateUser(ctx context.Context, user *models.User) er
 			register_number, department, year, semester, section, batch, is_hosteler,
 			notification_preferences, theme, timezone,
 			google_id, github_id,
-			is_active, is_verified, last_login_at, created_at, updated_at
+			role, is_active, is_verified, last_login_at, created_at, updated_at
 		) VALUES (
 			$1, $2, $3, $4, $5, $6,
 			$7, $8, $9, $10, $11, $12, $13,
 			$14, $15, $16,
 			$17, $18,
-			$19, $20, $21, $22, $23
+			$19, $20, $21, $22, $23, $24
 		) RETURNING id, created_at, updated_at
 	`
 
 	user.ID = models.NewUUID() // Assuming models.NewUUID() exists to generate a UUID
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
+	user.Role = "student"
 	user.IsActive = true
 	user.IsVerified = false
 	user.NotificationPreferences = map[string]interface{}{
@@ -61,7 +64,7 @@ func (r *PGUserRepository) CreateUser(ctx context.Context, user *models.User) er
 		user.RegisterNumber, user.Department, user.Year, user.Semester, user.Section, user.Batch, user.IsHosteler,
 		user.NotificationPreferences, user.Theme, user.Timezone,
 		user.GoogleID, user.GithubID,
-		user.IsActive, user.IsVerified, user.LastLoginAt, user.CreatedAt, user.UpdatedAt,
+		user.Role, user.IsActive, user.IsVerified, user.LastLoginAt, user.CreatedAt, user.UpdatedAt,
 	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 
 	return err
@@ -75,7 +78,7 @@ func (r *PGUserRepository) GetUserByEmail(ctx context.Context, email string) (*m
 			register_number, department, year, semester, section, batch, is_hosteler,
 			notification_preferences, theme, timezone,
 			google_id, github_id,
-			is_active, is_verified, last_login_at, created_at, updated_at
+			role, is_active, is_verified, last_login_at, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -85,7 +88,7 @@ func (r *PGUserRepository) GetUserByEmail(ctx context.Context, email string) (*m
 		&user.RegisterNumber, &user.Department, &user.Year, &user.Semester, &user.Section, &user.Batch, &user.IsHosteler,
 		&user.NotificationPreferences, &user.Theme, &user.Timezone,
 		&user.GoogleID, &user.GithubID,
-		&user.IsActive, &user.IsVerified, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.Role, &user.IsActive, &user.IsVerified, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -101,7 +104,7 @@ func (r *PGUserRepository) GetUserByID(ctx context.Context, id string) (*models.
 			register_number, department, year, semester, section, batch, is_hosteler,
 			notification_preferences, theme, timezone,
 			google_id, github_id,
-			is_active, is_verified, last_login_at, created_at, updated_at
+			role, is_active, is_verified, last_login_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -111,10 +114,30 @@ func (r *PGUserRepository) GetUserByID(ctx context.Context, id string) (*models.
 		&user.RegisterNumber, &user.Department, &user.Year, &user.Semester, &user.Section, &user.Batch, &user.IsHosteler,
 		&user.NotificationPreferences, &user.Theme, &user.Timezone,
 		&user.GoogleID, &user.GithubID,
-		&user.IsActive, &user.IsVerified, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
+		&user.Role, &user.IsActive, &user.IsVerified, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
+
+// GetActiveUserIDs retrieves the IDs of all active users.
+func (r *PGUserRepository) GetActiveUserIDs(ctx context.Context) ([]string, error) {
+	query := `SELECT id FROM users WHERE is_active = true ORDER BY created_at`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active users: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
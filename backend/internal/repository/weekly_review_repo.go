@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// --- Weekly Review Repository ---
+
+// WeeklyReviewRepository defines the interface for weekly review data operations.
+type WeeklyReviewRepository interface {
+	UpsertWeeklyReview(ctx context.Context, review *models.WeeklyReview) error
+	GetWeeklyReviewByID(ctx context.Context, id string) (*models.WeeklyReview, error)
+	GetWeeklyReviewByUserIDAndWeek(ctx context.Context, userID string, weekStart time.Time) (*models.WeeklyReview, error)
+	GetWeeklyReviewsByUserID(ctx context.Context, userID string) ([]models.WeeklyReview, error)
+	MarkWeeklyReviewEmailed(ctx context.Context, id string, emailedAt time.Time) error
+	RecordWeeklyReviewEmailFailure(ctx context.Context, id string) (int32, error)
+
+	GetWeekAssignments(ctx context.Context, userID string, start, end time.Time) ([]models.WeeklyReviewAssignment, error)
+}
+
+// PGWeeklyReviewRepository implements WeeklyReviewRepository for PostgreSQL.
+type PGWeeklyReviewRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPGWeeklyReviewRepository creates a new PostgreSQL weekly review repository.
+func NewPGWeeklyReviewRepository(db *pgxpool.Pool) *PGWeeklyReviewRepository {
+	return &PGWeeklyReviewRepository{db: db}
+}
+
+// UpsertWeeklyReview stores a user's review for a week, replacing the report of an existing one.
+// The review's ID, emailed time, email attempts and timestamps are filled in from the stored row.
+func (r *PGWeeklyReviewRepository) UpsertWeeklyReview(ctx context.Context, review *models.WeeklyReview) error {
+	review.ID = models.NewUUID()
+	query := `
+		INSERT INTO weekly_reviews (id, user_id, week_start, week_end, report)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, week_start) DO UPDATE SET
+			week_end = EXCLUDED.week_end, report = EXCLUDED.report
+		RETURNING id, emailed_at, email_attempts, created_at, updated_at
+	`
+	err := r.db.QueryRow(ctx, query, review.ID, review.UserID, review.WeekStart, review.WeekEnd, review.Report).Scan(
+		&review.ID, &review.EmailedAt, &review.EmailAttempts, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save weekly review: %w", err)
+	}
+	return nil
+}
+
+// GetWeeklyReviewByID retrieves a weekly review by its ID.
+func (r *PGWeeklyReviewRepository) GetWeeklyReviewByID(ctx context.Context, id string) (*models.WeeklyReview, error) {
+	review := &models.WeeklyReview{}
+	query := `
+		SELECT id, user_id, week_start, week_end, report, emailed_at, email_attempts, created_at, updated_at
+		FROM weekly_reviews
+		WHERE id = $1
+	`
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&review.ID, &review.UserID, &review.WeekStart, &review.WeekEnd, &review.Report, &review.EmailedAt, &review.EmailAttempts, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly review by ID: %w", err)
+	}
+	return review, nil
+}
+
+// GetWeeklyReviewByUserIDAndWeek retrieves a user's review of the week starting on weekStart.
+func (r *PGWeeklyReviewRepository) GetWeeklyReviewByUserIDAndWeek(ctx context.Context, userID string, weekStart time.Time) (*models.WeeklyReview, error) {
+	review := &models.WeeklyReview{}
+	query := `
+		SELECT id, user_id, week_start, week_end, report, emailed_at, email_attempts, created_at, updated_at
+		FROM weekly_reviews
+		WHERE user_id = $1 AND week_start = $2
+	`
+	err := r.db.QueryRow(ctx, query, userID, weekStart).Scan(
+		&review.ID, &review.UserID, &review.WeekStart, &review.WeekEnd, &review.Report, &review.EmailedAt, &review.EmailAttempts, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly review by week: %w", err)
+	}
+	return review, nil
+}
+
+// GetWeeklyReviewsByUserID retrieves all of a user's weekly reviews, newest week first.
+func (r *PGWeeklyReviewRepository) GetWeeklyReviewsByUserID(ctx context.Context, userID string) ([]models.WeeklyReview, error) {
+	var reviews []models.WeeklyReview
+	query := `
+		SELECT id, user_id, week_start, week_end, report, emailed_at, email_attempts, created_at, updated_at
+		FROM weekly_reviews
+		WHERE user_id = $1
+		ORDER BY week_start DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weekly reviews by user ID: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		review := models.WeeklyReview{}
+		err := rows.Scan(
+			&review.ID, &review.UserID, &review.WeekStart, &review.WeekEnd, &review.Report, &review.EmailedAt, &review.EmailAttempts, &review.CreatedAt, &review.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan weekly review row: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, nil
+}
+
+// MarkWeeklyReviewEmailed records when a weekly review was emailed to its user.
+func (r *PGWeeklyReviewRepository) MarkWeeklyReviewEmailed(ctx context.Context, id string, emailedAt time.Time) error {
+	query := `UPDATE weekly_reviews SET emailed_at = $1 WHERE id = $2`
+	result, err := r.db.Exec(ctx, query, emailedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark weekly review emailed: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("weekly review with ID %s not found", id)
+	}
+	return nil
+}
+
+// RecordWeeklyReviewEmailFailure counts a failed attempt to email a weekly review and returns the number
+// of failed attempts so far.
+func (r *PGWeeklyReviewRepository) RecordWeeklyReviewEmailFailure(ctx context.Context, id string) (int32, error) {
+	var attempts int32
+	query := `UPDATE weekly_reviews SET email_attempts = email_attempts + 1 WHERE id = $1 RETURNING email_attempts`
+	if err := r.db.QueryRow(ctx, query, id).Scan(&attempts); err != nil {
+		return 0, fmt.Errorf("failed to record weekly review email failure: %w", err)
+	}
+	return attempts, nil
+}
+
+// GetWeekAssignments retrieves a user's assignments that were completed in [start, end) or fell due in it
+// before now, soonest due first.
+func (r *PGWeeklyReviewRepository) GetWeekAssignments(ctx context.Context, userID string, start, end time.Time) ([]models.WeeklyReviewAssignment, error) {
+	var assignments []models.WeeklyReviewAssignment
+	query := `
+		SELECT id, subject_id, title, status, due_date, completed_at
+		FROM assignments
+		WHERE user_id = $1
+		  AND ((completed_at >= $2 AND completed_at < $3)
+		    OR (due_date >= $2 AND due_date < LEAST($3, NOW())))
+		ORDER BY due_date ASC
+	`
+	rows, err := r.db.Query(ctx, query, userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get week assignments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		assignment := models.WeeklyReviewAssignment{}
+		err := rows.Scan(&assignment.ID, &assignment.SubjectID, &assignment.Title, &assignment.Status, &assignment.DueDate, &assignment.CompletedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan week assignment row: %w", err)
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+// ActivityLogSubscriber records every domain event as an activity log entry.
+type ActivityLogSubscriber struct {
+	activityLogRepo repository.ActivityLogRepository
+}
+
+// NewActivityLogSubscriber creates a new activity log subscriber.
+func NewActivityLogSubscriber(activityLogRepo repository.ActivityLogRepository) *ActivityLogSubscriber {
+	return &ActivityLogSubscriber{activityLogRepo: activityLogRepo}
+}
+
+// Register subscribes the activity log to all events on the bus.
+func (s *ActivityLogSubscriber) Register(bus events.Bus) {
+	bus.Subscribe(events.AllEvents, s.HandleEvent)
+}
+
+// HandleEvent writes an activity log entry for an event. Failures are logged, since the change that
+// caused the event has already been saved.
+func (s *ActivityLogSubscriber) HandleEvent(ctx context.Context, event events.Event) {
+	activityLog := &models.ActivityLog{
+		UserID:       event.UserID,
+		ActivityType: event.Type,
+		Description:  sql.NullString{String: event.Description, Valid: event.Description != ""},
+		EntityType:   sql.NullString{String: event.EntityType, Valid: event.EntityType != ""},
+		EntityID:     sql.NullString{String: event.EntityID, Valid: event.EntityID != ""},
+		IPAddress:    sql.NullString{String: event.Request.IPAddress, Valid: event.Request.IPAddress != ""},
+		UserAgent:    sql.NullString{String: event.Request.UserAgent, Valid: event.Request.UserAgent != ""},
+	}
+	if len(event.Metadata) > 0 {
+		metadata, err := json.Marshal(event.Metadata)
+		if err != nil {
+			log.Printf("Warning: Could not encode metadata of %s event for %s %s: %v", event.Type, event.EntityType, event.EntityID, err)
+		} else {
+			activityLog.Metadata = metadata
+		}
+	}
+
+	if err := s.activityLogRepo.CreateActivityLog(ctx, activityLog); err != nil {
+		log.Printf("Warning: Could not log %s event for %s %s: %v", event.Type, event.EntityType, event.EntityID, err)
+	}
+}
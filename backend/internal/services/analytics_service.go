@@ -2,33 +2,75 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
 )
 
+var (
+	// ErrDailyStatsNotFound is returned when a user has no daily stats for the requested date.
+	ErrDailyStatsNotFound = errors.New("daily stats not found")
+	// ErrInvalidDateRange is returned when a date range ends before it starts or spans too many days.
+	ErrInvalidDateRange = errors.New("invalid date range")
+	// ErrInvalidTimeSeriesBucket is returned for a time series bucket other than day, week or month.
+	ErrInvalidTimeSeriesBucket = errors.New("invalid time series bucket: use day, week or month")
+)
+
+const (
+	defaultActivityLogPageSize = 50
+	maxActivityLogPageSize     = 200
+	maxDailyStatsRangeDays     = 366
+	defaultSummaryWeeks        = 8
+	defaultSummaryMonths       = 6
+	maxSummaryPeriods          = 52
+	defaultTimeSeriesDays      = 365
+	defaultTimeSeriesWeeks     = 26
+	defaultTimeSeriesMonths    = 12
+	maxTimeSeriesBuckets       = 366
+	heatmapLevels              = 4
+)
+
 // AnalyticsService defines the interface for analytics-related business logic.
 type AnalyticsService interface {
 	CreateActivityLog(ctx context.Context, userID string, input *models.ActivityLogCreationInput) (*models.ActivityLog, error)
 	GetActivityLogsByUserID(ctx context.Context, userID string) ([]models.ActivityLog, error)
+	GetActivityLogs(ctx context.Context, userID string, filter *models.ActivityLogFilter) (*models.ActivityLogPage, error)
 	GetDailyStatsByUserID(ctx context.Context, userID string) ([]models.DailyStats, error)
 	GetDailyStatsByUserIDAndDate(ctx context.Context, userID string, date time.Time) (*models.DailyStats, error)
+	GetDailyStatsByUserIDAndDateRange(ctx context.Context, userID string, from, to time.Time) ([]models.DailyStats, error)
 	UpsertDailyStats(ctx context.Context, userID string, input *models.DailyStatsUpdateInput) (*models.DailyStats, error)
+
+	GetWeeklyTotals(ctx context.Context, userID string, weeks int) ([]models.DailyStatsTotals, error)
+	GetMonthlyTotals(ctx context.Context, userID string, months int) ([]models.DailyStatsTotals, error)
+	GetStudyStreak(ctx context.Context, userID string) (*models.StudyStreak, error)
+	GetTimeSeries(ctx context.Context, userID, bucket string, from, to time.Time) (*models.TimeSeries, error)
 }
 
 // analyticsService implements AnalyticsService.
 type analyticsService struct {
 	activityLogRepo repository.ActivityLogRepository
 	dailyStatsRepo  repository.DailyStatsRepository
+	userRepo        repository.UserRepository
 }
 
 // NewAnalyticsService creates a new analytics service.
-func NewAnalyticsService(activityLogRepo repository.ActivityLogRepository, dailyStatsRepo repository.DailyStatsRepository) AnalyticsService {
+func NewAnalyticsService(
+	activityLogRepo repository.ActivityLogRepository,
+	dailyStatsRepo repository.DailyStatsRepository,
+	userRepo repository.UserRepository,
+) AnalyticsService {
 	return &analyticsService{
 		activityLogRepo: activityLogRepo,
 		dailyStatsRepo:  dailyStatsRepo,
+		userRepo:        userRepo,
 	}
 }
 
@@ -37,7 +79,13 @@ func (s *analyticsService) CreateActivityLog(ctx context.Context, userID string,
 	activityLog := &models.ActivityLog{
 		UserID:       userID,
 		ActivityType: input.ActivityType,
-		Metadata:     input.Metadata,
+	}
+	if input.Metadata != nil {
+		metadata, err := json.Marshal(input.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata: %w", err)
+		}
+		activityLog.Metadata = metadata
 	}
 	if input.Description != nil {
 		activityLog.Description = sql.NullString{String: *input.Description, Valid: true}
@@ -66,6 +114,34 @@ func (s *analyticsService) GetActivityLogsByUserID(ctx context.Context, userID s
 	return s.activityLogRepo.GetActivityLogsByUserID(ctx, userID)
 }
 
+// GetActivityLogs retrieves one page of a user's activity logs matching a filter. The page size defaults to
+// 50 and is capped at 200.
+func (s *analyticsService) GetActivityLogs(ctx context.Context, userID string, filter *models.ActivityLogFilter) (*models.ActivityLogPage, error) {
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		return nil, fmt.Errorf("%w: end must be after start", ErrInvalidDateRange)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultActivityLogPageSize
+	}
+	filter.Limit = min(filter.Limit, maxActivityLogPageSize)
+	filter.Offset = max(filter.Offset, 0)
+
+	logs, total, err := s.activityLogRepo.GetActivityLogs(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if logs == nil {
+		logs = []models.ActivityLog{}
+	}
+	return &models.ActivityLogPage{
+		Logs:    logs,
+		Total:   total,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+		HasMore: filter.Offset+len(logs) < total,
+	}, nil
+}
+
 // GetDailyStatsByUserID retrieves all daily stats for a user.
 func (s *analyticsService) GetDailyStatsByUserID(ctx context.Context, userID string) ([]models.DailyStats, error) {
 	return s.dailyStatsRepo.GetDailyStatsByUserID(ctx, userID)
@@ -73,7 +149,25 @@ func (s *analyticsService) GetDailyStatsByUserID(ctx context.Context, userID str
 
 // GetDailyStatsByUserIDAndDate retrieves daily stats for a user on a specific date.
 func (s *analyticsService) GetDailyStatsByUserIDAndDate(ctx context.Context, userID string, date time.Time) (*models.DailyStats, error) {
-	return s.dailyStatsRepo.GetDailyStatsByUserIDAndDate(ctx, userID, date)
+	stats, err := s.dailyStatsRepo.GetDailyStatsByUserIDAndDate(ctx, userID, date)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDailyStatsNotFound
+		}
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetDailyStatsByUserIDAndDateRange retrieves a user's daily stats between two dates (inclusive), oldest first.
+func (s *analyticsService) GetDailyStatsByUserIDAndDateRange(ctx context.Context, userID string, from, to time.Time) ([]models.DailyStats, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: end date is before start date", ErrInvalidDateRange)
+	}
+	if to.Sub(from) > maxDailyStatsRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: a range can span at most %d days", ErrInvalidDateRange, maxDailyStatsRangeDays)
+	}
+	return s.dailyStatsRepo.GetDailyStatsByUserIDAndDateRange(ctx, userID, from, to)
 }
 
 // UpsertDailyStats creates or updates daily stats for a user.
@@ -97,11 +191,10 @@ func (s *analyticsService) UpsertDailyStats(ctx context.Context, userID string,
 	}
 
 	// Try to get existing stats
-	existingStats, err := s.dailyStatsRepo.GetDailyStatsByUserIDAndDate(ctx, userID, statDate)
+	existingStats, err := s.GetDailyStatsByUserIDAndDate(ctx, userID, statDate)
 	if err == nil { // Stats exist, update them
 		stats = existingStats
-	} else if err != nil && err.Error() != "failed to get daily stats by user ID and date: no rows in result set" {
-		// Only propagate error if it's not a "not found" error
+	} else if !errors.Is(err, ErrDailyStatsNotFound) {
 		return nil, fmt.Errorf("failed to check for existing daily stats: %w", err)
 	}
 
@@ -135,3 +228,242 @@ func (s *analyticsService) UpsertDailyStats(ctx context.Context, userID string,
 	}
 	return stats, nil
 }
+
+// GetWeeklyTotals sums a user's daily stats per week (Monday to Sunday) for the given number of weeks,
+// oldest first and ending with the current week in the user's timezone. Weeks without stats are included.
+func (s *analyticsService) GetWeeklyTotals(ctx context.Context, userID string, weeks int) ([]models.DailyStatsTotals, error) {
+	if weeks <= 0 {
+		weeks = defaultSummaryWeeks
+	}
+	weeks = min(weeks, maxSummaryPeriods)
+
+	today := s.today(ctx, userID)
+	currentWeek := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	var periods []models.DailyStatsTotals
+	for i := weeks - 1; i >= 0; i-- {
+		start := currentWeek.AddDate(0, 0, -7*i)
+		periods = append(periods, models.DailyStatsTotals{Period: "week", StartDate: start, EndDate: start.AddDate(0, 0, 6)})
+	}
+	return s.fillTotals(ctx, userID, periods, today)
+}
+
+// GetMonthlyTotals sums a user's daily stats per calendar month for the given number of months,
+// oldest first and ending with the current month in the user's timezone. Months without stats are included.
+func (s *analyticsService) GetMonthlyTotals(ctx context.Context, userID string, months int) ([]models.DailyStatsTotals, error) {
+	if months <= 0 {
+		months = defaultSummaryMonths
+	}
+	months = min(months, maxSummaryPeriods)
+
+	today := s.today(ctx, userID)
+	currentMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	var periods []models.DailyStatsTotals
+	for i := months - 1; i >= 0; i-- {
+		start := currentMonth.AddDate(0, -i, 0)
+		periods = append(periods, models.DailyStatsTotals{Period: "month", StartDate: start, EndDate: start.AddDate(0, 1, -1)})
+	}
+	return s.fillTotals(ctx, userID, periods, today)
+}
+
+// GetStudyStreak works out the user's current and longest runs of consecutive days with study time or
+// completed sessions. The current streak stays alive until a full day passes without activity.
+func (s *analyticsService) GetStudyStreak(ctx context.Context, userID string) (*models.StudyStreak, error) {
+	statsList, err := s.dailyStatsRepo.GetDailyStatsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var activeDays []time.Time // Newest first, as returned by the repository
+	for _, stats := range statsList {
+		if isActiveDay(stats) {
+			activeDays = append(activeDays, statDay(stats.StatDate))
+		}
+	}
+
+	streak := &models.StudyStreak{}
+	if len(activeDays) == 0 {
+		return streak, nil
+	}
+	today := s.today(ctx, userID)
+	streak.LastActiveDate = sql.NullTime{Time: activeDays[0], Valid: true}
+	streak.ActiveToday = activeDays[0].Equal(today)
+
+	runStart, runEnd, runDays := activeDays[0], activeDays[0], int32(1)
+	record := func() {
+		if runDays > streak.LongestDays {
+			streak.LongestDays = runDays
+			streak.LongestStart = sql.NullTime{Time: runStart, Valid: true}
+			streak.LongestEnd = sql.NullTime{Time: runEnd, Valid: true}
+		}
+	}
+	currentOpen := !activeDays[0].Before(today.AddDate(0, 0, -1))
+	for _, day := range activeDays[1:] {
+		if day.Equal(runStart.AddDate(0, 0, -1)) {
+			runStart = day
+			runDays++
+			continue
+		}
+		if currentOpen {
+			streak.CurrentDays = runDays
+			currentOpen = false
+		}
+		record()
+		runStart, runEnd, runDays = day, day, 1
+	}
+	if currentOpen {
+		streak.CurrentDays = runDays
+	}
+	record()
+	return streak, nil
+}
+
+// GetTimeSeries buckets the user's daily stats by day, week (Monday to Sunday) or calendar month, oldest
+// first, for heatmaps and trend charts. The range is widened to whole buckets; without one it ends with the
+// current bucket and covers the last 365 days, 26 weeks or 12 months. Buckets follow the calendar of the
+// user's timezone, so each starts at a local midnight even across daylight saving changes.
+func (s *analyticsService) GetTimeSeries(ctx context.Context, userID, bucket string, from, to time.Time) (*models.TimeSeries, error) {
+	if bucket == "" {
+		bucket = "day"
+	}
+	var next func(date time.Time) time.Time
+	switch bucket {
+	case "day":
+		next = func(date time.Time) time.Time { return date.AddDate(0, 0, 1) }
+	case "week":
+		next = func(date time.Time) time.Time { return date.AddDate(0, 0, 7) }
+	case "month":
+		next = func(date time.Time) time.Time { return date.AddDate(0, 1, 0) }
+	default:
+		return nil, fmt.Errorf("%w, not %q", ErrInvalidTimeSeriesBucket, bucket)
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	if from.IsZero() && to.IsZero() {
+		to = localToday(loc)
+		switch bucket {
+		case "day":
+			from = to.AddDate(0, 0, -(defaultTimeSeriesDays - 1))
+		case "week":
+			from = weekStartOf(to).AddDate(0, 0, -7*(defaultTimeSeriesWeeks-1))
+		case "month":
+			from = time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -(defaultTimeSeriesMonths - 1), 0)
+		}
+	}
+	from, to = statDay(from), statDay(to)
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to is before from", ErrInvalidDateRange)
+	}
+	switch bucket {
+	case "week":
+		from = weekStartOf(from)
+	case "month":
+		from = time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	series := &models.TimeSeries{Bucket: bucket, Timezone: loc.String(), FromDate: from, Points: []models.TimeSeriesPoint{}}
+	for start := from; !start.After(to); start = next(start) {
+		if len(series.Points) == maxTimeSeriesBuckets {
+			return nil, fmt.Errorf("%w: a time series can have at most %d buckets", ErrInvalidDateRange, maxTimeSeriesBuckets)
+		}
+		end := next(start)
+		series.Points = append(series.Points, models.TimeSeriesPoint{
+			Date:  start,
+			Start: time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc),
+			End:   time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc),
+		})
+	}
+	series.ToDate = next(series.Points[len(series.Points)-1].Date).AddDate(0, 0, -1)
+
+	statsList, err := s.dailyStatsRepo.GetDailyStatsByUserIDAndDateRange(ctx, userID, series.FromDate, series.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	for _, stats := range statsList {
+		day := statDay(stats.StatDate)
+		i := sort.Search(len(series.Points), func(i int) bool { return series.Points[i].Date.After(day) }) - 1
+		if i < 0 {
+			continue
+		}
+		p := &series.Points[i]
+		p.StudyMinutes += stats.StudyMinutes
+		p.XPEarned += stats.XPEarned
+		p.SessionsCompleted += stats.SessionsCompleted
+		p.AssignmentsCompleted += stats.AssignmentsCompleted
+		p.ClassesAttended += stats.ClassesAttended
+		p.TotalClasses += stats.TotalClasses
+	}
+
+	var busiest int32
+	for _, p := range series.Points {
+		busiest = max(busiest, p.StudyMinutes)
+	}
+	for i := range series.Points {
+		p := &series.Points[i]
+		p.CompletedTasks = p.SessionsCompleted + p.AssignmentsCompleted
+		if p.TotalClasses > 0 {
+			p.AttendanceRate = sql.NullFloat64{Float64: roundTo(float64(p.ClassesAttended)*100/float64(p.TotalClasses), 1), Valid: true}
+		}
+		if p.StudyMinutes > 0 {
+			p.Level = int32(math.Ceil(float64(p.StudyMinutes) * heatmapLevels / float64(busiest)))
+		}
+	}
+	return series, nil
+}
+
+// today returns the current date in the user's timezone as a UTC midnight, matching stat_date.
+func (s *analyticsService) today(ctx context.Context, userID string) time.Time {
+	now := time.Now().In(userLocation(ctx, s.userRepo, userID))
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// fillTotals adds up the daily stats falling into each of the consecutive periods.
+func (s *analyticsService) fillTotals(ctx context.Context, userID string, periods []models.DailyStatsTotals, today time.Time) ([]models.DailyStatsTotals, error) {
+	statsList, err := s.dailyStatsRepo.GetDailyStatsByUserIDAndDateRange(ctx, userID, periods[0].StartDate, periods[len(periods)-1].EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stats := range statsList {
+		day := statDay(stats.StatDate)
+		for i := range periods {
+			p := &periods[i]
+			if day.Before(p.StartDate) || day.After(p.EndDate) {
+				continue
+			}
+			p.StudyMinutes += stats.StudyMinutes
+			p.SessionsCompleted += stats.SessionsCompleted
+			p.TopicsCovered += stats.TopicsCovered
+			p.AssignmentsCompleted += stats.AssignmentsCompleted
+			p.AssignmentsAdded += stats.AssignmentsAdded
+			p.ClassesAttended += stats.ClassesAttended
+			p.TotalClasses += stats.TotalClasses
+			p.XPEarned += stats.XPEarned
+			if isActiveDay(stats) {
+				p.ActiveDays++
+			}
+			break
+		}
+	}
+
+	for i := range periods {
+		p := &periods[i]
+		if p.TotalClasses > 0 {
+			p.AttendanceRate = sql.NullFloat64{Float64: roundTo(float64(p.ClassesAttended)*100/float64(p.TotalClasses), 1), Valid: true}
+		}
+		elapsedDays := int(minTime(p.EndDate, today).Sub(p.StartDate).Hours()/24) + 1
+		if elapsedDays > 0 {
+			p.AverageStudyMinutes = roundTo(float64(p.StudyMinutes)/float64(elapsedDays), 1)
+		}
+	}
+	return periods, nil
+}
+
+// isActiveDay reports whether the user studied on the day of the stats.
+func isActiveDay(stats models.DailyStats) bool {
+	return stats.StudyMinutes > 0 || stats.SessionsCompleted > 0
+}
+
+// statDay normalises a scanned stat_date to a UTC midnight.
+func statDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
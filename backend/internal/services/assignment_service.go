@@ -3,8 +3,11 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
 )
@@ -19,16 +22,50 @@ type AssignmentService interface {
 	UpdateAssignment(ctx context.Context, userID string, id string, input *models.AssignmentCreationInput) (*models.Assignment, error)
 	UpdateAssignmentStatus(ctx context.Context, id string, status string) error
 	DeleteAssignment(ctx context.Context, id string) error
+	GetScoredAssignmentsByUserID(ctx context.Context, userID string) ([]models.ScoredAssignment, error)
+	GetFocusAssignments(ctx context.Context, userID string, limit int) ([]models.ScoredAssignment, error)
 }
 
+// Urgency score weights. They add up to 100 so the score reads as a percentage.
+const (
+	dueProximityWeight = 40.0
+	workloadWeight     = 30.0
+	marksWeight        = 15.0
+	creditsWeight      = 15.0
+
+	// maxSubjectCredits is the credit count that earns the full credits component.
+	maxSubjectCredits = 4.0
+	// freeTimeHorizonDays caps how far ahead free time is computed for a deadline.
+	freeTimeHorizonDays = 30
+	// studyDayStartHour and studyDayEndHour bound the hours of a day counted as free time.
+	studyDayStartHour = 8
+	studyDayEndHour   = 22
+)
+
 // assignmentService implements AssignmentService.
 type assignmentService struct {
 	assignmentRepo repository.AssignmentRepository
+	subjectRepo    repository.SubjectRepository
+	slotRepo       repository.TimetableSlotRepository
+	userRepo       repository.UserRepository
+	eventBus       events.Bus
 }
 
 // NewAssignmentService creates a new assignment service.
-func NewAssignmentService(assignmentRepo repository.AssignmentRepository) AssignmentService {
-	return &assignmentService{assignmentRepo: assignmentRepo}
+func NewAssignmentService(
+	assignmentRepo repository.AssignmentRepository,
+	subjectRepo repository.SubjectRepository,
+	slotRepo repository.TimetableSlotRepository,
+	userRepo repository.UserRepository,
+	eventBus events.Bus,
+) AssignmentService {
+	return &assignmentService{
+		assignmentRepo: assignmentRepo,
+		subjectRepo:    subjectRepo,
+		slotRepo:       slotRepo,
+		userRepo:       userRepo,
+		eventBus:       eventBus,
+	}
 }
 
 // CreateAssignment creates a new assignment for a user.
@@ -97,6 +134,14 @@ func (s *assignmentService) CreateAssignment(ctx context.Context, userID string,
 	if err := s.assignmentRepo.CreateAssignment(ctx, assignment); err != nil {
 		return nil, fmt.Errorf("failed to create assignment: %w", err)
 	}
+	s.eventBus.Publish(ctx, events.Event{
+		Type:        events.AssignmentCreated,
+		UserID:      userID,
+		EntityType:  "assignment",
+		EntityID:    assignment.ID,
+		Description: "Created assignment " + assignment.Title,
+		Metadata:    map[string]interface{}{"title": assignment.Title, "dueDate": assignment.DueDate, "status": assignment.Status},
+	})
 	return assignment, nil
 }
 
@@ -129,6 +174,7 @@ func (s *assignmentService) UpdateAssignment(ctx context.Context, userID string,
 	if existingAssignment.UserID != userID {
 		return nil, fmt.Errorf("assignment does not belong to user")
 	}
+	before := *existingAssignment
 
 	if input.DueDate != "" {
 		dueDate, err := time.Parse(time.RFC3339, input.DueDate)
@@ -228,15 +274,290 @@ func (s *assignmentService) UpdateAssignment(ctx context.Context, userID string,
 	if err := s.assignmentRepo.UpdateAssignment(ctx, existingAssignment); err != nil {
 		return nil, fmt.Errorf("failed to update assignment: %w", err)
 	}
+	publishAssignmentChange(ctx, s.eventBus, &before, existingAssignment)
 	return existingAssignment, nil
 }
 
 // UpdateAssignmentStatus updates the status of an assignment.
 func (s *assignmentService) UpdateAssignmentStatus(ctx context.Context, id string, status string) error {
-	return s.assignmentRepo.UpdateAssignmentStatus(ctx, id, status)
+	existingAssignment, err := s.assignmentRepo.GetAssignmentByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("assignment not found: %w", err)
+	}
+	if err := s.assignmentRepo.UpdateAssignmentStatus(ctx, id, status); err != nil {
+		return err
+	}
+	updated := *existingAssignment
+	updated.Status = status
+	publishAssignmentChange(ctx, s.eventBus, existingAssignment, &updated)
+	return nil
 }
 
 // DeleteAssignment deletes an assignment.
 func (s *assignmentService) DeleteAssignment(ctx context.Context, id string) error {
-	return s.assignmentRepo.DeleteAssignment(ctx, id)
+	assignment, err := s.assignmentRepo.GetAssignmentByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("assignment not found: %w", err)
+	}
+	if err := s.assignmentRepo.DeleteAssignment(ctx, id); err != nil {
+		return err
+	}
+	publishAssignmentDeleted(ctx, s.eventBus, assignment)
+	return nil
+}
+
+// publishAssignmentChange publishes the changed fields of an updated assignment, and a completion
+// event when its status moved to completed.
+func publishAssignmentChange(ctx context.Context, eventBus events.Bus, before, after *models.Assignment) {
+	changes := events.Changes(before, after)
+	if len(changes) == 0 {
+		return
+	}
+	eventBus.Publish(ctx, events.Event{
+		Type:        events.AssignmentUpdated,
+		UserID:      after.UserID,
+		EntityType:  "assignment",
+		EntityID:    after.ID,
+		Description: "Updated assignment " + after.Title,
+		Metadata:    map[string]interface{}{"changes": changes},
+	})
+	if after.Status == "completed" && before.Status != "completed" {
+		eventBus.Publish(ctx, events.Event{
+			Type:        events.AssignmentCompleted,
+			UserID:      after.UserID,
+			EntityType:  "assignment",
+			EntityID:    after.ID,
+			Description: "Completed assignment " + after.Title,
+			Metadata:    map[string]interface{}{"title": after.Title, "dueDate": after.DueDate},
+		})
+	}
+}
+
+// publishAssignmentDeleted publishes the deletion of an assignment.
+func publishAssignmentDeleted(ctx context.Context, eventBus events.Bus, assignment *models.Assignment) {
+	eventBus.Publish(ctx, events.Event{
+		Type:        events.AssignmentDeleted,
+		UserID:      assignment.UserID,
+		EntityType:  "assignment",
+		EntityID:    assignment.ID,
+		Description: "Deleted assignment " + assignment.Title,
+		Metadata:    map[string]interface{}{"title": assignment.Title},
+	})
+}
+
+
+// GetScoredAssignmentsByUserID retrieves all assignments for a user with their urgency scores,
+// sorted from most to least urgent.
+func (s *assignmentService) GetScoredAssignmentsByUserID(ctx context.Context, userID string) ([]models.ScoredAssignment, error) {
+	assignments, err := s.assignmentRepo.GetAssignmentsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
+	}
+	return s.scoreAssignments(ctx, userID, assignments)
+}
+
+// GetFocusAssignments returns the open assignments the user should work on now, most urgent first.
+func (s *assignmentService) GetFocusAssignments(ctx context.Context, userID string, limit int) ([]models.ScoredAssignment, error) {
+	assignments, err := s.assignmentRepo.GetAssignmentsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
+	}
+
+	var open []models.Assignment
+	for _, a := range assignments {
+		if isOpenAssignmentStatus(a.Status) {
+			open = append(open, a)
+		}
+	}
+
+	scored, err := s.scoreAssignments(ctx, userID, open)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(scored) > limit {
+		scored = scored[:limit]
+	}
+	return scored, nil
+}
+
+// scoreAssignments computes urgency scores for the given assignments and sorts them by score.
+func (s *assignmentService) scoreAssignments(ctx context.Context, userID string, assignments []models.Assignment) ([]models.ScoredAssignment, error) {
+	loc := userLocation(ctx, s.userRepo, userID)
+	now := time.Now().In(loc)
+
+	busy, err := weeklyBusySlots(ctx, s.slotRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credits := make(map[string]sql.NullInt32)
+	scored := make([]models.ScoredAssignment, 0, len(assignments))
+	for _, a := range assignments {
+		var subjectCredits sql.NullInt32
+		if a.SubjectID.Valid {
+			c, ok := credits[a.SubjectID.String]
+			if !ok {
+				subject, err := s.subjectRepo.GetSubjectByID(ctx, a.SubjectID.String)
+				if err == nil {
+					c = subject.Credits
+				}
+				credits[a.SubjectID.String] = c
+			}
+			subjectCredits = c
+		}
+
+		freeHours := freeHoursBetween(now, a.DueDate.In(loc), busy)
+		scored = append(scored, scoreAssignment(a, now, freeHours, subjectCredits))
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		if scored[i].UrgencyScore == scored[j].UrgencyScore {
+			return scored[i].DueDate.Before(scored[j].DueDate)
+		}
+		return scored[i].UrgencyScore > scored[j].UrgencyScore
+	})
+	return scored, nil
+}
+
+// weeklyBusySlots returns the user's recurring timetable slots indexed by day of week.
+func weeklyBusySlots(ctx context.Context, slotRepo repository.TimetableSlotRepository, userID string) (map[time.Weekday][]models.TimetableSlot, error) {
+	busy := make(map[time.Weekday][]models.TimetableSlot)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		slots, err := slotRepo.GetTimetableSlotsByUserIDAndDay(ctx, userID, int32(day))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get timetable slots: %w", err)
+		}
+		busy[day] = slots
+	}
+	return busy, nil
+}
+
+// scoreAssignment computes the urgency score for a single assignment.
+func scoreAssignment(a models.Assignment, now time.Time, freeHours float64, subjectCredits sql.NullInt32) models.ScoredAssignment {
+	breakdown := models.AssignmentScoreBreakdown{
+		HoursUntilDue: roundTo(a.DueDate.Sub(now).Hours(), 1),
+		FreeHours:     roundTo(freeHours, 1),
+	}
+	var explanation []string
+
+	// Due proximity decays with days left: due now scores 1, three days out scores 0.5.
+	daysLeft := a.DueDate.Sub(now).Hours() / 24
+	proximity := 1.0
+	if daysLeft > 0 {
+		proximity = 1 / (1 + daysLeft/3)
+	}
+	breakdown.DueProximity = roundTo(proximity*dueProximityWeight, 1)
+	switch {
+	case daysLeft <= 0:
+		explanation = append(explanation, "Overdue")
+	case daysLeft < 1:
+		explanation = append(explanation, fmt.Sprintf("Due in %.0f hours", daysLeft*24))
+	default:
+		explanation = append(explanation, fmt.Sprintf("Due in %.1f days", daysLeft))
+	}
+
+	// Workload compares the hours still needed with the free hours left before the deadline.
+	if a.EstimatedHours.Valid {
+		remaining := a.EstimatedHours.Float64
+		if a.ActualHours.Valid {
+			remaining -= a.ActualHours.Float64
+		}
+		remaining = math.Max(remaining, 0)
+		breakdown.RemainingHours = roundTo(remaining, 1)
+
+		pressure := 0.0
+		switch {
+		case remaining == 0:
+		case freeHours <= 0:
+			pressure = 1
+		default:
+			pressure = math.Min(remaining/freeHours, 1)
+		}
+		breakdown.Workload = roundTo(pressure*workloadWeight, 1)
+		explanation = append(explanation, fmt.Sprintf("Needs %.1f more hours with %.1f free hours before the deadline", remaining, freeHours))
+	} else {
+		explanation = append(explanation, "No estimated hours set, workload not considered")
+	}
+
+	// Marks weight saturates at 100 marks.
+	if a.MaxMarks.Valid && a.MaxMarks.Float64 > 0 {
+		breakdown.MarksWeight = roundTo(math.Min(a.MaxMarks.Float64/100, 1)*marksWeight, 1)
+		explanation = append(explanation, fmt.Sprintf("Worth %.0f marks", a.MaxMarks.Float64))
+	}
+
+	if subjectCredits.Valid && subjectCredits.Int32 > 0 {
+		breakdown.Credits = roundTo(math.Min(float64(subjectCredits.Int32)/maxSubjectCredits, 1)*creditsWeight, 1)
+		explanation = append(explanation, fmt.Sprintf("Subject carries %d credits", subjectCredits.Int32))
+	}
+
+	score := breakdown.DueProximity + breakdown.Workload + breakdown.MarksWeight + breakdown.Credits
+	return models.ScoredAssignment{
+		Assignment:     a,
+		UrgencyScore:   roundTo(score, 1),
+		ScoreBreakdown: breakdown,
+		Explanation:    explanation,
+	}
+}
+
+// freeHoursBetween counts the study-day hours between from and to that are not taken by timetable slots.
+func freeHoursBetween(from, to time.Time, busy map[time.Weekday][]models.TimetableSlot) float64 {
+	if !to.After(from) {
+		return 0
+	}
+	horizon := from.AddDate(0, 0, freeTimeHorizonDays)
+	if to.After(horizon) {
+		to = horizon
+	}
+
+	loc := from.Location()
+	total := 0.0
+	for day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc); day.Before(to); day = day.AddDate(0, 0, 1) {
+		windowStart := maxTime(day.Add(studyDayStartHour*time.Hour), from)
+		windowEnd := minTime(day.Add(studyDayEndHour*time.Hour), to)
+		if !windowEnd.After(windowStart) {
+			continue
+		}
+		free := windowEnd.Sub(windowStart)
+		for _, slot := range busy[day.Weekday()] {
+			slotStart := time.Date(day.Year(), day.Month(), day.Day(), slot.StartTime.Hour(), slot.StartTime.Minute(), 0, 0, loc)
+			slotEnd := time.Date(day.Year(), day.Month(), day.Day(), slot.EndTime.Hour(), slot.EndTime.Minute(), 0, 0, loc)
+			overlapStart := maxTime(slotStart, windowStart)
+			overlapEnd := minTime(slotEnd, windowEnd)
+			if overlapEnd.After(overlapStart) {
+				free -= overlapEnd.Sub(overlapStart)
+			}
+		}
+		if free > 0 {
+			total += free.Hours()
+		}
+	}
+	return total
+}
+
+// isOpenAssignmentStatus reports whether an assignment with the given status still needs work.
+func isOpenAssignmentStatus(status string) bool {
+	switch status {
+	case "pending", "in_progress", "overdue":
+		return true
+	}
+	return false
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func roundTo(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
 }
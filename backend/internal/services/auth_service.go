@@ -89,9 +89,10 @@ func (s *authService) Login(ctx context.Context, input *models.LoginUserInput) (
 
 	// Create JWT token
 	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": user.ID,
-		"exp": time.Now().Add(time.Hour * 24 * 7).Unix(), // Token expires in 7 days
-		"iat": time.Now().Unix(),
+		"sub":  user.ID,
+		"role": user.Role,
+		"exp":  time.Now().Add(time.Hour * 24 * 7).Unix(), // Token expires in 7 days
+		"iat":  time.Now().Unix(),
 	})
 
 	token, err := claims.SignedString([]byte(s.jwtSecret))
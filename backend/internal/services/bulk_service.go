@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+// maxBulkIDs caps the number of explicit IDs accepted by a single bulk operation.
+const maxBulkIDs = 500
+
+// ErrInvalidBulkOperation is returned when a bulk operation fails validation.
+var ErrInvalidBulkOperation = errors.New("invalid bulk operation")
+
+// bulkStatuses lists the statuses accepted by 'set_status' for each entity type.
+var bulkStatuses = map[string][]string{
+	"assignment": {"pending", "in_progress", "completed", "submitted", "graded", "overdue"},
+	"exam":       {"not_started", "in_progress", "revision", "ready"},
+	"lab_record": {"pending", "practiced", "written", "printed", "submitted", "signed", "returned"},
+}
+
+// BulkService defines the interface for bulk operations on assignments, exams and lab records.
+type BulkService interface {
+	ApplyBulkOperation(ctx context.Context, userID, entityType string, input *models.BulkOperationInput) (*models.BulkOperationResult, error)
+}
+
+// bulkService implements BulkService.
+type bulkService struct {
+	bulkRepo       repository.BulkRepository
+	subjectRepo    repository.SubjectRepository
+	assignmentRepo repository.AssignmentRepository
+	examRepo       repository.ExamRepository
+	eventBus       events.Bus
+}
+
+// NewBulkService creates a new bulk service.
+func NewBulkService(
+	bulkRepo repository.BulkRepository,
+	subjectRepo repository.SubjectRepository,
+	assignmentRepo repository.AssignmentRepository,
+	examRepo repository.ExamRepository,
+	eventBus events.Bus,
+) BulkService {
+	return &bulkService{
+		bulkRepo:       bulkRepo,
+		subjectRepo:    subjectRepo,
+		assignmentRepo: assignmentRepo,
+		examRepo:       examRepo,
+		eventBus:       eventBus,
+	}
+}
+
+// bulkSnapshot holds the assignments or exams a bulk operation may touch, keyed by ID.
+type bulkSnapshot struct {
+	assignments map[string]*models.Assignment
+	exams       map[string]*models.Exam
+}
+
+// ApplyBulkOperation validates a bulk operation and applies it to the user's items of the given entity type.
+func (s *bulkService) ApplyBulkOperation(ctx context.Context, userID, entityType string, input *models.BulkOperationInput) (*models.BulkOperationResult, error) {
+	statuses, ok := bulkStatuses[entityType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported bulk entity type: %s", ErrInvalidBulkOperation, entityType)
+	}
+
+	if (len(input.IDs) == 0) == (input.Filter == nil) {
+		return nil, fmt.Errorf("%w: exactly one of ids or filter must be provided", ErrInvalidBulkOperation)
+	}
+	if len(input.IDs) > maxBulkIDs {
+		return nil, fmt.Errorf("%w: at most %d ids can be processed at once", ErrInvalidBulkOperation, maxBulkIDs)
+	}
+	if input.Filter != nil {
+		if input.Filter.Status != nil && !containsString(statuses, *input.Filter.Status) {
+			return nil, fmt.Errorf("%w: invalid filter status: %s", ErrInvalidBulkOperation, *input.Filter.Status)
+		}
+		for _, date := range []*string{input.Filter.DateFrom, input.Filter.DateTo} {
+			if date == nil {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", *date); err != nil {
+				return nil, fmt.Errorf("%w: invalid filter date format: %w", ErrInvalidBulkOperation, err)
+			}
+		}
+	}
+
+	switch input.Action {
+	case "set_status":
+		if input.Status == nil || !containsString(statuses, *input.Status) {
+			return nil, fmt.Errorf("%w: a valid status is required for set_status", ErrInvalidBulkOperation)
+		}
+	case "add_tags", "remove_tags":
+		if len(input.Tags) == 0 {
+			return nil, fmt.Errorf("%w: tags are required for %s", ErrInvalidBulkOperation, input.Action)
+		}
+	case "set_subject":
+		if input.SubjectID != nil {
+			if _, err := s.subjectRepo.GetSubjectByID(ctx, *input.SubjectID); err != nil {
+				return nil, fmt.Errorf("%w: subject not found: %w", ErrInvalidBulkOperation, err)
+			}
+		}
+	}
+
+	before, err := s.snapshot(ctx, userID, entityType, input)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.bulkRepo.ApplyBulkOperation(ctx, userID, entityType, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply bulk operation: %w", err)
+	}
+	s.publishChanges(ctx, userID, entityType, input, before, items)
+
+	result := &models.BulkOperationResult{
+		Action:  input.Action,
+		Matched: len(items),
+		Results: items,
+	}
+	for _, item := range items {
+		if item.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+	if result.Results == nil {
+		result.Results = []models.BulkItemResult{}
+	}
+	return result, nil
+}
+
+// snapshot loads the user's assignments or exams a bulk operation may touch, so that the events
+// published afterwards carry the same changes as an update of a single item. Lab records publish no events.
+func (s *bulkService) snapshot(ctx context.Context, userID, entityType string, input *models.BulkOperationInput) (*bulkSnapshot, error) {
+	snapshot := &bulkSnapshot{
+		assignments: make(map[string]*models.Assignment),
+		exams:       make(map[string]*models.Exam),
+	}
+	switch entityType {
+	case "assignment":
+		if input.Filter != nil {
+			assignments, err := s.assignmentRepo.GetAssignmentsByUserID(ctx, userID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load assignments: %w", err)
+			}
+			for i := range assignments {
+				snapshot.assignments[assignments[i].ID] = &assignments[i]
+			}
+			break
+		}
+		for _, id := range input.IDs {
+			// Missing and foreign items are reported as failed by the repository.
+			if assignment, err := s.assignmentRepo.GetAssignmentByID(ctx, id); err == nil && assignment.UserID == userID {
+				snapshot.assignments[id] = assignment
+			}
+		}
+	case "exam":
+		if input.Filter != nil {
+			exams, err := s.examRepo.GetExamsByUserID(ctx, userID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load exams: %w", err)
+			}
+			for i := range exams {
+				snapshot.exams[exams[i].ID] = &exams[i]
+			}
+			break
+		}
+		for _, id := range input.IDs {
+			if exam, err := s.examRepo.GetExamByID(ctx, id); err == nil && exam.UserID == userID {
+				snapshot.exams[id] = exam
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// publishChanges publishes the events of the single-item paths for every item a bulk operation
+// changed or deleted: the changed fields, completions and deletions.
+func (s *bulkService) publishChanges(ctx context.Context, userID, entityType string, input *models.BulkOperationInput, before *bulkSnapshot, items []models.BulkItemResult) {
+	if len(before.assignments) == 0 && len(before.exams) == 0 {
+		return
+	}
+	var ids []string
+	for _, item := range items {
+		if item.Success {
+			ids = append(ids, item.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	after := &bulkSnapshot{}
+	if input.Action != "delete" {
+		var err error
+		if after, err = s.snapshot(ctx, userID, entityType, &models.BulkOperationInput{IDs: ids}); err != nil {
+			log.Printf("Warning: Could not load items after bulk %s for user %s: %v", input.Action, userID, err)
+			return
+		}
+	}
+
+	for _, id := range ids {
+		if assignment, ok := before.assignments[id]; ok {
+			if input.Action == "delete" {
+				publishAssignmentDeleted(ctx, s.eventBus, assignment)
+			} else if updated, ok := after.assignments[id]; ok {
+				publishAssignmentChange(ctx, s.eventBus, assignment, updated)
+			}
+		}
+		if exam, ok := before.exams[id]; ok {
+			if input.Action == "delete" {
+				publishExamDeleted(ctx, s.eventBus, exam)
+			} else if updated, ok := after.exams[id]; ok {
+				publishExamChange(ctx, s.eventBus, exam, updated)
+			}
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+// Coverage thresholds (percentages) for advancing an exam's prep status.
+const (
+	revisionCoverageThreshold = 80.0 // covered topics needed to move to 'revision'
+	readyCoverageThreshold    = 95.0 // covered topics needed to move to 'ready'
+	readyRevisionThreshold    = 75.0 // revised topics needed to move to 'ready'
+	readyPracticeThreshold    = 60.0 // practised questions needed to move to 'ready', when there are any
+)
+
+// prepStatusRank orders prep statuses so coverage tracking only ever moves them forward.
+var prepStatusRank = map[string]int{
+	"not_started": 0,
+	"in_progress": 1,
+	"revision":    2,
+	"ready":       3,
+}
+
+// CoverageService defines the interface for syllabus coverage tracking.
+type CoverageService interface {
+	GetExamCoverage(ctx context.Context, userID, examID string) (*models.ExamCoverage, error)
+	GetUpcomingExamCoverage(ctx context.Context, userID string) ([]models.ExamCoverage, error)
+	ClearPrepStatusOverride(ctx context.Context, userID, examID string) (*models.ExamCoverage, error)
+	RefreshCoverage(ctx context.Context, userID string, subjectID string) error
+	Register(bus events.Bus)
+}
+
+// coverageService implements CoverageService.
+type coverageService struct {
+	examRepo              repository.ExamRepository
+	sessionRepo           repository.StudySessionRepository
+	importantQuestionRepo repository.ImportantQuestionRepository
+}
+
+// NewCoverageService creates a new coverage service.
+func NewCoverageService(
+	examRepo repository.ExamRepository,
+	sessionRepo repository.StudySessionRepository,
+	importantQuestionRepo repository.ImportantQuestionRepository,
+) CoverageService {
+	return &coverageService{
+		examRepo:              examRepo,
+		sessionRepo:           sessionRepo,
+		importantQuestionRepo: importantQuestionRepo,
+	}
+}
+
+// Register advances prep statuses whenever a question is practised or an exam is created or edited.
+// Completed study sessions refresh coverage directly where they are completed.
+func (s *coverageService) Register(bus events.Bus) {
+	bus.Subscribe(events.QuestionPractised, s.handleEvent)
+	bus.Subscribe(events.ExamCreated, s.handleEvent)
+	bus.Subscribe(events.ExamUpdated, s.handleEvent)
+}
+
+// handleEvent refreshes the coverage of the user's upcoming exams. Failures are logged, since the change
+// that caused the event has already been saved.
+func (s *coverageService) handleEvent(ctx context.Context, event events.Event) {
+	if err := s.RefreshCoverage(ctx, event.UserID, ""); err != nil {
+		log.Printf("Warning: Could not refresh coverage after %s for user %s: %v", event.Type, event.UserID, err)
+	}
+}
+
+// GetExamCoverage computes the coverage of one of the user's exams. Prep statuses are only advanced on
+// writes, so reading coverage has no side effects.
+func (s *coverageService) GetExamCoverage(ctx context.Context, userID, examID string) (*models.ExamCoverage, error) {
+	exam, err := s.examRepo.GetExamByID(ctx, examID)
+	if err != nil {
+		return nil, fmt.Errorf("exam not found: %w", err)
+	}
+	if exam.UserID != userID {
+		return nil, fmt.Errorf("exam does not belong to user")
+	}
+
+	sessions, questions, err := s.loadProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return computeExamCoverage(*exam, sessions, questions), nil
+}
+
+// GetUpcomingExamCoverage computes the coverage of every upcoming exam.
+func (s *coverageService) GetUpcomingExamCoverage(ctx context.Context, userID string) ([]models.ExamCoverage, error) {
+	exams, err := s.examRepo.GetUpcomingExamsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming exams: %w", err)
+	}
+	sessions, questions, err := s.loadProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	coverages := []models.ExamCoverage{}
+	for i := range exams {
+		coverages = append(coverages, *computeExamCoverage(exams[i], sessions, questions))
+	}
+	return coverages, nil
+}
+
+// ClearPrepStatusOverride hands an exam's prep status back to coverage tracking and recomputes it.
+func (s *coverageService) ClearPrepStatusOverride(ctx context.Context, userID, examID string) (*models.ExamCoverage, error) {
+	exam, err := s.examRepo.GetExamByID(ctx, examID)
+	if err != nil {
+		return nil, fmt.Errorf("exam not found: %w", err)
+	}
+	if exam.UserID != userID {
+		return nil, fmt.Errorf("exam does not belong to user")
+	}
+
+	// Restart from scratch so a manual status that was ahead of the actual coverage is corrected.
+	exam.PrepStatus = "not_started"
+	exam.PrepStatusOverride = false
+	if err := s.examRepo.UpdateExamPrepStatus(ctx, exam.ID, exam.PrepStatus, false); err != nil {
+		return nil, err
+	}
+
+	sessions, questions, err := s.loadProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyCoverage(ctx, exam, sessions, questions)
+}
+
+// RefreshCoverage advances the prep status of the user's upcoming exams for a subject, or of all
+// upcoming exams when subjectID is empty.
+func (s *coverageService) RefreshCoverage(ctx context.Context, userID string, subjectID string) error {
+	exams, err := s.examRepo.GetUpcomingExamsByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get upcoming exams: %w", err)
+	}
+	sessions, questions, err := s.loadProgress(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for i := range exams {
+		if subjectID != "" && exams[i].SubjectID.String != subjectID {
+			continue
+		}
+		if _, err := s.applyCoverage(ctx, &exams[i], sessions, questions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadProgress loads the study sessions and important questions coverage is computed from.
+func (s *coverageService) loadProgress(ctx context.Context, userID string) ([]models.StudySession, []models.ImportantQuestion, error) {
+	sessions, err := s.sessionRepo.GetStudySessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get study sessions: %w", err)
+	}
+	questions, err := s.importantQuestionRepo.GetImportantQuestionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get important questions: %w", err)
+	}
+	return sessions, questions, nil
+}
+
+// applyCoverage computes an exam's coverage and, unless the status was set manually, moves its prep
+// status forward to the suggested one.
+func (s *coverageService) applyCoverage(ctx context.Context, exam *models.Exam, sessions []models.StudySession, questions []models.ImportantQuestion) (*models.ExamCoverage, error) {
+	coverage := computeExamCoverage(*exam, sessions, questions)
+	if !exam.PrepStatusOverride && prepStatusRank[coverage.SuggestedPrepStatus] > prepStatusRank[exam.PrepStatus] {
+		if err := s.examRepo.UpdateExamPrepStatus(ctx, exam.ID, coverage.SuggestedPrepStatus, false); err != nil {
+			return nil, err
+		}
+		exam.PrepStatus = coverage.SuggestedPrepStatus
+		coverage.PrepStatus = exam.PrepStatus
+		coverage.PrepStatusAdvanced = true
+	}
+	return coverage, nil
+}
+
+// computeExamCoverage classifies each syllabus topic of an exam (falling back to its units) as
+// untouched, covered once or revised, using the topics covered in study sessions for the exam's
+// subject up to the exam date.
+func computeExamCoverage(exam models.Exam, sessions []models.StudySession, questions []models.ImportantQuestion) *models.ExamCoverage {
+	coverage := &models.ExamCoverage{
+		ExamID:             exam.ID,
+		ExamTitle:          exam.Title,
+		ExamDate:           exam.ExamDate,
+		SubjectID:          exam.SubjectID,
+		CoveredTopics:      []string{},
+		RevisedTopics:      []string{},
+		UntouchedTopics:    []string{},
+		PrepStatus:         exam.PrepStatus,
+		PrepStatusOverride: exam.PrepStatusOverride,
+	}
+
+	topics := uniqueTopics(exam.SyllabusTopics)
+	if len(topics) == 0 {
+		topics = uniqueTopics(exam.SyllabusUnits)
+	}
+	coverage.TotalTopics = len(topics)
+
+	examEnd := time.Date(exam.ExamDate.Year(), exam.ExamDate.Month(), exam.ExamDate.Day()+1, 0, 0, 0, 0, time.UTC)
+	for _, topic := range topics {
+		key := normalizeTopic(topic)
+		timesStudied, revised := 0, false
+		for _, session := range sessions {
+			if session.Status == "skipped" || !sessionCountsForExam(session, exam, examEnd) {
+				continue
+			}
+			for _, studied := range session.TopicsCovered {
+				if topicsMatch(key, normalizeTopic(studied)) {
+					timesStudied++
+					if session.SessionType == "revision" || session.SessionType == "practice" {
+						revised = true
+					}
+					break
+				}
+			}
+		}
+
+		switch {
+		case timesStudied == 0:
+			coverage.UntouchedTopics = append(coverage.UntouchedTopics, topic)
+		case revised || timesStudied > 1:
+			coverage.RevisedTopics = append(coverage.RevisedTopics, topic)
+		default:
+			coverage.CoveredTopics = append(coverage.CoveredTopics, topic)
+		}
+	}
+
+	for _, q := range questions {
+		if q.ExamID.String == exam.ID || (!q.ExamID.Valid && exam.SubjectID.Valid && q.SubjectID.String == exam.SubjectID.String) {
+			coverage.TotalQuestions++
+			if q.IsPracticed {
+				coverage.PracticedQuestions++
+			}
+		}
+	}
+
+	if coverage.TotalTopics > 0 {
+		touched := len(coverage.CoveredTopics) + len(coverage.RevisedTopics)
+		coverage.CoveragePercentage = roundTo(float64(touched)/float64(coverage.TotalTopics)*100, 1)
+		coverage.RevisionPercentage = roundTo(float64(len(coverage.RevisedTopics))/float64(coverage.TotalTopics)*100, 1)
+	}
+	if coverage.TotalQuestions > 0 {
+		coverage.PracticePercentage = roundTo(float64(coverage.PracticedQuestions)/float64(coverage.TotalQuestions)*100, 1)
+	}
+
+	coverage.SuggestedPrepStatus = suggestPrepStatus(exam, coverage)
+	return coverage
+}
+
+// suggestPrepStatus derives a prep status from coverage, revision and practice.
+func suggestPrepStatus(exam models.Exam, coverage *models.ExamCoverage) string {
+	practiceReady := coverage.TotalQuestions == 0 || coverage.PracticePercentage >= readyPracticeThreshold
+	switch {
+	case coverage.TotalTopics > 0 && coverage.CoveragePercentage >= readyCoverageThreshold &&
+		coverage.RevisionPercentage >= readyRevisionThreshold && practiceReady:
+		return "ready"
+	case coverage.TotalTopics > 0 && coverage.CoveragePercentage >= revisionCoverageThreshold:
+		return "revision"
+	case coverage.CoveragePercentage > 0 || coverage.PracticedQuestions > 0 ||
+		(exam.StudyHoursLogged.Valid && exam.StudyHoursLogged.Float64 > 0):
+		return "in_progress"
+	default:
+		return "not_started"
+	}
+}
+
+// sessionCountsForExam reports whether a session belongs to the exam's subject and took place before
+// the end of the exam day. Exams without a subject count sessions of every subject.
+func sessionCountsForExam(session models.StudySession, exam models.Exam, examEnd time.Time) bool {
+	if exam.SubjectID.Valid && session.SubjectID.String != exam.SubjectID.String {
+		return false
+	}
+	when := session.CreatedAt
+	if session.ActualStartTime.Valid {
+		when = session.ActualStartTime.Time
+	} else if session.PlannedStartTime.Valid {
+		when = session.PlannedStartTime.Time
+	}
+	return when.Before(examEnd)
+}
+
+// normalizeTopic lower-cases a topic name and reduces punctuation and repeated whitespace to single
+// spaces, so "Unit-3: Normalisation " and "unit 3 normalisation" compare equal.
+func normalizeTopic(topic string) string {
+	fields := strings.FieldsFunc(strings.ToLower(topic), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// topicsMatch compares two normalised topic names. Besides exact matches, a name of at least four
+// characters matches a longer name that contains it as whole words, e.g. "joins" and "sql joins".
+func topicsMatch(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if a == b {
+		return true
+	}
+	shorter, longer := a, b
+	if len(shorter) > len(longer) {
+		shorter, longer = longer, shorter
+	}
+	if len(shorter) < 4 {
+		return false
+	}
+	return strings.Contains(" "+longer+" ", " "+shorter+" ")
+}
+
+// uniqueTopics drops blank and duplicate (after normalisation) topic names, keeping the first spelling.
+func uniqueTopics(topics []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, topic := range topics {
+		key := normalizeTopic(topic)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, strings.TrimSpace(topic))
+	}
+	return result
+}
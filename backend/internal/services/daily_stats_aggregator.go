@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+const (
+	// dailyStatsReconcileHour is the local hour from which a user's recent days are reconciled each night.
+	dailyStatsReconcileHour = 3
+	// dailyStatsReconcileDays is how many days before today the nightly reconciliation recomputes.
+	dailyStatsReconcileDays = 3
+)
+
+// DailyStatsAggregator defines the interface for deriving daily stats from the source tables.
+type DailyStatsAggregator interface {
+	Backfill(ctx context.Context, input *models.DailyStatsBackfillInput) (*models.DailyStatsBackfillResult, error)
+	ReconcileRecentDays(ctx context.Context) error
+	RunScheduler(ctx context.Context, interval time.Duration)
+	Register(bus events.Bus)
+}
+
+// dailyStatsAggregator implements DailyStatsAggregator.
+type dailyStatsAggregator struct {
+	dailyStatsRepo repository.DailyStatsRepository
+	userRepo       repository.UserRepository
+
+	mu         sync.Mutex
+	reconciled map[string]time.Time // Local date each user was last reconciled on
+}
+
+// NewDailyStatsAggregator creates a new daily stats aggregator.
+func NewDailyStatsAggregator(
+	dailyStatsRepo repository.DailyStatsRepository,
+	userRepo repository.UserRepository,
+) DailyStatsAggregator {
+	return &dailyStatsAggregator{
+		dailyStatsRepo: dailyStatsRepo,
+		userRepo:       userRepo,
+		reconciled:     make(map[string]time.Time),
+	}
+}
+
+// Register recomputes the affected day whenever an assignment, study session or class attendance changes.
+func (s *dailyStatsAggregator) Register(bus events.Bus) {
+	bus.Subscribe(events.AllEvents, s.handleEvent)
+}
+
+// handleEvent recomputes the user's stats for the local day the event occurred on, or for attendance the
+// day of the class. Changes that land on other days, such as a session completed after midnight, are
+// picked up by the nightly reconciliation.
+func (s *dailyStatsAggregator) handleEvent(ctx context.Context, event events.Event) {
+	if event.EntityType != "assignment" && event.EntityType != "study_session" && event.EntityType != "class_attendance" {
+		return
+	}
+	loc := userLocation(ctx, s.userRepo, event.UserID)
+	occurred := event.OccurredAt.In(loc)
+	date := time.Date(occurred.Year(), occurred.Month(), occurred.Day(), 0, 0, 0, 0, time.UTC)
+	if classDate, ok := event.Metadata["classDate"].(string); ok {
+		if parsed, err := time.Parse("2006-01-02", classDate); err == nil {
+			date = parsed
+		}
+	}
+	if _, err := s.recomputeDay(ctx, event.UserID, date, loc); err != nil {
+		log.Printf("Warning: Could not update daily stats for user %s after %s: %v", event.UserID, event.Type, err)
+	}
+}
+
+// Backfill recomputes the daily stats of one user, or of every active user, for each date in a range.
+// A failing day is logged and counted, and the backfill carries on with the rest.
+func (s *dailyStatsAggregator) Backfill(ctx context.Context, input *models.DailyStatsBackfillInput) (*models.DailyStatsBackfillResult, error) {
+	from, err := time.Parse("2006-01-02", input.FromDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from date format: %w", err)
+	}
+	to, err := time.Parse("2006-01-02", input.ToDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to date format: %w", err)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: end date is before start date", ErrInvalidDateRange)
+	}
+	if to.Sub(from) > maxDailyStatsRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: a range can span at most %d days", ErrInvalidDateRange, maxDailyStatsRangeDays)
+	}
+
+	var userIDs []string
+	if input.UserID != nil {
+		if _, err := s.userRepo.GetUserByID(ctx, *input.UserID); err != nil {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+		userIDs = []string{*input.UserID}
+	} else {
+		userIDs, err = s.userRepo.GetActiveUserIDs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &models.DailyStatsBackfillResult{FromDate: from, ToDate: to, Users: len(userIDs)}
+	for _, userID := range userIDs {
+		loc := userLocation(ctx, s.userRepo, userID)
+		for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if _, err := s.recomputeDay(ctx, userID, date, loc); err != nil {
+				log.Printf("Warning: Could not backfill daily stats for user %s on %s: %v", userID, date.Format("2006-01-02"), err)
+				result.Failed++
+				continue
+			}
+			result.Days++
+		}
+	}
+	return result, nil
+}
+
+// ReconcileRecentDays recomputes the last few days, today included, of every active user whose local
+// time has passed the reconciliation hour and who has not been reconciled yet today. Run hourly, this
+// reconciles each user once a night in their own timezone, even after a missed run, and corrects anything
+// the incremental updates missed. A user whose days failed to reconcile is retried on the next run.
+func (s *dailyStatsAggregator) ReconcileRecentDays(ctx context.Context) error {
+	userIDs, err := s.userRepo.GetActiveUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		loc := userLocation(ctx, s.userRepo, userID)
+		now := time.Now().In(loc)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		if now.Hour() < dailyStatsReconcileHour || !s.lastReconciled(userID).Before(today) {
+			continue
+		}
+		failed := false
+		for offset := dailyStatsReconcileDays; offset >= 0; offset-- {
+			date := today.AddDate(0, 0, -offset)
+			if _, err := s.recomputeDay(ctx, userID, date, loc); err != nil {
+				log.Printf("Warning: Could not reconcile daily stats for user %s on %s: %v", userID, date.Format("2006-01-02"), err)
+				failed = true
+			}
+		}
+		if !failed {
+			s.mu.Lock()
+			s.reconciled[userID] = today
+			s.mu.Unlock()
+		}
+	}
+	return nil
+}
+
+// lastReconciled returns the local date the user was last reconciled on, or the zero time.
+func (s *dailyStatsAggregator) lastReconciled(userID string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reconciled[userID]
+}
+
+// RunScheduler reconciles recent daily stats right away and then every interval, until the context is
+// cancelled. The interval should be at most an hour so users are reconciled soon after their reconciliation hour.
+func (s *dailyStatsAggregator) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.ReconcileRecentDays(ctx); err != nil {
+			log.Printf("Warning: Daily stats reconciliation failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recomputeDay derives and stores a user's stats for a date, which is a midnight UTC calendar date
+// interpreted in loc.
+func (s *dailyStatsAggregator) recomputeDay(ctx context.Context, userID string, date time.Time, loc *time.Location) (*models.DailyStats, error) {
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	stats, err := s.dailyStatsRepo.AggregateDailyStats(ctx, userID, date, dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.dailyStatsRepo.SaveAggregatedDailyStats(ctx, stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+const (
+	// dashboardCacheTTL is how long a user's dashboard is served from memory before it is rebuilt.
+	dashboardCacheTTL = 30 * time.Second
+	// defaultWeeklyStudyGoalMinutes is the weekly study goal the dashboard measures progress against when
+	// the user has not set a weekly study hours goal.
+	defaultWeeklyStudyGoalMinutes = 600
+	// dashboardRecentDocuments is how many of the newest documents the dashboard lists.
+	dashboardRecentDocuments = 5
+)
+
+// DashboardService defines the interface for the dashboard summary.
+type DashboardService interface {
+	GetDashboard(ctx context.Context, userID string, refresh bool) (*models.Dashboard, error)
+	Register(bus events.Bus)
+}
+
+// dashboardCacheEntry is a built dashboard and when it goes stale.
+type dashboardCacheEntry struct {
+	dashboard *models.Dashboard
+	expiresAt time.Time
+}
+
+// dashboardService implements DashboardService.
+type dashboardService struct {
+	slotRepo            repository.TimetableSlotRepository
+	examRepo            repository.ExamRepository
+	assignmentRepo      repository.AssignmentRepository
+	labRecordRepo       repository.LabRecordRepository
+	documentRepo        repository.DocumentRepository
+	userRepo            repository.UserRepository
+	analyticsService    AnalyticsService
+	gamificationService GamificationService
+	goalService         GoalService
+
+	mu    sync.Mutex
+	cache map[string]dashboardCacheEntry
+}
+
+// NewDashboardService creates a new dashboard service.
+func NewDashboardService(
+	slotRepo repository.TimetableSlotRepository,
+	examRepo repository.ExamRepository,
+	assignmentRepo repository.AssignmentRepository,
+	labRecordRepo repository.LabRecordRepository,
+	documentRepo repository.DocumentRepository,
+	userRepo repository.UserRepository,
+	analyticsService AnalyticsService,
+	gamificationService GamificationService,
+	goalService GoalService,
+) DashboardService {
+	return &dashboardService{
+		slotRepo:            slotRepo,
+		examRepo:            examRepo,
+		assignmentRepo:      assignmentRepo,
+		labRecordRepo:       labRecordRepo,
+		documentRepo:        documentRepo,
+		userRepo:            userRepo,
+		analyticsService:    analyticsService,
+		gamificationService: gamificationService,
+		goalService:         goalService,
+		cache:               make(map[string]dashboardCacheEntry),
+	}
+}
+
+// applyWeeklyStudyGoals measures progress against the user's active weekly study hours goals. An overall
+// goal replaces the default target; with only per-subject goals, the study minutes of those subjects are
+// measured against the sum of their targets.
+func (s *dashboardService) applyWeeklyStudyGoals(ctx context.Context, userID string, progress *models.WeeklyStudyProgress) error {
+	goals, err := s.goalService.GetGoals(ctx, userID, false)
+	if err != nil {
+		return err
+	}
+	var subjectGoalMinutes, subjectStudyMinutes int32
+	for _, goal := range goals {
+		if goal.Status != "active" || goal.Metric != "study_hours" || goal.Period != "weekly" || goal.Progress == nil {
+			continue
+		}
+		if !goal.SubjectID.Valid {
+			progress.GoalMinutes = int32(math.Round(goal.TargetValue * 60))
+			progress.GoalSet = true
+			return nil
+		}
+		subjectGoalMinutes += int32(math.Round(goal.TargetValue * 60))
+		subjectStudyMinutes += int32(math.Round(goal.Progress.CurrentValue * 60))
+	}
+	if subjectGoalMinutes > 0 {
+		progress.GoalMinutes = subjectGoalMinutes
+		progress.StudyMinutes = subjectStudyMinutes
+		progress.GoalSet = true
+	}
+	return nil
+}
+
+// Register drops a user's cached dashboard whenever something of theirs changes, so edits show up
+// straight away instead of after the cache expires.
+func (s *dashboardService) Register(bus events.Bus) {
+	bus.Subscribe(events.AllEvents, func(ctx context.Context, event events.Event) {
+		s.invalidate(event.UserID)
+	})
+}
+
+// GetDashboard returns the user's dashboard, from the cache unless it is stale or refresh is set.
+// The sections are loaded concurrently; if any of them fails the whole dashboard fails.
+func (s *dashboardService) GetDashboard(ctx context.Context, userID string, refresh bool) (*models.Dashboard, error) {
+	if !refresh {
+		if dashboard, ok := s.cached(userID); ok {
+			return dashboard, nil
+		}
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	now := time.Now().In(loc)
+	dashboard := &models.Dashboard{
+		Date:               time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		TodayTimetable:     []models.TimetableSlot{},
+		LabRecordsByStatus: map[string]int{},
+		RecentDocuments:    []models.Document{},
+		GeneratedAt:        time.Now(),
+	}
+
+	var (
+		wg    sync.WaitGroup
+		errMu sync.Mutex
+		errs  []error
+	)
+	run := func(load func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := load(); err != nil {
+				errMu.Lock()
+				errs = append(errs, err)
+				errMu.Unlock()
+			}
+		}()
+	}
+
+	run(func() error {
+		slots, err := s.slotRepo.GetTimetableSlotsByUserIDAndDay(ctx, userID, int32(now.Weekday()))
+		if err != nil {
+			return err
+		}
+		if slots != nil {
+			dashboard.TodayTimetable = slots
+		}
+		return nil
+	})
+	run(func() error {
+		exams, err := s.examRepo.GetUpcomingExamsByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		dashboard.NextExam = nextExamCountdown(exams, now, loc)
+		return nil
+	})
+	run(func() error {
+		pending, err := s.assignmentRepo.GetPendingAssignmentsByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		dashboard.Assignments.Pending = len(pending)
+		return nil
+	})
+	run(func() error {
+		overdue, err := s.assignmentRepo.GetOverdueAssignmentsByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		dashboard.Assignments.Overdue = len(overdue)
+		return nil
+	})
+	run(func() error {
+		labRecords, err := s.labRecordRepo.GetLabRecordsByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, labRecord := range labRecords {
+			dashboard.LabRecordsByStatus[labRecord.Status]++
+		}
+		return nil
+	})
+	run(func() error {
+		weeks, err := s.analyticsService.GetWeeklyTotals(ctx, userID, 1)
+		if err != nil {
+			return err
+		}
+		progress := models.WeeklyStudyProgress{GoalMinutes: defaultWeeklyStudyGoalMinutes}
+		if len(weeks) > 0 {
+			progress.WeekStart = weeks[len(weeks)-1].StartDate
+			progress.StudyMinutes = weeks[len(weeks)-1].StudyMinutes
+		}
+		if err := s.applyWeeklyStudyGoals(ctx, userID, &progress); err != nil {
+			return err
+		}
+		progress.Percentage = roundTo(float64(progress.StudyMinutes)/float64(progress.GoalMinutes)*100, 1)
+		dashboard.WeeklyStudy = progress
+		return nil
+	})
+	run(func() error {
+		documents, err := s.documentRepo.GetDocumentsByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if len(documents) > dashboardRecentDocuments {
+			documents = documents[:dashboardRecentDocuments] // Newest first, as returned by the repository
+		}
+		if documents != nil {
+			dashboard.RecentDocuments = documents
+		}
+		return nil
+	})
+	run(func() error {
+		progress, err := s.gamificationService.GetProgress(ctx, userID)
+		if err != nil {
+			return err
+		}
+		dashboard.Streak = models.DashboardStreak{
+			CurrentStreak: progress.CurrentStreak,
+			LongestStreak: progress.LongestStreak,
+			StudiedToday:  progress.StudiedToday,
+			FreezeTokens:  progress.FreezeTokens,
+			Level:         progress.Level,
+			TotalXP:       progress.TotalXP,
+		}
+		return nil
+	})
+
+	wg.Wait()
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	s.store(userID, dashboard)
+	return dashboard, nil
+}
+
+// nextExamCountdown picks the first upcoming exam that has not started yet. Exams come ordered by date
+// and start time; one without a start time counts as upcoming for its whole day.
+func nextExamCountdown(exams []models.Exam, now time.Time, loc *time.Location) *models.ExamCountdown {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, exam := range exams {
+		examDay := time.Date(exam.ExamDate.Year(), exam.ExamDate.Month(), exam.ExamDate.Day(), 0, 0, 0, 0, time.UTC)
+		if examDay.Before(today) {
+			continue
+		}
+		countdown := &models.ExamCountdown{
+			ExamID:     exam.ID,
+			SubjectID:  exam.SubjectID,
+			Title:      exam.Title,
+			ExamType:   exam.ExamType,
+			ExamDate:   exam.ExamDate,
+			DaysLeft:   int32(examDay.Sub(today).Hours() / 24),
+			PrepStatus: exam.PrepStatus,
+		}
+		if exam.StartTime.Valid {
+			startsAt := time.Date(examDay.Year(), examDay.Month(), examDay.Day(), exam.StartTime.Time.Hour(), exam.StartTime.Time.Minute(), 0, 0, loc)
+			if startsAt.Before(now) {
+				continue
+			}
+			countdown.StartsAt.Time, countdown.StartsAt.Valid = startsAt, true
+		}
+		return countdown
+	}
+	return nil
+}
+
+// cached returns the user's dashboard if it has not gone stale.
+func (s *dashboardService) cached(userID string) (*models.Dashboard, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.cache[userID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.dashboard, true
+}
+
+// store caches a user's dashboard and evicts everyone else's stale entries along the way.
+func (s *dashboardService) store(userID string, dashboard *models.Dashboard) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id, entry := range s.cache {
+		if now.After(entry.expiresAt) {
+			delete(s.cache, id)
+		}
+	}
+	s.cache[userID] = dashboardCacheEntry{dashboard: dashboard, expiresAt: now.Add(dashboardCacheTTL)}
+}
+
+// invalidate drops a user's cached dashboard.
+func (s *dashboardService) invalidate(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cache, userID)
+}
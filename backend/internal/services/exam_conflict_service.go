@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+// examPrepHours is the default number of preparation hours expected for each exam type.
+var examPrepHours = map[string]float64{
+	"cat1":      8,
+	"cat2":      8,
+	"cat3":      8,
+	"fat":       15,
+	"model":     10,
+	"retest":    6,
+	"quiz":      2,
+	"viva":      3,
+	"practical": 4,
+}
+
+// defaultExamPrepHours is used for exam types missing from examPrepHours.
+const defaultExamPrepHours = 6.0
+
+// examPrepWindowDays is how many days before an exam its remaining prep hours are spread over.
+const examPrepWindowDays = 14
+
+// The limits used when the scheduler checks each user's exams for conflicts to notify about.
+const (
+	conflictNotifyMaxExamsPerDay = 1
+	conflictNotifyWeeks          = 4
+)
+
+// ExamConflictService defines the interface for detecting exam clashes and crunch periods.
+type ExamConflictService interface {
+	AnalyzeExamConflicts(ctx context.Context, userID string, maxExamsPerDay, weeks int) (*models.ExamConflictReport, error)
+	NotifyConflicts(ctx context.Context) error
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+// examConflictService implements ExamConflictService.
+type examConflictService struct {
+	examRepo            repository.ExamRepository
+	assignmentRepo      repository.AssignmentRepository
+	slotRepo            repository.TimetableSlotRepository
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+}
+
+// NewExamConflictService creates a new exam conflict service.
+func NewExamConflictService(
+	examRepo repository.ExamRepository,
+	assignmentRepo repository.AssignmentRepository,
+	slotRepo repository.TimetableSlotRepository,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+) ExamConflictService {
+	return &examConflictService{
+		examRepo:            examRepo,
+		assignmentRepo:      assignmentRepo,
+		slotRepo:            slotRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// AnalyzeExamConflicts flags overlapping exams, days with more than maxExamsPerDay exams and, for the
+// next number of weeks, weeks where remaining assignment and exam prep hours exceed the free time.
+func (s *examConflictService) AnalyzeExamConflicts(ctx context.Context, userID string, maxExamsPerDay, weeks int) (*models.ExamConflictReport, error) {
+	exams, err := s.examRepo.GetUpcomingExamsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming exams: %w", err)
+	}
+	assignments, err := s.assignmentRepo.GetAssignmentsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
+	}
+	busy, err := weeklyBusySlots(ctx, s.slotRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := userLocation(ctx, s.userRepo, userID)
+
+	return &models.ExamConflictReport{
+		MaxExamsPerDay: maxExamsPerDay,
+		Weeks:          weeks,
+		Clashes:        findExamClashes(exams),
+		OverloadedDays: findOverloadedDays(exams, maxExamsPerDay),
+		CrunchWeeks:    findCrunchWeeks(exams, assignments, busy, time.Now().In(loc), weeks),
+		GeneratedAt:    time.Now(),
+	}, nil
+}
+
+// NotifyConflicts analyses every active user's exams with the default limits and raises a
+// notification for each finding, once per underlying condition.
+func (s *examConflictService) NotifyConflicts(ctx context.Context) error {
+	userIDs, err := s.userRepo.GetActiveUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		report, err := s.AnalyzeExamConflicts(ctx, userID, conflictNotifyMaxExamsPerDay, conflictNotifyWeeks)
+		if err != nil {
+			log.Printf("Warning: Could not analyse exam conflicts for user %s: %v", userID, err)
+			continue
+		}
+		if err := s.notifyConflicts(ctx, userID, report); err != nil {
+			log.Printf("Warning: Could not notify exam conflicts for user %s: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// RunScheduler checks for exam conflicts right away and then every interval, until the context is cancelled.
+func (s *examConflictService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.NotifyConflicts(ctx); err != nil {
+			log.Printf("Warning: Exam conflict check failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifyConflicts raises a notification for every finding in the report.
+func (s *examConflictService) notifyConflicts(ctx context.Context, userID string, report *models.ExamConflictReport) error {
+	notify := func(notificationType, title, message, entityType, entityID, dedupeKey string) error {
+		_, err := s.notificationService.Notify(ctx, userID, notificationType, title, message, entityType, entityID, dedupeKey)
+		return err
+	}
+
+	for _, clash := range report.Clashes {
+		err := notify("exam_clash", "Exam clash on "+clash.Date,
+			fmt.Sprintf("%s overlaps with %s by %d minutes", clash.ExamTitles[0], clash.ExamTitles[1], clash.OverlapMinutes),
+			"exam", clash.ExamIDs[0], "exam_clash:"+clash.ExamIDs[0]+":"+clash.ExamIDs[1])
+		if err != nil {
+			return err
+		}
+	}
+	for _, day := range report.OverloadedDays {
+		err := notify("exam_overload", fmt.Sprintf("%d exams on %s", day.ExamCount, day.Date),
+			strings.Join(day.ExamTitles, ", "),
+			"exam", day.ExamIDs[0], fmt.Sprintf("exam_overload:%s:%d", day.Date, day.ExamCount))
+		if err != nil {
+			return err
+		}
+	}
+	for _, week := range report.CrunchWeeks {
+		err := notify("crunch_week", "Crunch week starting "+week.WeekStart,
+			fmt.Sprintf("%.1f hours of work but only %.1f free hours between %s and %s", week.RequiredHours, week.FreeHours, week.WeekStart, week.WeekEnd),
+			"", "", "crunch_week:"+week.WeekStart)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findExamClashes returns every pair of exams on the same date whose times overlap.
+// Exams without a start time cannot clash and are only counted towards overloaded days.
+func findExamClashes(exams []models.Exam) []models.ExamClash {
+	clashes := []models.ExamClash{}
+	for i := 0; i < len(exams); i++ {
+		startA, endA, ok := examTimeRange(exams[i])
+		if !ok {
+			continue
+		}
+		for j := i + 1; j < len(exams); j++ {
+			if !sameDate(exams[i].ExamDate, exams[j].ExamDate) {
+				continue
+			}
+			startB, endB, ok := examTimeRange(exams[j])
+			if !ok {
+				continue
+			}
+			overlap := minTime(endA, endB).Sub(maxTime(startA, startB))
+			if overlap <= 0 {
+				continue
+			}
+			clashes = append(clashes, models.ExamClash{
+				Date:           exams[i].ExamDate.Format("2006-01-02"),
+				ExamIDs:        []string{exams[i].ID, exams[j].ID},
+				ExamTitles:     []string{exams[i].Title, exams[j].Title},
+				OverlapMinutes: int(overlap.Minutes()),
+			})
+		}
+	}
+	return clashes
+}
+
+// findOverloadedDays returns the dates with more than maxExamsPerDay exams.
+func findOverloadedDays(exams []models.Exam, maxExamsPerDay int) []models.OverloadedDay {
+	byDate := make(map[string]*models.OverloadedDay)
+	var dates []string
+	for _, e := range exams {
+		date := e.ExamDate.Format("2006-01-02")
+		if byDate[date] == nil {
+			byDate[date] = &models.OverloadedDay{Date: date}
+			dates = append(dates, date)
+		}
+		byDate[date].ExamCount++
+		byDate[date].ExamIDs = append(byDate[date].ExamIDs, e.ID)
+		byDate[date].ExamTitles = append(byDate[date].ExamTitles, e.Title)
+	}
+
+	sort.Strings(dates)
+	days := []models.OverloadedDay{}
+	for _, date := range dates {
+		if byDate[date].ExamCount > maxExamsPerDay {
+			days = append(days, *byDate[date])
+		}
+	}
+	return days
+}
+
+// findCrunchWeeks compares the work due in each of the coming weeks with the free time in that week.
+func findCrunchWeeks(exams []models.Exam, assignments []models.Assignment, busy map[time.Weekday][]models.TimetableSlot, now time.Time, weeks int) []models.CrunchWeek {
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7)) // Monday
+
+	crunches := []models.CrunchWeek{}
+	for w := 0; w < weeks; w++ {
+		start := weekStart.AddDate(0, 0, 7*w)
+		end := start.AddDate(0, 0, 7)
+		week := models.CrunchWeek{
+			WeekStart:     start.Format("2006-01-02"),
+			WeekEnd:       end.AddDate(0, 0, -1).Format("2006-01-02"),
+			AssignmentIDs: []string{},
+			ExamIDs:       []string{},
+		}
+
+		var required float64
+		for _, a := range assignments {
+			due := a.DueDate.In(loc)
+			if !isOpenAssignmentStatus(a.Status) || !a.EstimatedHours.Valid || due.Before(start) || !due.Before(end) {
+				continue
+			}
+			remaining := a.EstimatedHours.Float64
+			if a.ActualHours.Valid {
+				remaining -= a.ActualHours.Float64
+			}
+			if remaining > 0 {
+				required += remaining
+				week.AssignmentIDs = append(week.AssignmentIDs, a.ID)
+			}
+		}
+		for _, e := range exams {
+			if e.PrepStatus == "ready" {
+				continue
+			}
+			if hours := examPrepHoursBetween(e, today, start, end); hours > 0 {
+				required += hours
+				week.ExamIDs = append(week.ExamIDs, e.ID)
+			}
+		}
+
+		free := freeHoursBetween(maxTime(start, now), end, busy)
+		if required > free {
+			week.RequiredHours = roundTo(required, 1)
+			week.FreeHours = roundTo(free, 1)
+			week.DeficitHours = roundTo(required-free, 1)
+			crunches = append(crunches, week)
+		}
+	}
+	return crunches
+}
+
+// examPrepHoursBetween returns the share of an exam's remaining prep hours that falls between start and
+// end. The hours are spread evenly over the days from today, or examPrepWindowDays before the exam if
+// that is later, up to the day before the exam; an exam today keeps them all on its own day.
+func examPrepHoursBetween(e models.Exam, today, start, end time.Time) float64 {
+	remaining := remainingExamPrepHours(e)
+	if remaining <= 0 {
+		return 0
+	}
+	loc := today.Location()
+	examDate := time.Date(e.ExamDate.Year(), e.ExamDate.Month(), e.ExamDate.Day(), 0, 0, 0, 0, loc)
+
+	windowStart := examDate.AddDate(0, 0, -examPrepWindowDays)
+	if windowStart.Before(today) {
+		windowStart = today
+	}
+	windowEnd := examDate
+	if !windowEnd.After(windowStart) {
+		windowEnd = windowStart.AddDate(0, 0, 1)
+	}
+
+	windowDays := daysBetween(windowStart, windowEnd)
+	overlap := daysBetween(maxTime(windowStart, start), minTime(windowEnd, end))
+	if overlap <= 0 {
+		return 0
+	}
+	return remaining * float64(overlap) / float64(windowDays)
+}
+
+// daysBetween counts the calendar days from one local midnight up to another.
+func daysBetween(from, to time.Time) int {
+	days := 0
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		days++
+	}
+	return days
+}
+
+// remainingExamPrepHours estimates the preparation hours still needed for an exam.
+func remainingExamPrepHours(e models.Exam) float64 {
+	hours, ok := examPrepHours[e.ExamType]
+	if !ok {
+		hours = defaultExamPrepHours
+	}
+	if e.StudyHoursLogged.Valid {
+		hours -= e.StudyHoursLogged.Float64
+	}
+	return math.Max(hours, 0)
+}
+
+// examTimeRange returns the start and end of an exam on its date, deriving the end from the
+// duration when no end time is set.
+func examTimeRange(e models.Exam) (time.Time, time.Time, bool) {
+	if !e.StartTime.Valid {
+		return time.Time{}, time.Time{}, false
+	}
+	date := e.ExamDate
+	start := time.Date(date.Year(), date.Month(), date.Day(), e.StartTime.Time.Hour(), e.StartTime.Time.Minute(), 0, 0, time.UTC)
+
+	var end time.Time
+	switch {
+	case e.EndTime.Valid:
+		end = time.Date(date.Year(), date.Month(), date.Day(), e.EndTime.Time.Hour(), e.EndTime.Time.Minute(), 0, 0, time.UTC)
+	case e.DurationMinutes.Valid:
+		end = start.Add(time.Duration(e.DurationMinutes.Int32) * time.Minute)
+	default:
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, end.After(start)
+}
+
+func sameDate(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
+}
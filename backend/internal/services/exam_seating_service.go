@@ -0,0 +1,425 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidSeatingImport is returned when a seating chart or its column mapping cannot be used.
+	ErrInvalidSeatingImport = errors.New("invalid seating chart")
+	// ErrNoRegisterNumber is returned when importing a seating chart for a user without a register number.
+	ErrNoRegisterNumber = errors.New("set your register number in your profile to import a seating chart")
+	// ErrSeatNotInChart is returned when the user's register number is not in an imported seating chart.
+	ErrSeatNotInChart = errors.New("your register number was not found in the seating chart")
+)
+
+const (
+	// defaultReportingLead is how long before the start students report when no reporting time is known.
+	defaultReportingLead = 30 * time.Minute
+	// maxSeatingChartRows caps the number of rows scanned in an imported seating chart.
+	maxSeatingChartRows = 20000
+)
+
+// seatingChartFields lists the columns read from a seating chart, in their default column order
+// used when there is no mapping and no header.
+var seatingChartFields = []string{
+	"register_number", "room", "seat", "venue", "reporting_time", "items_allowed", "instructions",
+	"register_from", "register_to", "course_code",
+}
+
+// seatingChartColumnAliases maps normalised seating chart headers to the field they are read into.
+var seatingChartColumnAliases = map[string]string{
+	"register number": "register_number", "register no": "register_number", "reg no": "register_number", "regno": "register_number",
+	"reg number": "register_number", "registration number": "register_number", "registration no": "register_number",
+	"roll no": "register_number", "roll number": "register_number",
+	"from": "register_from", "reg no from": "register_from", "from reg no": "register_from", "register number from": "register_from",
+	"from register number": "register_from", "register from": "register_from", "range from": "register_from", "start reg no": "register_from",
+	"to": "register_to", "reg no to": "register_to", "to reg no": "register_to", "register number to": "register_to",
+	"to register number": "register_to", "register to": "register_to", "range to": "register_to", "end reg no": "register_to",
+	"room": "room", "room no": "room", "room number": "room", "hall": "room", "hall no": "room", "exam hall": "room",
+	"classroom": "room", "class room": "room",
+	"seat": "seat", "seat no": "seat", "seat number": "seat", "bench": "seat", "bench no": "seat", "desk": "seat",
+	"venue": "venue", "block": "venue", "building": "venue",
+	"reporting time": "reporting_time", "report time": "reporting_time", "report by": "reporting_time", "reporting": "reporting_time",
+	"items allowed": "items_allowed", "allowed items": "items_allowed", "permitted items": "items_allowed",
+	"items permitted": "items_allowed", "materials allowed": "items_allowed",
+	"instructions": "instructions", "remarks": "instructions", "notes": "instructions",
+	"course code": "course_code", "subject code": "course_code", "code": "course_code", "course": "course_code",
+}
+
+// ExamSeatingService defines the interface for exam seating and hall ticket details.
+type ExamSeatingService interface {
+	GetExamSeating(ctx context.Context, userID, examID string) (*models.ExamSeating, error)
+	SetExamSeating(ctx context.Context, userID, examID string, input *models.ExamSeatingInput) (*models.ExamSeating, error)
+	ImportSeatingChart(ctx context.Context, userID, examID string, data []byte, input *models.ExamSeatingImportInput) (*models.ExamSeatingImportResult, error)
+	DeleteExamSeating(ctx context.Context, userID, examID string) error
+	GetExamBriefing(ctx context.Context, userID string, days int) ([]models.ExamBriefing, error)
+}
+
+// examSeatingService implements ExamSeatingService.
+type examSeatingService struct {
+	examSeatingRepo repository.ExamSeatingRepository
+	examRepo        repository.ExamRepository
+	userRepo        repository.UserRepository
+	subjectRepo     repository.SubjectRepository
+	venueRepo       repository.VenueRepository
+}
+
+// NewExamSeatingService creates a new exam seating service.
+func NewExamSeatingService(
+	examSeatingRepo repository.ExamSeatingRepository,
+	examRepo repository.ExamRepository,
+	userRepo repository.UserRepository,
+	subjectRepo repository.SubjectRepository,
+	venueRepo repository.VenueRepository,
+) ExamSeatingService {
+	return &examSeatingService{
+		examSeatingRepo: examSeatingRepo,
+		examRepo:        examRepo,
+		userRepo:        userRepo,
+		subjectRepo:     subjectRepo,
+		venueRepo:       venueRepo,
+	}
+}
+
+// GetExamSeating retrieves the seating of one of the user's exams.
+func (s *examSeatingService) GetExamSeating(ctx context.Context, userID, examID string) (*models.ExamSeating, error) {
+	if _, err := s.ownedExam(ctx, userID, examID); err != nil {
+		return nil, err
+	}
+	seating, err := s.examSeatingRepo.GetExamSeatingByExamID(ctx, examID)
+	if err != nil {
+		return nil, fmt.Errorf("exam seating not found: %w", err)
+	}
+	return seating, nil
+}
+
+// SetExamSeating records the seating of an exam entered by hand, replacing any imported seating.
+func (s *examSeatingService) SetExamSeating(ctx context.Context, userID, examID string, input *models.ExamSeatingInput) (*models.ExamSeating, error) {
+	exam, err := s.ownedExam(ctx, userID, examID)
+	if err != nil {
+		return nil, err
+	}
+
+	seating := &models.ExamSeating{
+		UserID:       userID,
+		ExamID:       exam.ID,
+		VenueID:      exam.VenueID,
+		ItemsAllowed: input.ItemsAllowed,
+		Source:       "manual",
+	}
+	if input.VenueID != nil {
+		seating.VenueID = sql.NullString{String: *input.VenueID, Valid: true}
+	}
+	if input.VenueName != nil {
+		seating.VenueName = sql.NullString{String: *input.VenueName, Valid: true}
+	}
+	if input.Room != nil {
+		seating.Room = sql.NullString{String: *input.Room, Valid: true}
+	}
+	if input.SeatNumber != nil {
+		seating.SeatNumber = sql.NullString{String: *input.SeatNumber, Valid: true}
+	}
+	if input.ReportingTime != nil {
+		reportingTime, err := time.Parse("15:04:05", *input.ReportingTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reporting time format: %w", err)
+		}
+		seating.ReportingTime = sql.NullTime{Time: reportingTime, Valid: true}
+	}
+	if input.Instructions != nil {
+		seating.Instructions = sql.NullString{String: *input.Instructions, Valid: true}
+	}
+	if user, err := s.userRepo.GetUserByID(ctx, userID); err == nil && user.RegisterNumber != nil {
+		seating.RegisterNumber = sql.NullString{String: *user.RegisterNumber, Valid: true}
+	}
+
+	if err := s.examSeatingRepo.UpsertExamSeating(ctx, seating); err != nil {
+		return nil, fmt.Errorf("failed to save exam seating: %w", err)
+	}
+	return seating, nil
+}
+
+// ImportSeatingChart finds the user's seat in a seating chart published by the exam cell. Rows are
+// matched by the user's register number, either exactly or within a "from"/"to" register number
+// range; when the chart has a course code column, only rows for the exam's subject are considered.
+func (s *examSeatingService) ImportSeatingChart(ctx context.Context, userID, examID string, data []byte, input *models.ExamSeatingImportInput) (*models.ExamSeatingImportResult, error) {
+	exam, err := s.ownedExam(ctx, userID, examID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user.RegisterNumber == nil || strings.TrimSpace(*user.RegisterNumber) == "" {
+		return nil, ErrNoRegisterNumber
+	}
+	registerNumber := normalizeRegisterNumber(*user.RegisterNumber)
+
+	records, err := parseCSVRecords(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSeatingImport, err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("%w: the chart needs a header row and at least one seat", ErrInvalidSeatingImport)
+	}
+	header, rows := records[0], records[1:]
+	if len(rows) > maxSeatingChartRows {
+		return nil, fmt.Errorf("%w: at most %d rows can be imported at once", ErrInvalidSeatingImport, maxSeatingChartRows)
+	}
+
+	var mapping map[string]string
+	if input.Mapping != nil && strings.TrimSpace(*input.Mapping) != "" {
+		if err := json.Unmarshal([]byte(*input.Mapping), &mapping); err != nil {
+			return nil, fmt.Errorf("%w: mapping must be a JSON object of field to column: %w", ErrInvalidSeatingImport, err)
+		}
+	}
+	columns, err := resolveImportColumns(header, len(header), mapping, seatingChartFields, seatingChartColumnAliases)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSeatingImport, err)
+	}
+	_, hasRegister := columns["register_number"]
+	_, hasFrom := columns["register_from"]
+	_, hasTo := columns["register_to"]
+	if !hasRegister && !(hasFrom && hasTo) {
+		return nil, fmt.Errorf("%w: no register number column found; map one with {\"register_number\": \"<column>\"}", ErrInvalidSeatingImport)
+	}
+
+	subjectCode := ""
+	if _, ok := columns["course_code"]; ok && exam.SubjectID.Valid {
+		if subject, err := s.subjectRepo.GetSubjectByID(ctx, exam.SubjectID.String); err == nil {
+			subjectCode = normalizeRegisterNumber(subject.Code)
+		}
+	}
+
+	matchedRow, matchedBy := -1, ""
+	for i, row := range rows {
+		value := func(field string) string { return seatingChartValue(row, columns, field) }
+		if code := normalizeRegisterNumber(value("course_code")); subjectCode != "" && code != "" && code != subjectCode {
+			continue
+		}
+		if hasRegister && normalizeRegisterNumber(value("register_number")) == registerNumber {
+			matchedRow, matchedBy = i, "register_number"
+			break
+		}
+		if matchedRow < 0 && hasFrom && hasTo && registerNumberInRange(registerNumber, value("register_from"), value("register_to")) {
+			// Keep scanning: an exact register number further down is a better match than a range.
+			matchedRow, matchedBy = i, "register_range"
+		}
+	}
+	if matchedRow < 0 {
+		return nil, ErrSeatNotInChart
+	}
+
+	row := rows[matchedRow]
+	value := func(field string) string { return seatingChartValue(row, columns, field) }
+	optional := func(field string) sql.NullString {
+		v := value(field)
+		return sql.NullString{String: v, Valid: v != ""}
+	}
+	seating := &models.ExamSeating{
+		UserID:         userID,
+		ExamID:         exam.ID,
+		VenueID:        exam.VenueID,
+		VenueName:      optional("venue"),
+		Room:           optional("room"),
+		SeatNumber:     optional("seat"),
+		RegisterNumber: sql.NullString{String: strings.TrimSpace(*user.RegisterNumber), Valid: true},
+		Instructions:   optional("instructions"),
+		Source:         "import",
+	}
+	if v := value("reporting_time"); v != "" {
+		reportingTime, err := parseClockTime(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid reporting time %q on row %d", ErrInvalidSeatingImport, v, matchedRow+2)
+		}
+		seating.ReportingTime = sql.NullTime{Time: reportingTime, Valid: true}
+	}
+	var items []string
+	for _, item := range strings.FieldsFunc(value("items_allowed"), func(r rune) bool { return r == ',' || r == ';' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	seating.ItemsAllowed = items
+	if seating.VenueName.Valid {
+		if venueID, ok := s.findVenue(ctx, seating.VenueName.String); ok {
+			seating.VenueID = sql.NullString{String: venueID, Valid: true}
+		}
+	}
+
+	result := &models.ExamSeatingImportResult{
+		Seating:     seating,
+		DryRun:      input.DryRun,
+		MatchedRow:  matchedRow + 2,
+		MatchedBy:   matchedBy,
+		RowsScanned: len(rows),
+		Mapping:     make(map[string]string, len(columns)),
+	}
+	for field, index := range columns {
+		result.Mapping[field] = strings.TrimSpace(header[index])
+	}
+	if input.DryRun {
+		return result, nil
+	}
+	if err := s.examSeatingRepo.UpsertExamSeating(ctx, seating); err != nil {
+		return nil, fmt.Errorf("failed to save exam seating: %w", err)
+	}
+	return result, nil
+}
+
+// DeleteExamSeating removes the seating of one of the user's exams.
+func (s *examSeatingService) DeleteExamSeating(ctx context.Context, userID, examID string) error {
+	if _, err := s.ownedExam(ctx, userID, examID); err != nil {
+		return err
+	}
+	return s.examSeatingRepo.DeleteExamSeating(ctx, examID, userID)
+}
+
+// GetExamBriefing lists the user's exams in the next days days, starting today, with their hall
+// ticket details and when to report.
+func (s *examSeatingService) GetExamBriefing(ctx context.Context, userID string, days int) ([]models.ExamBriefing, error) {
+	exams, err := s.examRepo.GetUpcomingExamsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming exams: %w", err)
+	}
+	seatings, err := s.examSeatingRepo.GetExamSeatingsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exam seatings: %w", err)
+	}
+	seatingByExam := make(map[string]*models.ExamSeating, len(seatings))
+	for i := range seatings {
+		seatingByExam[seatings[i].ExamID] = &seatings[i]
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	now := time.Now().In(loc)
+	until := time.Date(now.Year(), now.Month(), now.Day()+days, 0, 0, 0, 0, loc)
+
+	briefings := []models.ExamBriefing{}
+	subjectNames := make(map[string]string)
+	for _, exam := range exams {
+		examDay := time.Date(exam.ExamDate.Year(), exam.ExamDate.Month(), exam.ExamDate.Day(), 0, 0, 0, 0, loc)
+		if !examDay.Before(until) {
+			break
+		}
+		briefing := models.ExamBriefing{Exam: exam, Seating: seatingByExam[exam.ID]}
+		if exam.SubjectID.Valid {
+			name, ok := subjectNames[exam.SubjectID.String]
+			if !ok {
+				if subject, err := s.subjectRepo.GetSubjectByID(ctx, exam.SubjectID.String); err == nil {
+					name = subject.Name
+				} else {
+					log.Printf("Warning: Could not find subject for exam %s: %v", exam.ID, err)
+				}
+				subjectNames[exam.SubjectID.String] = name
+			}
+			briefing.SubjectName = name
+		}
+
+		if startsAt, reportBy, ok := examReportingTimes(exam, briefing.Seating, loc); ok {
+			briefing.StartsAt = sql.NullTime{Time: startsAt, Valid: true}
+			briefing.ReportBy = sql.NullTime{Time: reportBy, Valid: true}
+			if reportBy.After(now) {
+				briefing.MinutesUntilReport = sql.NullInt32{Int32: int32(reportBy.Sub(now).Minutes()), Valid: true}
+			}
+		}
+		briefings = append(briefings, briefing)
+	}
+	return briefings, nil
+}
+
+// ownedExam retrieves an exam and checks that it belongs to the user.
+func (s *examSeatingService) ownedExam(ctx context.Context, userID, examID string) (*models.Exam, error) {
+	exam, err := s.examRepo.GetExamByID(ctx, examID)
+	if err != nil {
+		return nil, fmt.Errorf("exam not found: %w", err)
+	}
+	if exam.UserID != userID {
+		return nil, fmt.Errorf("exam does not belong to user")
+	}
+	return exam, nil
+}
+
+// findVenue looks up a venue by the name printed on a seating chart.
+func (s *examSeatingService) findVenue(ctx context.Context, name string) (string, bool) {
+	venues, err := s.venueRepo.GetAllVenues(ctx)
+	if err != nil {
+		log.Printf("Warning: Could not load venues to match seating chart venue %q: %v", name, err)
+		return "", false
+	}
+	for _, venue := range venues {
+		if strings.EqualFold(strings.TrimSpace(venue.Name), strings.TrimSpace(name)) {
+			return venue.ID, true
+		}
+	}
+	return "", false
+}
+
+// examReportingTimes returns when an exam starts and when to report for it in loc. Without a
+// reporting time on the seating, students report defaultReportingLead before the start.
+func examReportingTimes(exam models.Exam, seating *models.ExamSeating, loc *time.Location) (time.Time, time.Time, bool) {
+	if !exam.StartTime.Valid {
+		return time.Time{}, time.Time{}, false
+	}
+	date := exam.ExamDate
+	startsAt := time.Date(date.Year(), date.Month(), date.Day(), exam.StartTime.Time.Hour(), exam.StartTime.Time.Minute(), 0, 0, loc)
+	reportBy := startsAt.Add(-defaultReportingLead)
+	if seating != nil && seating.ReportingTime.Valid {
+		reportBy = time.Date(date.Year(), date.Month(), date.Day(), seating.ReportingTime.Time.Hour(), seating.ReportingTime.Time.Minute(), 0, 0, loc)
+	}
+	return startsAt, reportBy, true
+}
+
+// seatingChartValue returns the trimmed value of a field in a seating chart row.
+func seatingChartValue(row []string, columns map[string]int, field string) string {
+	index, ok := columns[field]
+	if !ok || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+// normalizeRegisterNumber uppercases a register number and drops spaces and separators.
+func normalizeRegisterNumber(value string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(value) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// registerNumberInRange reports whether a normalised register number lies within an inclusive range.
+// Register numbers share a fixed format, so ranges only match numbers of the same length.
+func registerNumberInRange(registerNumber, from, to string) bool {
+	from, to = normalizeRegisterNumber(from), normalizeRegisterNumber(to)
+	if from == "" || to == "" || len(from) != len(registerNumber) || len(to) != len(registerNumber) {
+		return false
+	}
+	return registerNumber >= from && registerNumber <= to
+}
+
+// parseClockTime parses a time of day as written on seating charts, e.g. "09:15", "9:15 AM" or "2.30 PM".
+func parseClockTime(value string) (time.Time, error) {
+	value = strings.NewReplacer("A.M.", "AM", "P.M.", "PM").Replace(strings.ToUpper(strings.TrimSpace(value)))
+	value = strings.ReplaceAll(value, ".", ":")
+	for _, layout := range []string{"15:04", "15:04:05", "3:04 PM", "3:04PM", "3 PM", "3PM"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised time: %q", value)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
 )
@@ -31,13 +32,19 @@ type ExamService interface {
 type examService struct {
 	examRepo              repository.ExamRepository
 	importantQuestionRepo repository.ImportantQuestionRepository
+	eventBus              events.Bus
 }
 
 // NewExamService creates a new exam service.
-func NewExamService(examRepo repository.ExamRepository, importantQuestionRepo repository.ImportantQuestionRepository) ExamService {
+func NewExamService(
+	examRepo repository.ExamRepository,
+	importantQuestionRepo repository.ImportantQuestionRepository,
+	eventBus events.Bus,
+) ExamService {
 	return &examService{
 		examRepo:              examRepo,
 		importantQuestionRepo: importantQuestionRepo,
+		eventBus:              eventBus,
 	}
 }
 
@@ -59,6 +66,7 @@ func (s *examService) CreateExam(ctx context.Context, userID string, input *mode
 		ReminderEnabled:   true,
 		SyllabusUnits:     input.SyllabusUnits,
 		SyllabusTopics:    input.SyllabusTopics,
+		Tags:              input.Tags,
 	}
 
 	if input.StartTime != nil {
@@ -92,6 +100,7 @@ func (s *examService) CreateExam(ctx context.Context, userID string, input *mode
 	}
 	if input.PrepStatus != nil {
 		exam.PrepStatus = *input.PrepStatus
+		exam.PrepStatusOverride = *input.PrepStatus != "not_started"
 	}
 	if input.PrepNotes != nil {
 		exam.PrepNotes = sql.NullString{String: *input.PrepNotes, Valid: true}
@@ -106,6 +115,14 @@ func (s *examService) CreateExam(ctx context.Context, userID string, input *mode
 	if err := s.examRepo.CreateExam(ctx, exam); err != nil {
 		return nil, fmt.Errorf("failed to create exam: %w", err)
 	}
+	s.eventBus.Publish(ctx, events.Event{
+		Type:        events.ExamCreated,
+		UserID:      userID,
+		EntityType:  "exam",
+		EntityID:    exam.ID,
+		Description: "Created exam " + exam.Title,
+		Metadata:    map[string]interface{}{"title": exam.Title, "examDate": exam.ExamDate},
+	})
 	return exam, nil
 }
 
@@ -133,6 +150,7 @@ func (s *examService) UpdateExam(ctx context.Context, userID string, id string,
 	if existingExam.UserID != userID {
 		return nil, fmt.Errorf("exam does not belong to user")
 	}
+	before := *existingExam
 
 	if input.ExamDate != "" {
 		examDate, err := time.Parse("2006-01-02", input.ExamDate)
@@ -206,8 +224,9 @@ func (s *examService) UpdateExam(ctx context.Context, userID string, id string,
 	} else {
 		existingExam.Grade = sql.NullString{Valid: false}
 	}
-	if input.PrepStatus != nil {
+	if input.PrepStatus != nil && *input.PrepStatus != existingExam.PrepStatus {
 		existingExam.PrepStatus = *input.PrepStatus
+		existingExam.PrepStatusOverride = true
 	}
 	if input.PrepNotes != nil {
 		existingExam.PrepNotes = sql.NullString{String: *input.PrepNotes, Valid: true}
@@ -222,21 +241,73 @@ func (s *examService) UpdateExam(ctx context.Context, userID string, id string,
 	if input.ReminderEnabled != nil {
 		existingExam.ReminderEnabled = *input.ReminderEnabled
 	}
+	if input.Tags != nil {
+		existingExam.Tags = input.Tags
+	}
 
 	if err := s.examRepo.UpdateExam(ctx, existingExam); err != nil {
 		return nil, fmt.Errorf("failed to update exam: %w", err)
 	}
+	publishExamChange(ctx, s.eventBus, &before, existingExam)
 	return existingExam, nil
 }
 
-// UpdateExamPrepStatus updates the preparation status of an exam.
+// UpdateExamPrepStatus manually sets the preparation status of an exam, which stops coverage tracking
+// from advancing it until the override is cleared.
 func (s *examService) UpdateExamPrepStatus(ctx context.Context, id string, status string) error {
-	return s.examRepo.UpdateExamPrepStatus(ctx, id, status)
+	existingExam, err := s.examRepo.GetExamByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("exam not found: %w", err)
+	}
+	if err := s.examRepo.UpdateExamPrepStatus(ctx, id, status, true); err != nil {
+		return err
+	}
+	updated := *existingExam
+	updated.PrepStatus = status
+	updated.PrepStatusOverride = true
+	publishExamChange(ctx, s.eventBus, existingExam, &updated)
+	return nil
 }
 
 // DeleteExam deletes an exam.
 func (s *examService) DeleteExam(ctx context.Context, id string) error {
-	return s.examRepo.DeleteExam(ctx, id)
+	exam, err := s.examRepo.GetExamByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("exam not found: %w", err)
+	}
+	if err := s.examRepo.DeleteExam(ctx, id); err != nil {
+		return err
+	}
+	publishExamDeleted(ctx, s.eventBus, exam)
+	return nil
+}
+
+// publishExamChange publishes the changed fields of an updated exam.
+func publishExamChange(ctx context.Context, eventBus events.Bus, before, after *models.Exam) {
+	changes := events.Changes(before, after)
+	if len(changes) == 0 {
+		return
+	}
+	eventBus.Publish(ctx, events.Event{
+		Type:        events.ExamUpdated,
+		UserID:      after.UserID,
+		EntityType:  "exam",
+		EntityID:    after.ID,
+		Description: "Updated exam " + after.Title,
+		Metadata:    map[string]interface{}{"changes": changes},
+	})
+}
+
+// publishExamDeleted publishes the deletion of an exam.
+func publishExamDeleted(ctx context.Context, eventBus events.Bus, exam *models.Exam) {
+	eventBus.Publish(ctx, events.Event{
+		Type:        events.ExamDeleted,
+		UserID:      exam.UserID,
+		EntityType:  "exam",
+		EntityID:    exam.ID,
+		Description: "Deleted exam " + exam.Title,
+		Metadata:    map[string]interface{}{"title": exam.Title},
+	})
 }
 
 // CreateImportantQuestion creates a new important question for a user.
@@ -0,0 +1,377 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+// ErrInvalidAnalyticsExport is returned when an export names an unknown dataset, format or column,
+// or has an invalid date range.
+var ErrInvalidAnalyticsExport = errors.New("invalid analytics export")
+
+const (
+	// defaultExportDays is how many days an export covers when no start date is given.
+	defaultExportDays = 30
+	// exportFlushRows is how many rows are buffered before they are flushed to the client.
+	exportFlushRows = 200
+)
+
+// AnalyticsExport is a validated export, ready to be streamed to the client.
+type AnalyticsExport struct {
+	FileName    string
+	ContentType string
+
+	write func(ctx context.Context, w io.Writer) error
+}
+
+// WriteTo streams the export to w, reading rows from the database as it goes.
+func (e *AnalyticsExport) WriteTo(ctx context.Context, w io.Writer) error {
+	return e.write(ctx, w)
+}
+
+// ExportService defines the interface for exporting a user's history.
+type ExportService interface {
+	PrepareAnalyticsExport(ctx context.Context, userID string, input *models.AnalyticsExportInput) (*AnalyticsExport, error)
+}
+
+// exportService implements ExportService.
+type exportService struct {
+	exportRepo repository.ExportRepository
+	userRepo   repository.UserRepository
+}
+
+// NewExportService creates a new export service.
+func NewExportService(
+	exportRepo repository.ExportRepository,
+	userRepo repository.UserRepository,
+) ExportService {
+	return &exportService{
+		exportRepo: exportRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// exportRange is the period an export covers, both as calendar dates and as instants in the user's timezone.
+type exportRange struct {
+	fromDate time.Time // Midnight UTC calendar date
+	toDate   time.Time // Inclusive
+	start    time.Time // Midnight of fromDate in loc
+	end      time.Time // Midnight after toDate in loc
+	loc      *time.Location
+}
+
+// exportDataset describes one exportable table: its columns, in default order, and how to stream its rows
+// as values matching those columns.
+type exportDataset struct {
+	columns []string
+	stream  func(ctx context.Context, repo repository.ExportRepository, userID string, r exportRange, emit func([]interface{}) error) error
+}
+
+// exportDatasets are the datasets a user can export, keyed by the name used in the URL.
+var exportDatasets = map[string]exportDataset{
+	"daily-stats": {
+		columns: []string{
+			"statDate", "studyMinutes", "sessionsCompleted", "topicsCovered",
+			"assignmentsCompleted", "assignmentsAdded", "classesAttended", "totalClasses", "xpEarned",
+		},
+		stream: func(ctx context.Context, repo repository.ExportRepository, userID string, r exportRange, emit func([]interface{}) error) error {
+			return repo.StreamDailyStats(ctx, userID, r.fromDate, r.toDate, func(s *models.DailyStats) error {
+				return emit([]interface{}{
+					s.StatDate.Format("2006-01-02"), s.StudyMinutes, s.SessionsCompleted, s.TopicsCovered,
+					s.AssignmentsCompleted, s.AssignmentsAdded, s.ClassesAttended, s.TotalClasses, s.XPEarned,
+				})
+			})
+		},
+	},
+	"activity-logs": {
+		columns: []string{"id", "activityType", "description", "entityType", "entityId", "metadata", "createdAt"},
+		stream: func(ctx context.Context, repo repository.ExportRepository, userID string, r exportRange, emit func([]interface{}) error) error {
+			return repo.StreamActivityLogs(ctx, userID, r.start, r.end, func(l *models.ActivityLog) error {
+				var metadata interface{}
+				if len(l.Metadata) > 0 && string(l.Metadata) != "null" {
+					metadata = l.Metadata
+				}
+				return emit([]interface{}{
+					l.ID, l.ActivityType, exportNullString(l.Description), exportNullString(l.EntityType), exportNullString(l.EntityID),
+					metadata, exportTime(l.CreatedAt, r.loc),
+				})
+			})
+		},
+	},
+	"study-sessions": {
+		columns: []string{
+			"id", "studyPlanId", "subjectId", "examId", "assignmentId", "sessionType", "status",
+			"plannedStartTime", "plannedEndTime", "plannedDurationMinutes",
+			"actualStartTime", "actualEndTime", "actualDurationMinutes",
+			"topicsToCover", "topicsCovered", "completionPercentage", "productivityRating", "notes", "createdAt",
+		},
+		stream: func(ctx context.Context, repo repository.ExportRepository, userID string, r exportRange, emit func([]interface{}) error) error {
+			return repo.StreamStudySessions(ctx, userID, r.start, r.end, func(s *models.StudySession) error {
+				return emit([]interface{}{
+					s.ID, exportNullString(s.StudyPlanID), exportNullString(s.SubjectID), exportNullString(s.ExamID), exportNullString(s.AssignmentID), s.SessionType, s.Status,
+					exportNullTime(s.PlannedStartTime, r.loc), exportNullTime(s.PlannedEndTime, r.loc), exportNullInt32(s.PlannedDurationMinutes),
+					exportNullTime(s.ActualStartTime, r.loc), exportNullTime(s.ActualEndTime, r.loc), exportNullInt32(s.ActualDurationMinutes),
+					append([]string{}, s.TopicsToCover...), append([]string{}, s.TopicsCovered...), s.CompletionPercentage, exportNullInt32(s.ProductivityRating), exportNullString(s.Notes), exportTime(s.CreatedAt, r.loc),
+				})
+			})
+		},
+	},
+	"marks": {
+		columns: []string{"source", "sourceId", "subjectId", "subjectCode", "title", "kind", "maxMarks", "obtainedMarks", "percentage", "grade", "gradedOn"},
+		stream: func(ctx context.Context, repo repository.ExportRepository, userID string, r exportRange, emit func([]interface{}) error) error {
+			return repo.StreamGradedMarks(ctx, userID, r.start, r.end, r.loc.String(), func(m *models.GradedMark) error {
+				var percentage interface{}
+				if m.MaxMarks.Valid && m.MaxMarks.Float64 > 0 {
+					percentage = roundTo(m.ObtainedMarks/m.MaxMarks.Float64*100, 2)
+				}
+				return emit([]interface{}{
+					m.Source, m.SourceID, exportNullString(m.SubjectID), exportNullString(m.SubjectCode), m.Title, exportNullString(m.Kind),
+					exportNullFloat64(m.MaxMarks), m.ObtainedMarks, percentage, exportNullString(m.Grade), exportTime(m.GradedOn, r.loc),
+				})
+			})
+		},
+	},
+}
+
+// PrepareAnalyticsExport validates an export request and returns the export, ready to stream. Every check
+// happens here so that problems are reported before the response starts.
+func (s *exportService) PrepareAnalyticsExport(ctx context.Context, userID string, input *models.AnalyticsExportInput) (*AnalyticsExport, error) {
+	dataset, ok := exportDatasets[input.Dataset]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown dataset %q, use daily-stats, activity-logs, study-sessions or marks", ErrInvalidAnalyticsExport, input.Dataset)
+	}
+
+	var newWriter func(io.Writer) exportWriter
+	var contentType string
+	switch input.Format {
+	case "csv":
+		newWriter, contentType = newCSVExportWriter, "text/csv; charset=utf-8"
+	case "ndjson":
+		newWriter, contentType = newNDJSONExportWriter, "application/x-ndjson"
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q, use csv or ndjson", ErrInvalidAnalyticsExport, input.Format)
+	}
+
+	columns := dataset.columns
+	var indexes []int
+	if len(input.Columns) > 0 {
+		columns = nil
+		for _, column := range input.Columns {
+			index := -1
+			for i, name := range dataset.columns {
+				if name == column {
+					index = i
+					break
+				}
+			}
+			if index < 0 {
+				return nil, fmt.Errorf("%w: unknown column %q for %s, use %s", ErrInvalidAnalyticsExport, column, input.Dataset, strings.Join(dataset.columns, ", "))
+			}
+			columns = append(columns, column)
+			indexes = append(indexes, index)
+		}
+	}
+
+	r, err := s.exportRange(ctx, userID, input.FromDate, input.ToDate)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := fmt.Sprintf("%s_%s_%s.%s", input.Dataset, r.fromDate.Format("2006-01-02"), r.toDate.Format("2006-01-02"), input.Format)
+	return &AnalyticsExport{
+		FileName:    fileName,
+		ContentType: contentType,
+		write: func(ctx context.Context, w io.Writer) error {
+			out := newWriter(w)
+			if err := out.header(columns); err != nil {
+				return err
+			}
+			rows := 0
+			err := dataset.stream(ctx, s.exportRepo, userID, r, func(values []interface{}) error {
+				if indexes != nil {
+					selected := make([]interface{}, len(indexes))
+					for i, index := range indexes {
+						selected[i] = values[index]
+					}
+					values = selected
+				}
+				if err := out.row(columns, values); err != nil {
+					return err
+				}
+				rows++
+				if rows%exportFlushRows == 0 {
+					return out.flush()
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return out.flush()
+		},
+	}, nil
+}
+
+// exportRange parses an export's dates in the user's timezone. The end date defaults to today and the
+// start date to defaultExportDays before it.
+func (s *exportService) exportRange(ctx context.Context, userID, fromDate, toDate string) (exportRange, error) {
+	loc := userLocation(ctx, s.userRepo, userID)
+	now := time.Now().In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if toDate != "" {
+		parsed, err := time.Parse("2006-01-02", toDate)
+		if err != nil {
+			return exportRange{}, fmt.Errorf("%w: invalid to date format, use YYYY-MM-DD", ErrInvalidAnalyticsExport)
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -(defaultExportDays - 1))
+	if fromDate != "" {
+		parsed, err := time.Parse("2006-01-02", fromDate)
+		if err != nil {
+			return exportRange{}, fmt.Errorf("%w: invalid from date format, use YYYY-MM-DD", ErrInvalidAnalyticsExport)
+		}
+		from = parsed
+	}
+	if to.Before(from) {
+		return exportRange{}, fmt.Errorf("%w: end date is before start date", ErrInvalidAnalyticsExport)
+	}
+	return exportRange{
+		fromDate: from,
+		toDate:   to,
+		start:    time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc),
+		end:      time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1),
+		loc:      loc,
+	}, nil
+}
+
+// exportTime formats a timestamp in the user's timezone, keeping its UTC offset.
+func exportTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(time.RFC3339)
+}
+
+// exportNullTime formats a nullable timestamp in the user's timezone.
+func exportNullTime(t sql.NullTime, loc *time.Location) interface{} {
+	if !t.Valid {
+		return nil
+	}
+	return exportTime(t.Time, loc)
+}
+
+// exportNullString returns a nullable string's value, or nil.
+func exportNullString(s sql.NullString) interface{} {
+	if !s.Valid {
+		return nil
+	}
+	return s.String
+}
+
+// exportNullInt32 returns a nullable integer's value, or nil.
+func exportNullInt32(i sql.NullInt32) interface{} {
+	if !i.Valid {
+		return nil
+	}
+	return i.Int32
+}
+
+// exportNullFloat64 returns a nullable number's value, or nil.
+func exportNullFloat64(f sql.NullFloat64) interface{} {
+	if !f.Valid {
+		return nil
+	}
+	return f.Float64
+}
+
+// exportWriter renders export rows in one format.
+type exportWriter interface {
+	header(columns []string) error
+	row(columns []string, values []interface{}) error
+	flush() error
+}
+
+// csvExportWriter writes a header row followed by one CSV row per record. NULLs are empty cells and
+// lists are joined with semicolons.
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) exportWriter {
+	return &csvExportWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvExportWriter) header(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvExportWriter) row(columns []string, values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case []string:
+			record[i] = strings.Join(v, ";")
+		case json.RawMessage:
+			record[i] = string(v)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvExportWriter) flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ndjsonExportWriter writes one JSON object per line, with keys in column order.
+type ndjsonExportWriter struct {
+	w *bufio.Writer
+}
+
+func newNDJSONExportWriter(w io.Writer) exportWriter {
+	return &ndjsonExportWriter{w: bufio.NewWriter(w)}
+}
+
+func (n *ndjsonExportWriter) header(columns []string) error {
+	return nil
+}
+
+func (n *ndjsonExportWriter) row(columns []string, values []interface{}) error {
+	n.w.WriteByte('{')
+	for i, column := range columns {
+		if i > 0 {
+			n.w.WriteByte(',')
+		}
+		key, _ := json.Marshal(column)
+		n.w.Write(key)
+		n.w.WriteByte(':')
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", column, err)
+		}
+		n.w.Write(value)
+	}
+	n.w.WriteString("}\n")
+	return nil
+}
+
+func (n *ndjsonExportWriter) flush() error {
+	return n.w.Flush()
+}
@@ -0,0 +1,402 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+const (
+	// xpPerLevelStep sets the level curve: reaching level n takes xpPerLevelStep * n * (n-1) / 2 XP in
+	// total, so each level costs xpPerLevelStep more than the one before.
+	xpPerLevelStep = 100
+	// freezeTokenStreakDays is the streak length that earns a freeze token, and every multiple of it.
+	freezeTokenStreakDays = 7
+	// maxFreezeTokens is the most freeze tokens a user can hold.
+	maxFreezeTokens = 2
+	// recentXPAwardsLimit is the number of awards listed in a progress overview.
+	recentXPAwardsLimit = 10
+)
+
+// ErrXPRuleNotFound is returned when an XP rule does not exist.
+var ErrXPRuleNotFound = errors.New("XP rule not found")
+
+// studyRules are the XP rules whose activities count towards the daily study streak.
+var studyRules = map[string]bool{
+	"study_session_completed": true,
+	"question_practised":      true,
+	"quiz_completed":          true,
+}
+
+// achievement is an unlockable milestone. Its progress is read from the user's progress and the number
+// of rewarded activities per XP rule.
+type achievement struct {
+	code        string
+	title       string
+	description string
+	target      int32
+	progress    func(progress *models.UserProgress, counts map[string]int32) int32
+}
+
+// ruleCount measures an achievement by the number of rewarded activities under an XP rule.
+func ruleCount(rule string) func(*models.UserProgress, map[string]int32) int32 {
+	return func(_ *models.UserProgress, counts map[string]int32) int32 {
+		return counts[rule]
+	}
+}
+
+// achievements lists every achievement in the order they are shown.
+var achievements = []achievement{
+	{"first_session", "First Steps", "Complete your first study session", 1, ruleCount("study_session_completed")},
+	{"sessions_25", "Dedicated", "Complete 25 study sessions", 25, ruleCount("study_session_completed")},
+	{"sessions_100", "Scholar", "Complete 100 study sessions", 100, ruleCount("study_session_completed")},
+	{"on_time_10", "Punctual", "Complete 10 assignments before their due date", 10, ruleCount("assignment_on_time")},
+	{"questions_100", "Practice Makes Perfect", "Practise 100 important questions", 100, ruleCount("question_practised")},
+	{"quizzes_10", "Quiz Whiz", "Finish 10 quizzes", 10, ruleCount("quiz_completed")},
+	{"classes_50", "Front Row", "Attend 50 classes", 50, ruleCount("class_attended")},
+	{"streak_7", "On a Roll", "Study 7 days in a row", 7, func(p *models.UserProgress, _ map[string]int32) int32 { return p.LongestStreak }},
+	{"streak_30", "Unstoppable", "Study 30 days in a row", 30, func(p *models.UserProgress, _ map[string]int32) int32 { return p.LongestStreak }},
+	{"level_5", "Rising Star", "Reach level 5", 5, func(p *models.UserProgress, _ map[string]int32) int32 { return p.Level }},
+	{"level_10", "Campus Legend", "Reach level 10", 10, func(p *models.UserProgress, _ map[string]int32) int32 { return p.Level }},
+}
+
+// GamificationService defines the interface for XP, levels, streaks and achievements.
+type GamificationService interface {
+	GetProgress(ctx context.Context, userID string) (*models.ProgressOverview, error)
+	GetXPRules(ctx context.Context) ([]models.XPRule, error)
+	UpdateXPRule(ctx context.Context, rule string, input *models.XPRuleUpdateInput) (*models.XPRule, error)
+	Register(bus events.Bus)
+}
+
+// gamificationService implements GamificationService.
+type gamificationService struct {
+	gamificationRepo    repository.GamificationRepository
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+
+	// mu serialises awards so that concurrent events for a user do not overwrite each other's progress.
+	mu sync.Mutex
+}
+
+// NewGamificationService creates a new gamification service.
+func NewGamificationService(
+	gamificationRepo repository.GamificationRepository,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+) GamificationService {
+	return &gamificationService{
+		gamificationRepo:    gamificationRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// Register awards XP for the events that earn it.
+func (s *gamificationService) Register(bus events.Bus) {
+	bus.Subscribe(events.StudySessionCompleted, s.handleEvent)
+	bus.Subscribe(events.AssignmentCompleted, s.handleEvent)
+	bus.Subscribe(events.QuestionPractised, s.handleEvent)
+	bus.Subscribe(events.QuizCompleted, s.handleEvent)
+	bus.Subscribe(events.ClassAttendanceRecorded, s.handleEvent)
+}
+
+// handleEvent maps an event to the XP rule it earns and the activity it rewards. The activity's key makes
+// repeated events, such as a session completed twice, earn XP only once.
+func (s *gamificationService) handleEvent(ctx context.Context, event events.Event) {
+	loc := userLocation(ctx, s.userRepo, event.UserID)
+	occurred := event.OccurredAt.In(loc)
+	date := time.Date(occurred.Year(), occurred.Month(), occurred.Day(), 0, 0, 0, 0, time.UTC)
+
+	var err error
+	switch event.Type {
+	case events.StudySessionCompleted:
+		err = s.award(ctx, event.UserID, "study_session_completed", event.EntityID, date)
+	case events.AssignmentCompleted:
+		rule := "assignment_on_time"
+		if dueDate, ok := event.Metadata["dueDate"].(time.Time); ok && event.OccurredAt.After(dueDate) {
+			rule = "assignment_late"
+		}
+		err = s.award(ctx, event.UserID, rule, event.EntityID, date)
+	case events.QuestionPractised:
+		reviewID, _ := event.Metadata["reviewId"].(string)
+		err = s.award(ctx, event.UserID, "question_practised", reviewID, date)
+	case events.QuizCompleted:
+		err = s.award(ctx, event.UserID, "quiz_completed", event.EntityID, date)
+	case events.ClassAttendanceRecorded:
+		// One award per class, keyed by its slot and date, on the day the class took place
+		if attended, _ := event.Metadata["attended"].(bool); !attended {
+			break
+		}
+		slotID, _ := event.Metadata["slotId"].(string)
+		classDate, _ := event.Metadata["classDate"].(string)
+		day, parseErr := time.Parse("2006-01-02", classDate)
+		if parseErr != nil {
+			day = date
+		}
+		err = s.award(ctx, event.UserID, "class_attended", slotID+"#"+classDate, day)
+	}
+	if err != nil {
+		log.Printf("Warning: Could not award XP to user %s for %s: %v", event.UserID, event.Type, err)
+	}
+}
+
+// award records an activity under an XP rule for a local date: it earns the rule's XP up to its daily cap,
+// extends the study streak for study activities, and unlocks any achievements now reached. Inactive rules
+// still record the activity, without XP, so that achievements keep counting.
+func (s *gamificationService) award(ctx context.Context, userID, rule, sourceKey string, date time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	xpRule, err := s.gamificationRepo.GetXPRule(ctx, rule)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", ErrXPRuleNotFound, rule)
+		}
+		return err
+	}
+	progress, err := s.loadProgress(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	xp := int32(0)
+	if xpRule.IsActive {
+		xp = xpRule.XP
+		if xpRule.DailyCap.Valid {
+			awarded, err := s.gamificationRepo.GetXPAwardedOnDate(ctx, userID, date, rule)
+			if err != nil {
+				return err
+			}
+			xp = max(0, min(xp, xpRule.DailyCap.Int32-awarded))
+		}
+	}
+
+	previousLevel := progress.Level
+	progress.TotalXP += xp
+	progress.Level = levelForXP(progress.TotalXP)
+	if studyRules[rule] {
+		extendStreak(progress, date)
+	}
+
+	award := &models.XPAward{UserID: userID, Rule: rule, SourceKey: sourceKey, XP: xp, AwardDate: date}
+	recorded, err := s.gamificationRepo.RecordXPAward(ctx, award, progress)
+	if err != nil || !recorded {
+		return err
+	}
+
+	if progress.Level > previousLevel {
+		title := fmt.Sprintf("Level %d reached", progress.Level)
+		message := fmt.Sprintf("You have earned %d XP in total. Keep it up!", progress.TotalXP)
+		if _, err := s.notificationService.Notify(ctx, userID, "level_up", title, message, "", "", fmt.Sprintf("level_up:%d", progress.Level)); err != nil {
+			log.Printf("Warning: Could not notify user %s of level %d: %v", userID, progress.Level, err)
+		}
+	}
+	return s.unlockAchievements(ctx, progress)
+}
+
+// unlockAchievements records and announces every achievement the user has newly reached.
+func (s *gamificationService) unlockAchievements(ctx context.Context, progress *models.UserProgress) error {
+	counts, err := s.gamificationRepo.CountXPAwardsByRule(ctx, progress.UserID)
+	if err != nil {
+		return err
+	}
+	for _, a := range achievements {
+		if a.progress(progress, counts) < a.target {
+			continue
+		}
+		unlocked, err := s.gamificationRepo.CreateUserAchievement(ctx, &models.UserAchievement{
+			UserID:      progress.UserID,
+			Achievement: a.code,
+			UnlockedAt:  time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if !unlocked {
+			continue
+		}
+		title := "Achievement unlocked: " + a.title
+		if _, err := s.notificationService.Notify(ctx, progress.UserID, "achievement_unlocked", title, a.description, "", "", "achievement:"+a.code); err != nil {
+			log.Printf("Warning: Could not notify user %s of achievement %s: %v", progress.UserID, a.code, err)
+		}
+	}
+	return nil
+}
+
+// GetProgress returns a user's XP, level, streak, achievements and latest awards. A streak whose missed
+// days outnumber the user's freeze tokens is reported as broken.
+func (s *gamificationService) GetProgress(ctx context.Context, userID string) (*models.ProgressOverview, error) {
+	progress, err := s.loadProgress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := userLocation(ctx, s.userRepo, userID)
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	xpToday, err := s.gamificationRepo.GetXPAwardedOnDate(ctx, userID, today, "")
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.gamificationRepo.CountXPAwardsByRule(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	unlocked, err := s.gamificationRepo.GetUserAchievements(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	recent, err := s.gamificationRepo.GetRecentXPAwards(ctx, userID, recentXPAwardsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &models.ProgressOverview{
+		TotalXP:        progress.TotalXP,
+		XPToday:        xpToday,
+		Level:          progress.Level,
+		LevelStartXP:   levelThreshold(progress.Level),
+		NextLevelXP:    levelThreshold(progress.Level + 1),
+		CurrentStreak:  progress.CurrentStreak,
+		LongestStreak:  progress.LongestStreak,
+		LastActiveDate: progress.LastActiveDate,
+		FreezeTokens:   progress.FreezeTokens,
+		FreezesUsed:    progress.FreezesUsed,
+		Achievements:   []models.Achievement{},
+		RecentAwards:   recent,
+	}
+	overview.XPToNextLevel = overview.NextLevelXP - progress.TotalXP
+	if progress.LastActiveDate.Valid {
+		lastActive := statDay(progress.LastActiveDate.Time)
+		overview.StudiedToday = lastActive.Equal(today)
+		missed := int32(today.Sub(lastActive).Hours()/24) - 1
+		if missed > progress.FreezeTokens {
+			overview.CurrentStreak = 0
+		}
+	}
+	if overview.RecentAwards == nil {
+		overview.RecentAwards = []models.XPAward{}
+	}
+
+	unlockedAt := make(map[string]time.Time, len(unlocked))
+	for _, u := range unlocked {
+		unlockedAt[u.Achievement] = u.UnlockedAt
+	}
+	for _, a := range achievements {
+		view := models.Achievement{
+			Code:        a.code,
+			Title:       a.title,
+			Description: a.description,
+			Progress:    min(a.progress(progress, counts), a.target),
+			Target:      a.target,
+		}
+		if at, ok := unlockedAt[a.code]; ok {
+			view.Unlocked = true
+			view.UnlockedAt = sql.NullTime{Time: at, Valid: true}
+			view.Progress = a.target
+		}
+		overview.Achievements = append(overview.Achievements, view)
+	}
+	return overview, nil
+}
+
+// GetXPRules returns every XP rule.
+func (s *gamificationService) GetXPRules(ctx context.Context) ([]models.XPRule, error) {
+	rules, err := s.gamificationRepo.GetXPRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []models.XPRule{}
+	}
+	return rules, nil
+}
+
+// UpdateXPRule tunes an XP rule. New values apply to activities from then on; past awards are kept.
+func (s *gamificationService) UpdateXPRule(ctx context.Context, rule string, input *models.XPRuleUpdateInput) (*models.XPRule, error) {
+	xpRule, err := s.gamificationRepo.GetXPRule(ctx, rule)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrXPRuleNotFound, rule)
+		}
+		return nil, err
+	}
+	if input.XP != nil {
+		xpRule.XP = *input.XP
+	}
+	if input.DailyCap != nil {
+		xpRule.DailyCap = sql.NullInt32{Int32: *input.DailyCap, Valid: *input.DailyCap > 0}
+	}
+	if input.IsActive != nil {
+		xpRule.IsActive = *input.IsActive
+	}
+	if err := s.gamificationRepo.UpdateXPRule(ctx, xpRule); err != nil {
+		return nil, fmt.Errorf("failed to update XP rule: %w", err)
+	}
+	return xpRule, nil
+}
+
+// loadProgress returns a user's stored progress, or a fresh level 1 progress for a new user.
+func (s *gamificationService) loadProgress(ctx context.Context, userID string) (*models.UserProgress, error) {
+	progress, err := s.gamificationRepo.GetUserProgress(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &models.UserProgress{UserID: userID, Level: 1}, nil
+		}
+		return nil, err
+	}
+	return progress, nil
+}
+
+// extendStreak counts a day of study towards the streak. Days missed since the last active day are
+// covered by freeze tokens while they last, otherwise the streak starts over. Every freezeTokenStreakDays
+// days of streak earn a token, up to maxFreezeTokens. Days before the last active day change nothing.
+func extendStreak(progress *models.UserProgress, date time.Time) {
+	if progress.LastActiveDate.Valid {
+		lastActive := statDay(progress.LastActiveDate.Time)
+		if !date.After(lastActive) {
+			return
+		}
+		missed := int32(date.Sub(lastActive).Hours()/24) - 1
+		switch {
+		case missed == 0:
+			progress.CurrentStreak++
+		case missed <= progress.FreezeTokens:
+			progress.FreezeTokens -= missed
+			progress.FreezesUsed += missed
+			progress.CurrentStreak++
+		default:
+			progress.CurrentStreak = 1
+		}
+	} else {
+		progress.CurrentStreak = 1
+	}
+	progress.LastActiveDate = sql.NullTime{Time: date, Valid: true}
+	progress.LongestStreak = max(progress.LongestStreak, progress.CurrentStreak)
+	if progress.CurrentStreak%freezeTokenStreakDays == 0 && progress.FreezeTokens < maxFreezeTokens {
+		progress.FreezeTokens++
+	}
+}
+
+// levelThreshold returns the total XP at which a level is reached.
+func levelThreshold(level int32) int32 {
+	return xpPerLevelStep * level * (level - 1) / 2
+}
+
+// levelForXP returns the level reached with a total amount of XP.
+func levelForXP(totalXP int32) int32 {
+	level := int32(1)
+	for levelThreshold(level+1) <= totalXP {
+		level++
+	}
+	return level
+}
@@ -0,0 +1,599 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+var (
+	// ErrGoalNotFound is returned when a goal does not exist.
+	ErrGoalNotFound = errors.New("goal not found")
+	// ErrInvalidGoal is returned when a goal's settings are inconsistent.
+	ErrInvalidGoal = errors.New("invalid goal")
+)
+
+const (
+	// goalBehindPace is the share of the even-pace progress below which a goal is falling behind.
+	goalBehindPace = 0.8
+	// goalMinElapsed is the share of a period that must have passed before a goal can fall behind, so a
+	// slow first day or two does not count.
+	goalMinElapsed = 0.3
+)
+
+// GoalService defines the interface for goal setting and tracking.
+type GoalService interface {
+	CreateGoal(ctx context.Context, userID string, input *models.GoalCreationInput) (*models.GoalWithProgress, error)
+	GetGoals(ctx context.Context, userID string, includeArchived bool) ([]models.GoalWithProgress, error)
+	GetGoal(ctx context.Context, userID, id string) (*models.GoalWithProgress, error)
+	UpdateGoal(ctx context.Context, userID, id string, input *models.GoalUpdateInput) (*models.GoalWithProgress, error)
+	DeleteGoal(ctx context.Context, userID, id string) error
+	GetGoalHistory(ctx context.Context, userID, id string) (*models.GoalHistory, error)
+	EvaluateGoals(ctx context.Context) error
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+// goalService implements GoalService.
+type goalService struct {
+	goalRepo            repository.GoalRepository
+	examRepo            repository.ExamRepository
+	subjectRepo         repository.SubjectRepository
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+}
+
+// NewGoalService creates a new goal service.
+func NewGoalService(
+	goalRepo repository.GoalRepository,
+	examRepo repository.ExamRepository,
+	subjectRepo repository.SubjectRepository,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+) GoalService {
+	return &goalService{
+		goalRepo:            goalRepo,
+		examRepo:            examRepo,
+		subjectRepo:         subjectRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// CreateGoal creates a goal for the user. Questions practised can be limited to a subject or to the
+// questions of an exam, whose eve then becomes the default end date; study hours and attendance can be
+// limited to a subject the user takes.
+func (s *goalService) CreateGoal(ctx context.Context, userID string, input *models.GoalCreationInput) (*models.GoalWithProgress, error) {
+	loc := userLocation(ctx, s.userRepo, userID)
+	today := localToday(loc)
+
+	goal := &models.Goal{
+		UserID:      userID,
+		Title:       strings.TrimSpace(input.Title),
+		Metric:      input.Metric,
+		TargetValue: input.TargetValue,
+		Period:      input.Period,
+		StartDate:   today,
+		Status:      "active",
+	}
+	if goal.Title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidGoal)
+	}
+	if err := validateGoalTarget(goal.Metric, goal.TargetValue); err != nil {
+		return nil, err
+	}
+
+	if input.SubjectID != nil && *input.SubjectID != "" {
+		if _, err := s.subjectRepo.GetSubjectByID(ctx, *input.SubjectID); err != nil {
+			return nil, fmt.Errorf("subject not found: %w", err)
+		}
+		taken, err := s.subjectRepo.IsSubjectOfUser(ctx, *input.SubjectID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !taken {
+			return nil, fmt.Errorf("subject does not belong to user")
+		}
+		goal.SubjectID = sql.NullString{String: *input.SubjectID, Valid: true}
+	}
+	var exam *models.Exam
+	if input.ExamID != nil && *input.ExamID != "" {
+		if goal.Metric != "questions_practised" {
+			return nil, fmt.Errorf("%w: only questions practised can be limited to an exam", ErrInvalidGoal)
+		}
+		var err error
+		exam, err = s.examRepo.GetExamByID(ctx, *input.ExamID)
+		if err != nil {
+			return nil, fmt.Errorf("exam not found: %w", err)
+		}
+		if exam.UserID != userID {
+			return nil, fmt.Errorf("exam does not belong to user")
+		}
+		goal.ExamID = sql.NullString{String: exam.ID, Valid: true}
+	}
+
+	if input.StartDate != nil && *input.StartDate != "" {
+		startDate, err := time.Parse("2006-01-02", *input.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid start date format: %v", ErrInvalidGoal, err)
+		}
+		goal.StartDate = startDate
+	}
+	if input.EndDate != nil && *input.EndDate != "" {
+		endDate, err := time.Parse("2006-01-02", *input.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid end date format: %v", ErrInvalidGoal, err)
+		}
+		goal.EndDate = sql.NullTime{Time: endDate, Valid: true}
+	} else if exam != nil && goal.Period == "once" {
+		goal.EndDate = sql.NullTime{Time: statDay(exam.ExamDate).AddDate(0, 0, -1), Valid: true}
+	}
+	if goal.Period == "once" && !goal.EndDate.Valid {
+		return nil, fmt.Errorf("%w: a one-off goal needs an end date or an exam", ErrInvalidGoal)
+	}
+	if goal.EndDate.Valid && goal.EndDate.Time.Before(goal.StartDate) {
+		return nil, fmt.Errorf("%w: the end date is before the start date", ErrInvalidGoal)
+	}
+
+	if err := s.goalRepo.CreateGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+	return s.withProgress(ctx, goal, loc)
+}
+
+// GetGoals retrieves the user's goals, newest first, with the progress of the active ones.
+func (s *goalService) GetGoals(ctx context.Context, userID string, includeArchived bool) ([]models.GoalWithProgress, error) {
+	goals, err := s.goalRepo.GetGoalsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc := userLocation(ctx, s.userRepo, userID)
+
+	result := []models.GoalWithProgress{}
+	for i := range goals {
+		if goals[i].Status == "archived" && !includeArchived {
+			continue
+		}
+		goal, err := s.withProgress(ctx, &goals[i], loc)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *goal)
+	}
+	return result, nil
+}
+
+// GetGoal retrieves one of the user's goals with its progress.
+func (s *goalService) GetGoal(ctx context.Context, userID, id string) (*models.GoalWithProgress, error) {
+	goal, err := s.ownedGoal(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withProgress(ctx, goal, userLocation(ctx, s.userRepo, userID))
+}
+
+// UpdateGoal changes the title, target or end date of one of the user's goals, or archives it. The target
+// and end date can only change while the goal is active. An unarchived goal becomes active again and is
+// re-evaluated by the scheduler.
+func (s *goalService) UpdateGoal(ctx context.Context, userID, id string, input *models.GoalUpdateInput) (*models.GoalWithProgress, error) {
+	goal, err := s.ownedGoal(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Title != nil {
+		goal.Title = strings.TrimSpace(*input.Title)
+		if goal.Title == "" {
+			return nil, fmt.Errorf("%w: title is required", ErrInvalidGoal)
+		}
+	}
+	if input.Archived != nil {
+		if *input.Archived {
+			goal.Status = "archived"
+		} else if goal.Status == "archived" {
+			goal.Status = "active"
+		}
+	}
+	if (input.TargetValue != nil || input.EndDate != nil) && goal.Status != "active" {
+		return nil, fmt.Errorf("%w: only active goals can change their target or end date", ErrInvalidGoal)
+	}
+	if input.TargetValue != nil {
+		if err := validateGoalTarget(goal.Metric, *input.TargetValue); err != nil {
+			return nil, err
+		}
+		goal.TargetValue = *input.TargetValue
+	}
+	if input.EndDate != nil {
+		if *input.EndDate == "" {
+			if goal.Period == "once" {
+				return nil, fmt.Errorf("%w: a one-off goal needs an end date", ErrInvalidGoal)
+			}
+			goal.EndDate = sql.NullTime{}
+		} else {
+			endDate, err := time.Parse("2006-01-02", *input.EndDate)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid end date format: %v", ErrInvalidGoal, err)
+			}
+			if endDate.Before(statDay(goal.StartDate)) {
+				return nil, fmt.Errorf("%w: the end date is before the start date", ErrInvalidGoal)
+			}
+			goal.EndDate = sql.NullTime{Time: endDate, Valid: true}
+		}
+	}
+
+	if err := s.goalRepo.UpdateGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+	return s.withProgress(ctx, goal, userLocation(ctx, s.userRepo, userID))
+}
+
+// DeleteGoal deletes one of the user's goals together with its history.
+func (s *goalService) DeleteGoal(ctx context.Context, userID, id string) error {
+	if _, err := s.ownedGoal(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.goalRepo.DeleteGoal(ctx, id, userID)
+}
+
+// GetGoalHistory retrieves the results of a goal's finished periods, with its attainment rate and its
+// current run of achieved periods.
+func (s *goalService) GetGoalHistory(ctx context.Context, userID, id string) (*models.GoalHistory, error) {
+	goal, err := s.ownedGoal(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.goalRepo.GetGoalResultsByGoalID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &models.GoalHistory{Goal: *goal, Results: []models.GoalResult{}, PeriodsTotal: len(results)}
+	streakBroken := false
+	for _, result := range results {
+		history.Results = append(history.Results, result)
+		if result.Achieved {
+			history.PeriodsAchieved++
+			if !streakBroken {
+				history.CurrentStreak++
+			}
+		} else {
+			streakBroken = true
+		}
+	}
+	if history.PeriodsTotal > 0 {
+		rate := float64(history.PeriodsAchieved) / float64(history.PeriodsTotal) * 100
+		history.AttainmentRate = sql.NullFloat64{Float64: roundTo(rate, 1), Valid: true}
+	}
+	return history, nil
+}
+
+// EvaluateGoals brings the active goals of every active user up to date in the user's timezone. Each
+// finished week of a weekly goal is recorded in its history, and the goal ends after its end date. A
+// one-off goal is achieved as soon as its hours or questions reach the target, and is otherwise settled
+// after its end date. Goals falling behind notify their user at most once a week.
+func (s *goalService) EvaluateGoals(ctx context.Context) error {
+	userIDs, err := s.userRepo.GetActiveUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		goals, err := s.goalRepo.GetGoalsByUserID(ctx, userID)
+		if err != nil {
+			log.Printf("Warning: Could not load goals for user %s: %v", userID, err)
+			continue
+		}
+		loc := userLocation(ctx, s.userRepo, userID)
+		for i := range goals {
+			if goals[i].Status != "active" {
+				continue
+			}
+			if err := s.evaluate(ctx, &goals[i], loc); err != nil {
+				log.Printf("Warning: Could not evaluate goal %s: %v", goals[i].ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RunScheduler evaluates goals right away and then every interval, until the context is cancelled.
+func (s *goalService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.EvaluateGoals(ctx); err != nil {
+			log.Printf("Warning: Goal evaluation failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ownedGoal loads a goal and checks that it belongs to the user.
+func (s *goalService) ownedGoal(ctx context.Context, userID, id string) (*models.Goal, error) {
+	goal, err := s.goalRepo.GetGoalByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrGoalNotFound, id)
+		}
+		return nil, err
+	}
+	if goal.UserID != userID {
+		return nil, fmt.Errorf("goal does not belong to user")
+	}
+	return goal, nil
+}
+
+// withProgress attaches the progress of the current period to an active goal.
+func (s *goalService) withProgress(ctx context.Context, goal *models.Goal, loc *time.Location) (*models.GoalWithProgress, error) {
+	result := &models.GoalWithProgress{Goal: *goal}
+	if goal.Status != "active" {
+		return result, nil
+	}
+	progress, err := s.progress(ctx, goal, loc, localToday(loc))
+	if err != nil {
+		return nil, err
+	}
+	result.Progress = progress
+	return result, nil
+}
+
+// evaluate records the finished periods of an active goal, settles or ends it when it is over, and
+// otherwise notifies its user when it is falling behind.
+func (s *goalService) evaluate(ctx context.Context, goal *models.Goal, loc *time.Location) error {
+	today := localToday(loc)
+
+	if goal.Period == "weekly" {
+		if err := s.recordFinishedWeeks(ctx, goal, loc, today); err != nil {
+			return err
+		}
+		if goal.EndDate.Valid && statDay(goal.EndDate.Time).Before(today) {
+			return s.goalRepo.UpdateGoalStatus(ctx, goal.ID, "ended")
+		}
+	}
+
+	progress, err := s.progress(ctx, goal, loc, today)
+	if err != nil {
+		return err
+	}
+	if goal.Period == "once" {
+		over := statDay(goal.EndDate.Time).Before(today)
+		if over || (progress.Attained && goal.Metric != "attendance_rate") {
+			return s.settle(ctx, goal, loc, today)
+		}
+	}
+	if !progress.FallingBehind {
+		return nil
+	}
+	title := fmt.Sprintf("Falling behind on \"%s\"", goal.Title)
+	message := fmt.Sprintf("%s of %s so far, %s expected by now. Days left: %d.",
+		formatGoalValue(goal.Metric, progress.CurrentValue), formatGoalValue(goal.Metric, progress.TargetValue),
+		formatGoalValue(goal.Metric, progress.ExpectedValue), progress.DaysLeft)
+	if goal.Metric == "attendance_rate" {
+		message = fmt.Sprintf("Attendance is at %s, below your target of %s.",
+			formatGoalValue(goal.Metric, progress.CurrentValue), formatGoalValue(goal.Metric, progress.TargetValue))
+	}
+	_, err = s.notificationService.Notify(ctx, goal.UserID, "goal_behind", title, message, "goal", goal.ID,
+		fmt.Sprintf("goal_behind:%s:%s", goal.ID, weekStartOf(today).Format("2006-01-02")))
+	return err
+}
+
+// recordFinishedWeeks records each week of a weekly goal that has ended and is not in its history yet.
+// Attendance weeks without classes are skipped.
+func (s *goalService) recordFinishedWeeks(ctx context.Context, goal *models.Goal, loc *time.Location, today time.Time) error {
+	results, err := s.goalRepo.GetGoalResultsByGoalID(ctx, goal.ID)
+	if err != nil {
+		return err
+	}
+	weekStart := weekStartOf(goal.StartDate)
+	if len(results) > 0 {
+		weekStart = statDay(results[0].PeriodStart).AddDate(0, 0, 7)
+	}
+	for ; weekStart.AddDate(0, 0, 6).Before(today); weekStart = weekStart.AddDate(0, 0, 7) {
+		if goal.EndDate.Valid && weekStart.After(statDay(goal.EndDate.Time)) {
+			break
+		}
+		weekEnd := weekStart.AddDate(0, 0, 6)
+		value, measured, err := s.measure(ctx, goal, loc, weekStart, weekEnd)
+		if err != nil {
+			return err
+		}
+		if !measured {
+			continue
+		}
+		_, err = s.goalRepo.RecordGoalResult(ctx, &models.GoalResult{
+			GoalID:      goal.ID,
+			UserID:      goal.UserID,
+			PeriodStart: weekStart,
+			PeriodEnd:   weekEnd,
+			TargetValue: goal.TargetValue,
+			ActualValue: value,
+			Achieved:    value >= goal.TargetValue,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// settle records the result of a one-off goal, marks it achieved or missed and tells its user.
+func (s *goalService) settle(ctx context.Context, goal *models.Goal, loc *time.Location, today time.Time) error {
+	periodEnd := statDay(goal.EndDate.Time)
+	measureTo := periodEnd
+	if today.Before(measureTo) {
+		measureTo = today
+	}
+	value, measured, err := s.measure(ctx, goal, loc, statDay(goal.StartDate), measureTo)
+	if err != nil {
+		return err
+	}
+	achieved := measured && value >= goal.TargetValue
+
+	_, err = s.goalRepo.RecordGoalResult(ctx, &models.GoalResult{
+		GoalID:      goal.ID,
+		UserID:      goal.UserID,
+		PeriodStart: statDay(goal.StartDate),
+		PeriodEnd:   periodEnd,
+		TargetValue: goal.TargetValue,
+		ActualValue: value,
+		Achieved:    achieved,
+	})
+	if err != nil {
+		return err
+	}
+	status := "missed"
+	title := fmt.Sprintf("Goal missed: %s", goal.Title)
+	if achieved {
+		status = "achieved"
+		title = fmt.Sprintf("Goal achieved: %s", goal.Title)
+	}
+	if err := s.goalRepo.UpdateGoalStatus(ctx, goal.ID, status); err != nil {
+		return err
+	}
+	message := fmt.Sprintf("Reached %s of %s.", formatGoalValue(goal.Metric, value), formatGoalValue(goal.Metric, goal.TargetValue))
+	if _, err := s.notificationService.Notify(ctx, goal.UserID, "goal_"+status, title, message, "goal", goal.ID,
+		"goal_"+status+":"+goal.ID); err != nil {
+		log.Printf("Warning: Could not notify user %s of goal %s: %v", goal.UserID, goal.ID, err)
+	}
+	return nil
+}
+
+// progress computes how far a goal has come in the period containing today. Hours and questions are
+// compared with an even pace through the period, counted in whole days before today; attendance simply
+// has to stay at or above the target.
+func (s *goalService) progress(ctx context.Context, goal *models.Goal, loc *time.Location, today time.Time) (*models.GoalProgress, error) {
+	periodStart, periodEnd := statDay(goal.StartDate), statDay(goal.EndDate.Time)
+	if goal.Period == "weekly" {
+		periodStart = weekStartOf(today)
+		if start := statDay(goal.StartDate); periodStart.Before(weekStartOf(start)) {
+			periodStart = weekStartOf(start)
+		}
+		periodEnd = periodStart.AddDate(0, 0, 6)
+	}
+	progress := &models.GoalProgress{
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		TargetValue: goal.TargetValue,
+	}
+	if today.Before(periodStart) {
+		progress.DaysLeft = int32(periodEnd.Sub(periodStart).Hours()/24) + 1
+		return progress, nil
+	}
+
+	measureTo := periodEnd
+	if today.Before(measureTo) {
+		measureTo = today
+	}
+	value, measured, err := s.measure(ctx, goal, loc, periodStart, measureTo)
+	if err != nil {
+		return nil, err
+	}
+	progress.CurrentValue = value
+	progress.Percent = math.Min(100, roundTo(value/goal.TargetValue*100, 1))
+	if !today.After(periodEnd) {
+		progress.DaysLeft = int32(periodEnd.Sub(today).Hours()/24) + 1
+	}
+
+	if goal.Metric == "attendance_rate" {
+		progress.ExpectedValue = goal.TargetValue
+		progress.Attained = measured && value >= goal.TargetValue
+		progress.FallingBehind = measured && value < goal.TargetValue
+		return progress, nil
+	}
+	totalDays := periodEnd.Sub(periodStart).Hours()/24 + 1
+	elapsed := math.Min(1, today.Sub(periodStart).Hours()/24/totalDays)
+	progress.ExpectedValue = roundTo(goal.TargetValue*elapsed, 2)
+	progress.Attained = value >= goal.TargetValue
+	progress.FallingBehind = !progress.Attained && elapsed >= goalMinElapsed && value < progress.ExpectedValue*goalBehindPace
+	return progress, nil
+}
+
+// measure computes a goal's metric between two dates, inclusive, in the user's timezone: hours studied,
+// distinct questions practised, or the attendance percentage. measured is false for attendance without
+// any classes.
+func (s *goalService) measure(ctx context.Context, goal *models.Goal, loc *time.Location, fromDate, toDate time.Time) (float64, bool, error) {
+	start := time.Date(fromDate.Year(), fromDate.Month(), fromDate.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(toDate.Year(), toDate.Month(), toDate.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+
+	switch goal.Metric {
+	case "study_hours":
+		var minutes int32
+		var err error
+		if goal.SubjectID.Valid {
+			minutes, err = s.goalRepo.SumSubjectStudyMinutes(ctx, goal.UserID, goal.SubjectID.String, start, end)
+		} else {
+			minutes, err = s.goalRepo.SumDailyStudyMinutes(ctx, goal.UserID, fromDate, toDate)
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		return roundTo(float64(minutes)/60, 2), true, nil
+	case "questions_practised":
+		count, err := s.goalRepo.CountQuestionsPractised(ctx, goal.UserID, goal.SubjectID.String, goal.ExamID.String, start, end)
+		if err != nil {
+			return 0, false, err
+		}
+		return float64(count), true, nil
+	case "attendance_rate":
+		var attended, total int32
+		var err error
+		if goal.SubjectID.Valid {
+			attended, total, err = s.goalRepo.SumSubjectAttendance(ctx, goal.UserID, goal.SubjectID.String, fromDate, toDate)
+		} else {
+			attended, total, err = s.goalRepo.SumAttendance(ctx, goal.UserID, fromDate, toDate)
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		if total == 0 {
+			return 0, false, nil
+		}
+		return roundTo(float64(attended)/float64(total)*100, 1), true, nil
+	}
+	return 0, false, fmt.Errorf("%w: unknown metric %q", ErrInvalidGoal, goal.Metric)
+}
+
+// validateGoalTarget checks that a target makes sense for its metric.
+func validateGoalTarget(metric string, target float64) error {
+	if target <= 0 {
+		return fmt.Errorf("%w: the target must be positive", ErrInvalidGoal)
+	}
+	if metric == "attendance_rate" && target > 100 {
+		return fmt.Errorf("%w: an attendance target is a percentage up to 100", ErrInvalidGoal)
+	}
+	if metric == "questions_practised" && target != math.Trunc(target) {
+		return fmt.Errorf("%w: a question target must be a whole number", ErrInvalidGoal)
+	}
+	return nil
+}
+
+// formatGoalValue formats a value of a goal's metric, e.g. "12.5 h", "30 questions" or "78%".
+func formatGoalValue(metric string, value float64) string {
+	switch metric {
+	case "study_hours":
+		return fmt.Sprintf("%.1f h", value)
+	case "questions_practised":
+		return fmt.Sprintf("%.0f questions", value)
+	}
+	return fmt.Sprintf("%.0f%%", value)
+}
+
+// localToday returns today's date in loc as a midnight UTC calendar date.
+func localToday(loc *time.Location) time.Time {
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
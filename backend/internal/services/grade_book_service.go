@@ -0,0 +1,577 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+// gradeBand maps a minimum percentage to a letter grade and grade point.
+type gradeBand struct {
+	grade         string
+	minPercentage float64
+	gradePoint    float64
+}
+
+// gradeScale is the 10-point grading scale, ordered from highest to lowest band.
+var gradeScale = []gradeBand{
+	{grade: "S", minPercentage: 90, gradePoint: 10},
+	{grade: "A", minPercentage: 80, gradePoint: 9},
+	{grade: "B", minPercentage: 70, gradePoint: 8},
+	{grade: "C", minPercentage: 60, gradePoint: 7},
+	{grade: "D", minPercentage: 55, gradePoint: 6},
+	{grade: "E", minPercentage: 50, gradePoint: 5},
+	{grade: "F", minPercentage: 0, gradePoint: 0},
+}
+
+// defaultLabRecordMaxMarks is used for lab records when the scheme component does not set item max marks.
+const defaultLabRecordMaxMarks = 10.0
+
+// defaultGradeScheme is applied to subjects the user has not configured a scheme for.
+var defaultGradeScheme = models.GradeScheme{
+	Name: "Default",
+	Components: []models.GradeSchemeComponent{
+		{Name: "CAT1", SourceType: "exam", ExamType: sql.NullString{String: "cat1", Valid: true}, Weight: 15},
+		{Name: "CAT2", SourceType: "exam", ExamType: sql.NullString{String: "cat2", Valid: true}, Weight: 15},
+		{Name: "Assignments", SourceType: "assignment", Weight: 30},
+		{Name: "FAT", SourceType: "exam", ExamType: sql.NullString{String: "fat", Valid: true}, Weight: 40, IsFinal: true},
+	},
+}
+
+// GradeBookService defines the interface for grade book business logic.
+type GradeBookService interface {
+	CreateGradeScheme(ctx context.Context, userID string, input *models.GradeSchemeInput) (*models.GradeScheme, error)
+	GetGradeSchemeByID(ctx context.Context, userID, id string) (*models.GradeScheme, error)
+	GetGradeSchemesByUserID(ctx context.Context, userID string) ([]models.GradeScheme, error)
+	UpdateGradeScheme(ctx context.Context, userID, id string, input *models.GradeSchemeInput) (*models.GradeScheme, error)
+	DeleteGradeScheme(ctx context.Context, userID, id string) error
+
+	GetGradeBook(ctx context.Context, userID string) (*models.GradeBook, error)
+	GetSubjectGrade(ctx context.Context, userID, subjectID string) (*models.SubjectGrade, error)
+	ProjectGrade(ctx context.Context, userID, subjectID, targetGrade string) (*models.GradeProjection, error)
+}
+
+// gradeBookService implements GradeBookService.
+type gradeBookService struct {
+	gradeSchemeRepo repository.GradeSchemeRepository
+	subjectRepo     repository.SubjectRepository
+	examRepo        repository.ExamRepository
+	assignmentRepo  repository.AssignmentRepository
+	labRecordRepo   repository.LabRecordRepository
+}
+
+// NewGradeBookService creates a new grade book service.
+func NewGradeBookService(
+	gradeSchemeRepo repository.GradeSchemeRepository,
+	subjectRepo repository.SubjectRepository,
+	examRepo repository.ExamRepository,
+	assignmentRepo repository.AssignmentRepository,
+	labRecordRepo repository.LabRecordRepository,
+) GradeBookService {
+	return &gradeBookService{
+		gradeSchemeRepo: gradeSchemeRepo,
+		subjectRepo:     subjectRepo,
+		examRepo:        examRepo,
+		assignmentRepo:  assignmentRepo,
+		labRecordRepo:   labRecordRepo,
+	}
+}
+
+// markedItems holds a user's marks for a single subject.
+type markedItems struct {
+	exams       []models.Exam
+	assignments []models.Assignment
+	labRecords  []models.LabRecord
+}
+
+// CreateGradeScheme creates a new grade scheme for a user.
+func (s *gradeBookService) CreateGradeScheme(ctx context.Context, userID string, input *models.GradeSchemeInput) (*models.GradeScheme, error) {
+	scheme := &models.GradeScheme{UserID: userID}
+	if err := s.applyGradeSchemeInput(ctx, scheme, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.gradeSchemeRepo.CreateGradeScheme(ctx, scheme); err != nil {
+		return nil, fmt.Errorf("failed to create grade scheme: %w", err)
+	}
+	return scheme, nil
+}
+
+// GetGradeSchemeByID retrieves a single grade scheme owned by the user.
+func (s *gradeBookService) GetGradeSchemeByID(ctx context.Context, userID, id string) (*models.GradeScheme, error) {
+	scheme, err := s.gradeSchemeRepo.GetGradeSchemeByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("grade scheme not found: %w", err)
+	}
+	if scheme.UserID != userID {
+		return nil, fmt.Errorf("grade scheme does not belong to user")
+	}
+	return scheme, nil
+}
+
+// GetGradeSchemesByUserID retrieves all grade schemes for a user.
+func (s *gradeBookService) GetGradeSchemesByUserID(ctx context.Context, userID string) ([]models.GradeScheme, error) {
+	return s.gradeSchemeRepo.GetGradeSchemesByUserID(ctx, userID)
+}
+
+// UpdateGradeScheme replaces the name, subject and components of an existing grade scheme.
+func (s *gradeBookService) UpdateGradeScheme(ctx context.Context, userID, id string, input *models.GradeSchemeInput) (*models.GradeScheme, error) {
+	scheme, err := s.GetGradeSchemeByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyGradeSchemeInput(ctx, scheme, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.gradeSchemeRepo.UpdateGradeScheme(ctx, scheme); err != nil {
+		return nil, fmt.Errorf("failed to update grade scheme: %w", err)
+	}
+	return scheme, nil
+}
+
+// DeleteGradeScheme deletes a grade scheme owned by the user.
+func (s *gradeBookService) DeleteGradeScheme(ctx context.Context, userID, id string) error {
+	return s.gradeSchemeRepo.DeleteGradeScheme(ctx, id, userID)
+}
+
+// GetGradeBook computes grades for every subject the user has marks in, with SGPA per semester and CGPA.
+func (s *gradeBookService) GetGradeBook(ctx context.Context, userID string) (*models.GradeBook, error) {
+	items, err := s.loadMarkedItems(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	schemes, err := s.loadSchemes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	semesters := make(map[int32]*models.SemesterGPA)
+	for subjectID, subjectItems := range items {
+		subject, err := s.subjectRepo.GetSubjectByID(ctx, subjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subject %s: %w", subjectID, err)
+		}
+		grade := computeSubjectGrade(subject, schemeForSubject(schemes, subjectID), subjectItems)
+
+		semester := int32(0)
+		if subject.Semester.Valid {
+			semester = subject.Semester.Int32
+		}
+		if semesters[semester] == nil {
+			semesters[semester] = &models.SemesterGPA{Semester: semester}
+		}
+		semesters[semester].Subjects = append(semesters[semester].Subjects, *grade)
+	}
+
+	book := &models.GradeBook{Semesters: []models.SemesterGPA{}}
+	var totalPoints float64
+	for _, semester := range semesters {
+		sort.Slice(semester.Subjects, func(i, j int) bool {
+			return semester.Subjects[i].SubjectCode < semester.Subjects[j].SubjectCode
+		})
+
+		var points float64
+		for _, subject := range semester.Subjects {
+			if !subject.GradePoint.Valid || !subject.Credits.Valid || subject.Credits.Int32 <= 0 {
+				continue
+			}
+			points += subject.GradePoint.Float64 * float64(subject.Credits.Int32)
+			semester.GradedCredits += subject.Credits.Int32
+		}
+		if semester.GradedCredits > 0 {
+			semester.SGPA = sql.NullFloat64{Float64: roundTo(points/float64(semester.GradedCredits), 2), Valid: true}
+		}
+
+		totalPoints += points
+		book.GradedCredits += semester.GradedCredits
+		book.Semesters = append(book.Semesters, *semester)
+	}
+	sort.Slice(book.Semesters, func(i, j int) bool {
+		return book.Semesters[i].Semester < book.Semesters[j].Semester
+	})
+	if book.GradedCredits > 0 {
+		book.CGPA = sql.NullFloat64{Float64: roundTo(totalPoints/float64(book.GradedCredits), 2), Valid: true}
+	}
+	return book, nil
+}
+
+// GetSubjectGrade computes the grade-book entry for a single subject.
+func (s *gradeBookService) GetSubjectGrade(ctx context.Context, userID, subjectID string) (*models.SubjectGrade, error) {
+	grade, _, err := s.subjectGrade(ctx, userID, subjectID)
+	return grade, err
+}
+
+// ProjectGrade works out what the user needs in the remaining components of a subject to reach a target grade.
+func (s *gradeBookService) ProjectGrade(ctx context.Context, userID, subjectID, targetGrade string) (*models.GradeProjection, error) {
+	band, ok := findGradeBand(targetGrade)
+	if !ok {
+		return nil, fmt.Errorf("unknown target grade: %s", targetGrade)
+	}
+
+	grade, scheme, err := s.subjectGrade(ctx, userID, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projection := &models.GradeProjection{
+		SubjectID:           subjectID,
+		TargetGrade:         band.grade,
+		TargetPercentage:    band.minPercentage,
+		SecuredMarks:        grade.SecuredMarks,
+		RemainingWeight:     roundTo(grade.TotalWeight-grade.GradedWeight, 2),
+		RemainingComponents: []string{},
+	}
+	for _, component := range grade.Components {
+		if !component.Percentage.Valid {
+			projection.RemainingComponents = append(projection.RemainingComponents, component.Name)
+		}
+	}
+
+	neededMarks := band.minPercentage/100*grade.TotalWeight - grade.SecuredMarks
+	if projection.RemainingWeight <= 0 {
+		projection.Achievable = neededMarks <= 0
+		if projection.Achievable {
+			projection.Message = fmt.Sprintf("All components are graded and grade %s is secured", band.grade)
+		} else {
+			projection.Message = fmt.Sprintf("All components are graded and grade %s was not reached", band.grade)
+		}
+		return projection, nil
+	}
+
+	required := math.Max(neededMarks/projection.RemainingWeight*100, 0)
+	projection.RequiredPercentage = roundTo(required, 2)
+	projection.Achievable = required <= 100
+
+	// When only the final exam is left, express the requirement in its raw marks.
+	if len(projection.RemainingComponents) == 1 {
+		for _, component := range grade.Components {
+			if component.IsFinal && !component.Percentage.Valid {
+				if maxMarks, ok := s.finalExamMaxMarks(ctx, userID, subjectID, scheme); ok {
+					projection.RequiredFinalMarks = sql.NullFloat64{Float64: roundTo(required/100*maxMarks, 2), Valid: true}
+				}
+			}
+		}
+	}
+
+	switch {
+	case required == 0:
+		projection.Message = fmt.Sprintf("Grade %s is already secured", band.grade)
+	case projection.Achievable:
+		projection.Message = fmt.Sprintf("Score at least %.2f%% in %s to get grade %s", required, strings.Join(projection.RemainingComponents, ", "), band.grade)
+	default:
+		projection.Message = fmt.Sprintf("Grade %s is out of reach: it would need %.2f%% in %s", band.grade, required, strings.Join(projection.RemainingComponents, ", "))
+	}
+	return projection, nil
+}
+
+// subjectGrade computes the grade-book entry for a single subject together with the scheme applied to it.
+func (s *gradeBookService) subjectGrade(ctx context.Context, userID, subjectID string) (*models.SubjectGrade, models.GradeScheme, error) {
+	subject, err := s.subjectRepo.GetSubjectByID(ctx, subjectID)
+	if err != nil {
+		return nil, models.GradeScheme{}, fmt.Errorf("subject not found: %w", err)
+	}
+	items, err := s.loadMarkedItems(ctx, userID)
+	if err != nil {
+		return nil, models.GradeScheme{}, err
+	}
+	schemes, err := s.loadSchemes(ctx, userID)
+	if err != nil {
+		return nil, models.GradeScheme{}, err
+	}
+	scheme := schemeForSubject(schemes, subjectID)
+	return computeSubjectGrade(subject, scheme, items[subjectID]), scheme, nil
+}
+
+// applyGradeSchemeInput validates a grade scheme input and copies it onto the scheme.
+func (s *gradeBookService) applyGradeSchemeInput(ctx context.Context, scheme *models.GradeScheme, input *models.GradeSchemeInput) error {
+	if input.SubjectID != nil {
+		if _, err := s.subjectRepo.GetSubjectByID(ctx, *input.SubjectID); err != nil {
+			return fmt.Errorf("subject not found: %w", err)
+		}
+		scheme.SubjectID = sql.NullString{String: *input.SubjectID, Valid: true}
+	} else {
+		scheme.SubjectID = sql.NullString{Valid: false}
+	}
+	scheme.Name = input.Name
+
+	var totalWeight float64
+	finals := 0
+	scheme.Components = make([]models.GradeSchemeComponent, 0, len(input.Components))
+	for _, c := range input.Components {
+		component := models.GradeSchemeComponent{
+			Name:       c.Name,
+			SourceType: c.SourceType,
+			Weight:     c.Weight,
+		}
+		if c.ExamType != nil {
+			if c.SourceType != "exam" {
+				return fmt.Errorf("examType is only valid for exam components")
+			}
+			component.ExamType = sql.NullString{String: *c.ExamType, Valid: true}
+		}
+		if c.ItemMaxMarks != nil {
+			component.ItemMaxMarks = sql.NullFloat64{Float64: *c.ItemMaxMarks, Valid: true}
+		}
+		if c.IsFinal != nil && *c.IsFinal {
+			component.IsFinal = true
+			finals++
+		}
+		totalWeight += c.Weight
+		scheme.Components = append(scheme.Components, component)
+	}
+
+	if finals > 1 {
+		return fmt.Errorf("a grade scheme can have at most one final component")
+	}
+	if math.Abs(totalWeight-100) > 0.01 {
+		return fmt.Errorf("component weights must add up to 100, got %.2f", totalWeight)
+	}
+	return nil
+}
+
+// loadMarkedItems groups the user's exams, assignments and lab records by subject.
+func (s *gradeBookService) loadMarkedItems(ctx context.Context, userID string) (map[string]*markedItems, error) {
+	exams, err := s.examRepo.GetExamsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exams: %w", err)
+	}
+	assignments, err := s.assignmentRepo.GetAssignmentsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
+	}
+	labRecords, err := s.labRecordRepo.GetLabRecordsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lab records: %w", err)
+	}
+
+	items := make(map[string]*markedItems)
+	forSubject := func(subjectID string) *markedItems {
+		if items[subjectID] == nil {
+			items[subjectID] = &markedItems{}
+		}
+		return items[subjectID]
+	}
+	for _, e := range exams {
+		if e.SubjectID.Valid {
+			forSubject(e.SubjectID.String).exams = append(forSubject(e.SubjectID.String).exams, e)
+		}
+	}
+	for _, a := range assignments {
+		if a.SubjectID.Valid {
+			forSubject(a.SubjectID.String).assignments = append(forSubject(a.SubjectID.String).assignments, a)
+		}
+	}
+	for _, l := range labRecords {
+		if l.SubjectID.Valid {
+			forSubject(l.SubjectID.String).labRecords = append(forSubject(l.SubjectID.String).labRecords, l)
+		}
+	}
+	return items, nil
+}
+
+// loadSchemes retrieves the user's grade schemes keyed by subject ID, with "" for the default scheme.
+func (s *gradeBookService) loadSchemes(ctx context.Context, userID string) (map[string]models.GradeScheme, error) {
+	schemes, err := s.gradeSchemeRepo.GetGradeSchemesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get grade schemes: %w", err)
+	}
+	bySubject := make(map[string]models.GradeScheme)
+	for _, scheme := range schemes {
+		bySubject[scheme.SubjectID.String] = scheme
+	}
+	return bySubject, nil
+}
+
+// schemeForSubject picks the subject's own scheme, then the user's default, then the built-in default.
+func schemeForSubject(schemes map[string]models.GradeScheme, subjectID string) models.GradeScheme {
+	if scheme, ok := schemes[subjectID]; ok {
+		return scheme
+	}
+	if scheme, ok := schemes[""]; ok {
+		return scheme
+	}
+	return defaultGradeScheme
+}
+
+// computeSubjectGrade applies a grade scheme to a subject's marks.
+func computeSubjectGrade(subject *models.Subject, scheme models.GradeScheme, items *markedItems) *models.SubjectGrade {
+	if items == nil {
+		items = &markedItems{}
+	}
+
+	grade := &models.SubjectGrade{
+		SubjectID:   subject.ID,
+		SubjectCode: subject.Code,
+		SubjectName: subject.Name,
+		Semester:    subject.Semester,
+		Credits:     subject.Credits,
+		SchemeName:  scheme.Name,
+		Components:  []models.ComponentScore{},
+	}
+
+	claimedExamTypes := make(map[string]bool)
+	for _, component := range scheme.Components {
+		if component.SourceType == "exam" && component.ExamType.Valid {
+			claimedExamTypes[component.ExamType.String] = true
+		}
+	}
+
+	for _, component := range scheme.Components {
+		var obtained, max float64
+		graded := 0
+
+		switch component.SourceType {
+		case "exam":
+			for _, e := range items.exams {
+				if component.ExamType.Valid && e.ExamType != component.ExamType.String {
+					continue
+				}
+				if !component.ExamType.Valid && claimedExamTypes[e.ExamType] {
+					continue
+				}
+				itemMax := e.MaxMarks
+				if !itemMax.Valid {
+					itemMax = component.ItemMaxMarks
+				}
+				if !e.ObtainedMarks.Valid || !itemMax.Valid || itemMax.Float64 <= 0 {
+					continue
+				}
+				obtained += e.ObtainedMarks.Float64
+				max += itemMax.Float64
+				graded++
+			}
+		case "assignment":
+			for _, a := range items.assignments {
+				itemMax := a.MaxMarks
+				if !itemMax.Valid {
+					itemMax = component.ItemMaxMarks
+				}
+				if !a.ObtainedMarks.Valid || !itemMax.Valid || itemMax.Float64 <= 0 {
+					continue
+				}
+				obtained += a.ObtainedMarks.Float64
+				max += itemMax.Float64
+				graded++
+			}
+		case "lab_record":
+			itemMax := defaultLabRecordMaxMarks
+			if component.ItemMaxMarks.Valid && component.ItemMaxMarks.Float64 > 0 {
+				itemMax = component.ItemMaxMarks.Float64
+			}
+			for _, l := range items.labRecords {
+				if !l.Marks.Valid {
+					continue
+				}
+				obtained += l.Marks.Float64
+				max += itemMax
+				graded++
+			}
+		}
+
+		score := models.ComponentScore{
+			Name:        component.Name,
+			SourceType:  component.SourceType,
+			Weight:      component.Weight,
+			IsFinal:     component.IsFinal,
+			GradedItems: graded,
+		}
+		grade.TotalWeight += component.Weight
+		if !component.IsFinal {
+			grade.InternalMaxMarks += component.Weight
+		}
+		if graded > 0 {
+			percentage := math.Min(obtained/max*100, 100)
+			score.Percentage = sql.NullFloat64{Float64: roundTo(percentage, 2), Valid: true}
+			score.Contribution = roundTo(percentage/100*component.Weight, 2)
+
+			grade.SecuredMarks += score.Contribution
+			grade.GradedWeight += component.Weight
+			if !component.IsFinal {
+				grade.InternalMarks += score.Contribution
+			}
+		}
+		grade.Components = append(grade.Components, score)
+	}
+
+	grade.InternalMarks = roundTo(grade.InternalMarks, 2)
+	grade.SecuredMarks = roundTo(grade.SecuredMarks, 2)
+	grade.IsComplete = grade.TotalWeight > 0 && grade.GradedWeight >= grade.TotalWeight
+
+	// A letter grade recorded on the final exam is authoritative.
+	finalType, hasFinal := finalExamType(scheme)
+	for _, e := range items.exams {
+		if hasFinal && e.ExamType == finalType && e.Grade.Valid {
+			if band, ok := findGradeBand(e.Grade.String); ok {
+				grade.IsComplete = true
+				grade.Grade = sql.NullString{String: band.grade, Valid: true}
+				grade.GradePoint = sql.NullFloat64{Float64: band.gradePoint, Valid: true}
+			}
+		}
+	}
+
+	if grade.IsComplete && grade.TotalWeight > 0 {
+		percentage := roundTo(grade.SecuredMarks/grade.TotalWeight*100, 2)
+		grade.Percentage = sql.NullFloat64{Float64: percentage, Valid: true}
+		if !grade.Grade.Valid {
+			band := gradeForPercentage(percentage)
+			grade.Grade = sql.NullString{String: band.grade, Valid: true}
+			grade.GradePoint = sql.NullFloat64{Float64: band.gradePoint, Valid: true}
+		}
+	}
+	return grade
+}
+
+// finalExamType returns the exam type of the scheme's final component, if that component is an exam.
+func finalExamType(scheme models.GradeScheme) (string, bool) {
+	for _, component := range scheme.Components {
+		if component.IsFinal && component.SourceType == "exam" && component.ExamType.Valid {
+			return component.ExamType.String, true
+		}
+	}
+	return "", false
+}
+
+// finalExamMaxMarks returns the max marks of the subject's final exam under the given scheme, if one is scheduled.
+func (s *gradeBookService) finalExamMaxMarks(ctx context.Context, userID, subjectID string, scheme models.GradeScheme) (float64, bool) {
+	finalType, ok := finalExamType(scheme)
+	if !ok {
+		return 0, false
+	}
+	exams, err := s.examRepo.GetExamsByUserID(ctx, userID)
+	if err != nil {
+		return 0, false
+	}
+	for _, e := range exams {
+		if e.SubjectID.String == subjectID && e.ExamType == finalType && e.MaxMarks.Valid {
+			return e.MaxMarks.Float64, true
+		}
+	}
+	return 0, false
+}
+
+// findGradeBand looks up a grade band by its letter.
+func findGradeBand(grade string) (gradeBand, bool) {
+	for _, band := range gradeScale {
+		if strings.EqualFold(band.grade, strings.TrimSpace(grade)) {
+			return band, true
+		}
+	}
+	return gradeBand{}, false
+}
+
+// gradeForPercentage maps a percentage to its grade band.
+func gradeForPercentage(percentage float64) gradeBand {
+	for _, band := range gradeScale {
+		if percentage >= band.minPercentage {
+			return band
+		}
+	}
+	return gradeScale[len(gradeScale)-1]
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+const (
+	// defaultInsightsDays is how many days insights cover when no range is given.
+	defaultInsightsDays = 90
+	// onTimeStartMinutes is how far a session may start from its planned start and still count as on time.
+	onTimeStartMinutes = 15
+	// estimateTolerance is the relative miss within which an assignment estimate counts as accurate.
+	estimateTolerance = 0.10
+	// minRatedSessionsForBestHour is how many rated sessions an hour needs before it can be a best hour.
+	minRatedSessionsForBestHour = 2
+	// maxBestHours and maxCommonBlockers cap the length of those lists.
+	maxBestHours      = 3
+	maxCommonBlockers = 10
+)
+
+// InsightsService defines the interface for study productivity insights.
+type InsightsService interface {
+	GetProductivityInsights(ctx context.Context, userID string, from, to time.Time) (*models.ProductivityInsights, error)
+}
+
+// insightsService implements InsightsService.
+type insightsService struct {
+	exportRepo     repository.ExportRepository
+	assignmentRepo repository.AssignmentRepository
+	subjectRepo    repository.SubjectRepository
+	userRepo       repository.UserRepository
+}
+
+// NewInsightsService creates a new insights service.
+func NewInsightsService(
+	exportRepo repository.ExportRepository,
+	assignmentRepo repository.AssignmentRepository,
+	subjectRepo repository.SubjectRepository,
+	userRepo repository.UserRepository,
+) InsightsService {
+	return &insightsService{
+		exportRepo:     exportRepo,
+		assignmentRepo: assignmentRepo,
+		subjectRepo:    subjectRepo,
+		userRepo:       userRepo,
+	}
+}
+
+// productivityTally accumulates the sessions of one hour, subject or session type.
+type productivityTally struct {
+	sessions      int
+	minutes       int32
+	rated         int
+	ratingSum     int32
+	completionSum int32
+}
+
+func (t *productivityTally) add(session *models.StudySession, minutes int32) {
+	t.sessions++
+	t.minutes += minutes
+	t.completionSum += session.CompletionPercentage
+	if session.ProductivityRating.Valid {
+		t.rated++
+		t.ratingSum += session.ProductivityRating.Int32
+	}
+}
+
+func (t *productivityTally) avgProductivity() sql.NullFloat64 {
+	if t.rated == 0 {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: roundTo(float64(t.ratingSum)/float64(t.rated), 2), Valid: true}
+}
+
+func (t *productivityTally) avgCompletion() float64 {
+	if t.sessions == 0 {
+		return 0
+	}
+	return roundTo(float64(t.completionSum)/float64(t.sessions), 1)
+}
+
+// GetProductivityInsights analyses the user's finished study sessions and assignment estimates between two
+// dates in their timezone. Without dates it covers the last defaultInsightsDays days. Sessions count from
+// when they started, or were planned to start; assignments from when they were submitted, or were due.
+func (s *insightsService) GetProductivityInsights(ctx context.Context, userID string, from, to time.Time) (*models.ProductivityInsights, error) {
+	loc := userLocation(ctx, s.userRepo, userID)
+	if from.IsZero() && to.IsZero() {
+		now := time.Now().In(loc)
+		to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		from = to.AddDate(0, 0, -(defaultInsightsDays - 1))
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: end date is before start date", ErrInvalidDateRange)
+	}
+	if to.Sub(from) > maxDailyStatsRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: a range can span at most %d days", ErrInvalidDateRange, maxDailyStatsRangeDays)
+	}
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+
+	insights := &models.ProductivityInsights{
+		FromDate:       from,
+		ToDate:         to,
+		Hours:          []models.HourProductivity{},
+		BestHours:      []int32{},
+		BySubject:      []models.ProductivityBreakdown{},
+		BySessionType:  []models.ProductivityBreakdown{},
+		CommonBlockers: []models.BlockerCount{},
+	}
+
+	var hours [24]productivityTally
+	subjects := map[string]*productivityTally{}
+	sessionTypes := map[string]*productivityTally{}
+	blockers := map[string]*models.BlockerCount{}
+	var blockerOrder []string
+	var startDelaySum float64
+	var startDelays int
+	adherence := &insights.PlanAdherence
+
+	// Sessions are tallied as they stream in rather than loaded all at once.
+	err := s.exportRepo.StreamStudySessions(ctx, userID, start, end, func(session *models.StudySession) error {
+		switch session.Status {
+		case "completed":
+			adherence.CompletedSessions++
+		case "partial":
+			adherence.PartialSessions++
+		case "skipped":
+			adherence.SkippedSessions++
+		default:
+			return nil // Planned or in progress; nothing to analyse yet
+		}
+		insights.SessionsAnalysed++
+
+		planned := plannedSessionMinutes(session)
+		actual := actualSessionMinutes(session)
+		if planned > 0 {
+			adherence.PlannedSessions++
+			adherence.PlannedMinutes += planned
+			adherence.ActualMinutes += actual
+		}
+		if session.PlannedStartTime.Valid && session.ActualStartTime.Valid {
+			delay := session.ActualStartTime.Time.Sub(session.PlannedStartTime.Time).Minutes()
+			if delay > onTimeStartMinutes {
+				adherence.LateStarts++
+			} else {
+				adherence.OnTimeStarts++
+			}
+			startDelaySum += delay
+			startDelays++
+		}
+
+		seen := map[string]bool{}
+		for _, blocker := range splitBlockers(session.Blockers.String) {
+			key := strings.ToLower(blocker)
+			if seen[key] {
+				continue // Counted once per session
+			}
+			seen[key] = true
+			if count, ok := blockers[key]; ok {
+				count.Count++
+				continue
+			}
+			blockers[key] = &models.BlockerCount{Blocker: blocker, Count: 1}
+			blockerOrder = append(blockerOrder, key)
+		}
+
+		if session.Status == "skipped" {
+			return nil
+		}
+		if session.ActualStartTime.Valid {
+			hours[session.ActualStartTime.Time.In(loc).Hour()].add(session, actual)
+		}
+		subjectID := session.SubjectID.String
+		if subjects[subjectID] == nil {
+			subjects[subjectID] = &productivityTally{}
+		}
+		subjects[subjectID].add(session, actual)
+		if sessionTypes[session.SessionType] == nil {
+			sessionTypes[session.SessionType] = &productivityTally{}
+		}
+		sessionTypes[session.SessionType].add(session, actual)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if adherence.PlannedMinutes > 0 {
+		adherence.AdherencePercentage = sql.NullFloat64{Float64: roundTo(float64(adherence.ActualMinutes)/float64(adherence.PlannedMinutes)*100, 1), Valid: true}
+	}
+	if startDelays > 0 {
+		adherence.AvgStartDelayMinutes = sql.NullFloat64{Float64: roundTo(startDelaySum/float64(startDelays), 1), Valid: true}
+	}
+
+	for hour := range hours {
+		tally := &hours[hour]
+		if tally.sessions == 0 {
+			continue
+		}
+		insights.Hours = append(insights.Hours, models.HourProductivity{
+			Hour:            int32(hour),
+			Sessions:        tally.sessions,
+			ActualMinutes:   tally.minutes,
+			RatedSessions:   tally.rated,
+			AvgProductivity: tally.avgProductivity(),
+			AvgCompletion:   tally.avgCompletion(),
+		})
+	}
+	insights.BestHours = bestHours(insights.Hours)
+
+	for subjectID, tally := range subjects {
+		label := "No subject"
+		if subjectID != "" {
+			label = subjectID
+			if subject, err := s.subjectRepo.GetSubjectByID(ctx, subjectID); err == nil {
+				label = subject.Name
+			}
+		}
+		insights.BySubject = append(insights.BySubject, productivityBreakdown(subjectID, label, tally))
+	}
+	for sessionType, tally := range sessionTypes {
+		insights.BySessionType = append(insights.BySessionType, productivityBreakdown(sessionType, sessionType, tally))
+	}
+	sortBreakdowns(insights.BySubject)
+	sortBreakdowns(insights.BySessionType)
+
+	for _, key := range blockerOrder {
+		insights.CommonBlockers = append(insights.CommonBlockers, *blockers[key])
+	}
+	sort.SliceStable(insights.CommonBlockers, func(i, j int) bool {
+		return insights.CommonBlockers[i].Count > insights.CommonBlockers[j].Count
+	})
+	if len(insights.CommonBlockers) > maxCommonBlockers {
+		insights.CommonBlockers = insights.CommonBlockers[:maxCommonBlockers]
+	}
+
+	assignments, err := s.assignmentRepo.GetAssignmentsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignments: %w", err)
+	}
+	insights.EstimateAccuracy = estimateAccuracy(assignments, start, end)
+
+	return insights, nil
+}
+
+// plannedSessionMinutes is a session's planned duration, falling back to its planned start and end times.
+func plannedSessionMinutes(session *models.StudySession) int32 {
+	if session.PlannedDurationMinutes.Valid {
+		return session.PlannedDurationMinutes.Int32
+	}
+	if session.PlannedStartTime.Valid && session.PlannedEndTime.Valid {
+		return int32(math.Max(0, session.PlannedEndTime.Time.Sub(session.PlannedStartTime.Time).Minutes()))
+	}
+	return 0
+}
+
+// actualSessionMinutes is how long a session actually ran, falling back to its actual start and end times.
+func actualSessionMinutes(session *models.StudySession) int32 {
+	if session.ActualDurationMinutes.Valid {
+		return session.ActualDurationMinutes.Int32
+	}
+	if session.ActualStartTime.Valid && session.ActualEndTime.Valid {
+		return int32(math.Max(0, session.ActualEndTime.Time.Sub(session.ActualStartTime.Time).Minutes()))
+	}
+	return 0
+}
+
+// splitBlockers breaks a session's free-text blockers into individual entries. Blockers are usually
+// listed on separate lines or separated by commas or semicolons; placeholders like "none" are dropped.
+func splitBlockers(text string) []string {
+	var blockers []string
+	for _, part := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ',' || r == ';' }) {
+		blocker := strings.TrimRight(strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(part), "-*•")), ".")
+		switch strings.ToLower(blocker) {
+		case "", "none", "nil", "n/a", "na", "nothing":
+			continue
+		}
+		blockers = append(blockers, blocker)
+	}
+	return blockers
+}
+
+// bestHours picks the hours with the highest average productivity, breaking ties on completion and then
+// study time. Hours with too few rated sessions to judge are left out.
+func bestHours(hours []models.HourProductivity) []int32 {
+	var candidates []models.HourProductivity
+	for _, hour := range hours {
+		if hour.RatedSessions >= minRatedSessionsForBestHour {
+			candidates = append(candidates, hour)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].AvgProductivity.Float64 != candidates[j].AvgProductivity.Float64 {
+			return candidates[i].AvgProductivity.Float64 > candidates[j].AvgProductivity.Float64
+		}
+		if candidates[i].AvgCompletion != candidates[j].AvgCompletion {
+			return candidates[i].AvgCompletion > candidates[j].AvgCompletion
+		}
+		return candidates[i].ActualMinutes > candidates[j].ActualMinutes
+	})
+	best := []int32{}
+	for i := 0; i < len(candidates) && i < maxBestHours; i++ {
+		best = append(best, candidates[i].Hour)
+	}
+	return best
+}
+
+// productivityBreakdown turns a tally into a breakdown entry.
+func productivityBreakdown(key, label string, tally *productivityTally) models.ProductivityBreakdown {
+	return models.ProductivityBreakdown{
+		Key:             key,
+		Label:           label,
+		Sessions:        tally.sessions,
+		ActualMinutes:   tally.minutes,
+		RatedSessions:   tally.rated,
+		AvgProductivity: tally.avgProductivity(),
+		AvgCompletion:   tally.avgCompletion(),
+	}
+}
+
+// sortBreakdowns orders breakdown entries by study time, most first.
+func sortBreakdowns(breakdowns []models.ProductivityBreakdown) {
+	sort.Slice(breakdowns, func(i, j int) bool {
+		if breakdowns[i].ActualMinutes != breakdowns[j].ActualMinutes {
+			return breakdowns[i].ActualMinutes > breakdowns[j].ActualMinutes
+		}
+		return breakdowns[i].Label < breakdowns[j].Label
+	})
+}
+
+// estimateAccuracy compares estimated and actual hours of the assignments submitted, or due, in [start, end).
+func estimateAccuracy(assignments []models.Assignment, start, end time.Time) models.EstimateAccuracy {
+	var accuracy models.EstimateAccuracy
+	var errorSum float64
+	for _, assignment := range assignments {
+		if !assignment.EstimatedHours.Valid || assignment.EstimatedHours.Float64 <= 0 || !assignment.ActualHours.Valid || assignment.ActualHours.Float64 <= 0 {
+			continue
+		}
+		at := assignment.DueDate
+		if assignment.SubmittedAt.Valid {
+			at = assignment.SubmittedAt.Time
+		}
+		if at.Before(start) || !at.Before(end) {
+			continue
+		}
+
+		estimated, actual := assignment.EstimatedHours.Float64, assignment.ActualHours.Float64
+		accuracy.Assignments++
+		accuracy.EstimatedHours += estimated
+		accuracy.ActualHours += actual
+		miss := (actual - estimated) / estimated
+		errorSum += math.Abs(miss)
+		switch {
+		case miss > estimateTolerance:
+			accuracy.Underestimated++
+		case miss < -estimateTolerance:
+			accuracy.Overestimated++
+		default:
+			accuracy.Accurate++
+		}
+	}
+	if accuracy.Assignments > 0 {
+		accuracy.ActualToEstimate = sql.NullFloat64{Float64: roundTo(accuracy.ActualHours/accuracy.EstimatedHours, 2), Valid: true}
+		accuracy.MeanAbsErrorPct = sql.NullFloat64{Float64: roundTo(errorSum/float64(accuracy.Assignments)*100, 1), Valid: true}
+	}
+	accuracy.EstimatedHours = roundTo(accuracy.EstimatedHours, 2)
+	accuracy.ActualHours = roundTo(accuracy.ActualHours, 2)
+	return accuracy
+}
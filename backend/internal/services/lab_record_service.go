@@ -40,6 +40,7 @@ func (s *labRecordService) CreateLabRecord(ctx context.Context, userID string, i
 		PrintRequired:     true, // Default
 		Status:            "pending", // Default
 		VivaQuestions:     input.VivaQuestions,
+		Tags:              input.Tags,
 	}
 
 	if input.LabDate != nil {
@@ -209,6 +210,9 @@ func (s *labRecordService) UpdateLabRecord(ctx context.Context, userID string, i
 	if input.VivaQuestions != nil {
 		existingRecord.VivaQuestions = input.VivaQuestions
 	}
+	if input.Tags != nil {
+		existingRecord.Tags = input.Tags
+	}
 	if input.PrintRequired != nil {
 		existingRecord.PrintRequired = *input.PrintRequired
 	}
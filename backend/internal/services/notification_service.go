@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+// NotificationService defines the interface for notification-related business logic.
+type NotificationService interface {
+	Notify(ctx context.Context, userID, notificationType, title, message, entityType, entityID, dedupeKey string) (bool, error)
+	GetNotificationsByUserID(ctx context.Context, userID string, unreadOnly bool) ([]models.Notification, error)
+	MarkNotificationRead(ctx context.Context, userID, id string) error
+	MarkAllNotificationsRead(ctx context.Context, userID string) error
+	DeleteNotification(ctx context.Context, userID, id string) error
+}
+
+// notificationService implements NotificationService.
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationService creates a new notification service.
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{notificationRepo: notificationRepo}
+}
+
+// Notify creates a notification for a user. Empty optional arguments are stored as NULL.
+// It returns false when a notification with the same dedupe key already exists.
+func (s *notificationService) Notify(ctx context.Context, userID, notificationType, title, message, entityType, entityID, dedupeKey string) (bool, error) {
+	notification := &models.Notification{
+		UserID:           userID,
+		NotificationType: notificationType,
+		Title:            title,
+		Message:          sql.NullString{String: message, Valid: message != ""},
+		EntityType:       sql.NullString{String: entityType, Valid: entityType != ""},
+		EntityID:         sql.NullString{String: entityID, Valid: entityID != ""},
+		DedupeKey:        sql.NullString{String: dedupeKey, Valid: dedupeKey != ""},
+	}
+
+	created, err := s.notificationRepo.CreateNotification(ctx, notification)
+	if err != nil {
+		return false, fmt.Errorf("failed to create notification: %w", err)
+	}
+	return created, nil
+}
+
+// GetNotificationsByUserID retrieves a user's notifications.
+func (s *notificationService) GetNotificationsByUserID(ctx context.Context, userID string, unreadOnly bool) ([]models.Notification, error) {
+	return s.notificationRepo.GetNotificationsByUserID(ctx, userID, unreadOnly)
+}
+
+// MarkNotificationRead marks one of the user's notifications as read.
+func (s *notificationService) MarkNotificationRead(ctx context.Context, userID, id string) error {
+	return s.notificationRepo.MarkNotificationRead(ctx, id, userID)
+}
+
+// MarkAllNotificationsRead marks all of the user's notifications as read.
+func (s *notificationService) MarkAllNotificationsRead(ctx context.Context, userID string) error {
+	return s.notificationRepo.MarkAllNotificationsRead(ctx, userID)
+}
+
+// DeleteNotification deletes one of the user's notifications.
+func (s *notificationService) DeleteNotification(ctx context.Context, userID, id string) error {
+	return s.notificationRepo.DeleteNotification(ctx, id, userID)
+}
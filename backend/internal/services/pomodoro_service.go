@@ -0,0 +1,562 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+var (
+	// ErrPomodoroActive is returned when starting a pomodoro while another one is running or paused.
+	ErrPomodoroActive = errors.New("a pomodoro is already active")
+	// ErrNoActivePomodoro is returned when there is no running or paused pomodoro to act on.
+	ErrNoActivePomodoro = errors.New("no active pomodoro")
+	// ErrInvalidPomodoroAction is returned when an action does not fit the pomodoro's current phase or status.
+	ErrInvalidPomodoroAction = errors.New("invalid pomodoro action")
+	// ErrPomodoroChanged is returned when another request changed the pomodoro while an action was applied.
+	ErrPomodoroChanged = errors.New("pomodoro was changed by another request")
+)
+
+const (
+	defaultPomodoroWorkMinutes           = 25
+	defaultPomodoroShortBreakMinutes     = 5
+	defaultPomodoroLongBreakMinutes      = 15
+	defaultPomodoroCyclesBeforeLongBreak = 4
+	defaultPomodoroTargetCycles          = 4
+	maxPomodoroCycles                    = 12
+)
+
+// focusSpan is a stretch of work whose minutes go to the daily stats once the pomodoro is saved.
+type focusSpan struct {
+	from, to time.Time
+}
+
+// PomodoroService defines the interface for running pomodoros on study sessions.
+type PomodoroService interface {
+	StartPomodoro(ctx context.Context, userID, studySessionID string, input *models.PomodoroStartInput) (*models.PomodoroState, error)
+	GetCurrentPomodoro(ctx context.Context, userID string) (*models.PomodoroState, error)
+	PausePomodoro(ctx context.Context, userID string) (*models.PomodoroState, error)
+	ResumePomodoro(ctx context.Context, userID string) (*models.PomodoroState, error)
+	SkipBreak(ctx context.Context, userID string) (*models.PomodoroState, error)
+	RecordInterruption(ctx context.Context, userID string, input *models.PomodoroInterruptionInput) (*models.PomodoroState, error)
+	StopPomodoro(ctx context.Context, userID string, input *models.PomodoroStopInput) (*models.PomodoroState, error)
+	GetPomodorosByStudySession(ctx context.Context, userID, studySessionID string) ([]models.PomodoroSession, error)
+}
+
+// pomodoroService implements PomodoroService.
+type pomodoroService struct {
+	pomodoroRepo    repository.PomodoroRepository
+	sessionRepo     repository.StudySessionRepository
+	timeEntryRepo   repository.TimeEntryRepository
+	dailyStatsRepo  repository.DailyStatsRepository
+	userRepo        repository.UserRepository
+	coverageService CoverageService
+	eventBus        events.Bus
+}
+
+// NewPomodoroService creates a new pomodoro service.
+func NewPomodoroService(
+	pomodoroRepo repository.PomodoroRepository,
+	sessionRepo repository.StudySessionRepository,
+	timeEntryRepo repository.TimeEntryRepository,
+	dailyStatsRepo repository.DailyStatsRepository,
+	userRepo repository.UserRepository,
+	coverageService CoverageService,
+	eventBus events.Bus,
+) PomodoroService {
+	return &pomodoroService{
+		pomodoroRepo:    pomodoroRepo,
+		sessionRepo:     sessionRepo,
+		timeEntryRepo:   timeEntryRepo,
+		dailyStatsRepo:  dailyStatsRepo,
+		userRepo:        userRepo,
+		coverageService: coverageService,
+		eventBus:        eventBus,
+	}
+}
+
+// StartPomodoro starts work/break cycles on a study session. Without a target, enough cycles are run to
+// fill the session's planned duration. Only one pomodoro may be active at a time, and not alongside a timer.
+func (s *pomodoroService) StartPomodoro(ctx context.Context, userID, studySessionID string, input *models.PomodoroStartInput) (*models.PomodoroState, error) {
+	session, err := s.sessionRepo.GetStudySessionByID(ctx, studySessionID)
+	if err != nil {
+		return nil, fmt.Errorf("study session not found: %w", err)
+	}
+	if session.UserID != userID {
+		return nil, fmt.Errorf("study session does not belong to user")
+	}
+	if session.Status == "completed" || session.Status == "skipped" {
+		return nil, fmt.Errorf("%w: study session is already %s", ErrInvalidPomodoroAction, session.Status)
+	}
+
+	if _, _, err := s.loadActive(ctx, userID, time.Now()); err == nil {
+		return nil, ErrPomodoroActive
+	} else if !errors.Is(err, ErrNoActivePomodoro) {
+		return nil, err
+	}
+	if _, err := s.timeEntryRepo.GetRunningTimeEntryByUserID(ctx, userID); err == nil {
+		return nil, ErrTimerAlreadyRunning
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	now := time.Now()
+	pomodoro := &models.PomodoroSession{
+		UserID:                userID,
+		StudySessionID:        studySessionID,
+		WorkMinutes:           defaultPomodoroWorkMinutes,
+		ShortBreakMinutes:     defaultPomodoroShortBreakMinutes,
+		LongBreakMinutes:      defaultPomodoroLongBreakMinutes,
+		CyclesBeforeLongBreak: defaultPomodoroCyclesBeforeLongBreak,
+		TargetCycles:          defaultPomodoroTargetCycles,
+		Phase:                 "work",
+		Status:                "running",
+		PhaseStartedAt:        now,
+		StartedAt:             now,
+	}
+	if input.WorkMinutes != nil {
+		pomodoro.WorkMinutes = int32(*input.WorkMinutes)
+	}
+	if input.ShortBreakMinutes != nil {
+		pomodoro.ShortBreakMinutes = int32(*input.ShortBreakMinutes)
+	}
+	if input.LongBreakMinutes != nil {
+		pomodoro.LongBreakMinutes = int32(*input.LongBreakMinutes)
+	}
+	if input.CyclesBeforeLongBreak != nil {
+		pomodoro.CyclesBeforeLongBreak = int32(*input.CyclesBeforeLongBreak)
+	}
+	switch {
+	case input.TargetCycles != nil:
+		pomodoro.TargetCycles = int32(*input.TargetCycles)
+	case session.PlannedDurationMinutes.Valid && session.PlannedDurationMinutes.Int32 > 0:
+		cycles := int32(math.Ceil(float64(session.PlannedDurationMinutes.Int32) / float64(pomodoro.WorkMinutes)))
+		pomodoro.TargetCycles = min(max(cycles, 1), maxPomodoroCycles)
+	}
+
+	if err := s.pomodoroRepo.CreatePomodoro(ctx, pomodoro); err != nil {
+		return nil, fmt.Errorf("failed to start pomodoro: %w", err)
+	}
+
+	if !session.ActualStartTime.Valid || session.Status == "planned" {
+		if !session.ActualStartTime.Valid {
+			session.ActualStartTime = sql.NullTime{Time: now, Valid: true}
+		}
+		if session.Status == "planned" {
+			session.Status = "in_progress"
+		}
+		if err := s.sessionRepo.UpdateStudySession(ctx, session); err != nil {
+			return nil, fmt.Errorf("failed to update study session: %w", err)
+		}
+	}
+	return s.state(ctx, pomodoro, now, nil)
+}
+
+// GetCurrentPomodoro returns the active pomodoro with the time left in its phase. A pomodoro that
+// reached its target cycles while nobody was watching is finished and returned one last time.
+func (s *pomodoroService) GetCurrentPomodoro(ctx context.Context, userID string) (*models.PomodoroState, error) {
+	now := time.Now()
+	pomodoro, finished, err := s.loadActive(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.state(ctx, pomodoro, now, finished)
+}
+
+// PausePomodoro pauses the running pomodoro, keeping the time already spent in the current phase.
+func (s *pomodoroService) PausePomodoro(ctx context.Context, userID string) (*models.PomodoroState, error) {
+	now := time.Now()
+	pomodoro, finished, err := s.loadActive(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if finished != nil {
+		return s.state(ctx, pomodoro, now, finished)
+	}
+	if pomodoro.Status != "running" {
+		return nil, fmt.Errorf("%w: pomodoro is not running", ErrInvalidPomodoroAction)
+	}
+
+	before := *pomodoro
+	spans := pause(pomodoro, now)
+	if err := s.save(ctx, pomodoro, &before, spans); err != nil {
+		return nil, err
+	}
+	return s.state(ctx, pomodoro, now, nil)
+}
+
+// ResumePomodoro continues a paused pomodoro where it left off.
+func (s *pomodoroService) ResumePomodoro(ctx context.Context, userID string) (*models.PomodoroState, error) {
+	now := time.Now()
+	pomodoro, _, err := s.loadActive(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if pomodoro.Status != "paused" {
+		return nil, fmt.Errorf("%w: pomodoro is not paused", ErrInvalidPomodoroAction)
+	}
+
+	before := *pomodoro
+	pomodoro.Status = "running"
+	pomodoro.PhaseStartedAt = now
+	pomodoro.PausedAt = sql.NullTime{Valid: false}
+	if err := s.save(ctx, pomodoro, &before, nil); err != nil {
+		return nil, err
+	}
+	return s.state(ctx, pomodoro, now, nil)
+}
+
+// SkipBreak ends the current break and starts the next work phase right away.
+func (s *pomodoroService) SkipBreak(ctx context.Context, userID string) (*models.PomodoroState, error) {
+	now := time.Now()
+	pomodoro, finished, err := s.loadActive(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if finished != nil {
+		return s.state(ctx, pomodoro, now, finished)
+	}
+	if pomodoro.Phase == "work" {
+		return nil, fmt.Errorf("%w: there is no break to skip", ErrInvalidPomodoroAction)
+	}
+
+	before := *pomodoro
+	pomodoro.Phase = "work"
+	pomodoro.Status = "running"
+	pomodoro.PhaseStartedAt = now
+	pomodoro.PhaseElapsedSeconds = 0
+	pomodoro.PausedAt = sql.NullTime{Valid: false}
+	if err := s.save(ctx, pomodoro, &before, nil); err != nil {
+		return nil, err
+	}
+	return s.state(ctx, pomodoro, now, nil)
+}
+
+// RecordInterruption logs an interruption of the active pomodoro, optionally pausing it.
+func (s *pomodoroService) RecordInterruption(ctx context.Context, userID string, input *models.PomodoroInterruptionInput) (*models.PomodoroState, error) {
+	now := time.Now()
+	pomodoro, finished, err := s.loadActive(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if finished != nil {
+		return s.state(ctx, pomodoro, now, finished)
+	}
+
+	interruption := &models.PomodoroInterruption{
+		PomodoroID: pomodoro.ID,
+		OccurredAt: now,
+		Phase:      pomodoro.Phase,
+		Kind:       "external",
+	}
+	if input.Kind != "" {
+		interruption.Kind = input.Kind
+	}
+	if input.Reason != nil && *input.Reason != "" {
+		interruption.Reason = sql.NullString{String: *input.Reason, Valid: true}
+	}
+
+	before := *pomodoro
+	var spans []focusSpan
+	pomodoro.InterruptionCount++
+	if input.Pause && pomodoro.Status == "running" {
+		spans = pause(pomodoro, now)
+	}
+	if err := s.save(ctx, pomodoro, &before, spans); err != nil {
+		return nil, err
+	}
+	if err := s.pomodoroRepo.CreatePomodoroInterruption(ctx, interruption); err != nil {
+		return nil, err
+	}
+	return s.state(ctx, pomodoro, now, nil)
+}
+
+// StopPomodoro finishes the active pomodoro and writes its focus time, covered topics and completion
+// to the study session and daily stats.
+func (s *pomodoroService) StopPomodoro(ctx context.Context, userID string, input *models.PomodoroStopInput) (*models.PomodoroState, error) {
+	now := time.Now()
+	pomodoro, err := s.pomodoroRepo.GetActivePomodoroByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoActivePomodoro
+		}
+		return nil, err
+	}
+	before := *pomodoro
+	spans := advance(pomodoro, now)
+
+	if pomodoro.Status == "running" || pomodoro.Status == "paused" {
+		if pomodoro.Status == "running" && pomodoro.Phase == "work" {
+			spans = addFocus(pomodoro, spans, pomodoro.PhaseStartedAt, now)
+		}
+		pomodoro.Status = "stopped"
+		if pomodoro.CompletedCycles >= pomodoro.TargetCycles {
+			pomodoro.Status = "completed"
+		}
+		pomodoro.EndedAt = sql.NullTime{Time: now, Valid: true}
+		pomodoro.PausedAt = sql.NullTime{Valid: false}
+	}
+	if err := s.save(ctx, pomodoro, &before, spans); err != nil {
+		return nil, err
+	}
+
+	session, err := s.finishStudySession(ctx, pomodoro, input)
+	if err != nil {
+		return nil, err
+	}
+	return s.state(ctx, pomodoro, now, session)
+}
+
+// GetPomodorosByStudySession retrieves the pomodoros run on one of the user's study sessions.
+func (s *pomodoroService) GetPomodorosByStudySession(ctx context.Context, userID, studySessionID string) ([]models.PomodoroSession, error) {
+	session, err := s.sessionRepo.GetStudySessionByID(ctx, studySessionID)
+	if err != nil {
+		return nil, fmt.Errorf("study session not found: %w", err)
+	}
+	if session.UserID != userID {
+		return nil, fmt.Errorf("study session does not belong to user")
+	}
+	return s.pomodoroRepo.GetPomodorosByStudySessionID(ctx, studySessionID)
+}
+
+// loadActive returns the user's active pomodoro brought up to date. When it reached its target cycles
+// in the meantime it is finished, and the updated study session is returned with it.
+func (s *pomodoroService) loadActive(ctx context.Context, userID string, now time.Time) (*models.PomodoroSession, *models.StudySession, error) {
+	pomodoro, err := s.pomodoroRepo.GetActivePomodoroByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrNoActivePomodoro
+		}
+		return nil, nil, err
+	}
+
+	before := *pomodoro
+	spans := advance(pomodoro, now)
+	if pomodoro.Phase == before.Phase && pomodoro.Status == before.Status && pomodoro.CompletedCycles == before.CompletedCycles {
+		return pomodoro, nil, nil
+	}
+	if err := s.save(ctx, pomodoro, &before, spans); err != nil {
+		if errors.Is(err, ErrPomodoroChanged) {
+			// Another request advanced the pomodoro first and recorded its focus time; read its result.
+			return s.loadActive(ctx, userID, now)
+		}
+		return nil, nil, err
+	}
+	if pomodoro.Status != "completed" {
+		return pomodoro, nil, nil
+	}
+
+	session, err := s.finishStudySession(ctx, pomodoro, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pomodoro, session, nil
+}
+
+// save writes a pomodoro that was read as before, then adds its new focus spans to the daily study
+// minutes. Nothing is recorded when another request changed the pomodoro first.
+func (s *pomodoroService) save(ctx context.Context, pomodoro, before *models.PomodoroSession, spans []focusSpan) error {
+	updated, err := s.pomodoroRepo.UpdatePomodoro(ctx, pomodoro, before)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrPomodoroChanged
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	loc := userLocation(ctx, s.userRepo, pomodoro.UserID)
+	for _, span := range spans {
+		for day, minutes := range splitMinutesByDay(span.from, span.to, loc) {
+			if minutes == 0 {
+				continue
+			}
+			if err := s.dailyStatsRepo.AddStudyMinutes(ctx, pomodoro.UserID, day, minutes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// advance moves a running pomodoro through every phase that has ended by now, adding the focus time of
+// each finished work phase. It completes the pomodoro once the target cycles are done, and returns the
+// work spans to record.
+func advance(pomodoro *models.PomodoroSession, now time.Time) []focusSpan {
+	var spans []focusSpan
+	for pomodoro.Status == "running" {
+		remaining := time.Duration(phaseSeconds(pomodoro)-pomodoro.PhaseElapsedSeconds) * time.Second
+		phaseEnd := pomodoro.PhaseStartedAt.Add(remaining)
+		if now.Before(phaseEnd) {
+			return spans
+		}
+
+		if pomodoro.Phase == "work" {
+			spans = addFocus(pomodoro, spans, pomodoro.PhaseStartedAt, phaseEnd)
+			pomodoro.CompletedCycles++
+			switch {
+			case pomodoro.CompletedCycles >= pomodoro.TargetCycles:
+				pomodoro.Status = "completed"
+				pomodoro.EndedAt = sql.NullTime{Time: phaseEnd, Valid: true}
+			case pomodoro.CompletedCycles%pomodoro.CyclesBeforeLongBreak == 0:
+				pomodoro.Phase = "long_break"
+			default:
+				pomodoro.Phase = "short_break"
+			}
+		} else {
+			pomodoro.Phase = "work"
+		}
+		pomodoro.PhaseStartedAt = phaseEnd
+		pomodoro.PhaseElapsedSeconds = 0
+	}
+	return spans
+}
+
+// pause stops the clock of a running pomodoro at now and returns the work span to record.
+func pause(pomodoro *models.PomodoroSession, now time.Time) []focusSpan {
+	var spans []focusSpan
+	if pomodoro.Phase == "work" {
+		spans = addFocus(pomodoro, spans, pomodoro.PhaseStartedAt, now)
+	}
+	pomodoro.PhaseElapsedSeconds += int32(now.Sub(pomodoro.PhaseStartedAt).Seconds())
+	pomodoro.PhaseStartedAt = now
+	pomodoro.Status = "paused"
+	pomodoro.PausedAt = sql.NullTime{Time: now, Valid: true}
+	return spans
+}
+
+// addFocus adds a stretch of work to the pomodoro's focus time and to the spans to record.
+func addFocus(pomodoro *models.PomodoroSession, spans []focusSpan, from, to time.Time) []focusSpan {
+	if !to.After(from) {
+		return spans
+	}
+	pomodoro.FocusSeconds += int32(to.Sub(from).Seconds())
+	return append(spans, focusSpan{from: from, to: to})
+}
+
+// finishStudySession writes a finished pomodoro to its study session: the actual start, end and
+// duration, the topics covered and the completion percentage. Unless given, the completion comes from
+// the share of topics covered, or else from the focus time against the planned duration.
+func (s *pomodoroService) finishStudySession(ctx context.Context, pomodoro *models.PomodoroSession, input *models.PomodoroStopInput) (*models.StudySession, error) {
+	session, err := s.sessionRepo.GetStudySessionByID(ctx, pomodoro.StudySessionID)
+	if err != nil {
+		return nil, fmt.Errorf("study session not found: %w", err)
+	}
+
+	if !session.ActualStartTime.Valid {
+		session.ActualStartTime = sql.NullTime{Time: pomodoro.StartedAt, Valid: true}
+	}
+	session.ActualEndTime = pomodoro.EndedAt
+	session.ActualDurationMinutes = sql.NullInt32{
+		Int32: session.ActualDurationMinutes.Int32 + int32(math.Round(float64(pomodoro.FocusSeconds)/60)),
+		Valid: true,
+	}
+
+	newTopics := 0
+	if input != nil {
+		covered := []string(session.TopicsCovered)
+		for _, topic := range input.TopicsCovered {
+			if topic != "" && !containsTopic(covered, topic) {
+				covered = append(covered, topic)
+				newTopics++
+			}
+		}
+		session.TopicsCovered = covered
+	}
+
+	completion := int(session.CompletionPercentage)
+	switch {
+	case input != nil && input.CompletionPercentage != nil:
+		completion = *input.CompletionPercentage
+	case len(session.TopicsToCover) > 0 && newTopics > 0:
+		done := len(session.TopicsToCover) - len(uncoveredTopics(session))
+		completion = max(completion, done*100/len(session.TopicsToCover))
+	default:
+		planned := int(pomodoro.TargetCycles * pomodoro.WorkMinutes)
+		if session.PlannedDurationMinutes.Valid && session.PlannedDurationMinutes.Int32 > 0 {
+			planned = int(session.PlannedDurationMinutes.Int32)
+		}
+		completion = max(completion, min(100, int(session.ActualDurationMinutes.Int32)*100/planned))
+	}
+	session.CompletionPercentage = int32(completion)
+
+	wasCompleted := session.Status == "completed"
+	session.Status = "partial"
+	if completion >= 100 {
+		session.Status = "completed"
+	}
+	if err := s.sessionRepo.UpdateStudySession(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to update study session: %w", err)
+	}
+
+	var sessionsCompleted int32
+	if session.Status == "completed" && !wasCompleted {
+		sessionsCompleted = 1
+	}
+	if sessionsCompleted > 0 || newTopics > 0 {
+		loc := userLocation(ctx, s.userRepo, pomodoro.UserID)
+		ended := pomodoro.EndedAt.Time.In(loc)
+		day := time.Date(ended.Year(), ended.Month(), ended.Day(), 0, 0, 0, 0, time.UTC)
+		if err := s.dailyStatsRepo.AddSessionProgress(ctx, pomodoro.UserID, day, sessionsCompleted, int32(newTopics)); err != nil {
+			return nil, err
+		}
+	}
+	if newTopics > 0 && session.SubjectID.Valid {
+		if err := s.coverageService.RefreshCoverage(ctx, session.UserID, session.SubjectID.String); err != nil {
+			log.Printf("Warning: Could not refresh syllabus coverage after pomodoro %s: %v", pomodoro.ID, err)
+		}
+	}
+	if sessionsCompleted > 0 {
+		publishStudySessionCompleted(ctx, s.eventBus, session)
+	}
+	return session, nil
+}
+
+// state describes a pomodoro as seen at now.
+func (s *pomodoroService) state(ctx context.Context, pomodoro *models.PomodoroSession, now time.Time, session *models.StudySession) (*models.PomodoroState, error) {
+	interruptions, err := s.pomodoroRepo.GetPomodoroInterruptions(ctx, pomodoro.ID)
+	if err != nil {
+		return nil, err
+	}
+	if interruptions == nil {
+		interruptions = []models.PomodoroInterruption{}
+	}
+
+	state := &models.PomodoroState{
+		Pomodoro:      *pomodoro,
+		ServerTime:    now,
+		Interruptions: interruptions,
+		StudySession:  session,
+	}
+	switch pomodoro.Status {
+	case "running":
+		remaining := phaseSeconds(pomodoro) - pomodoro.PhaseElapsedSeconds - int32(now.Sub(pomodoro.PhaseStartedAt).Seconds())
+		state.RemainingSeconds = max(remaining, 0)
+		state.PhaseEndsAt = sql.NullTime{Time: now.Add(time.Duration(state.RemainingSeconds) * time.Second), Valid: true}
+	case "paused":
+		state.RemainingSeconds = max(phaseSeconds(pomodoro)-pomodoro.PhaseElapsedSeconds, 0)
+	}
+	return state, nil
+}
+
+// phaseSeconds returns the length of the pomodoro's current phase.
+func phaseSeconds(pomodoro *models.PomodoroSession) int32 {
+	switch pomodoro.Phase {
+	case "short_break":
+		return pomodoro.ShortBreakMinutes * 60
+	case "long_break":
+		return pomodoro.LongBreakMinutes * 60
+	}
+	return pomodoro.WorkMinutes * 60
+}
@@ -0,0 +1,661 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidQuestionImport is returned when an import file or its column mapping cannot be used.
+	ErrInvalidQuestionImport = errors.New("invalid question import")
+	// ErrUnsupportedExportFormat is returned when exporting to a format other than csv, markdown or anki.
+	ErrUnsupportedExportFormat = errors.New("unsupported export format: use csv, markdown or anki")
+)
+
+// maxImportRows caps the number of questions accepted by a single import.
+const maxImportRows = 2000
+
+// questionBankFields lists the importable fields in their default column order, used when a file
+// has no header row and no mapping.
+var questionBankFields = []string{"question", "answer", "unit", "topic", "marks", "tags", "frequency", "source", "confidence"}
+
+// questionBankColumnAliases maps normalised column headers to the field they are imported into.
+var questionBankColumnAliases = map[string]string{
+	"question": "question", "questions": "question", "question text": "question", "questiontext": "question", "q": "question", "front": "question",
+	"answer": "answer", "answers": "answer", "answer text": "answer", "answertext": "answer", "a": "answer", "back": "answer", "solution": "answer",
+	"unit": "unit", "module": "unit", "chapter": "unit",
+	"topic": "topic", "topics": "topic",
+	"marks": "marks", "mark": "marks", "max marks": "marks",
+	"tags": "tags", "tag": "tags", "labels": "tags",
+	"frequency": "frequency", "frequency count": "frequency", "frequencycount": "frequency", "times asked": "frequency", "asked": "frequency",
+	"source": "source", "paper": "source", "year": "source",
+	"confidence": "confidence", "confidence level": "confidence", "confidencelevel": "confidence",
+}
+
+// questionBankHeaders are the column headers written by CSV and Markdown exports. They are
+// recognised by import so that exported files can be shared and imported again.
+var questionBankHeaders = []string{"Question", "Answer", "Unit", "Topic", "Marks", "Frequency", "Source", "Tags", "Confidence"}
+
+var (
+	markdownSeparatorRow = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+	markdownLineBreak    = regexp.MustCompile(`(?i)<br\s*/?>`)
+	ankiHeaderLine       = regexp.MustCompile(`^#[a-z ]+:`)
+)
+
+// QuestionBankService defines the interface for importing and exporting important questions.
+type QuestionBankService interface {
+	ImportQuestions(ctx context.Context, userID string, data []byte, fileName string, input *models.QuestionImportInput) (*models.QuestionImportResult, error)
+	ExportQuestions(ctx context.Context, userID, format string, examID, subjectID *string) (*models.QuestionExport, error)
+}
+
+// questionBankService implements QuestionBankService.
+type questionBankService struct {
+	importantQuestionRepo repository.ImportantQuestionRepository
+	examRepo              repository.ExamRepository
+}
+
+// NewQuestionBankService creates a new question bank service.
+func NewQuestionBankService(
+	importantQuestionRepo repository.ImportantQuestionRepository,
+	examRepo repository.ExamRepository,
+) QuestionBankService {
+	return &questionBankService{
+		importantQuestionRepo: importantQuestionRepo,
+		examRepo:              examRepo,
+	}
+}
+
+// ImportQuestions imports important questions from a CSV file or Markdown table. Columns are matched
+// to fields by the mapping, falling back to well-known header names. Questions already in the bank
+// with the same wording are skipped, and invalid rows are reported without failing the import. The
+// valid rows are saved in a single transaction.
+func (s *questionBankService) ImportQuestions(ctx context.Context, userID string, data []byte, fileName string, input *models.QuestionImportInput) (*models.QuestionImportResult, error) {
+	if input.ExamID != nil {
+		exam, err := s.examRepo.GetExamByID(ctx, *input.ExamID)
+		if err != nil {
+			return nil, fmt.Errorf("exam not found: %w", err)
+		}
+		if exam.UserID != userID {
+			return nil, fmt.Errorf("exam does not belong to user")
+		}
+	}
+
+	format, err := importFormat(input.Format, fileName, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var records [][]string
+	if format == "markdown" {
+		records = parseMarkdownTable(string(data))
+	} else {
+		records, err = parseCSVRecords(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidQuestionImport, err)
+		}
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: no rows found", ErrInvalidQuestionImport)
+	}
+
+	hasHeader := input.HasHeader == nil || *input.HasHeader
+	var header []string
+	firstRow := 1
+	if hasHeader {
+		header, records = records[0], records[1:]
+		firstRow = 2
+	}
+	if len(records) > maxImportRows {
+		return nil, fmt.Errorf("%w: at most %d rows can be imported at once", ErrInvalidQuestionImport, maxImportRows)
+	}
+
+	var mapping map[string]string
+	if input.Mapping != nil && strings.TrimSpace(*input.Mapping) != "" {
+		if err := json.Unmarshal([]byte(*input.Mapping), &mapping); err != nil {
+			return nil, fmt.Errorf("%w: mapping must be a JSON object of field to column: %w", ErrInvalidQuestionImport, err)
+		}
+	}
+	columns, err := resolveImportColumns(header, importWidth(header, records), mapping, questionBankFields, questionBankColumnAliases)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidQuestionImport, err)
+	}
+	if _, ok := columns["question"]; !ok {
+		return nil, fmt.Errorf("%w: no question column found; map one with {\"question\": \"<column>\"}", ErrInvalidQuestionImport)
+	}
+
+	existing, err := s.importantQuestionRepo.GetImportantQuestionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get important questions: %w", err)
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, q := range existing {
+		seen[normalizeTopic(q.QuestionText)] = true
+	}
+
+	result := &models.QuestionImportResult{
+		Format:  format,
+		DryRun:  input.DryRun,
+		Mapping: make(map[string]string, len(columns)),
+		Rows:    []models.QuestionImportRow{},
+	}
+	for field, index := range columns {
+		if header != nil {
+			result.Mapping[field] = strings.TrimSpace(header[index])
+		} else {
+			result.Mapping[field] = "column " + strconv.Itoa(index+1)
+		}
+	}
+
+	// The created questions are inserted together once every row is checked; createdRows holds the
+	// index in result.Rows of each.
+	var created []*models.ImportantQuestion
+	var createdRows []int
+	for i, record := range records {
+		row := models.QuestionImportRow{Row: firstRow + i}
+		question, err := importedQuestion(record, columns)
+		if question != nil {
+			row.QuestionText = question.QuestionText
+		}
+		switch {
+		case err != nil:
+			row.Action = "invalid"
+			row.Error = err.Error()
+			result.Invalid++
+		case seen[normalizeTopic(question.QuestionText)]:
+			row.Action = "duplicate"
+			result.Duplicates++
+		default:
+			question.UserID = userID
+			if input.SubjectID != nil {
+				question.SubjectID = sql.NullString{String: *input.SubjectID, Valid: true}
+			}
+			if input.ExamID != nil {
+				question.ExamID = sql.NullString{String: *input.ExamID, Valid: true}
+			}
+			seen[normalizeTopic(question.QuestionText)] = true
+			row.Action = "created"
+			result.Created++
+			created = append(created, question)
+			createdRows = append(createdRows, len(result.Rows))
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	if input.DryRun || len(created) == 0 {
+		return result, nil
+	}
+	if err := s.importantQuestionRepo.CreateImportantQuestions(ctx, created); err != nil {
+		return nil, fmt.Errorf("failed to import questions: %w", err)
+	}
+	for i, question := range created {
+		result.Rows[createdRows[i]].QuestionID = question.ID
+	}
+	return result, nil
+}
+
+// ExportQuestions renders the user's important questions, optionally filtered by exam or subject,
+// as CSV, a Markdown table or tab-separated Anki notes.
+func (s *questionBankService) ExportQuestions(ctx context.Context, userID, format string, examID, subjectID *string) (*models.QuestionExport, error) {
+	title := "Important Questions"
+	fileName := "important-questions"
+	if examID != nil {
+		exam, err := s.examRepo.GetExamByID(ctx, *examID)
+		if err != nil {
+			return nil, fmt.Errorf("exam not found: %w", err)
+		}
+		if exam.UserID != userID {
+			return nil, fmt.Errorf("exam does not belong to user")
+		}
+		title += " - " + exam.Title
+		fileName += "-" + fileNameSlug(exam.Title)
+	}
+
+	all, err := s.importantQuestionRepo.GetImportantQuestionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get important questions: %w", err)
+	}
+	var questions []models.ImportantQuestion
+	for _, q := range all {
+		if examID != nil && q.ExamID.String != *examID {
+			continue
+		}
+		if subjectID != nil && q.SubjectID.String != *subjectID {
+			continue
+		}
+		questions = append(questions, q)
+	}
+	sort.SliceStable(questions, func(i, j int) bool {
+		if ui, uj := questions[i].Unit.String, questions[j].Unit.String; ui != uj {
+			return unitLess(ui, uj)
+		}
+		if fi, fj := questions[i].FrequencyCount.Int32, questions[j].FrequencyCount.Int32; fi != fj {
+			return fi > fj
+		}
+		return questions[i].CreatedAt.Before(questions[j].CreatedAt)
+	})
+
+	switch format {
+	case "csv":
+		content, err := questionsCSV(questions)
+		if err != nil {
+			return nil, err
+		}
+		return &models.QuestionExport{FileName: fileName + ".csv", ContentType: "text/csv; charset=utf-8", Content: content}, nil
+	case "markdown", "md":
+		return &models.QuestionExport{FileName: fileName + ".md", ContentType: "text/markdown; charset=utf-8", Content: questionsMarkdown(title, questions)}, nil
+	case "anki":
+		return &models.QuestionExport{FileName: fileName + "-anki.txt", ContentType: "text/plain; charset=utf-8", Content: questionsAnki(title, questions)}, nil
+	}
+	return nil, ErrUnsupportedExportFormat
+}
+
+// importFormat decides whether an import is CSV or Markdown from the requested format, the file
+// extension or, failing both, whether the content starts with a table row.
+func importFormat(requested *string, fileName string, data []byte) (string, error) {
+	if requested != nil && *requested != "" {
+		switch strings.ToLower(*requested) {
+		case "csv", "tsv":
+			return "csv", nil
+		case "markdown", "md":
+			return "markdown", nil
+		}
+		return "", fmt.Errorf("%w: unsupported format %q: use csv or markdown", ErrInvalidQuestionImport, *requested)
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".md", ".markdown":
+		return "markdown", nil
+	case ".csv", ".tsv", ".txt":
+		return "csv", nil
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("|")) {
+		return "markdown", nil
+	}
+	return "csv", nil
+}
+
+// parseCSVRecords reads a delimited file, choosing comma, semicolon or tab by whichever is most
+// frequent in the first line. Anki header lines ("#separator:tab", "#columns:...") set the delimiter
+// and header row, and HTML fields of Anki notes are converted back to text.
+func parseCSVRecords(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	lines := strings.SplitAfter(string(data), "\n")
+	ankiHeaders := make(map[string]string)
+	skip := 0
+	for skip < len(lines) && ankiHeaderLine.MatchString(lines[skip]) {
+		key, value, _ := strings.Cut(strings.TrimSpace(lines[skip])[1:], ":")
+		ankiHeaders[strings.ToLower(key)] = value
+		skip++
+	}
+	body := strings.Join(lines[skip:], "")
+
+	firstLine := body
+	if i := strings.IndexByte(body, '\n'); i >= 0 {
+		firstLine = body[:i]
+	}
+	delimiter := ','
+	best := strings.Count(firstLine, ",")
+	for _, candidate := range []rune{';', '\t'} {
+		if n := strings.Count(firstLine, string(candidate)); n > best {
+			delimiter, best = candidate, n
+		}
+	}
+	switch strings.ToLower(ankiHeaders["separator"]) {
+	case "tab":
+		delimiter = '\t'
+	case "comma":
+		delimiter = ','
+	case "semicolon":
+		delimiter = ';'
+	case "pipe":
+		delimiter = '|'
+	}
+
+	reader := csv.NewReader(strings.NewReader(body))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+
+	var rows [][]string
+	if columns, ok := ankiHeaders["columns"]; ok {
+		rows = append(rows, strings.Split(columns, string(delimiter)))
+	}
+	for _, record := range records {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		if ankiHeaders["html"] == "true" {
+			for i := range record {
+				record[i] = html.UnescapeString(markdownLineBreak.ReplaceAllString(record[i], "\n"))
+			}
+		}
+		// Anki tags are separated by spaces rather than commas.
+		if n, err := strconv.Atoi(ankiHeaders["tags column"]); err == nil && n >= 1 && n <= len(record) {
+			record[n-1] = strings.Join(strings.Fields(record[n-1]), "; ")
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// parseMarkdownTable reads the rows of the Markdown tables in a document. Separator rows and
+// repeated header rows of later tables are dropped, so a bank split into one table per unit imports
+// as a single table.
+func parseMarkdownTable(text string) [][]string {
+	var rows [][]string
+	var header string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") || markdownSeparatorRow.MatchString(line) {
+			continue
+		}
+		key := strings.ToLower(strings.Join(strings.Fields(line), " "))
+		if header == "" {
+			header = key
+		} else if key == header {
+			continue
+		}
+
+		line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+		var cells []string
+		var cell strings.Builder
+		for i := 0; i < len(line); i++ {
+			switch {
+			case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+				cell.WriteByte('|')
+				i++
+			case line[i] == '|':
+				cells = append(cells, cell.String())
+				cell.Reset()
+			default:
+				cell.WriteByte(line[i])
+			}
+		}
+		cells = append(cells, cell.String())
+		for i := range cells {
+			cells[i] = strings.TrimSpace(markdownLineBreak.ReplaceAllString(cells[i], "\n"))
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+// resolveImportColumns maps each of fields to a column index below width, the number of columns in
+// the file. Explicit mappings name a column header (case-insensitive) or a 1-based column number;
+// unmapped fields are matched by header names known to aliases, or by the order of fields when there
+// is no header.
+func resolveImportColumns(header []string, width int, mapping map[string]string, fields []string, aliases map[string]string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for field, column := range mapping {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if canonical, ok := aliases[field]; ok {
+			field = canonical
+		}
+		if !containsString(fields, field) {
+			return nil, fmt.Errorf("unknown field %q in mapping", field)
+		}
+		index := -1
+		for i, name := range header {
+			if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(column)) {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			n, err := strconv.Atoi(strings.TrimSpace(column))
+			if err != nil {
+				return nil, fmt.Errorf("column %q for %s not found", column, field)
+			}
+			if n < 1 || n > width {
+				return nil, fmt.Errorf("column %d for %s is out of range: the file has %d columns", n, field, width)
+			}
+			index = n - 1
+		}
+		columns[field] = index
+	}
+
+	if header == nil {
+		if len(mapping) == 0 {
+			for i, field := range fields {
+				if i >= width {
+					break
+				}
+				columns[field] = i
+			}
+		}
+	} else {
+		for i, name := range header {
+			field, ok := aliases[normalizeTopic(name)]
+			if !ok {
+				continue
+			}
+			if _, mapped := columns[field]; !mapped {
+				columns[field] = i
+			}
+		}
+	}
+	return columns, nil
+}
+
+// importWidth returns the number of columns in an imported file: the header's when there is one,
+// otherwise that of its widest row.
+func importWidth(header []string, records [][]string) int {
+	if header != nil {
+		return len(header)
+	}
+	width := 0
+	for _, record := range records {
+		width = max(width, len(record))
+	}
+	return width
+}
+
+// importedQuestion builds an important question from an imported row.
+func importedQuestion(record []string, columns map[string]int) (*models.ImportantQuestion, error) {
+	value := func(field string) string {
+		index, ok := columns[field]
+		if !ok || index >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[index])
+	}
+	optional := func(field string) sql.NullString {
+		v := value(field)
+		return sql.NullString{String: v, Valid: v != ""}
+	}
+
+	question := &models.ImportantQuestion{
+		QuestionText: value("question"),
+		AnswerText:   optional("answer"),
+		Source:       optional("source"),
+		Unit:         optional("unit"),
+		Topic:        optional("topic"),
+	}
+	if question.QuestionText == "" {
+		return nil, errors.New("question text is empty")
+	}
+
+	numbers := []struct {
+		field    string
+		min, max int
+		target   *sql.NullInt32
+	}{
+		{"marks", 0, 100, &question.Marks},
+		{"frequency", 1, 1000, &question.FrequencyCount},
+		{"confidence", 1, 5, &question.ConfidenceLevel},
+	}
+	for _, number := range numbers {
+		v := value(number.field)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.ToLower(v), "marks")))
+		if err != nil || n < number.min || n > number.max {
+			return question, fmt.Errorf("%s must be a number between %d and %d", number.field, number.min, number.max)
+		}
+		*number.target = sql.NullInt32{Int32: int32(n), Valid: true}
+	}
+
+	var tags []string
+	for _, tag := range strings.FieldsFunc(value("tags"), func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" && !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	question.Tags = tags
+	return question, nil
+}
+
+// questionBankRow returns the exported columns of a question, matching questionBankHeaders.
+func questionBankRow(q models.ImportantQuestion) []string {
+	marks, frequency, confidence := "", "", ""
+	if q.Marks.Valid {
+		marks = strconv.Itoa(int(q.Marks.Int32))
+	}
+	if q.FrequencyCount.Valid {
+		frequency = strconv.Itoa(int(q.FrequencyCount.Int32))
+	}
+	if q.ConfidenceLevel.Valid {
+		confidence = strconv.Itoa(int(q.ConfidenceLevel.Int32))
+	}
+	return []string{
+		q.QuestionText, q.AnswerText.String, q.Unit.String, q.Topic.String,
+		marks, frequency, q.Source.String, strings.Join(q.Tags, "; "), confidence,
+	}
+}
+
+// questionsCSV renders questions as CSV with a header row.
+func questionsCSV(questions []models.ImportantQuestion) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(questionBankHeaders); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, q := range questions {
+		if err := writer.Write(questionBankRow(q)); err != nil {
+			return nil, fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// questionsMarkdown renders questions as a titled Markdown table.
+func questionsMarkdown(title string, questions []models.ImportantQuestion) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n\n", title)
+	fmt.Fprintf(&buf, "_Exported %s - %d questions_\n\n", time.Now().Format("2 Jan 2006"), len(questions))
+
+	buf.WriteString("| " + strings.Join(questionBankHeaders, " | ") + " |\n")
+	buf.WriteString("|" + strings.Repeat(" --- |", len(questionBankHeaders)) + "\n")
+	for _, q := range questions {
+		cells := questionBankRow(q)
+		for i, cell := range cells {
+			cell = strings.ReplaceAll(cell, "|", `\|`)
+			cell = strings.ReplaceAll(strings.ReplaceAll(cell, "\r\n", "\n"), "\n", "<br>")
+			cells[i] = cell
+		}
+		buf.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return buf.Bytes()
+}
+
+// questionsAnki renders questions as tab-separated Anki notes for the Basic note type. The file
+// header tells Anki the separator, deck and tag column; unit, topic and marks are kept as
+// hierarchical tags and shown beneath the answer.
+func questionsAnki(deck string, questions []models.ImportantQuestion) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("#separator:tab\n")
+	buf.WriteString("#html:true\n")
+	buf.WriteString("#notetype:Basic\n")
+	fmt.Fprintf(&buf, "#deck:%s\n", ankiField(deck))
+	buf.WriteString("#tags column:3\n")
+	buf.WriteString("#columns:Front\tBack\tTags\n")
+
+	for _, q := range questions {
+		back := ankiField(q.AnswerText.String)
+		var details []string
+		var tags []string
+		if q.Unit.Valid && q.Unit.String != "" {
+			details = append(details, html.EscapeString(q.Unit.String))
+			tags = append(tags, "unit::"+ankiTag(q.Unit.String))
+		}
+		if q.Topic.Valid && q.Topic.String != "" {
+			details = append(details, html.EscapeString(q.Topic.String))
+			tags = append(tags, "topic::"+ankiTag(q.Topic.String))
+		}
+		if q.Marks.Valid {
+			details = append(details, fmt.Sprintf("%d marks", q.Marks.Int32))
+			tags = append(tags, fmt.Sprintf("marks::%d", q.Marks.Int32))
+		}
+		if q.FrequencyCount.Int32 > 1 {
+			details = append(details, fmt.Sprintf("asked %d times", q.FrequencyCount.Int32))
+		}
+		if len(details) > 0 {
+			if back != "" {
+				back += "<br><br>"
+			}
+			back += "<small>" + strings.Join(details, " &middot; ") + "</small>"
+		}
+		for _, tag := range q.Tags {
+			tags = append(tags, ankiTag(tag))
+		}
+		fmt.Fprintf(&buf, "%s\t%s\t%s\n", ankiField(q.QuestionText), back, strings.Join(tags, " "))
+	}
+	return buf.Bytes()
+}
+
+// ankiField escapes text for an HTML Anki field, replacing tabs and line breaks.
+func ankiField(text string) string {
+	text = html.EscapeString(strings.ReplaceAll(text, "\r\n", "\n"))
+	text = strings.ReplaceAll(text, "\t", " ")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+// ankiTag turns a label into an Anki tag, which cannot contain spaces.
+func ankiTag(label string) string {
+	return strings.Join(strings.Fields(label), "_")
+}
+
+// fileNameSlug reduces a title to lowercase words joined by hyphens for use in a file name.
+func fileNameSlug(title string) string {
+	return strings.ReplaceAll(normalizeTopic(title), " ", "-")
+}
+
+// unitLess orders unit names by unit number where they have one, placing questions without a unit last.
+func unitLess(a, b string) bool {
+	if a == "" || b == "" {
+		return b == ""
+	}
+	ca, cb := canonicalUnit(a), canonicalUnit(b)
+	na, errA := strconv.Atoi(strings.TrimPrefix(ca, "unit "))
+	nb, errB := strconv.Atoi(strings.TrimPrefix(cb, "unit "))
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+	if (errA == nil) != (errB == nil) {
+		return errA == nil
+	}
+	return ca < cb
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrUnsupportedPaperFormat is returned when a question paper is neither text nor a readable PDF.
+	ErrUnsupportedPaperFormat = errors.New("unsupported question paper format: upload a text or PDF file")
+	// ErrNoQuestionsExtracted is returned when no questions could be found in a question paper.
+	ErrNoQuestionsExtracted = errors.New("no questions could be extracted from the question paper")
+	// ErrPaperTooLarge is returned when the compressed streams of a PDF inflate past maxInflatedPaperSize.
+	ErrPaperTooLarge = errors.New("question paper expands to too much content to process")
+)
+
+// minQuestionLength is the shortest text, in characters, treated as a question rather than a heading.
+const minQuestionLength = 15
+
+// maxInflatedPaperSize caps the bytes the compressed streams of one PDF may inflate to, all streams
+// together, so a small upload cannot expand into gigabytes.
+const maxInflatedPaperSize = 32 << 20
+
+var (
+	unitHeadingPattern    = regexp.MustCompile(`(?i)^\s*(?:unit|module)\s*[-:.]?\s*([0-9]{1,2}|[ivx]{1,4})\b`)
+	questionStartPattern  = regexp.MustCompile(`(?i)^\s*(?:q\.?\s*)?\d{1,2}\s*[.):]\s*(?:\(?[a-h][.)]\s*)?(.*)$`)
+	questionNoDotPattern  = regexp.MustCompile(`(?i)^\s*q\.?\s*\d{1,2}\s+(.*)$`)
+	subPartPattern        = regexp.MustCompile(`(?i)^\s*\(?(?:[a-h]|i{1,3}|iv|v|vi)[.)]\s+(.*)$`)
+	trailingMarksPattern  = regexp.MustCompile(`(?i)\s*[(\[]\s*(\d{1,2})\s*(?:marks?|m)?\s*[)\]]\s*$`)
+	trailingMarksPlain    = regexp.MustCompile(`(?i)\s+(\d{1,2})\s*marks?\s*$`)
+	paperBoilerplateLines = regexp.MustCompile(`(?i)^\s*(?:\(?or\)?|part\s*[-:]?\s*[a-c]\b.*|section\s*[-:]?\s*[a-c]\b.*|answer\s+(?:all|any).*|time\s*:.*|max(?:imum)?\.?\s*marks.*|reg(?:ister)?\.?\s*no.*)\s*$`)
+	pdfStreamPattern      = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+)
+
+// romanNumerals maps the unit numbers written as roman numerals in paper headings.
+var romanNumerals = map[string]int{
+	"i": 1, "ii": 2, "iii": 3, "iv": 4, "v": 5, "vi": 6, "vii": 7, "viii": 8, "ix": 9, "x": 10,
+}
+
+// questionStopWords are ignored when comparing questions, so that "Explain X" and "Describe X with
+// a neat diagram" are recognised as the same question.
+var questionStopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "of": true, "and": true, "or": true, "to": true, "in": true,
+	"on": true, "for": true, "with": true, "is": true, "are": true, "its": true, "their": true,
+	"what": true, "how": true, "why": true, "explain": true, "describe": true, "discuss": true,
+	"write": true, "short": true, "note": true, "notes": true, "briefly": true, "brief": true,
+	"detail": true, "details": true, "give": true, "define": true, "state": true, "list": true,
+	"example": true, "examples": true, "suitable": true, "neat": true, "diagram": true,
+	"any": true, "two": true, "three": true, "following": true, "between": true, "marks": true,
+}
+
+// parsedQuestion is a question found in the text of a question paper.
+type parsedQuestion struct {
+	Text  string
+	Unit  string
+	Marks int
+}
+
+// extractPaperText returns the text of an uploaded question paper, which may be plain text or a PDF.
+func extractPaperText(data []byte) (string, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("%PDF")) {
+		text, err := extractPDFText(data)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(text) == "" {
+			return "", ErrUnsupportedPaperFormat
+		}
+		return text, nil
+	}
+	if !utf8.Valid(data) {
+		return "", ErrUnsupportedPaperFormat
+	}
+	return string(data), nil
+}
+
+// extractPDFText pulls the text drawn by the content streams of a PDF. It understands uncompressed
+// and Flate-compressed streams with literal strings, which covers papers exported from word
+// processors; scanned papers and fonts with custom encodings yield no text.
+func extractPDFText(data []byte) (string, error) {
+	var out strings.Builder
+	budget := int64(maxInflatedPaperSize)
+	for _, match := range pdfStreamPattern.FindAllSubmatch(data, -1) {
+		content := match[1]
+		if reader, err := zlib.NewReader(bytes.NewReader(content)); err == nil {
+			inflated, err := io.ReadAll(io.LimitReader(reader, budget+1))
+			reader.Close()
+			if int64(len(inflated)) > budget {
+				return "", ErrPaperTooLarge
+			}
+			budget -= int64(len(inflated))
+			if err == nil {
+				content = inflated
+			}
+		}
+		if !bytes.Contains(content, []byte("BT")) {
+			continue
+		}
+		out.WriteString(pdfContentText(content))
+		out.WriteString("\n")
+	}
+	return out.String(), nil
+}
+
+// pdfContentText interprets the text operators of a single PDF content stream.
+func pdfContentText(content []byte) string {
+	var out, line strings.Builder
+	var pending []string
+	newLine := func() {
+		if strings.TrimSpace(line.String()) != "" {
+			out.WriteString(strings.TrimSpace(line.String()))
+			out.WriteString("\n")
+		}
+		line.Reset()
+	}
+
+	for i := 0; i < len(content); i++ {
+		ch := content[i]
+		switch {
+		case ch == '(':
+			text, end := pdfLiteralString(content, i)
+			pending = append(pending, text)
+			i = end
+		case ch == '-' || (ch >= '0' && ch <= '9'):
+			// A large negative kerning inside a TJ array stands for a word gap.
+			start := i
+			for i+1 < len(content) && (content[i+1] == '.' || (content[i+1] >= '0' && content[i+1] <= '9')) {
+				i++
+			}
+			if n, err := strconv.ParseFloat(string(content[start:i+1]), 64); err == nil && n <= -200 && len(pending) > 0 {
+				pending = append(pending, " ")
+			}
+		case (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z') || ch == '\'' || ch == '"' || ch == '*':
+			start := i
+			for i+1 < len(content) && ((content[i+1] >= 'A' && content[i+1] <= 'Z') || (content[i+1] >= 'a' && content[i+1] <= 'z') || content[i+1] == '*') {
+				i++
+			}
+			switch string(content[start : i+1]) {
+			case "Tj", "TJ":
+				line.WriteString(strings.Join(pending, ""))
+			case "'", "\"":
+				newLine()
+				line.WriteString(strings.Join(pending, ""))
+			case "T*", "Td", "TD", "Tm", "ET":
+				newLine()
+			}
+			pending = nil
+		}
+	}
+	newLine()
+	return out.String()
+}
+
+// pdfLiteralString decodes the PDF literal string starting at the '(' at start and returns it with
+// the index of its closing parenthesis.
+func pdfLiteralString(content []byte, start int) (string, int) {
+	var sb strings.Builder
+	depth := 0
+	for i := start; i < len(content); i++ {
+		ch := content[i]
+		switch {
+		case ch == '\\' && i+1 < len(content):
+			i++
+			switch esc := content[i]; esc {
+			case 'n', 'r':
+				sb.WriteByte(' ')
+			case 't':
+				sb.WriteByte('\t')
+			case 'b', 'f':
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				end := i
+				for end+1 < len(content) && end-i < 2 && content[end+1] >= '0' && content[end+1] <= '7' {
+					end++
+				}
+				if n, err := strconv.ParseUint(string(content[i:end+1]), 8, 8); err == nil {
+					sb.WriteByte(byte(n))
+				}
+				i = end
+			default:
+				sb.WriteByte(esc)
+			}
+		case ch == '(':
+			if depth > 0 {
+				sb.WriteByte(ch)
+			}
+			depth++
+		case ch == ')':
+			depth--
+			if depth == 0 {
+				return sb.String(), i
+			}
+			sb.WriteByte(ch)
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+	return sb.String(), len(content) - 1
+}
+
+// parseQuestionPaper splits the text of a question paper into questions. Numbered questions ("1.",
+// "Q2)") and lettered sub-parts ("a)", "(ii)") each start a new question, lines in between are joined
+// to the current one, "Unit"/"Module" headings set the unit and trailing "(10 marks)" style
+// annotations are read as marks.
+func parseQuestionPaper(text string) []parsedQuestion {
+	var questions []parsedQuestion
+	var current *parsedQuestion
+	unit := ""
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		q := *current
+		current = nil
+		q.Text = strings.Join(strings.Fields(q.Text), " ")
+		if m := trailingMarksPattern.FindStringSubmatch(q.Text); m != nil {
+			q.Marks, _ = strconv.Atoi(m[1])
+			q.Text = strings.TrimSpace(trailingMarksPattern.ReplaceAllString(q.Text, ""))
+		} else if m := trailingMarksPlain.FindStringSubmatch(q.Text); m != nil {
+			q.Marks, _ = strconv.Atoi(m[1])
+			q.Text = strings.TrimSpace(trailingMarksPlain.ReplaceAllString(q.Text, ""))
+		}
+		if len(q.Text) < minQuestionLength || strings.HasSuffix(q.Text, ":") {
+			return
+		}
+		questions = append(questions, q)
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || paperBoilerplateLines.MatchString(line) {
+			continue
+		}
+		if m := unitHeadingPattern.FindStringSubmatch(line); m != nil {
+			flush()
+			unit = "Unit " + unitNumber(m[1])
+			continue
+		}
+
+		var rest string
+		matched := false
+		for _, pattern := range []*regexp.Regexp{questionStartPattern, questionNoDotPattern, subPartPattern} {
+			if m := pattern.FindStringSubmatch(line); m != nil {
+				rest, matched = m[1], true
+				break
+			}
+		}
+		if matched {
+			flush()
+			current = &parsedQuestion{Text: rest, Unit: unit}
+			continue
+		}
+		if current != nil {
+			current.Text += " " + line
+		}
+	}
+	flush()
+	return questions
+}
+
+// unitNumber converts a unit number written in digits or roman numerals to digits.
+func unitNumber(raw string) string {
+	if n, ok := romanNumerals[strings.ToLower(raw)]; ok {
+		return strconv.Itoa(n)
+	}
+	return raw
+}
+
+// questionTokens returns the significant words of a question for similarity comparison.
+func questionTokens(text string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, word := range strings.Fields(normalizeTopic(text)) {
+		if !questionStopWords[word] {
+			tokens[word] = true
+		}
+	}
+	return tokens
+}
+
+// questionSimilarity is the Jaccard similarity of the significant words of two questions.
+func questionSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,438 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+var (
+	// ErrNotAQuestionPaper is returned when ingesting a vault document that is not a previous paper.
+	ErrNotAQuestionPaper = errors.New("document is not a previous or FAT paper")
+	// ErrPaperAlreadyIngested is returned when ingesting the same vault document twice.
+	ErrPaperAlreadyIngested = errors.New("document has already been ingested")
+)
+
+const (
+	// duplicateQuestionThreshold is the similarity above which two questions are considered the same.
+	duplicateQuestionThreshold = 0.7
+	// maxPaperSize caps the size of a question paper downloaded from the vault.
+	maxPaperSize = 20 << 20
+)
+
+// maxPaperRedirects caps how many redirects are followed when downloading a vault document.
+const maxPaperRedirects = 5
+
+// ErrPaperURLNotAllowed is returned when a vault document's file URL points at an address the server
+// must not fetch from, such as a private network or a cloud metadata endpoint.
+var ErrPaperURLNotAllowed = errors.New("document file URL points to a disallowed address")
+
+// paperClient downloads vault documents for ingestion. Every connection, including those made for
+// redirects, is checked against the address it actually dials, so a host name cannot resolve or
+// redirect its way into the server's own network.
+var paperClient = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				ip, err := netip.ParseAddr(host)
+				if err != nil || !isPublicAddr(ip) {
+					return ErrPaperURLNotAllowed
+				}
+				return nil
+			},
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 20 * time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxPaperRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxPaperRedirects)
+		}
+		if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+			return ErrPaperURLNotAllowed
+		}
+		return nil
+	},
+}
+
+// carrierGradeNAT is the shared address space (RFC 6598), which some clouds use for metadata services.
+var carrierGradeNAT = netip.MustParsePrefix("100.64.0.0/10")
+
+// isPublicAddr reports whether ip is a globally routable unicast address. Loopback, private, link-local
+// (including the 169.254.169.254 metadata endpoint), shared, multicast and unspecified addresses are not.
+func isPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() &&
+		!carrierGradeNAT.Contains(ip)
+}
+
+// QuestionPaperService defines the interface for ingesting previous papers into the question bank.
+type QuestionPaperService interface {
+	IngestPaper(ctx context.Context, userID string, data []byte, fileName string, input *models.QuestionPaperIngestInput) (*models.QuestionPaperIngestResult, error)
+	IngestDocument(ctx context.Context, userID, documentID string, input *models.QuestionPaperIngestInput) (*models.QuestionPaperIngestResult, error)
+	GetQuestionPapersByUserID(ctx context.Context, userID string) ([]models.QuestionPaper, error)
+	DeleteQuestionPaper(ctx context.Context, userID, id string) error
+	GetExamQuestionSuggestions(ctx context.Context, userID, examID string, perUnit, minFrequency int) (*models.ExamQuestionSuggestions, error)
+}
+
+// questionPaperService implements QuestionPaperService.
+type questionPaperService struct {
+	questionPaperRepo     repository.QuestionPaperRepository
+	importantQuestionRepo repository.ImportantQuestionRepository
+	documentRepo          repository.DocumentRepository
+	examRepo              repository.ExamRepository
+}
+
+// NewQuestionPaperService creates a new question paper service.
+func NewQuestionPaperService(
+	questionPaperRepo repository.QuestionPaperRepository,
+	importantQuestionRepo repository.ImportantQuestionRepository,
+	documentRepo repository.DocumentRepository,
+	examRepo repository.ExamRepository,
+) QuestionPaperService {
+	return &questionPaperService{
+		questionPaperRepo:     questionPaperRepo,
+		importantQuestionRepo: importantQuestionRepo,
+		documentRepo:          documentRepo,
+		examRepo:              examRepo,
+	}
+}
+
+// IngestPaper extracts the questions of an uploaded (or pasted) paper into the question bank.
+func (s *questionPaperService) IngestPaper(ctx context.Context, userID string, data []byte, fileName string, input *models.QuestionPaperIngestInput) (*models.QuestionPaperIngestResult, error) {
+	var text string
+	if len(data) > 0 {
+		extracted, err := extractPaperText(data)
+		if err != nil {
+			return nil, err
+		}
+		text = extracted
+	} else if input.Text != nil {
+		text = *input.Text
+	}
+
+	paper := &models.QuestionPaper{
+		UserID:   userID,
+		Source:   strings.TrimSuffix(fileName, filepath.Ext(fileName)),
+		FileName: sql.NullString{String: fileName, Valid: fileName != ""},
+	}
+	if paper.Source == "" {
+		paper.Source = "Question paper " + time.Now().Format("2 Jan 2006")
+	}
+	return s.ingest(ctx, paper, text, input)
+}
+
+// IngestDocument downloads a previous paper from the documents vault and ingests its questions.
+// The document's subject and title are used unless the input overrides them.
+func (s *questionPaperService) IngestDocument(ctx context.Context, userID, documentID string, input *models.QuestionPaperIngestInput) (*models.QuestionPaperIngestResult, error) {
+	document, err := s.documentRepo.GetDocumentByID(ctx, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("document not found: %w", err)
+	}
+	if document.UserID != userID {
+		return nil, fmt.Errorf("document does not belong to user")
+	}
+	if document.DocumentType != "previous_paper" && document.DocumentType != "fat_paper" {
+		return nil, ErrNotAQuestionPaper
+	}
+	if !input.DryRun {
+		if _, err := s.questionPaperRepo.GetQuestionPaperByDocumentID(ctx, userID, documentID); err == nil {
+			return nil, ErrPaperAlreadyIngested
+		}
+	}
+
+	data, err := downloadPaper(ctx, document.FileURL)
+	if err != nil {
+		return nil, err
+	}
+	text, err := extractPaperText(data)
+	if err != nil {
+		return nil, err
+	}
+
+	paper := &models.QuestionPaper{
+		UserID:     userID,
+		DocumentID: sql.NullString{String: document.ID, Valid: true},
+		SubjectID:  document.SubjectID,
+		Source:     document.Title,
+		FileName:   document.FileName,
+	}
+	return s.ingest(ctx, paper, text, input)
+}
+
+// GetQuestionPapersByUserID retrieves the papers a user has ingested.
+func (s *questionPaperService) GetQuestionPapersByUserID(ctx context.Context, userID string) ([]models.QuestionPaper, error) {
+	return s.questionPaperRepo.GetQuestionPapersByUserID(ctx, userID)
+}
+
+// DeleteQuestionPaper deletes the record of an ingested paper. Questions and frequency counts are kept.
+func (s *questionPaperService) DeleteQuestionPaper(ctx context.Context, userID, id string) error {
+	return s.questionPaperRepo.DeleteQuestionPaper(ctx, id, userID)
+}
+
+// GetExamQuestionSuggestions lists, per unit of an exam's syllabus, the questions asked at least
+// minFrequency times, most frequent first.
+func (s *questionPaperService) GetExamQuestionSuggestions(ctx context.Context, userID, examID string, perUnit, minFrequency int) (*models.ExamQuestionSuggestions, error) {
+	exam, err := s.examRepo.GetExamByID(ctx, examID)
+	if err != nil {
+		return nil, fmt.Errorf("exam not found: %w", err)
+	}
+	if exam.UserID != userID {
+		return nil, fmt.Errorf("exam does not belong to user")
+	}
+	bank, err := s.importantQuestionRepo.GetImportantQuestionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get important questions: %w", err)
+	}
+
+	syllabusUnits := make(map[string]bool)
+	for _, unit := range exam.SyllabusUnits {
+		syllabusUnits[canonicalUnit(unit)] = true
+	}
+
+	// Questions are grouped by canonical unit, labelled with the first spelling seen.
+	byUnit := make(map[string][]models.ImportantQuestion)
+	unitLabels := make(map[string]string)
+	var units []string
+	for _, q := range bank {
+		if q.ExamID.String != exam.ID && (!exam.SubjectID.Valid || q.SubjectID.String != exam.SubjectID.String) {
+			continue
+		}
+		if int(q.FrequencyCount.Int32) < minFrequency {
+			continue
+		}
+		label := strings.TrimSpace(q.Unit.String)
+		key := canonicalUnit(label)
+		if len(syllabusUnits) > 0 && key != "" && !syllabusUnits[key] {
+			continue
+		}
+		if label == "" {
+			label = "Unspecified"
+		}
+		if _, ok := byUnit[key]; !ok {
+			units = append(units, key)
+			unitLabels[key] = label
+		}
+		byUnit[key] = append(byUnit[key], q)
+	}
+
+	sort.Strings(units)
+	suggestions := &models.ExamQuestionSuggestions{
+		ExamID:    exam.ID,
+		ExamTitle: exam.Title,
+		ExamDate:  exam.ExamDate,
+		Units:     []models.UnitQuestionSuggestions{},
+	}
+	for _, unit := range units {
+		questions := byUnit[unit]
+		sort.SliceStable(questions, func(i, j int) bool {
+			if questions[i].FrequencyCount.Int32 != questions[j].FrequencyCount.Int32 {
+				return questions[i].FrequencyCount.Int32 > questions[j].FrequencyCount.Int32
+			}
+			return questions[i].Marks.Int32 > questions[j].Marks.Int32
+		})
+		if perUnit > 0 && len(questions) > perUnit {
+			questions = questions[:perUnit]
+		}
+		suggestions.Units = append(suggestions.Units, models.UnitQuestionSuggestions{Unit: unitLabels[unit], Questions: questions})
+	}
+	return suggestions, nil
+}
+
+// ingest matches every question of a paper against the user's question bank for the same subject.
+// Near-duplicates bump the existing question's frequency count (once per paper); other questions are
+// added to the bank. All changes are saved in one transaction; in a dry run nothing is saved.
+func (s *questionPaperService) ingest(ctx context.Context, paper *models.QuestionPaper, text string, input *models.QuestionPaperIngestInput) (*models.QuestionPaperIngestResult, error) {
+	if input.SubjectID != nil {
+		paper.SubjectID = sql.NullString{String: *input.SubjectID, Valid: true}
+	}
+	if input.ExamID != nil {
+		exam, err := s.examRepo.GetExamByID(ctx, *input.ExamID)
+		if err != nil {
+			return nil, fmt.Errorf("exam not found: %w", err)
+		}
+		if exam.UserID != paper.UserID {
+			return nil, fmt.Errorf("exam does not belong to user")
+		}
+		paper.ExamID = sql.NullString{String: exam.ID, Valid: true}
+	}
+	if input.Source != nil && strings.TrimSpace(*input.Source) != "" {
+		paper.Source = strings.TrimSpace(*input.Source)
+	}
+
+	parsed := parseQuestionPaper(text)
+	if len(parsed) == 0 {
+		return nil, ErrNoQuestionsExtracted
+	}
+
+	all, err := s.importantQuestionRepo.GetImportantQuestionsByUserID(ctx, paper.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get important questions: %w", err)
+	}
+	var bank []*models.ImportantQuestion
+	for i := range all {
+		if !paper.SubjectID.Valid || all[i].SubjectID.String == paper.SubjectID.String {
+			bank = append(bank, &all[i])
+		}
+	}
+	tokens := make(map[*models.ImportantQuestion]map[string]bool, len(bank))
+	for _, q := range bank {
+		tokens[q] = questionTokens(q.QuestionText)
+	}
+
+	result := &models.QuestionPaperIngestResult{DryRun: input.DryRun, Questions: []models.ExtractedQuestion{}}
+	var created, merged []*models.ImportantQuestion
+	var occurrences []models.QuestionPaperOccurrence
+	matched := make([]*models.ImportantQuestion, 0, len(parsed))
+	countedInPaper := make(map[*models.ImportantQuestion]bool)
+
+	for _, pq := range parsed {
+		extracted := models.ExtractedQuestion{
+			Text:  pq.Text,
+			Unit:  sql.NullString{String: pq.Unit, Valid: pq.Unit != ""},
+			Marks: sql.NullInt32{Int32: int32(pq.Marks), Valid: pq.Marks > 0},
+		}
+		pqTokens := questionTokens(pq.Text)
+
+		var best *models.ImportantQuestion
+		bestScore := 0.0
+		for _, q := range bank {
+			if score := questionSimilarity(pqTokens, tokens[q]); score > bestScore {
+				best, bestScore = q, score
+			}
+		}
+
+		if best != nil && bestScore >= duplicateQuestionThreshold {
+			extracted.Action = "merged"
+			extracted.Similarity = roundTo(bestScore, 3)
+			extracted.MatchedText = sql.NullString{String: best.QuestionText, Valid: true}
+			// A question repeated within one paper (e.g. as an OR alternative) only counts once.
+			if !countedInPaper[best] {
+				countedInPaper[best] = true
+				frequency := best.FrequencyCount.Int32
+				if frequency == 0 {
+					frequency = 1
+				}
+				best.FrequencyCount = sql.NullInt32{Int32: frequency + 1, Valid: true}
+				if !best.Unit.Valid && extracted.Unit.Valid {
+					best.Unit = extracted.Unit
+				}
+				if !best.Marks.Valid && extracted.Marks.Valid {
+					best.Marks = extracted.Marks
+				}
+				if !best.Source.Valid {
+					best.Source = sql.NullString{String: paper.Source, Valid: true}
+				}
+				if best.ID != "" {
+					merged = append(merged, best)
+				}
+				paper.MergedCount++
+			}
+		} else {
+			question := &models.ImportantQuestion{
+				UserID:         paper.UserID,
+				SubjectID:      paper.SubjectID,
+				ExamID:         paper.ExamID,
+				QuestionText:   pq.Text,
+				Source:         sql.NullString{String: paper.Source, Valid: true},
+				Unit:           extracted.Unit,
+				Marks:          extracted.Marks,
+				FrequencyCount: sql.NullInt32{Int32: 1, Valid: true},
+			}
+			created = append(created, question)
+			bank = append(bank, question)
+			tokens[question] = pqTokens
+			countedInPaper[question] = true
+			best, bestScore = question, 1
+			extracted.Action = "created"
+			extracted.Similarity = 1
+			paper.CreatedCount++
+		}
+
+		extracted.FrequencyCount = best.FrequencyCount.Int32
+		occurrences = append(occurrences, models.QuestionPaperOccurrence{
+			ExtractedText: pq.Text,
+			Similarity:    roundTo(bestScore, 3),
+		})
+		matched = append(matched, best)
+		result.Questions = append(result.Questions, extracted)
+	}
+	paper.ExtractedCount = int32(len(parsed))
+
+	if !input.DryRun {
+		if err := s.questionPaperRepo.IngestQuestionPaper(ctx, paper, created, merged, occurrences, matched); err != nil {
+			return nil, err
+		}
+		result.Paper = paper
+	}
+	// Questions created in a dry run have no ID yet.
+	for i, q := range matched {
+		if q.ID != "" {
+			result.Questions[i].QuestionID = sql.NullString{String: q.ID, Valid: true}
+		}
+	}
+	return result, nil
+}
+
+// downloadPaper fetches a vault document over HTTP(S) from a public address.
+func downloadPaper(ctx context.Context, fileURL string) ([]byte, error) {
+	if !strings.HasPrefix(fileURL, "http://") && !strings.HasPrefix(fileURL, "https://") {
+		return nil, fmt.Errorf("document file URL must be http or https")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document file URL: %w", err)
+	}
+	resp, err := paperClient.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrPaperURLNotAllowed) {
+			return nil, ErrPaperURLNotAllowed
+		}
+		return nil, fmt.Errorf("failed to download document: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download document: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPaperSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download document: %w", err)
+	}
+	if len(data) > maxPaperSize {
+		return nil, fmt.Errorf("document is larger than %d MB", maxPaperSize>>20)
+	}
+	return data, nil
+}
+
+// canonicalUnit reduces unit names such as "Unit III", "unit-3" and "3" to a common form.
+func canonicalUnit(unit string) string {
+	if m := unitHeadingPattern.FindStringSubmatch(unit); m != nil {
+		return "unit " + unitNumber(m[1])
+	}
+	trimmed := strings.TrimSpace(unit)
+	if _, ok := romanNumerals[strings.ToLower(trimmed)]; ok || (trimmed != "" && strings.Trim(trimmed, "0123456789") == "") {
+		return "unit " + unitNumber(trimmed)
+	}
+	return normalizeTopic(unit)
+}
@@ -0,0 +1,431 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+var (
+	// ErrNoQuizQuestions is returned when no important questions match the quiz filters.
+	ErrNoQuizQuestions = errors.New("no important questions match the quiz filters")
+	// ErrQuizCompleted is returned when acting on a quiz that has no questions left to answer.
+	ErrQuizCompleted = errors.New("quiz is already completed")
+	// ErrQuizQuestionAnswered is returned when answering a quiz question twice.
+	ErrQuizQuestionAnswered = errors.New("quiz question is already answered")
+	// ErrQuizAnswerRequired is returned when an answer has neither a self grade nor a confidence level.
+	ErrQuizAnswerRequired = errors.New("selfGrade or confidenceLevel is required")
+)
+
+// defaultQuizSize is the number of questions in a quiz when no size is requested.
+const defaultQuizSize = 10
+
+// QuizService defines the interface for quiz-related business logic.
+type QuizService interface {
+	CreateQuiz(ctx context.Context, userID string, input *models.QuizCreationInput) (*models.Quiz, error)
+	GetQuizzesByUserID(ctx context.Context, userID string) ([]models.Quiz, error)
+	GetQuizByID(ctx context.Context, userID, id string) (*models.Quiz, error)
+	GetCurrentQuestion(ctx context.Context, userID, id string) (*models.QuizQuestionView, error)
+	AnswerQuestion(ctx context.Context, userID, id string, position int, input *models.QuizAnswerInput) (*models.QuizAnswerResult, error)
+	CompleteQuiz(ctx context.Context, userID, id string) (*models.Quiz, error)
+	DeleteQuiz(ctx context.Context, userID, id string) error
+}
+
+// quizService implements QuizService.
+type quizService struct {
+	quizRepo                repository.QuizRepository
+	importantQuestionRepo   repository.ImportantQuestionRepository
+	spacedRepetitionService SpacedRepetitionService
+	eventBus                events.Bus
+}
+
+// NewQuizService creates a new quiz service.
+func NewQuizService(
+	quizRepo repository.QuizRepository,
+	importantQuestionRepo repository.ImportantQuestionRepository,
+	spacedRepetitionService SpacedRepetitionService,
+	eventBus events.Bus,
+) QuizService {
+	return &quizService{
+		quizRepo:                quizRepo,
+		importantQuestionRepo:   importantQuestionRepo,
+		spacedRepetitionService: spacedRepetitionService,
+		eventBus:                eventBus,
+	}
+}
+
+// CreateQuiz assembles a quiz from the user's important questions that match every given filter.
+func (s *quizService) CreateQuiz(ctx context.Context, userID string, input *models.QuizCreationInput) (*models.Quiz, error) {
+	bank, err := s.importantQuestionRepo.GetImportantQuestionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get important questions: %w", err)
+	}
+
+	var matched []models.ImportantQuestion
+	for _, q := range bank {
+		if input.ExamID != nil && q.ExamID.String != *input.ExamID {
+			continue
+		}
+		if input.SubjectID != nil && q.SubjectID.String != *input.SubjectID {
+			continue
+		}
+		if input.Unit != nil && !strings.EqualFold(strings.TrimSpace(q.Unit.String), strings.TrimSpace(*input.Unit)) {
+			continue
+		}
+		if input.Topic != nil && !strings.EqualFold(strings.TrimSpace(q.Topic.String), strings.TrimSpace(*input.Topic)) {
+			continue
+		}
+		if input.Marks != nil && (!q.Marks.Valid || int(q.Marks.Int32) != *input.Marks) {
+			continue
+		}
+		matched = append(matched, q)
+	}
+	if len(matched) == 0 {
+		return nil, ErrNoQuizQuestions
+	}
+
+	if input.Shuffle == nil || *input.Shuffle {
+		rand.Shuffle(len(matched), func(i, j int) { matched[i], matched[j] = matched[j], matched[i] })
+	}
+	size := defaultQuizSize
+	if input.Size != nil {
+		size = *input.Size
+	}
+	if len(matched) > size {
+		matched = matched[:size]
+	}
+
+	quiz := &models.Quiz{
+		UserID:         userID,
+		Title:          "Practice quiz " + time.Now().Format("2 Jan 2006"),
+		Status:         "in_progress",
+		TotalQuestions: int32(len(matched)),
+	}
+	if input.Title != nil && strings.TrimSpace(*input.Title) != "" {
+		quiz.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.ExamID != nil {
+		quiz.ExamID = sql.NullString{String: *input.ExamID, Valid: true}
+	}
+	if input.SubjectID != nil {
+		quiz.SubjectID = sql.NullString{String: *input.SubjectID, Valid: true}
+	}
+	if input.Unit != nil {
+		quiz.Unit = sql.NullString{String: *input.Unit, Valid: true}
+	}
+	if input.Topic != nil {
+		quiz.Topic = sql.NullString{String: *input.Topic, Valid: true}
+	}
+	if input.Marks != nil {
+		quiz.Marks = sql.NullInt32{Int32: int32(*input.Marks), Valid: true}
+	}
+
+	for i, q := range matched {
+		quiz.Questions = append(quiz.Questions, models.QuizQuestion{
+			QuestionID:   sql.NullString{String: q.ID, Valid: true},
+			Position:     int32(i + 1),
+			QuestionText: q.QuestionText,
+			Marks:        q.Marks,
+		})
+	}
+
+	if err := s.quizRepo.CreateQuiz(ctx, quiz); err != nil {
+		return nil, fmt.Errorf("failed to create quiz: %w", err)
+	}
+	// Questions are presented one at a time through GetCurrentQuestion.
+	quiz.Questions = nil
+	return quiz, nil
+}
+
+// GetQuizzesByUserID retrieves a user's quizzes without their questions.
+func (s *quizService) GetQuizzesByUserID(ctx context.Context, userID string) ([]models.Quiz, error) {
+	return s.quizRepo.GetQuizzesByUserID(ctx, userID)
+}
+
+// GetQuizByID retrieves one of the user's quizzes with its questions and responses.
+func (s *quizService) GetQuizByID(ctx context.Context, userID, id string) (*models.Quiz, error) {
+	quiz, err := s.ownedQuiz(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := s.quizRepo.GetQuizQuestions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	quiz.Questions = questions
+	return quiz, nil
+}
+
+// GetCurrentQuestion returns the first unanswered question of a quiz, without its answer.
+func (s *quizService) GetCurrentQuestion(ctx context.Context, userID, id string) (*models.QuizQuestionView, error) {
+	quiz, err := s.ownedQuiz(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.Status == "completed" {
+		return nil, ErrQuizCompleted
+	}
+	questions, err := s.quizRepo.GetQuizQuestions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	view := s.nextQuestionView(ctx, quiz, questions)
+	if view == nil {
+		return nil, ErrQuizCompleted
+	}
+	return view, nil
+}
+
+// AnswerQuestion records the response to the question at position, schedules the underlying important
+// question for review and completes the quiz once every question is answered.
+func (s *quizService) AnswerQuestion(ctx context.Context, userID, id string, position int, input *models.QuizAnswerInput) (*models.QuizAnswerResult, error) {
+	if input.SelfGrade == nil && input.ConfidenceLevel == nil {
+		return nil, ErrQuizAnswerRequired
+	}
+
+	quiz, err := s.ownedQuiz(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.Status == "completed" {
+		return nil, ErrQuizCompleted
+	}
+	questions, err := s.quizRepo.GetQuizQuestions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var question *models.QuizQuestion
+	for i := range questions {
+		if int(questions[i].Position) == position {
+			question = &questions[i]
+		}
+	}
+	if question == nil {
+		return nil, fmt.Errorf("quiz question not found")
+	}
+	if question.AnsweredAt.Valid {
+		return nil, ErrQuizQuestionAnswered
+	}
+
+	grade := quizSelfGrade(input)
+	question.SelfGrade = sql.NullString{String: grade, Valid: true}
+	question.AnsweredAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if input.ConfidenceLevel != nil {
+		question.ConfidenceLevel = sql.NullInt32{Int32: int32(*input.ConfidenceLevel), Valid: true}
+	}
+	if input.TimeSpentSeconds != nil {
+		question.TimeSpentSeconds = sql.NullInt32{Int32: int32(*input.TimeSpentSeconds), Valid: true}
+	}
+
+	applyQuizResults(quiz, questions)
+	if quiz.AnsweredCount == quiz.TotalQuestions {
+		quiz.Status = "completed"
+		quiz.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+	answered, err := s.quizRepo.AnswerQuizQuestion(ctx, quiz, question)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record quiz answer: %w", err)
+	}
+	if !answered {
+		return nil, ErrQuizQuestionAnswered
+	}
+	if quiz.Status == "completed" {
+		s.publishQuizCompleted(ctx, quiz)
+	}
+
+	// Feed the attempt into the spaced repetition schedule once the answer is stored, so a retried
+	// answer cannot review the question twice. Skipped questions were not attempted.
+	var answerText sql.NullString
+	if question.QuestionID.Valid {
+		if grade != "skipped" {
+			quality := quizReviewQuality(grade, question.ConfidenceLevel)
+			review := &models.QuestionReviewInput{
+				Quality:          &quality,
+				ConfidenceLevel:  input.ConfidenceLevel,
+				TimeSpentSeconds: input.TimeSpentSeconds,
+			}
+			if _, err := s.spacedRepetitionService.ReviewQuestion(ctx, userID, question.QuestionID.String, review); err != nil {
+				log.Printf("Warning: Could not schedule review of question %s from quiz %s: %v", question.QuestionID.String, quiz.ID, err)
+			}
+		}
+		if source, err := s.importantQuestionRepo.GetImportantQuestionByID(ctx, question.QuestionID.String); err == nil {
+			answerText = source.AnswerText
+		}
+	}
+
+	result := &models.QuizAnswerResult{
+		Question:   *question,
+		AnswerText: answerText,
+		Quiz:       *quiz,
+	}
+	if quiz.Status != "completed" {
+		result.NextQuestion = s.nextQuestionView(ctx, quiz, questions)
+	}
+	return result, nil
+}
+
+// CompleteQuiz ends a quiz early. Unanswered questions count against the score.
+func (s *quizService) CompleteQuiz(ctx context.Context, userID, id string) (*models.Quiz, error) {
+	quiz, err := s.ownedQuiz(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if quiz.Status == "completed" {
+		return nil, ErrQuizCompleted
+	}
+	questions, err := s.quizRepo.GetQuizQuestions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyQuizResults(quiz, questions)
+	quiz.Status = "completed"
+	quiz.CompletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := s.quizRepo.UpdateQuiz(ctx, quiz); err != nil {
+		return nil, fmt.Errorf("failed to complete quiz: %w", err)
+	}
+	s.publishQuizCompleted(ctx, quiz)
+	quiz.Questions = questions
+	return quiz, nil
+}
+
+// DeleteQuiz deletes one of the user's quizzes.
+func (s *quizService) DeleteQuiz(ctx context.Context, userID, id string) error {
+	return s.quizRepo.DeleteQuiz(ctx, id, userID)
+}
+
+// publishQuizCompleted publishes the completion of a quiz with its results.
+func (s *quizService) publishQuizCompleted(ctx context.Context, quiz *models.Quiz) {
+	s.eventBus.Publish(ctx, events.Event{
+		Type:        events.QuizCompleted,
+		UserID:      quiz.UserID,
+		EntityType:  "quiz",
+		EntityID:    quiz.ID,
+		Description: "Completed quiz " + quiz.Title,
+		Metadata: map[string]interface{}{
+			"title":           quiz.Title,
+			"totalQuestions":  quiz.TotalQuestions,
+			"answeredCount":   quiz.AnsweredCount,
+			"scorePercentage": quiz.ScorePercentage,
+		},
+	})
+}
+
+// ownedQuiz loads a quiz and checks that it belongs to the user.
+func (s *quizService) ownedQuiz(ctx context.Context, userID, id string) (*models.Quiz, error) {
+	quiz, err := s.quizRepo.GetQuizByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("quiz not found: %w", err)
+	}
+	if quiz.UserID != userID {
+		return nil, fmt.Errorf("quiz does not belong to user")
+	}
+	return quiz, nil
+}
+
+// nextQuestionView builds the view of the first unanswered question, or nil when none is left.
+func (s *quizService) nextQuestionView(ctx context.Context, quiz *models.Quiz, questions []models.QuizQuestion) *models.QuizQuestionView {
+	for _, q := range questions {
+		if q.AnsweredAt.Valid {
+			continue
+		}
+		view := &models.QuizQuestionView{
+			QuizID:         quiz.ID,
+			Position:       q.Position,
+			TotalQuestions: quiz.TotalQuestions,
+			QuestionID:     q.QuestionID,
+			QuestionText:   q.QuestionText,
+			Marks:          q.Marks,
+		}
+		if q.QuestionID.Valid {
+			if source, err := s.importantQuestionRepo.GetImportantQuestionByID(ctx, q.QuestionID.String); err == nil {
+				view.Unit = source.Unit
+				view.Topic = source.Topic
+			}
+		}
+		return view
+	}
+	return nil
+}
+
+// applyQuizResults recomputes a quiz's counts, score and average confidence from its questions.
+// Correct answers score fully and partial answers half, out of every question in the quiz.
+func applyQuizResults(quiz *models.Quiz, questions []models.QuizQuestion) {
+	quiz.AnsweredCount, quiz.CorrectCount, quiz.PartialCount, quiz.IncorrectCount, quiz.SkippedCount = 0, 0, 0, 0, 0
+	quiz.TotalTimeSeconds = 0
+
+	var confidenceSum, confidenceCount int32
+	for _, q := range questions {
+		if !q.AnsweredAt.Valid {
+			continue
+		}
+		quiz.AnsweredCount++
+		switch q.SelfGrade.String {
+		case "correct":
+			quiz.CorrectCount++
+		case "partial":
+			quiz.PartialCount++
+		case "incorrect":
+			quiz.IncorrectCount++
+		case "skipped":
+			quiz.SkippedCount++
+		}
+		if q.ConfidenceLevel.Valid {
+			confidenceSum += q.ConfidenceLevel.Int32
+			confidenceCount++
+		}
+		if q.TimeSpentSeconds.Valid {
+			quiz.TotalTimeSeconds += q.TimeSpentSeconds.Int32
+		}
+	}
+
+	if quiz.TotalQuestions > 0 {
+		score := (float64(quiz.CorrectCount) + 0.5*float64(quiz.PartialCount)) / float64(quiz.TotalQuestions) * 100
+		quiz.ScorePercentage = sql.NullFloat64{Float64: roundTo(score, 2), Valid: true}
+	}
+	if confidenceCount > 0 {
+		quiz.AverageConfidence = sql.NullFloat64{Float64: roundTo(float64(confidenceSum)/float64(confidenceCount), 2), Valid: true}
+	}
+}
+
+// quizSelfGrade returns the self grade of an answer, deriving it from the confidence level when the
+// user only rated their confidence: 4-5 counts as correct, 3 as partial and 1-2 as incorrect.
+func quizSelfGrade(input *models.QuizAnswerInput) string {
+	if input.SelfGrade != nil {
+		return *input.SelfGrade
+	}
+	switch {
+	case *input.ConfidenceLevel >= 4:
+		return "correct"
+	case *input.ConfidenceLevel == 3:
+		return "partial"
+	default:
+		return "incorrect"
+	}
+}
+
+// quizReviewQuality maps a quiz self grade to an SM-2 recall quality.
+func quizReviewQuality(grade string, confidence sql.NullInt32) int {
+	switch grade {
+	case "correct":
+		if confidence.Valid && confidence.Int32 < 4 {
+			return 4
+		}
+		return 5
+	case "partial":
+		return 3
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+const (
+	defaultEaseFactor = 2.5
+	minEaseFactor     = 1.3
+)
+
+// SpacedRepetitionService defines the interface for scheduling important question reviews.
+type SpacedRepetitionService interface {
+	ReviewQuestion(ctx context.Context, userID, questionID string, input *models.QuestionReviewInput) (*models.QuestionReview, error)
+	GetDueQuestions(ctx context.Context, userID string, limit int) ([]models.DueQuestion, error)
+	GetReviewSession(ctx context.Context, userID string, size int, examID, subjectID string) (*models.ReviewSession, error)
+	SubmitReviewSession(ctx context.Context, userID string, input *models.ReviewSessionInput) (*models.ReviewSessionResult, error)
+	GetQuestionReviews(ctx context.Context, userID, questionID string) ([]models.QuestionReview, error)
+}
+
+// spacedRepetitionService implements SpacedRepetitionService.
+type spacedRepetitionService struct {
+	importantQuestionRepo repository.ImportantQuestionRepository
+	questionReviewRepo    repository.QuestionReviewRepository
+	examRepo              repository.ExamRepository
+	userRepo              repository.UserRepository
+	eventBus              events.Bus
+}
+
+// NewSpacedRepetitionService creates a new spaced repetition service.
+func NewSpacedRepetitionService(
+	importantQuestionRepo repository.ImportantQuestionRepository,
+	questionReviewRepo repository.QuestionReviewRepository,
+	examRepo repository.ExamRepository,
+	userRepo repository.UserRepository,
+	eventBus events.Bus,
+) SpacedRepetitionService {
+	return &spacedRepetitionService{
+		importantQuestionRepo: importantQuestionRepo,
+		questionReviewRepo:    questionReviewRepo,
+		examRepo:              examRepo,
+		userRepo:              userRepo,
+		eventBus:              eventBus,
+	}
+}
+
+// ReviewQuestion records a practice attempt and reschedules the question using SM-2.
+func (s *spacedRepetitionService) ReviewQuestion(ctx context.Context, userID, questionID string, input *models.QuestionReviewInput) (*models.QuestionReview, error) {
+	question, err := s.importantQuestionRepo.GetImportantQuestionByID(ctx, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("important question not found: %w", err)
+	}
+	if question.UserID != userID {
+		return nil, fmt.Errorf("important question does not belong to user")
+	}
+
+	exams, err := s.examRepo.GetUpcomingExamsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming exams: %w", err)
+	}
+	loc := userLocation(ctx, s.userRepo, userID)
+	return s.recordReview(ctx, question, input, linkedExam(*question, exams), time.Now().In(loc))
+}
+
+// GetDueQuestions returns the user's questions due for review, prioritised by the nearest linked exam.
+// A limit of zero or less returns every due question.
+func (s *spacedRepetitionService) GetDueQuestions(ctx context.Context, userID string, limit int) ([]models.DueQuestion, error) {
+	due, err := s.dueQuestions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+// GetReviewSession picks up to size due questions, optionally restricted to an exam or subject.
+func (s *spacedRepetitionService) GetReviewSession(ctx context.Context, userID string, size int, examID, subjectID string) (*models.ReviewSession, error) {
+	due, err := s.dueQuestions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := []models.DueQuestion{}
+	for _, q := range due {
+		if examID != "" && q.ExamID.String != examID && q.LinkedExamID.String != examID {
+			continue
+		}
+		if subjectID != "" && q.SubjectID.String != subjectID {
+			continue
+		}
+		questions = append(questions, q)
+	}
+
+	session := &models.ReviewSession{
+		TotalDue:    len(questions),
+		GeneratedAt: time.Now(),
+	}
+	if size > 0 && len(questions) > size {
+		questions = questions[:size]
+	}
+	session.Questions = questions
+	return session, nil
+}
+
+// SubmitReviewSession records every review in a session. All questions are checked for ownership
+// before any review is stored, and the reviews are stored together or not at all.
+func (s *spacedRepetitionService) SubmitReviewSession(ctx context.Context, userID string, input *models.ReviewSessionInput) (*models.ReviewSessionResult, error) {
+	// A question reviewed twice in one session is scheduled from its first review.
+	byID := make(map[string]*models.ImportantQuestion)
+	questions := make([]*models.ImportantQuestion, len(input.Reviews))
+	for i, review := range input.Reviews {
+		if question, ok := byID[review.QuestionID]; ok {
+			questions[i] = question
+			continue
+		}
+		question, err := s.importantQuestionRepo.GetImportantQuestionByID(ctx, review.QuestionID)
+		if err != nil {
+			return nil, fmt.Errorf("important question not found: %w", err)
+		}
+		if question.UserID != userID {
+			return nil, fmt.Errorf("important question does not belong to user")
+		}
+		byID[review.QuestionID] = question
+		questions[i] = question
+	}
+
+	exams, err := s.examRepo.GetUpcomingExamsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming exams: %w", err)
+	}
+	now := time.Now().In(userLocation(ctx, s.userRepo, userID))
+
+	reviews := make([]*models.QuestionReview, len(input.Reviews))
+	snapshots := make([]*models.ImportantQuestion, len(input.Reviews))
+	for i := range input.Reviews {
+		review, err := scheduleReview(questions[i], &input.Reviews[i], linkedExam(*questions[i], exams), now)
+		if err != nil {
+			return nil, err
+		}
+		reviews[i] = review
+		snapshot := *questions[i]
+		snapshots[i] = &snapshot
+	}
+	if err := s.questionReviewRepo.RecordQuestionReviews(ctx, reviews, snapshots); err != nil {
+		return nil, fmt.Errorf("failed to record reviews: %w", err)
+	}
+
+	result := &models.ReviewSessionResult{Reviews: []models.QuestionReview{}}
+	var qualitySum int32
+	for _, review := range reviews {
+		s.publishReview(ctx, review)
+		result.Reviews = append(result.Reviews, *review)
+		qualitySum += review.Quality
+	}
+	result.ReviewedCount = len(result.Reviews)
+	result.AverageQuality = roundTo(float64(qualitySum)/float64(result.ReviewedCount), 2)
+
+	remaining, err := s.importantQuestionRepo.GetDueImportantQuestionsByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due questions: %w", err)
+	}
+	result.RemainingDue = len(remaining)
+	return result, nil
+}
+
+// GetQuestionReviews returns the review history of one of the user's questions.
+func (s *spacedRepetitionService) GetQuestionReviews(ctx context.Context, userID, questionID string) ([]models.QuestionReview, error) {
+	question, err := s.importantQuestionRepo.GetImportantQuestionByID(ctx, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("important question not found: %w", err)
+	}
+	if question.UserID != userID {
+		return nil, fmt.Errorf("important question does not belong to user")
+	}
+	return s.questionReviewRepo.GetQuestionReviewsByQuestionID(ctx, questionID)
+}
+
+// recordReview applies SM-2 to a question, pulls the next review in before its linked exam and stores both.
+func (s *spacedRepetitionService) recordReview(ctx context.Context, question *models.ImportantQuestion, input *models.QuestionReviewInput, exam *models.Exam, now time.Time) (*models.QuestionReview, error) {
+	review, err := scheduleReview(question, input, exam, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.questionReviewRepo.RecordQuestionReviews(ctx, []*models.QuestionReview{review}, []*models.ImportantQuestion{question}); err != nil {
+		return nil, fmt.Errorf("failed to record review: %w", err)
+	}
+	s.publishReview(ctx, review)
+	return review, nil
+}
+
+// scheduleReview applies SM-2 to a question, moving it to its next schedule, and returns the review to store.
+func scheduleReview(question *models.ImportantQuestion, input *models.QuestionReviewInput, exam *models.Exam, now time.Time) (*models.QuestionReview, error) {
+	quality := *input.Quality
+	if quality < 0 || quality > 5 {
+		return nil, fmt.Errorf("quality must be between 0 and 5")
+	}
+
+	easeFactor, repetitions, interval := sm2(question.EaseFactor, int(question.RepetitionCount), int(question.IntervalDays), quality)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	nextReview := today.AddDate(0, 0, interval)
+	if exam != nil {
+		// Make sure the question comes up again at least once before the exam.
+		dayBefore := time.Date(exam.ExamDate.Year(), exam.ExamDate.Month(), exam.ExamDate.Day()-1, 0, 0, 0, 0, now.Location())
+		if nextReview.After(dayBefore) {
+			nextReview = maxTime(dayBefore, today.AddDate(0, 0, 1))
+		}
+	}
+
+	confidence := sql.NullInt32{Int32: int32(math.Max(1, float64(quality))), Valid: true}
+	if input.ConfidenceLevel != nil {
+		confidence.Int32 = int32(*input.ConfidenceLevel)
+	}
+
+	review := &models.QuestionReview{
+		UserID:          question.UserID,
+		QuestionID:      question.ID,
+		Quality:         int32(quality),
+		ConfidenceLevel: confidence,
+		EaseFactor:      easeFactor,
+		IntervalDays:    int32(interval),
+		NextReviewAt:    nextReview,
+		ReviewedAt:      now,
+	}
+	if input.TimeSpentSeconds != nil {
+		review.TimeSpentSeconds = sql.NullInt32{Int32: int32(*input.TimeSpentSeconds), Valid: true}
+	}
+
+	question.IsPracticed = true
+	question.LastPracticedAt = sql.NullTime{Time: now, Valid: true}
+	question.ConfidenceLevel = confidence
+	question.EaseFactor = easeFactor
+	question.RepetitionCount = int32(repetitions)
+	question.IntervalDays = int32(interval)
+	question.NextReviewAt = sql.NullTime{Time: nextReview, Valid: true}
+	return review, nil
+}
+
+// publishReview announces a stored review.
+func (s *spacedRepetitionService) publishReview(ctx context.Context, review *models.QuestionReview) {
+	s.eventBus.Publish(ctx, events.Event{
+		Type:        events.QuestionPractised,
+		UserID:      review.UserID,
+		EntityType:  "important_question",
+		EntityID:    review.QuestionID,
+		Description: "Practised an important question",
+		Metadata:    map[string]interface{}{"reviewId": review.ID, "quality": review.Quality, "nextReviewAt": review.NextReviewAt},
+	})
+}
+
+// dueQuestions loads the user's due questions and orders them: questions linked to the nearest upcoming
+// exam first, then the most overdue, then the hardest (lowest ease factor).
+func (s *spacedRepetitionService) dueQuestions(ctx context.Context, userID string) ([]models.DueQuestion, error) {
+	questions, err := s.importantQuestionRepo.GetDueImportantQuestionsByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due questions: %w", err)
+	}
+	exams, err := s.examRepo.GetUpcomingExamsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming exams: %w", err)
+	}
+	now := time.Now().In(userLocation(ctx, s.userRepo, userID))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	due := make([]models.DueQuestion, 0, len(questions))
+	for _, q := range questions {
+		item := models.DueQuestion{ImportantQuestion: q}
+		if q.NextReviewAt.Valid {
+			item.OverdueDays = int(now.Sub(q.NextReviewAt.Time).Hours() / 24)
+		}
+		if exam := linkedExam(q, exams); exam != nil {
+			examDay := time.Date(exam.ExamDate.Year(), exam.ExamDate.Month(), exam.ExamDate.Day(), 0, 0, 0, 0, time.UTC)
+			item.LinkedExamID = sql.NullString{String: exam.ID, Valid: true}
+			item.LinkedExamTitle = sql.NullString{String: exam.Title, Valid: true}
+			item.LinkedExamDate = sql.NullTime{Time: exam.ExamDate, Valid: true}
+			item.DaysUntilExam = sql.NullInt32{Int32: int32(examDay.Sub(today).Hours() / 24), Valid: true}
+		}
+		due = append(due, item)
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		a, b := due[i], due[j]
+		if a.DaysUntilExam.Valid != b.DaysUntilExam.Valid {
+			return a.DaysUntilExam.Valid
+		}
+		if a.DaysUntilExam.Valid && a.DaysUntilExam.Int32 != b.DaysUntilExam.Int32 {
+			return a.DaysUntilExam.Int32 < b.DaysUntilExam.Int32
+		}
+		if a.OverdueDays != b.OverdueDays {
+			return a.OverdueDays > b.OverdueDays
+		}
+		return a.EaseFactor < b.EaseFactor
+	})
+	return due, nil
+}
+
+// sm2 applies one SuperMemo-2 step for a recall quality between 0 and 5 and returns the new ease factor,
+// repetition count and interval in days.
+func sm2(easeFactor float64, repetitions, interval, quality int) (float64, int, int) {
+	if easeFactor == 0 {
+		easeFactor = defaultEaseFactor
+	}
+
+	if quality < 3 {
+		repetitions = 0
+		interval = 1
+	} else {
+		switch repetitions {
+		case 0:
+			interval = 1
+		case 1:
+			interval = 6
+		default:
+			interval = int(math.Round(float64(interval) * easeFactor))
+		}
+		repetitions++
+	}
+
+	q := float64(5 - quality)
+	easeFactor += 0.1 - q*(0.08+q*0.02)
+	if easeFactor < minEaseFactor {
+		easeFactor = minEaseFactor
+	}
+	return roundTo(easeFactor, 2), repetitions, interval
+}
+
+// linkedExam returns the upcoming exam a question prepares for: its own exam when that is still upcoming,
+// otherwise the nearest upcoming exam of its subject. exams must be sorted by date.
+func linkedExam(question models.ImportantQuestion, exams []models.Exam) *models.Exam {
+	if question.ExamID.Valid {
+		for i := range exams {
+			if exams[i].ID == question.ExamID.String {
+				return &exams[i]
+			}
+		}
+	}
+	if question.SubjectID.Valid {
+		for i := range exams {
+			if exams[i].SubjectID.Valid && exams[i].SubjectID.String == question.SubjectID.String {
+				return &exams[i]
+			}
+		}
+	}
+	return nil
+}
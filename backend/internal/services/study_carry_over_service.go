@@ -0,0 +1,456 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+// carryOverHorizonDays is how far ahead unfinished work without an exam, assignment or plan end is rescheduled.
+const carryOverHorizonDays = 7
+
+// StudyCarryOverService defines the interface for carrying over the unfinished work of skipped and partial sessions.
+type StudyCarryOverService interface {
+	CarryOverSessions(ctx context.Context, userID string, dryRun bool) (*models.StudyCarryOverReport, error)
+	CarryOverEndedDays(ctx context.Context) error
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+// studyCarryOverService implements StudyCarryOverService.
+type studyCarryOverService struct {
+	sessionRepo         repository.StudySessionRepository
+	studyPlanRepo       repository.StudyPlanRepository
+	examRepo            repository.ExamRepository
+	assignmentRepo      repository.AssignmentRepository
+	slotRepo            repository.TimetableSlotRepository
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+}
+
+// NewStudyCarryOverService creates a new study carry-over service.
+func NewStudyCarryOverService(
+	sessionRepo repository.StudySessionRepository,
+	studyPlanRepo repository.StudyPlanRepository,
+	examRepo repository.ExamRepository,
+	assignmentRepo repository.AssignmentRepository,
+	slotRepo repository.TimetableSlotRepository,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+) StudyCarryOverService {
+	return &studyCarryOverService{
+		sessionRepo:         sessionRepo,
+		studyPlanRepo:       studyPlanRepo,
+		examRepo:            examRepo,
+		assignmentRepo:      assignmentRepo,
+		slotRepo:            slotRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// carryOverRun holds what a carry-over run for one user has loaded and scheduled so far.
+type carryOverRun struct {
+	userID    string
+	loc       *time.Location
+	now       time.Time
+	today     time.Time
+	dryRun    bool
+	busySlots map[time.Weekday][]models.TimetableSlot
+	exams     []models.Exam
+	sessions  []models.StudySession // Grows with the sessions created during the run
+
+	plans       map[string]*models.StudyPlan
+	examsByID   map[string]*models.Exam
+	assignments map[string]*models.Assignment
+}
+
+// CarryOverSessions carries over the unfinished work of all of a user's skipped and partial sessions
+// that have started, including today's. With dryRun the outcome is reported without saving anything.
+func (s *studyCarryOverService) CarryOverSessions(ctx context.Context, userID string, dryRun bool) (*models.StudyCarryOverReport, error) {
+	candidates, err := s.sessionRepo.GetCarryOverSessionsByUserID(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.carryOver(ctx, userID, candidates, dryRun)
+}
+
+// CarryOverEndedDays carries over skipped and partial sessions of every user whose day, in the user's
+// timezone, has ended.
+func (s *studyCarryOverService) CarryOverEndedDays(ctx context.Context) error {
+	candidates, err := s.sessionRepo.GetCarryOverSessions(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	byUser := make(map[string][]models.StudySession)
+	var userIDs []string
+	for _, session := range candidates {
+		if _, ok := byUser[session.UserID]; !ok {
+			userIDs = append(userIDs, session.UserID)
+		}
+		byUser[session.UserID] = append(byUser[session.UserID], session)
+	}
+
+	for _, userID := range userIDs {
+		loc := userLocation(ctx, s.userRepo, userID)
+		now := time.Now().In(loc)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+		var ended []models.StudySession
+		for _, session := range byUser[userID] {
+			if session.PlannedStartTime.Time.In(loc).Before(today) {
+				ended = append(ended, session)
+			}
+		}
+		if len(ended) == 0 {
+			continue
+		}
+		if _, err := s.carryOver(ctx, userID, ended, false); err != nil {
+			log.Printf("Warning: Could not carry over study sessions for user %s: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// RunScheduler carries over the sessions of ended days right away and then every interval, until the
+// context is cancelled. Running hourly lets each user's day end in their own timezone.
+func (s *studyCarryOverService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.CarryOverEndedDays(ctx); err != nil {
+			log.Printf("Warning: Study session carry-over failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// carryOver moves the unfinished work of the given sessions, oldest first, into free time before each
+// session's deadline. Topics that no longer fit are handed to later sessions for the same exam,
+// assignment or subject, and the plan is reported infeasible, with a notification, when time runs out.
+func (s *studyCarryOverService) carryOver(ctx context.Context, userID string, candidates []models.StudySession, dryRun bool) (*models.StudyCarryOverReport, error) {
+	report := &models.StudyCarryOverReport{
+		DryRun:     dryRun,
+		CarryOvers: []models.StudySessionCarryOver{},
+		Feasible:   true,
+		Warnings:   []string{},
+	}
+	if len(candidates) == 0 {
+		return report, nil
+	}
+
+	run := &carryOverRun{
+		userID:      userID,
+		loc:         userLocation(ctx, s.userRepo, userID),
+		dryRun:      dryRun,
+		plans:       make(map[string]*models.StudyPlan),
+		examsByID:   make(map[string]*models.Exam),
+		assignments: make(map[string]*models.Assignment),
+	}
+	run.now = time.Now().In(run.loc)
+	run.today = time.Date(run.now.Year(), run.now.Month(), run.now.Day(), 0, 0, 0, 0, run.loc)
+
+	var err error
+	if run.busySlots, err = weeklyBusySlots(ctx, s.slotRepo, userID); err != nil {
+		return nil, err
+	}
+	if run.exams, err = s.examRepo.GetUpcomingExamsByUserID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to get upcoming exams: %w", err)
+	}
+	if run.sessions, err = s.sessionRepo.GetStudySessionsByUserID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to get study sessions: %w", err)
+	}
+
+	for i := range candidates {
+		carryOver, err := s.carryOverSession(ctx, run, &candidates[i])
+		if err != nil {
+			return nil, err
+		}
+		report.Processed++
+		report.CarryOvers = append(report.CarryOvers, *carryOver)
+		if !carryOver.Feasible {
+			report.Feasible = false
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%d minutes of unfinished work from the session on %s no longer fit before %s",
+				carryOver.UnplacedMinutes, candidates[i].PlannedStartTime.Time.In(run.loc).Format("Mon Jan 2"), carryOver.Deadline.Time.In(run.loc).Format("Mon Jan 2")))
+		}
+	}
+	return report, nil
+}
+
+// carryOverSession reschedules the unfinished work of a single skipped or partial session.
+func (s *studyCarryOverService) carryOverSession(ctx context.Context, run *carryOverRun, source *models.StudySession) (*models.StudySessionCarryOver, error) {
+	topics := uncoveredTopics(source)
+	minutes := unfinishedMinutes(source, len(topics))
+	carryOver := &models.StudySessionCarryOver{
+		SourceSessionID:      source.ID,
+		SourceStatus:         source.Status,
+		Topics:               topics,
+		Minutes:              minutes,
+		NewSessions:          []models.StudySession{},
+		RebalancedSessionIDs: []string{},
+		UnplacedTopics:       []string{},
+		Feasible:             true,
+	}
+	if minutes == 0 {
+		// A partial session whose topics were all covered leaves nothing to carry over.
+		return carryOver, s.saveCarryOver(ctx, run, source, nil, nil)
+	}
+
+	settings := defaultPlanSettings(run.loc)
+	var plan *models.StudyPlan
+	if source.StudyPlanID.Valid {
+		plan = s.loadPlan(ctx, run, source.StudyPlanID.String)
+		if plan != nil && plan.GenerationSettings != nil {
+			settings = plan.GenerationSettings
+		}
+	}
+	deadline := s.carryOverDeadline(ctx, run, source, plan)
+	carryOver.Deadline = sql.NullTime{Time: deadline, Valid: true}
+
+	label := "Carried over from " + source.PlannedStartTime.Time.In(run.loc).Format("Mon Jan 2")
+	if source.Notes.Valid && source.Notes.String != "" {
+		label += ": " + source.Notes.String
+	}
+	task := &planTask{
+		sessionType:  source.SessionType,
+		label:        label,
+		subjectID:    source.SubjectID,
+		examID:       source.ExamID,
+		assignmentID: source.AssignmentID,
+		earliest:     run.today,
+		deadline:     deadline,
+		total:        minutes,
+		minutes:      minutes,
+		topics:       topics,
+	}
+
+	var newSessions []models.StudySession
+	if deadline.After(run.now) {
+		lastDay := deadline.Add(-time.Minute)
+		lastDay = minTime(time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), 0, 0, 0, 0, run.loc), run.today.AddDate(0, 0, freeTimeHorizonDays-1))
+		days := buildPlanDays(settings, run.today, lastDay, run.now, run.busySlots, run.exams, run.sessions, run.loc)
+		scratch := &models.StudyPlanGenerationResult{Items: make([]models.StudyPlanGenerationItem, 1)}
+		newSessions = scheduleTasks(source.UserID, []*planTask{task}, days, settings, scratch)
+	}
+	for i := range newSessions {
+		newSessions[i].StudyPlanID = source.StudyPlanID
+		newSessions[i].CarriedOverFrom = sql.NullString{String: source.ID, Valid: true}
+	}
+
+	var rebalanced []models.StudySession
+	if task.minutes > 0 {
+		carryOver.Feasible = false
+		carryOver.UnplacedMinutes = task.minutes
+		carryOver.UnplacedTopics = task.sessionTopics(task.minutes)
+		rebalanced = s.rebalanceLaterSessions(run, source, deadline, carryOver.UnplacedTopics)
+		if len(rebalanced) > 0 {
+			for _, session := range rebalanced {
+				carryOver.RebalancedSessionIDs = append(carryOver.RebalancedSessionIDs, session.ID)
+			}
+			carryOver.UnplacedTopics = []string{}
+		}
+	}
+
+	if err := s.saveCarryOver(ctx, run, source, newSessions, rebalanced); err != nil {
+		return nil, err
+	}
+	run.sessions = append(run.sessions, newSessions...)
+	carryOver.NewSessions = append(carryOver.NewSessions, newSessions...)
+
+	if !carryOver.Feasible && !run.dryRun {
+		title := "Study plan no longer fits before the deadline"
+		message := fmt.Sprintf("%d minutes of unfinished work from %s could not be rescheduled before %s.",
+			carryOver.UnplacedMinutes, source.PlannedStartTime.Time.In(run.loc).Format("Mon Jan 2"), deadline.Format("Mon Jan 2"))
+		if len(carryOver.RebalancedSessionIDs) > 0 {
+			message += fmt.Sprintf(" Its topics were added to %d later sessions.", len(carryOver.RebalancedSessionIDs))
+		}
+		if _, err := s.notificationService.Notify(ctx, source.UserID, "study_plan_infeasible", title, message, "study_session", source.ID, "carry_over:"+source.ID); err != nil {
+			log.Printf("Warning: Could not notify user %s about an infeasible study plan: %v", source.UserID, err)
+		}
+	}
+	return carryOver, nil
+}
+
+// saveCarryOver persists a carry-over unless the run is a dry run.
+func (s *studyCarryOverService) saveCarryOver(ctx context.Context, run *carryOverRun, source *models.StudySession, newSessions, rebalanced []models.StudySession) error {
+	if run.dryRun {
+		return nil
+	}
+	if err := s.sessionRepo.CarryOverStudySession(ctx, source, newSessions, rebalanced); err != nil {
+		return fmt.Errorf("failed to carry over study session: %w", err)
+	}
+	return nil
+}
+
+// carryOverDeadline returns when a session's unfinished work must be done by: the exam day, the
+// assignment's due date, the day after the plan ends, or carryOverHorizonDays from today.
+func (s *studyCarryOverService) carryOverDeadline(ctx context.Context, run *carryOverRun, source *models.StudySession, plan *models.StudyPlan) time.Time {
+	if source.ExamID.Valid {
+		exam, ok := run.examsByID[source.ExamID.String]
+		if !ok {
+			loaded, err := s.examRepo.GetExamByID(ctx, source.ExamID.String)
+			if err != nil {
+				log.Printf("Warning: Could not load exam %s for study session carry-over: %v", source.ExamID.String, err)
+			}
+			exam = loaded
+			run.examsByID[source.ExamID.String] = exam
+		}
+		if exam != nil {
+			return localExamDay(*exam, run.loc)
+		}
+	}
+	if source.AssignmentID.Valid {
+		assignment, ok := run.assignments[source.AssignmentID.String]
+		if !ok {
+			loaded, err := s.assignmentRepo.GetAssignmentByID(ctx, source.AssignmentID.String)
+			if err != nil {
+				log.Printf("Warning: Could not load assignment %s for study session carry-over: %v", source.AssignmentID.String, err)
+			}
+			assignment = loaded
+			run.assignments[source.AssignmentID.String] = assignment
+		}
+		if assignment != nil {
+			return assignment.DueDate.In(run.loc)
+		}
+	}
+	if plan != nil && plan.EndDate.Valid {
+		end := plan.EndDate.Time
+		return time.Date(end.Year(), end.Month(), end.Day()+1, 0, 0, 0, 0, run.loc)
+	}
+	return run.today.AddDate(0, 0, carryOverHorizonDays)
+}
+
+// loadPlan returns a study plan from the run's cache, loading it on first use.
+func (s *studyCarryOverService) loadPlan(ctx context.Context, run *carryOverRun, planID string) *models.StudyPlan {
+	if plan, ok := run.plans[planID]; ok {
+		return plan
+	}
+	plan, err := s.studyPlanRepo.GetStudyPlanByID(ctx, planID)
+	if err != nil {
+		log.Printf("Warning: Could not load study plan %s for study session carry-over: %v", planID, err)
+		plan = nil
+	}
+	run.plans[planID] = plan
+	return plan
+}
+
+// rebalanceLaterSessions spreads topics that did not fit over the later planned sessions working
+// towards the same exam, assignment or subject before the deadline, and returns the sessions it changed.
+func (s *studyCarryOverService) rebalanceLaterSessions(run *carryOverRun, source *models.StudySession, deadline time.Time, topics []string) []models.StudySession {
+	if len(topics) == 0 {
+		return nil
+	}
+	var later []int
+	for i, session := range run.sessions {
+		if session.ID == "" || session.ID == source.ID || session.Status != "planned" || !session.PlannedStartTime.Valid {
+			continue
+		}
+		start := session.PlannedStartTime.Time
+		if !start.After(run.now) || !start.Before(deadline) || !sameStudyTarget(source, &session) {
+			continue
+		}
+		later = append(later, i)
+	}
+	if len(later) == 0 {
+		return nil
+	}
+
+	// Topics a later session already plans to cover need no new home.
+	var missing []string
+	for _, topic := range topics {
+		planned := false
+		for _, i := range later {
+			if containsTopic(run.sessions[i].TopicsToCover, topic) {
+				planned = true
+				break
+			}
+		}
+		if !planned {
+			missing = append(missing, topic)
+		}
+	}
+
+	changed := make(map[int]bool)
+	for j, topic := range missing {
+		i := later[j%len(later)]
+		run.sessions[i].TopicsToCover = append(append([]string{}, run.sessions[i].TopicsToCover...), topic)
+		changed[i] = true
+	}
+
+	var rebalanced []models.StudySession
+	for _, i := range later {
+		if changed[i] {
+			rebalanced = append(rebalanced, run.sessions[i])
+		}
+	}
+	return rebalanced
+}
+
+// sameStudyTarget reports whether two sessions work towards the same exam, assignment or, when
+// neither is linked, the same subject.
+func sameStudyTarget(a, b *models.StudySession) bool {
+	switch {
+	case a.ExamID.Valid:
+		return b.ExamID.Valid && b.ExamID.String == a.ExamID.String
+	case a.AssignmentID.Valid:
+		return b.AssignmentID.Valid && b.AssignmentID.String == a.AssignmentID.String
+	case a.SubjectID.Valid:
+		return !b.ExamID.Valid && !b.AssignmentID.Valid && b.SubjectID.Valid && b.SubjectID.String == a.SubjectID.String
+	}
+	return false
+}
+
+// uncoveredTopics returns the topics a session set out to cover that are not among its covered topics.
+func uncoveredTopics(session *models.StudySession) []string {
+	uncovered := []string{}
+	for _, topic := range session.TopicsToCover {
+		if !containsTopic(session.TopicsCovered, topic) {
+			uncovered = append(uncovered, topic)
+		}
+	}
+	return uncovered
+}
+
+// containsTopic reports whether a topic matches any of the given topics after normalisation.
+func containsTopic(topics []string, topic string) bool {
+	key := normalizeTopic(topic)
+	for _, t := range topics {
+		if topicsMatch(key, normalizeTopic(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// unfinishedMinutes estimates the minutes of a skipped or partial session still to be done. A partial
+// session with every topic covered has nothing left; one with topics left gets at least a short session.
+func unfinishedMinutes(session *models.StudySession, uncoveredCount int) int {
+	duration := defaultSessionMinutes
+	switch {
+	case session.PlannedDurationMinutes.Valid && session.PlannedDurationMinutes.Int32 > 0:
+		duration = int(session.PlannedDurationMinutes.Int32)
+	case session.PlannedStartTime.Valid && session.PlannedEndTime.Valid && session.PlannedEndTime.Time.After(session.PlannedStartTime.Time):
+		duration = int(session.PlannedEndTime.Time.Sub(session.PlannedStartTime.Time).Minutes())
+	}
+	if session.Status == "skipped" {
+		return duration
+	}
+
+	if len(session.TopicsToCover) > 0 && uncoveredCount == 0 {
+		return 0
+	}
+	remaining := duration * (100 - int(session.CompletionPercentage)) / 100
+	if uncoveredCount > 0 {
+		remaining = max(remaining, minSessionMinutes)
+	}
+	return remaining
+}
@@ -0,0 +1,651 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidPlanSettings is returned when study plan generation settings are inconsistent.
+	ErrInvalidPlanSettings = errors.New("invalid study plan settings")
+	// ErrNothingToPlan is returned when there are no upcoming exams or pending assignments to plan for.
+	ErrNothingToPlan = errors.New("no upcoming exams or pending assignments to plan for")
+	// ErrNoFreeTime is returned when no study session fits into the free time of the plan range.
+	ErrNoFreeTime = errors.New("no free time left for study sessions in the plan range")
+)
+
+const (
+	defaultDailyStudyMinutes = 240
+	defaultSessionMinutes    = 50
+	defaultBreakMinutes      = 10
+	defaultRevisionDays      = 1
+	// revisionShare is the part of an exam's preparation time set aside for revision passes.
+	revisionShare = 0.3
+	// minSessionMinutes is the shortest session placed into a gap, unless less work than that is left.
+	minSessionMinutes = 20
+)
+
+// StudyPlanGeneratorService defines the interface for generating study plans automatically.
+type StudyPlanGeneratorService interface {
+	GenerateStudyPlan(ctx context.Context, userID string, input *models.StudyPlanGenerationInput) (*models.StudyPlanGenerationResult, error)
+}
+
+// studyPlanGeneratorService implements StudyPlanGeneratorService.
+type studyPlanGeneratorService struct {
+	studyPlanRepo   repository.StudyPlanRepository
+	sessionRepo     repository.StudySessionRepository
+	examRepo        repository.ExamRepository
+	assignmentRepo  repository.AssignmentRepository
+	slotRepo        repository.TimetableSlotRepository
+	userRepo        repository.UserRepository
+	coverageService CoverageService
+	eventBus        events.Bus
+}
+
+// NewStudyPlanGeneratorService creates a new study plan generator service.
+func NewStudyPlanGeneratorService(
+	studyPlanRepo repository.StudyPlanRepository,
+	sessionRepo repository.StudySessionRepository,
+	examRepo repository.ExamRepository,
+	assignmentRepo repository.AssignmentRepository,
+	slotRepo repository.TimetableSlotRepository,
+	userRepo repository.UserRepository,
+	coverageService CoverageService,
+	eventBus events.Bus,
+) StudyPlanGeneratorService {
+	return &studyPlanGeneratorService{
+		studyPlanRepo:   studyPlanRepo,
+		sessionRepo:     sessionRepo,
+		examRepo:        examRepo,
+		assignmentRepo:  assignmentRepo,
+		slotRepo:        slotRepo,
+		userRepo:        userRepo,
+		coverageService: coverageService,
+		eventBus:        eventBus,
+	}
+}
+
+// planTask is a block of work for one exam or assignment that the generator places into free time.
+type planTask struct {
+	item         int // Index into the result items
+	sessionType  string
+	label        string
+	subjectID    sql.NullString
+	examID       sql.NullString
+	assignmentID sql.NullString
+
+	earliest time.Time
+	deadline time.Time
+	total    int // Minutes of work in the task
+	minutes  int // Minutes still to schedule
+
+	topics []string // Covered in order, spread evenly over the task's minutes
+}
+
+// freeWindow is a stretch of free time within one day.
+type freeWindow struct {
+	start time.Time
+	end   time.Time
+}
+
+// planDay is the free time of one day together with the minutes already planned on it.
+type planDay struct {
+	windows        []freeWindow
+	plannedMinutes int
+}
+
+// GenerateStudyPlan builds a multi-day study plan from upcoming exams, pending assignments and the
+// free time around the user's timetable. Exams get preparation sessions for untouched syllabus topics
+// followed by revision passes in the days before the exam; assignments get sessions for their
+// remaining estimated hours. Work is placed earliest deadline first within the preferred hours and
+// daily limit, and whatever does not fit is reported per exam or assignment.
+func (s *studyPlanGeneratorService) GenerateStudyPlan(ctx context.Context, userID string, input *models.StudyPlanGenerationInput) (*models.StudyPlanGenerationResult, error) {
+	loc := userLocation(ctx, s.userRepo, userID)
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	settings, startDay, err := resolvePlanSettings(input, today, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	upcomingExams, err := s.examRepo.GetUpcomingExamsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upcoming exams: %w", err)
+	}
+	exams, err := s.selectExams(ctx, userID, input.ExamIDs, upcomingExams, startDay, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments []models.Assignment
+	if settings.IncludeAssignments {
+		all, err := s.assignmentRepo.GetAssignmentsByUserID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get assignments: %w", err)
+		}
+		for _, a := range all {
+			if isOpenAssignmentStatus(a.Status) && a.DueDate.After(now) {
+				assignments = append(assignments, a)
+			}
+		}
+	}
+	if len(exams) == 0 && len(assignments) == 0 {
+		return nil, ErrNothingToPlan
+	}
+
+	endDay, err := resolvePlanEndDay(input.EndDate, startDay, exams, assignments, loc)
+	if err != nil {
+		return nil, err
+	}
+	settings.EndDate = endDay.Format("2006-01-02")
+
+	result := &models.StudyPlanGenerationResult{
+		Sessions: []models.StudySession{},
+		Items:    []models.StudyPlanGenerationItem{},
+		DryRun:   input.DryRun,
+		Warnings: []string{},
+	}
+
+	var tasks []*planTask
+	for _, e := range exams {
+		tasks = append(tasks, s.examTasks(ctx, userID, e, settings, startDay, loc, result)...)
+	}
+	for _, a := range assignments {
+		tasks = append(tasks, assignmentTasks(a, loc, result)...)
+	}
+	if len(tasks) == 0 {
+		return nil, ErrNothingToPlan
+	}
+
+	windows, err := s.freeWindows(ctx, userID, settings, startDay, endDay, now, upcomingExams, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Sessions = scheduleTasks(userID, tasks, windows, settings, result)
+	if len(result.Sessions) == 0 {
+		return nil, ErrNoFreeTime
+	}
+
+	for i := range result.Items {
+		item := &result.Items[i]
+		item.UnscheduledMinutes = item.RequiredMinutes - item.ScheduledMinutes
+		if item.UnscheduledMinutes > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Could not fit %d of %d minutes for %s %q before its deadline",
+				item.UnscheduledMinutes, item.RequiredMinutes, item.Kind, item.Title))
+		}
+	}
+
+	plan := &models.StudyPlan{
+		UserID:             userID,
+		Title:              fmt.Sprintf("Study plan %s - %s", startDay.Format("Jan 2"), endDay.Format("Jan 2")),
+		PlanDate:           time.Date(startDay.Year(), startDay.Month(), startDay.Day(), 0, 0, 0, 0, time.UTC),
+		EndDate:            sql.NullTime{Time: time.Date(endDay.Year(), endDay.Month(), endDay.Day(), 0, 0, 0, 0, time.UTC), Valid: true},
+		PlanType:           "custom",
+		Status:             "planned",
+		Notes:              sql.NullString{String: fmt.Sprintf("Generated for %d exams and %d assignments", len(exams), len(assignments)), Valid: true},
+		GenerationSettings: settings,
+	}
+	if input.Title != nil && strings.TrimSpace(*input.Title) != "" {
+		plan.Title = strings.TrimSpace(*input.Title)
+	}
+	if len(exams) > 0 {
+		plan.PlanType = "exam_prep"
+	}
+
+	for _, session := range result.Sessions {
+		result.TotalScheduledMinutes += int(session.PlannedDurationMinutes.Int32)
+	}
+	result.Plan = plan
+
+	if input.DryRun {
+		return result, nil
+	}
+	if err := s.studyPlanRepo.CreateStudyPlanWithSessions(ctx, plan, result.Sessions); err != nil {
+		return nil, fmt.Errorf("failed to create study plan: %w", err)
+	}
+	publishStudyPlanCreated(ctx, s.eventBus, plan, len(result.Sessions), "generated")
+	return result, nil
+}
+
+// defaultPlanSettings returns the settings used when generating or rescheduling without explicit ones.
+func defaultPlanSettings(loc *time.Location) *models.StudyPlanGenerationSettings {
+	return &models.StudyPlanGenerationSettings{
+		Timezone:           loc.String(),
+		DailyLimitMinutes:  defaultDailyStudyMinutes,
+		PreferredStartHour: studyDayStartHour,
+		PreferredEndHour:   studyDayEndHour,
+		SessionMinutes:     defaultSessionMinutes,
+		BreakMinutes:       defaultBreakMinutes,
+		RevisionDays:       defaultRevisionDays,
+		IncludeAssignments: true,
+	}
+}
+
+// resolvePlanSettings applies defaults to the generation input and validates it.
+func resolvePlanSettings(input *models.StudyPlanGenerationInput, today time.Time, loc *time.Location) (*models.StudyPlanGenerationSettings, time.Time, error) {
+	settings := defaultPlanSettings(loc)
+	settings.ExamIDs = input.ExamIDs
+	if input.DailyLimitMinutes != nil {
+		settings.DailyLimitMinutes = *input.DailyLimitMinutes
+	}
+	if input.PreferredStartHour != nil {
+		settings.PreferredStartHour = *input.PreferredStartHour
+	}
+	if input.PreferredEndHour != nil {
+		settings.PreferredEndHour = *input.PreferredEndHour
+	}
+	if input.SessionMinutes != nil {
+		settings.SessionMinutes = *input.SessionMinutes
+	}
+	if input.BreakMinutes != nil {
+		settings.BreakMinutes = *input.BreakMinutes
+	}
+	if input.RevisionDays != nil {
+		settings.RevisionDays = *input.RevisionDays
+	}
+	if input.IncludeAssignments != nil {
+		settings.IncludeAssignments = *input.IncludeAssignments
+	}
+
+	if settings.PreferredEndHour <= settings.PreferredStartHour {
+		return nil, time.Time{}, fmt.Errorf("%w: preferred end hour must be after the preferred start hour", ErrInvalidPlanSettings)
+	}
+	if settings.SessionMinutes > settings.DailyLimitMinutes {
+		return nil, time.Time{}, fmt.Errorf("%w: session length cannot exceed the daily study limit", ErrInvalidPlanSettings)
+	}
+
+	startDay := today
+	if input.StartDate != nil && *input.StartDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *input.StartDate, loc)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("%w: invalid start date %q", ErrInvalidPlanSettings, *input.StartDate)
+		}
+		if parsed.Before(today) {
+			return nil, time.Time{}, fmt.Errorf("%w: start date cannot be in the past", ErrInvalidPlanSettings)
+		}
+		startDay = parsed
+	}
+	settings.StartDate = startDay.Format("2006-01-02")
+	return settings, startDay, nil
+}
+
+// resolvePlanEndDay returns the last day of the plan: the requested end date, or the last day work
+// can still be done for the latest exam or assignment, capped at freeTimeHorizonDays.
+func resolvePlanEndDay(endDate *string, startDay time.Time, exams []models.Exam, assignments []models.Assignment, loc *time.Location) (time.Time, error) {
+	lastDay := startDay.AddDate(0, 0, freeTimeHorizonDays-1)
+	if endDate != nil && *endDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *endDate, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: invalid end date %q", ErrInvalidPlanSettings, *endDate)
+		}
+		if parsed.Before(startDay) {
+			return time.Time{}, fmt.Errorf("%w: end date cannot be before the start date", ErrInvalidPlanSettings)
+		}
+		if parsed.After(lastDay) {
+			return time.Time{}, fmt.Errorf("%w: a plan can span at most %d days", ErrInvalidPlanSettings, freeTimeHorizonDays)
+		}
+		return parsed, nil
+	}
+
+	endDay := startDay
+	for _, e := range exams {
+		endDay = maxTime(endDay, localExamDay(e, loc).AddDate(0, 0, -1))
+	}
+	for _, a := range assignments {
+		due := a.DueDate.In(loc)
+		endDay = maxTime(endDay, time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, loc))
+	}
+	return minTime(endDay, lastDay), nil
+}
+
+// selectExams returns the exams to plan for: the requested ones, or every upcoming exam from the start day on.
+func (s *studyPlanGeneratorService) selectExams(ctx context.Context, userID string, examIDs []string, upcoming []models.Exam, startDay time.Time, loc *time.Location) ([]models.Exam, error) {
+	var exams []models.Exam
+	if len(examIDs) == 0 {
+		for _, e := range upcoming {
+			if !localExamDay(e, loc).Before(startDay) {
+				exams = append(exams, e)
+			}
+		}
+		return exams, nil
+	}
+
+	for _, id := range examIDs {
+		exam, err := s.examRepo.GetExamByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("exam not found: %w", err)
+		}
+		if exam.UserID != userID {
+			return nil, fmt.Errorf("exam does not belong to user")
+		}
+		if localExamDay(*exam, loc).Before(startDay) {
+			return nil, fmt.Errorf("%w: exam %q takes place before the plan starts", ErrInvalidPlanSettings, exam.Title)
+		}
+		exams = append(exams, *exam)
+	}
+	return exams, nil
+}
+
+// examTasks splits an exam's remaining preparation time into preparation work for untouched topics
+// and revision passes in the days before the exam, and records the exam in the result items.
+func (s *studyPlanGeneratorService) examTasks(ctx context.Context, userID string, e models.Exam, settings *models.StudyPlanGenerationSettings, startDay time.Time, loc *time.Location, result *models.StudyPlanGenerationResult) []*planTask {
+	examDay := localExamDay(e, loc)
+
+	learnTopics := []string(e.SyllabusTopics)
+	reviseTopics := []string(e.SyllabusTopics)
+	allCovered := false
+	coverage, err := s.coverageService.GetExamCoverage(ctx, userID, e.ID)
+	if err != nil {
+		log.Printf("Warning: Could not compute syllabus coverage for exam %s: %v", e.ID, err)
+	} else {
+		learnTopics = coverage.UntouchedTopics
+		reviseTopics = append(append([]string{}, coverage.UntouchedTopics...), coverage.CoveredTopics...)
+		if len(reviseTopics) == 0 {
+			reviseTopics = coverage.RevisedTopics
+		}
+		allCovered = coverage.TotalTopics > 0 && len(coverage.UntouchedTopics) == 0
+	}
+	if len(reviseTopics) == 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Exam %q has no syllabus topics; sessions are planned without topics", e.Title))
+	}
+
+	required := int(math.Round(remainingExamPrepHours(e) * 60))
+	learning, revision := required, 0
+	if settings.RevisionDays > 0 {
+		revision = int(math.Max(math.Round(float64(required)*revisionShare), float64(settings.SessionMinutes)))
+		learning = max(required-revision, 0)
+		if allCovered {
+			revision += learning
+			learning = 0
+		}
+	}
+
+	item := models.StudyPlanGenerationItem{
+		Kind:            "exam",
+		ID:              e.ID,
+		Title:           e.Title,
+		Deadline:        examDay,
+		RequiredMinutes: learning + revision,
+	}
+	if start, _, ok := examTimeRange(e); ok {
+		item.Deadline = time.Date(examDay.Year(), examDay.Month(), examDay.Day(), start.Hour(), start.Minute(), 0, 0, loc)
+	}
+	result.Items = append(result.Items, item)
+	index := len(result.Items) - 1
+
+	revisionStart := maxTime(examDay.AddDate(0, 0, -settings.RevisionDays), startDay)
+	learningDeadline := revisionStart
+	if !revisionStart.After(startDay) {
+		learningDeadline = examDay
+	}
+
+	var tasks []*planTask
+	if learning > 0 {
+		tasks = append(tasks, &planTask{
+			item:        index,
+			sessionType: "exam_prep",
+			label:       "Prepare for " + e.Title,
+			subjectID:   e.SubjectID,
+			examID:      sql.NullString{String: e.ID, Valid: true},
+			earliest:    startDay,
+			deadline:    learningDeadline,
+			total:       learning,
+			minutes:     learning,
+			topics:      learnTopics,
+		})
+	}
+	if revision > 0 {
+		tasks = append(tasks, &planTask{
+			item:        index,
+			sessionType: "revision",
+			label:       "Revise for " + e.Title,
+			subjectID:   e.SubjectID,
+			examID:      sql.NullString{String: e.ID, Valid: true},
+			earliest:    revisionStart,
+			deadline:    examDay,
+			total:       revision,
+			minutes:     revision,
+			topics:      reviseTopics,
+		})
+	}
+	return tasks
+}
+
+// assignmentTasks turns an assignment's remaining estimated hours into work due by its deadline.
+func assignmentTasks(a models.Assignment, loc *time.Location, result *models.StudyPlanGenerationResult) []*planTask {
+	if !a.EstimatedHours.Valid {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Assignment %q has no estimated hours and was not planned", a.Title))
+		return nil
+	}
+	remaining := a.EstimatedHours.Float64
+	if a.ActualHours.Valid {
+		remaining -= a.ActualHours.Float64
+	}
+	minutes := int(math.Round(remaining * 60))
+	if minutes <= 0 {
+		return nil
+	}
+
+	result.Items = append(result.Items, models.StudyPlanGenerationItem{
+		Kind:            "assignment",
+		ID:              a.ID,
+		Title:           a.Title,
+		Deadline:        a.DueDate.In(loc),
+		RequiredMinutes: minutes,
+	})
+	return []*planTask{{
+		item:         len(result.Items) - 1,
+		sessionType:  "assignment",
+		label:        "Work on " + a.Title,
+		subjectID:    a.SubjectID,
+		assignmentID: sql.NullString{String: a.ID, Valid: true},
+		deadline:     a.DueDate.In(loc),
+		total:        minutes,
+		minutes:      minutes,
+	}}
+}
+
+// freeWindows loads the user's timetable and planned sessions and returns the free time on each day of the plan.
+func (s *studyPlanGeneratorService) freeWindows(ctx context.Context, userID string, settings *models.StudyPlanGenerationSettings, startDay, endDay, now time.Time, exams []models.Exam, loc *time.Location) ([]planDay, error) {
+	busySlots, err := weeklyBusySlots(ctx, s.slotRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.sessionRepo.GetStudySessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get study sessions: %w", err)
+	}
+	return buildPlanDays(settings, startDay, endDay, now, busySlots, exams, sessions, loc), nil
+}
+
+// buildPlanDays returns the free time on each day from startDay to endDay: the preferred hours minus
+// timetable slots, exams and sessions that are already planned, whose minutes count towards the daily limit.
+func buildPlanDays(settings *models.StudyPlanGenerationSettings, startDay, endDay, now time.Time, busySlots map[time.Weekday][]models.TimetableSlot, exams []models.Exam, sessions []models.StudySession, loc *time.Location) []planDay {
+	var busy []freeWindow
+	for _, e := range exams {
+		if start, end, ok := examTimeRange(e); ok {
+			busy = append(busy, freeWindow{
+				start: time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), start.Minute(), 0, 0, loc),
+				end:   time.Date(end.Year(), end.Month(), end.Day(), end.Hour(), end.Minute(), 0, 0, loc),
+			})
+		}
+	}
+	var planned []freeWindow
+	for _, session := range sessions {
+		if (session.Status == "planned" || session.Status == "in_progress") && session.PlannedStartTime.Valid && session.PlannedEndTime.Valid {
+			planned = append(planned, freeWindow{start: session.PlannedStartTime.Time.In(loc), end: session.PlannedEndTime.Time.In(loc)})
+		}
+	}
+
+	// Sessions starting today begin at the next quarter hour.
+	earliest := now.Truncate(15 * time.Minute).Add(15 * time.Minute)
+
+	var days []planDay
+	for day := startDay; !day.After(endDay); day = day.AddDate(0, 0, 1) {
+		nextDay := day.AddDate(0, 0, 1)
+		dayBusy := append(append([]freeWindow{}, busy...), planned...)
+		for _, slot := range busySlots[day.Weekday()] {
+			dayBusy = append(dayBusy, freeWindow{
+				start: time.Date(day.Year(), day.Month(), day.Day(), slot.StartTime.Hour(), slot.StartTime.Minute(), 0, 0, loc),
+				end:   time.Date(day.Year(), day.Month(), day.Day(), slot.EndTime.Hour(), slot.EndTime.Minute(), 0, 0, loc),
+			})
+		}
+
+		plannedMinutes := 0
+		for _, p := range planned {
+			if p.start.Before(nextDay) && !p.start.Before(day) {
+				plannedMinutes += int(p.end.Sub(p.start).Minutes())
+			}
+		}
+
+		window := freeWindow{
+			start: maxTime(day.Add(time.Duration(settings.PreferredStartHour)*time.Hour), earliest),
+			end:   day.Add(time.Duration(settings.PreferredEndHour) * time.Hour),
+		}
+		days = append(days, planDay{windows: subtractBusy(window, dayBusy), plannedMinutes: plannedMinutes})
+	}
+	return days
+}
+
+// subtractBusy removes the busy intervals from a window and returns the free parts in order.
+func subtractBusy(window freeWindow, busy []freeWindow) []freeWindow {
+	free := []freeWindow{}
+	if !window.end.After(window.start) {
+		return free
+	}
+	free = append(free, window)
+	for _, b := range busy {
+		var next []freeWindow
+		for _, f := range free {
+			if !b.start.Before(f.end) || !b.end.After(f.start) {
+				next = append(next, f)
+				continue
+			}
+			if b.start.After(f.start) {
+				next = append(next, freeWindow{start: f.start, end: b.start})
+			}
+			if b.end.Before(f.end) {
+				next = append(next, freeWindow{start: b.end, end: f.end})
+			}
+		}
+		free = next
+	}
+	sort.Slice(free, func(i, j int) bool { return free[i].start.Before(free[j].start) })
+	return free
+}
+
+// scheduleTasks fills the free windows day by day with sessions, always working on the task with the
+// earliest deadline but switching to another one after each session when possible, so subjects are
+// interleaved and topics spread across days.
+func scheduleTasks(userID string, tasks []*planTask, days []planDay, settings *models.StudyPlanGenerationSettings, result *models.StudyPlanGenerationResult) []models.StudySession {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].deadline.Equal(tasks[j].deadline) {
+			return tasks[i].earliest.Before(tasks[j].earliest)
+		}
+		return tasks[i].deadline.Before(tasks[j].deadline)
+	})
+
+	sessions := []models.StudySession{}
+	var last *planTask
+	for _, day := range days {
+		used := day.plannedMinutes
+		for _, w := range day.windows {
+			cursor := w.start
+			for {
+				available := min(int(w.end.Sub(cursor).Minutes()), settings.DailyLimitMinutes-used)
+				task := pickTask(tasks, cursor, available, last)
+				if task == nil {
+					break
+				}
+
+				room := min(available, int(task.deadline.Sub(cursor).Minutes()))
+				length := min(settings.SessionMinutes, task.minutes, room)
+				if task.minutes-length < minSessionMinutes && task.minutes <= room {
+					// Finish the task rather than leaving a remainder too short for a session of its own.
+					length = task.minutes
+				}
+				end := cursor.Add(time.Duration(length) * time.Minute)
+				session := models.StudySession{
+					UserID:                 userID,
+					SubjectID:              task.subjectID,
+					ExamID:                 task.examID,
+					AssignmentID:           task.assignmentID,
+					PlannedStartTime:       sql.NullTime{Time: cursor.UTC(), Valid: true},
+					PlannedEndTime:         sql.NullTime{Time: end.UTC(), Valid: true},
+					PlannedDurationMinutes: sql.NullInt32{Int32: int32(length), Valid: true},
+					SessionType:            task.sessionType,
+					TopicsToCover:          task.sessionTopics(length),
+					TopicsCovered:          []string{},
+					Status:                 "planned",
+					Notes:                  sql.NullString{String: task.label, Valid: true},
+				}
+				sessions = append(sessions, session)
+
+				item := &result.Items[task.item]
+				item.ScheduledMinutes += length
+				item.Sessions++
+				if task.sessionType == "revision" {
+					item.RevisionMinutes += length
+				}
+
+				task.minutes -= length
+				used += length
+				last = task
+				cursor = end.Add(time.Duration(settings.BreakMinutes) * time.Minute)
+			}
+		}
+	}
+	return sessions
+}
+
+// pickTask returns the task with the earliest deadline that can use a session starting at the cursor,
+// preferring a different task than the previous session's. It returns nil when none fits.
+func pickTask(tasks []*planTask, cursor time.Time, available int, last *planTask) *planTask {
+	var picked *planTask
+	for _, task := range tasks {
+		if task.minutes <= 0 || cursor.Before(task.earliest) {
+			continue
+		}
+		needed := min(minSessionMinutes, task.minutes)
+		if available < needed || cursor.Add(time.Duration(needed)*time.Minute).After(task.deadline) {
+			continue
+		}
+		if task != last {
+			return task
+		}
+		if picked == nil {
+			picked = task
+		}
+	}
+	return picked
+}
+
+// localExamDay returns midnight of the exam date in the given location.
+func localExamDay(e models.Exam, loc *time.Location) time.Time {
+	return time.Date(e.ExamDate.Year(), e.ExamDate.Month(), e.ExamDate.Day(), 0, 0, 0, 0, loc)
+}
+
+// sessionTopics returns the topics falling into the next length minutes of the task, given how much of
+// it is already scheduled. Consecutive sessions share a topic when there is more time than topics.
+func (t *planTask) sessionTopics(length int) []string {
+	if len(t.topics) == 0 || t.total <= 0 {
+		return []string{}
+	}
+	done := t.total - t.minutes
+	from := min(done*len(t.topics)/t.total, len(t.topics)-1)
+	to := max((done+length)*len(t.topics)/t.total, from+1)
+	return append([]string{}, t.topics[from:min(to, len(t.topics))]...)
+}
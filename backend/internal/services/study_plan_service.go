@@ -3,8 +3,10 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
 )
@@ -28,15 +30,30 @@ type StudyPlanService interface {
 
 // studyPlanService implements StudyPlanService.
 type studyPlanService struct {
-	studyPlanRepo repository.StudyPlanRepository
-	sessionRepo   repository.StudySessionRepository
+	studyPlanRepo   repository.StudyPlanRepository
+	sessionRepo     repository.StudySessionRepository
+	examRepo        repository.ExamRepository
+	assignmentRepo  repository.AssignmentRepository
+	coverageService CoverageService
+	eventBus        events.Bus
 }
 
 // NewStudyPlanService creates a new study plan service.
-func NewStudyPlanService(studyPlanRepo repository.StudyPlanRepository, sessionRepo repository.StudySessionRepository) StudyPlanService {
+func NewStudyPlanService(
+	studyPlanRepo repository.StudyPlanRepository,
+	sessionRepo repository.StudySessionRepository,
+	examRepo repository.ExamRepository,
+	assignmentRepo repository.AssignmentRepository,
+	coverageService CoverageService,
+	eventBus events.Bus,
+) StudyPlanService {
 	return &studyPlanService{
-		studyPlanRepo: studyPlanRepo,
-		sessionRepo:   sessionRepo,
+		studyPlanRepo:   studyPlanRepo,
+		sessionRepo:     sessionRepo,
+		examRepo:        examRepo,
+		assignmentRepo:  assignmentRepo,
+		coverageService: coverageService,
+		eventBus:        eventBus,
 	}
 }
 
@@ -65,6 +82,7 @@ func (s *studyPlanService) CreateStudyPlan(ctx context.Context, userID string, i
 	if err := s.studyPlanRepo.CreateStudyPlan(ctx, studyPlan); err != nil {
 		return nil, fmt.Errorf("failed to create study plan: %w", err)
 	}
+	publishStudyPlanCreated(ctx, s.eventBus, studyPlan, 0, "manual")
 	return studyPlan, nil
 }
 
@@ -92,6 +110,7 @@ func (s *studyPlanService) UpdateStudyPlan(ctx context.Context, userID string, i
 	if existingPlan.UserID != userID {
 		return nil, fmt.Errorf("study plan does not belong to user")
 	}
+	before := *existingPlan
 
 	if input.PlanDate != "" {
 		planDate, err := time.Parse("2006-01-02", input.PlanDate)
@@ -118,16 +137,45 @@ func (s *studyPlanService) UpdateStudyPlan(ctx context.Context, userID string, i
 	if err := s.studyPlanRepo.UpdateStudyPlan(ctx, existingPlan); err != nil {
 		return nil, fmt.Errorf("failed to update study plan: %w", err)
 	}
+	if changes := events.Changes(&before, existingPlan); len(changes) > 0 {
+		s.eventBus.Publish(ctx, events.Event{
+			Type:        events.StudyPlanUpdated,
+			UserID:      userID,
+			EntityType:  "study_plan",
+			EntityID:    existingPlan.ID,
+			Description: "Updated study plan " + existingPlan.Title,
+			Metadata:    map[string]interface{}{"changes": changes},
+		})
+	}
 	return existingPlan, nil
 }
 
 // DeleteStudyPlan deletes a study plan.
 func (s *studyPlanService) DeleteStudyPlan(ctx context.Context, id string) error {
-	return s.studyPlanRepo.DeleteStudyPlan(ctx, id)
+	plan, err := s.studyPlanRepo.GetStudyPlanByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("study plan not found: %w", err)
+	}
+	if err := s.studyPlanRepo.DeleteStudyPlan(ctx, id); err != nil {
+		return err
+	}
+	s.eventBus.Publish(ctx, events.Event{
+		Type:        events.StudyPlanDeleted,
+		UserID:      plan.UserID,
+		EntityType:  "study_plan",
+		EntityID:    plan.ID,
+		Description: "Deleted study plan " + plan.Title,
+		Metadata:    map[string]interface{}{"title": plan.Title},
+	})
+	return nil
 }
 
 // CreateStudySession creates a new study session for a user.
 func (s *studyPlanService) CreateStudySession(ctx context.Context, userID string, input *models.StudySessionCreationInput) (*models.StudySession, error) {
+	if err := s.checkSessionLinks(ctx, userID, input); err != nil {
+		return nil, err
+	}
+
 	studySession := &models.StudySession{
 		UserID:             userID,
 		StudyPlanID:        sql.NullString{String: *input.StudyPlanID, Valid: input.StudyPlanID != nil},
@@ -139,6 +187,12 @@ func (s *studyPlanService) CreateStudySession(ctx context.Context, userID string
 		CompletionPercentage: 0,
 	}
 
+	if input.ExamID != nil {
+		studySession.ExamID = sql.NullString{String: *input.ExamID, Valid: true}
+	}
+	if input.AssignmentID != nil {
+		studySession.AssignmentID = sql.NullString{String: *input.AssignmentID, Valid: true}
+	}
 	if input.PlannedStartTime != nil {
 		plannedStartTime, err := time.Parse(time.RFC3339, *input.PlannedStartTime)
 		if err != nil {
@@ -192,6 +246,15 @@ func (s *studyPlanService) CreateStudySession(ctx context.Context, userID string
 	if err := s.sessionRepo.CreateStudySession(ctx, studySession); err != nil {
 		return nil, fmt.Errorf("failed to create study session: %w", err)
 	}
+	s.refreshCoverage(ctx, studySession)
+	s.eventBus.Publish(ctx, events.Event{
+		Type:        events.StudySessionCreated,
+		UserID:      userID,
+		EntityType:  "study_session",
+		EntityID:    studySession.ID,
+		Description: "Created " + studySession.SessionType + " session",
+		Metadata:    map[string]interface{}{"sessionType": studySession.SessionType, "status": studySession.Status, "plannedStartTime": studySession.PlannedStartTime},
+	})
 	return studySession, nil
 }
 
@@ -219,6 +282,10 @@ func (s *studyPlanService) UpdateStudySession(ctx context.Context, userID string
 	if existingSession.UserID != userID {
 		return nil, fmt.Errorf("study session does not belong to user")
 	}
+	if err := s.checkSessionLinks(ctx, userID, input); err != nil {
+		return nil, err
+	}
+	before := *existingSession
 
 	if input.StudyPlanID != nil {
 		existingSession.StudyPlanID = sql.NullString{String: *input.StudyPlanID, Valid: true}
@@ -230,6 +297,16 @@ func (s *studyPlanService) UpdateStudySession(ctx context.Context, userID string
 	} else {
 		existingSession.SubjectID = sql.NullString{Valid: false}
 	}
+	if input.ExamID != nil {
+		existingSession.ExamID = sql.NullString{String: *input.ExamID, Valid: true}
+	} else {
+		existingSession.ExamID = sql.NullString{Valid: false}
+	}
+	if input.AssignmentID != nil {
+		existingSession.AssignmentID = sql.NullString{String: *input.AssignmentID, Valid: true}
+	} else {
+		existingSession.AssignmentID = sql.NullString{Valid: false}
+	}
 	if input.PlannedStartTime != nil {
 		plannedStartTime, err := time.Parse(time.RFC3339, *input.PlannedStartTime)
 		if err != nil {
@@ -310,10 +387,112 @@ func (s *studyPlanService) UpdateStudySession(ctx context.Context, userID string
 	if err := s.sessionRepo.UpdateStudySession(ctx, existingSession); err != nil {
 		return nil, fmt.Errorf("failed to update study session: %w", err)
 	}
+	s.refreshCoverage(ctx, existingSession)
+	publishStudySessionChange(ctx, s.eventBus, &before, existingSession)
 	return existingSession, nil
 }
 
 // DeleteStudySession deletes a study session.
 func (s *studyPlanService) DeleteStudySession(ctx context.Context, id string) error {
-	return s.sessionRepo.DeleteStudySession(ctx, id)
+	session, err := s.sessionRepo.GetStudySessionByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("study session not found: %w", err)
+	}
+	if err := s.sessionRepo.DeleteStudySession(ctx, id); err != nil {
+		return err
+	}
+	s.eventBus.Publish(ctx, events.Event{
+		Type:        events.StudySessionDeleted,
+		UserID:      session.UserID,
+		EntityType:  "study_session",
+		EntityID:    session.ID,
+		Description: "Deleted " + session.SessionType + " session",
+		Metadata:    map[string]interface{}{"sessionType": session.SessionType, "status": session.Status},
+	})
+	return nil
+}
+
+// refreshCoverage advances the prep status of exams for the session's subject once topics are covered.
+// Failures are logged rather than returned, since the session itself was saved.
+func (s *studyPlanService) refreshCoverage(ctx context.Context, session *models.StudySession) {
+	if len(session.TopicsCovered) == 0 {
+		return
+	}
+	if err := s.coverageService.RefreshCoverage(ctx, session.UserID, session.SubjectID.String); err != nil {
+		log.Printf("Warning: Could not refresh syllabus coverage after study session %s: %v", session.ID, err)
+	}
+}
+
+// publishStudyPlanCreated publishes the creation of a study plan along with how it was made:
+// 'manual', 'generated', 'template' or 'clone'.
+func publishStudyPlanCreated(ctx context.Context, eventBus events.Bus, plan *models.StudyPlan, sessions int, source string) {
+	eventBus.Publish(ctx, events.Event{
+		Type:        events.StudyPlanCreated,
+		UserID:      plan.UserID,
+		EntityType:  "study_plan",
+		EntityID:    plan.ID,
+		Description: "Created study plan " + plan.Title,
+		Metadata:    map[string]interface{}{"title": plan.Title, "planDate": plan.PlanDate, "sessions": sessions, "source": source},
+	})
+}
+
+// publishStudySessionChange publishes the changed fields of an updated study session, and a completion
+// event when its status moved to completed.
+func publishStudySessionChange(ctx context.Context, eventBus events.Bus, before, after *models.StudySession) {
+	changes := events.Changes(before, after)
+	if len(changes) == 0 {
+		return
+	}
+	eventBus.Publish(ctx, events.Event{
+		Type:        events.StudySessionUpdated,
+		UserID:      after.UserID,
+		EntityType:  "study_session",
+		EntityID:    after.ID,
+		Description: "Updated " + after.SessionType + " session",
+		Metadata:    map[string]interface{}{"changes": changes},
+	})
+	if after.Status == "completed" && before.Status != "completed" {
+		publishStudySessionCompleted(ctx, eventBus, after)
+	}
+}
+
+// publishStudySessionCompleted publishes the completion of a study session.
+func publishStudySessionCompleted(ctx context.Context, eventBus events.Bus, session *models.StudySession) {
+	eventBus.Publish(ctx, events.Event{
+		Type:        events.StudySessionCompleted,
+		UserID:      session.UserID,
+		EntityType:  "study_session",
+		EntityID:    session.ID,
+		Description: "Completed " + session.SessionType + " session",
+		Metadata: map[string]interface{}{
+			"sessionType":           session.SessionType,
+			"subjectId":             session.SubjectID,
+			"actualDurationMinutes": session.ActualDurationMinutes,
+			"completionPercentage":  session.CompletionPercentage,
+			"topicsCovered":         []string(session.TopicsCovered),
+		},
+	})
+}
+
+// checkSessionLinks verifies that the exam and assignment a study session is linked to belong to the user.
+func (s *studyPlanService) checkSessionLinks(ctx context.Context, userID string, input *models.StudySessionCreationInput) error {
+	if input.ExamID != nil {
+		exam, err := s.examRepo.GetExamByID(ctx, *input.ExamID)
+		if err != nil {
+			return fmt.Errorf("exam not found: %w", err)
+		}
+		if exam.UserID != userID {
+			return fmt.Errorf("exam does not belong to user")
+		}
+	}
+	if input.AssignmentID != nil {
+		assignment, err := s.assignmentRepo.GetAssignmentByID(ctx, *input.AssignmentID)
+		if err != nil {
+			return fmt.Errorf("assignment not found: %w", err)
+		}
+		if assignment.UserID != userID {
+			return fmt.Errorf("assignment does not belong to user")
+		}
+	}
+	return nil
 }
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+var (
+	// ErrInvalidStudyPlanTemplate is returned when a template's session blueprints cannot be laid out.
+	ErrInvalidStudyPlanTemplate = errors.New("invalid study plan template")
+	// ErrUnfilledTemplateSlot is returned when a template is instantiated without a subject for one of its subject slots.
+	ErrUnfilledTemplateSlot = errors.New("template slot not filled")
+	// ErrInvalidPlanDate is returned when the dates to instantiate or clone a plan onto are missing or out of range.
+	ErrInvalidPlanDate = errors.New("invalid plan date")
+)
+
+const (
+	defaultTemplateRepeatDays = 7
+	maxTemplateRangeDays      = 366
+)
+
+// StudyPlanTemplateService defines the interface for study plan templates and plan cloning.
+type StudyPlanTemplateService interface {
+	CreateStudyPlanTemplate(ctx context.Context, userID string, input *models.StudyPlanTemplateInput) (*models.StudyPlanTemplate, error)
+	GetStudyPlanTemplateByID(ctx context.Context, userID, id string) (*models.StudyPlanTemplate, error)
+	GetStudyPlanTemplatesByUserID(ctx context.Context, userID string) ([]models.StudyPlanTemplate, error)
+	UpdateStudyPlanTemplate(ctx context.Context, userID, id string, input *models.StudyPlanTemplateInput) (*models.StudyPlanTemplate, error)
+	DeleteStudyPlanTemplate(ctx context.Context, userID, id string) error
+
+	InstantiateStudyPlanTemplate(ctx context.Context, userID, id string, input *models.StudyPlanTemplateInstantiationInput) (*models.StudyPlanWithSessions, error)
+	CloneStudyPlan(ctx context.Context, userID, studyPlanID string, input *models.StudyPlanCloneInput) (*models.StudyPlanWithSessions, error)
+}
+
+// studyPlanTemplateService implements StudyPlanTemplateService.
+type studyPlanTemplateService struct {
+	templateRepo  repository.StudyPlanTemplateRepository
+	studyPlanRepo repository.StudyPlanRepository
+	sessionRepo   repository.StudySessionRepository
+	subjectRepo   repository.SubjectRepository
+	userRepo      repository.UserRepository
+	eventBus      events.Bus
+}
+
+// NewStudyPlanTemplateService creates a new study plan template service.
+func NewStudyPlanTemplateService(
+	templateRepo repository.StudyPlanTemplateRepository,
+	studyPlanRepo repository.StudyPlanRepository,
+	sessionRepo repository.StudySessionRepository,
+	subjectRepo repository.SubjectRepository,
+	userRepo repository.UserRepository,
+	eventBus events.Bus,
+) StudyPlanTemplateService {
+	return &studyPlanTemplateService{
+		templateRepo:  templateRepo,
+		studyPlanRepo: studyPlanRepo,
+		sessionRepo:   sessionRepo,
+		subjectRepo:   subjectRepo,
+		userRepo:      userRepo,
+		eventBus:      eventBus,
+	}
+}
+
+// CreateStudyPlanTemplate creates a new study plan template for a user.
+func (s *studyPlanTemplateService) CreateStudyPlanTemplate(ctx context.Context, userID string, input *models.StudyPlanTemplateInput) (*models.StudyPlanTemplate, error) {
+	template := &models.StudyPlanTemplate{UserID: userID}
+	if err := applyTemplateInput(template, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.CreateStudyPlanTemplate(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to create study plan template: %w", err)
+	}
+	return template, nil
+}
+
+// GetStudyPlanTemplateByID retrieves one of the user's study plan templates.
+func (s *studyPlanTemplateService) GetStudyPlanTemplateByID(ctx context.Context, userID, id string) (*models.StudyPlanTemplate, error) {
+	template, err := s.templateRepo.GetStudyPlanTemplateByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("study plan template not found: %w", err)
+	}
+	if template.UserID != userID {
+		return nil, fmt.Errorf("study plan template does not belong to user")
+	}
+	return template, nil
+}
+
+// GetStudyPlanTemplatesByUserID retrieves all study plan templates for a user.
+func (s *studyPlanTemplateService) GetStudyPlanTemplatesByUserID(ctx context.Context, userID string) ([]models.StudyPlanTemplate, error) {
+	return s.templateRepo.GetStudyPlanTemplatesByUserID(ctx, userID)
+}
+
+// UpdateStudyPlanTemplate replaces the contents of an existing study plan template.
+func (s *studyPlanTemplateService) UpdateStudyPlanTemplate(ctx context.Context, userID, id string, input *models.StudyPlanTemplateInput) (*models.StudyPlanTemplate, error) {
+	template, err := s.GetStudyPlanTemplateByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyTemplateInput(template, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.UpdateStudyPlanTemplate(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to update study plan template: %w", err)
+	}
+	return template, nil
+}
+
+// DeleteStudyPlanTemplate deletes one of the user's study plan templates. Plans created from it are kept.
+func (s *studyPlanTemplateService) DeleteStudyPlanTemplate(ctx context.Context, userID, id string) error {
+	if _, err := s.GetStudyPlanTemplateByID(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.templateRepo.DeleteStudyPlanTemplate(ctx, id, userID)
+}
+
+// InstantiateStudyPlanTemplate creates a study plan from a template. Without an end date the template is laid
+// out once from the start date; with one it repeats every RepeatEveryDays until the end date. Subject slots
+// take the given subjects, and the topics of each topic slot are dealt out in order across its sessions.
+func (s *studyPlanTemplateService) InstantiateStudyPlanTemplate(ctx context.Context, userID, id string, input *models.StudyPlanTemplateInstantiationInput) (*models.StudyPlanWithSessions, error) {
+	template, err := s.GetStudyPlanTemplateByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	startDay, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid start date format", ErrInvalidPlanDate)
+	}
+	lastOffset := 0
+	for _, blueprint := range template.Sessions {
+		lastOffset = max(lastOffset, blueprint.DayOffset)
+	}
+	endDay := startDay.AddDate(0, 0, lastOffset)
+	repeat := false
+	if input.EndDate != nil && *input.EndDate != "" {
+		endDay, err = time.Parse("2006-01-02", *input.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid end date format", ErrInvalidPlanDate)
+		}
+		if endDay.Before(startDay) {
+			return nil, fmt.Errorf("%w: end date is before start date", ErrInvalidPlanDate)
+		}
+		repeat = true
+	}
+	if endDay.Sub(startDay) > maxTemplateRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: a plan can span at most %d days", ErrInvalidPlanDate, maxTemplateRangeDays)
+	}
+
+	subjects := make(map[string]bool)
+	for i, blueprint := range template.Sessions {
+		subjectID, err := templateSubject(blueprint, input.Subjects)
+		if err != nil {
+			return nil, fmt.Errorf("session %d: %w", i+1, err)
+		}
+		if subjectID != "" && !subjects[subjectID] {
+			if _, err := s.subjectRepo.GetSubjectByID(ctx, subjectID); err != nil {
+				return nil, fmt.Errorf("subject not found: %w", err)
+			}
+			subjects[subjectID] = true
+		}
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	var sessions []models.StudySession
+	var slots []string // Topic slot of each session
+	for cycleStart := startDay; !cycleStart.After(endDay); cycleStart = cycleStart.AddDate(0, 0, int(template.RepeatEveryDays)) {
+		for _, blueprint := range template.Sessions {
+			day := cycleStart.AddDate(0, 0, blueprint.DayOffset)
+			if day.After(endDay) {
+				continue
+			}
+			clock, _ := time.Parse("15:04", blueprint.StartTime)
+			start := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+			end := start.Add(time.Duration(blueprint.DurationMinutes) * time.Minute)
+			subjectID, _ := templateSubject(blueprint, input.Subjects)
+
+			session := models.StudySession{
+				UserID:                 userID,
+				SubjectID:              sql.NullString{String: subjectID, Valid: subjectID != ""},
+				PlannedStartTime:       sql.NullTime{Time: start, Valid: true},
+				PlannedEndTime:         sql.NullTime{Time: end, Valid: true},
+				PlannedDurationMinutes: sql.NullInt32{Int32: int32(blueprint.DurationMinutes), Valid: true},
+				SessionType:            blueprint.SessionType,
+				TopicsToCover:          append([]string{}, blueprint.Topics...),
+				TopicsCovered:          []string{},
+				Status:                 "planned",
+			}
+			if blueprint.Notes != "" {
+				session.Notes = sql.NullString{String: blueprint.Notes, Valid: true}
+			}
+			sessions = append(sessions, session)
+			slots = append(slots, blueprint.TopicSlot)
+		}
+		if !repeat {
+			break
+		}
+	}
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("%w: no template sessions fall between the start and end date", ErrInvalidPlanDate)
+	}
+
+	order := make([]int, len(sessions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sessions[order[a]].PlannedStartTime.Time.Before(sessions[order[b]].PlannedStartTime.Time)
+	})
+	ordered := make([]models.StudySession, len(sessions))
+	orderedSlots := make([]string, len(sessions))
+	for i, idx := range order {
+		ordered[i] = sessions[idx]
+		orderedSlots[i] = slots[idx]
+	}
+	dealSlotTopics(ordered, orderedSlots, input.Topics)
+
+	lastDay := ordered[len(ordered)-1].PlannedStartTime.Time.In(loc)
+	planEnd := endDay
+	if !repeat {
+		planEnd = time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	plan := &models.StudyPlan{
+		UserID:   userID,
+		Title:    template.Title,
+		PlanDate: startDay,
+		PlanType: template.PlanType,
+		Status:   "planned",
+		Notes:    template.Description,
+	}
+	if input.Title != nil && *input.Title != "" {
+		plan.Title = *input.Title
+	}
+	if planEnd.After(startDay) {
+		plan.EndDate = sql.NullTime{Time: planEnd, Valid: true}
+	}
+
+	if err := s.studyPlanRepo.CreateStudyPlanWithSessions(ctx, plan, ordered); err != nil {
+		return nil, fmt.Errorf("failed to create study plan from template: %w", err)
+	}
+	publishStudyPlanCreated(ctx, s.eventBus, plan, len(ordered), "template")
+	return &models.StudyPlanWithSessions{Plan: plan, Sessions: ordered}, nil
+}
+
+// CloneStudyPlan copies a study plan and its sessions to a new first day. Session times keep their local
+// time of day, and the copies start over as planned with no progress recorded.
+func (s *studyPlanTemplateService) CloneStudyPlan(ctx context.Context, userID, studyPlanID string, input *models.StudyPlanCloneInput) (*models.StudyPlanWithSessions, error) {
+	source, err := s.studyPlanRepo.GetStudyPlanByID(ctx, studyPlanID)
+	if err != nil {
+		return nil, fmt.Errorf("study plan not found: %w", err)
+	}
+	if source.UserID != userID {
+		return nil, fmt.Errorf("study plan does not belong to user")
+	}
+
+	planDate, err := time.Parse("2006-01-02", input.PlanDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid plan date format", ErrInvalidPlanDate)
+	}
+	sourceDay := time.Date(source.PlanDate.Year(), source.PlanDate.Month(), source.PlanDate.Day(), 0, 0, 0, 0, time.UTC)
+	shiftDays := int(planDate.Sub(sourceDay).Hours() / 24)
+
+	sourceSessions, err := s.sessionRepo.GetStudySessionsByStudyPlanID(ctx, studyPlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get study sessions: %w", err)
+	}
+
+	plan := &models.StudyPlan{
+		UserID:   userID,
+		Title:    source.Title,
+		PlanDate: planDate,
+		PlanType: source.PlanType,
+		Status:   "planned",
+		Notes:    source.Notes,
+	}
+	if input.Title != nil && *input.Title != "" {
+		plan.Title = *input.Title
+	}
+	if source.EndDate.Valid {
+		plan.EndDate = sql.NullTime{Time: source.EndDate.Time.AddDate(0, 0, shiftDays), Valid: true}
+	}
+
+	loc := userLocation(ctx, s.userRepo, userID)
+	sessions := make([]models.StudySession, 0, len(sourceSessions))
+	for _, src := range sourceSessions {
+		session := models.StudySession{
+			UserID:                 userID,
+			SubjectID:              src.SubjectID,
+			ExamID:                 src.ExamID,
+			AssignmentID:           src.AssignmentID,
+			PlannedDurationMinutes: src.PlannedDurationMinutes,
+			SessionType:            src.SessionType,
+			TopicsToCover:          src.TopicsToCover,
+			TopicsCovered:          []string{},
+			Status:                 "planned",
+			Notes:                  src.Notes,
+		}
+		if src.PlannedStartTime.Valid {
+			session.PlannedStartTime = sql.NullTime{Time: src.PlannedStartTime.Time.In(loc).AddDate(0, 0, shiftDays), Valid: true}
+		}
+		if src.PlannedEndTime.Valid {
+			session.PlannedEndTime = sql.NullTime{Time: src.PlannedEndTime.Time.In(loc).AddDate(0, 0, shiftDays), Valid: true}
+		}
+		if session.TopicsToCover == nil {
+			session.TopicsToCover = []string{}
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := s.studyPlanRepo.CreateStudyPlanWithSessions(ctx, plan, sessions); err != nil {
+		return nil, fmt.Errorf("failed to clone study plan: %w", err)
+	}
+	publishStudyPlanCreated(ctx, s.eventBus, plan, len(sessions), "clone")
+	return &models.StudyPlanWithSessions{Plan: plan, Sessions: sessions}, nil
+}
+
+// applyTemplateInput validates a template input and copies it onto the template.
+func applyTemplateInput(template *models.StudyPlanTemplate, input *models.StudyPlanTemplateInput) error {
+	repeatEveryDays := defaultTemplateRepeatDays
+	if input.RepeatEveryDays != nil {
+		repeatEveryDays = *input.RepeatEveryDays
+	}
+	for i, blueprint := range input.Sessions {
+		if _, err := time.Parse("15:04", blueprint.StartTime); err != nil {
+			return fmt.Errorf("%w: session %d start time must be HH:MM", ErrInvalidStudyPlanTemplate, i+1)
+		}
+		if blueprint.DayOffset >= repeatEveryDays {
+			return fmt.Errorf("%w: session %d falls outside the %d-day cycle", ErrInvalidStudyPlanTemplate, i+1, repeatEveryDays)
+		}
+	}
+
+	template.Title = input.Title
+	template.PlanType = input.PlanType
+	template.Description = sql.NullString{}
+	if input.Description != nil {
+		template.Description = sql.NullString{String: *input.Description, Valid: true}
+	}
+	template.RepeatEveryDays = int32(repeatEveryDays)
+	template.Sessions = input.Sessions
+	return nil
+}
+
+// templateSubject resolves the subject of a blueprint from the filled subject slots.
+func templateSubject(blueprint models.StudySessionBlueprint, subjects map[string]string) (string, error) {
+	if blueprint.SubjectSlot != "" {
+		if subjectID := subjects[blueprint.SubjectSlot]; subjectID != "" {
+			return subjectID, nil
+		}
+		if blueprint.SubjectID == nil {
+			return "", fmt.Errorf("%w: subject slot %q", ErrUnfilledTemplateSlot, blueprint.SubjectSlot)
+		}
+	}
+	if blueprint.SubjectID != nil {
+		return *blueprint.SubjectID, nil
+	}
+	return "", nil
+}
+
+// dealSlotTopics deals the topics of each topic slot out in order across the sessions sharing the slot.
+// With more topics than sessions each session takes a consecutive share; with fewer, topics span
+// consecutive sessions.
+func dealSlotTopics(sessions []models.StudySession, slots []string, topics map[string][]string) {
+	bySlot := make(map[string][]int)
+	for i, slot := range slots {
+		if slot != "" {
+			bySlot[slot] = append(bySlot[slot], i)
+		}
+	}
+	for slot, indexes := range bySlot {
+		slotTopics := topics[slot]
+		n, m := len(slotTopics), len(indexes)
+		if n == 0 {
+			continue
+		}
+		for k, idx := range indexes {
+			share := []string{slotTopics[k*n/m]}
+			if n >= m {
+				share = slotTopics[k*n/m : (k+1)*n/m]
+			}
+			for _, topic := range share {
+				if !containsTopic(sessions[idx].TopicsToCover, topic) {
+					sessions[idx].TopicsToCover = append(sessions[idx].TopicsToCover, topic)
+				}
+			}
+		}
+	}
+}
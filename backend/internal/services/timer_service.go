@@ -0,0 +1,448 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+const (
+	// maxTimerDuration caps how long a single running timer may count. A timer
+	// running longer than this was left on and is treated as abandoned.
+	maxTimerDuration = 12 * time.Hour
+	// abandonedTimerGrace is how long past the local midnight after it started a
+	// timer may keep running, so late-night sessions are not cut off at midnight.
+	abandonedTimerGrace = 2 * time.Hour
+)
+
+var (
+	// ErrTimerAlreadyRunning is returned when starting a timer while another one is running.
+	ErrTimerAlreadyRunning = errors.New("a timer is already running")
+	// ErrNoActiveTimer is returned when there is no running or paused timer to act on.
+	ErrNoActiveTimer = errors.New("no active timer")
+)
+
+// TimerService defines the interface for time tracking business logic.
+type TimerService interface {
+	StartTimer(ctx context.Context, userID string, input *models.TimerStartInput) (*models.TimeEntry, error)
+	PauseTimer(ctx context.Context, userID string) (*models.TimeEntry, error)
+	ResumeTimer(ctx context.Context, userID string) (*models.TimeEntry, error)
+	StopTimer(ctx context.Context, userID string) (*models.TimeEntry, error)
+	GetCurrentTimer(ctx context.Context, userID string) (*models.TimeEntry, error)
+	GetTimeEntriesByUserID(ctx context.Context, userID string) ([]models.TimeEntry, error)
+	GetTimeEntriesByEntity(ctx context.Context, userID, entityType, entityID string) ([]models.TimeEntry, error)
+}
+
+// timerService implements TimerService.
+type timerService struct {
+	timeEntryRepo   repository.TimeEntryRepository
+	assignmentRepo  repository.AssignmentRepository
+	examRepo        repository.ExamRepository
+	sessionRepo     repository.StudySessionRepository
+	userRepo        repository.UserRepository
+	pomodoroService PomodoroService
+}
+
+// NewTimerService creates a new timer service.
+func NewTimerService(
+	timeEntryRepo repository.TimeEntryRepository,
+	assignmentRepo repository.AssignmentRepository,
+	examRepo repository.ExamRepository,
+	sessionRepo repository.StudySessionRepository,
+	userRepo repository.UserRepository,
+	pomodoroService PomodoroService,
+) TimerService {
+	return &timerService{
+		timeEntryRepo:   timeEntryRepo,
+		assignmentRepo:  assignmentRepo,
+		examRepo:        examRepo,
+		sessionRepo:     sessionRepo,
+		userRepo:        userRepo,
+		pomodoroService: pomodoroService,
+	}
+}
+
+// StartTimer starts a new timer against an assignment, exam or study session.
+// A paused timer is stopped in its place. Timers cannot run alongside a pomodoro.
+func (s *timerService) StartTimer(ctx context.Context, userID string, input *models.TimerStartInput) (*models.TimeEntry, error) {
+	active, err := s.getActiveEntry(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil && active.Status == "running" {
+		return nil, ErrTimerAlreadyRunning
+	}
+	if err := s.checkNoActivePomodoro(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkEntityOwnership(ctx, userID, input.EntityType, input.EntityID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.TimeEntry{
+		UserID:     userID,
+		EntityType: input.EntityType,
+		EntityID:   input.EntityID,
+		StartedAt:  time.Now(),
+		Status:     "running",
+	}
+	if input.Notes != nil {
+		entry.Notes = sql.NullString{String: *input.Notes, Valid: true}
+	}
+
+	if active != nil {
+		err = s.replacePausedEntry(ctx, active, entry)
+	} else {
+		err = s.timeEntryRepo.CreateTimeEntry(ctx, entry)
+	}
+	if err != nil {
+		// The paused timer changing under us means another request resumed or stopped it.
+		if isUniqueViolation(err) || errors.Is(err, ErrNoActiveTimer) {
+			return nil, ErrTimerAlreadyRunning
+		}
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+
+	if entry.EntityType == "study_session" {
+		if err := s.markSessionStarted(ctx, entry); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// PauseTimer closes the running timer so it can be resumed later.
+func (s *timerService) PauseTimer(ctx context.Context, userID string) (*models.TimeEntry, error) {
+	running, err := s.getRunningEntry(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	if running == nil {
+		return nil, ErrNoActiveTimer
+	}
+
+	if err := s.closeEntry(ctx, running, time.Now(), "paused", false); err != nil {
+		return nil, err
+	}
+	return running, nil
+}
+
+// ResumeTimer starts a new running entry for the entity of the most recently paused timer.
+func (s *timerService) ResumeTimer(ctx context.Context, userID string) (*models.TimeEntry, error) {
+	latest, err := s.getActiveEntry(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, ErrNoActiveTimer
+	}
+	if latest.Status == "running" {
+		return nil, ErrTimerAlreadyRunning
+	}
+	if err := s.checkNoActivePomodoro(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.TimeEntry{
+		UserID:     userID,
+		EntityType: latest.EntityType,
+		EntityID:   latest.EntityID,
+		StartedAt:  time.Now(),
+		Status:     "running",
+		Notes:      latest.Notes,
+	}
+	if err := s.replacePausedEntry(ctx, latest, entry); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrTimerAlreadyRunning
+		}
+		if errors.Is(err, ErrNoActiveTimer) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to resume timer: %w", err)
+	}
+	return entry, nil
+}
+
+// StopTimer closes the running or paused timer for good. When the timer was abandoned and has been
+// auto-stopped, that entry is returned with AutoStopped set so the client can show where it was cut off.
+func (s *timerService) StopTimer(ctx context.Context, userID string) (*models.TimeEntry, error) {
+	latest, err := s.getActiveEntry(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return s.getAutoStoppedEntry(ctx, userID)
+	}
+
+	if latest.Status == "running" {
+		if err := s.closeEntry(ctx, latest, time.Now(), "stopped", false); err != nil {
+			return nil, err
+		}
+		return latest, nil
+	}
+
+	// Already paused: the time was rolled up on pause, only the status changes.
+	latest.Status = "stopped"
+	stopped, err := s.timeEntryRepo.CloseTimeEntry(ctx, latest, "paused", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+	if !stopped {
+		return nil, ErrNoActiveTimer
+	}
+	return latest, nil
+}
+
+// GetCurrentTimer retrieves the running or paused timer for a user, if any. An abandoned timer is left
+// out; it is auto-stopped by the next timer action.
+func (s *timerService) GetCurrentTimer(ctx context.Context, userID string) (*models.TimeEntry, error) {
+	latest, err := s.getActiveEntry(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, ErrNoActiveTimer
+	}
+	return latest, nil
+}
+
+// GetTimeEntriesByUserID retrieves all time entries for a user.
+func (s *timerService) GetTimeEntriesByUserID(ctx context.Context, userID string) ([]models.TimeEntry, error) {
+	return s.timeEntryRepo.GetTimeEntriesByUserID(ctx, userID)
+}
+
+// GetTimeEntriesByEntity retrieves all time entries a user recorded against a single entity.
+func (s *timerService) GetTimeEntriesByEntity(ctx context.Context, userID, entityType, entityID string) ([]models.TimeEntry, error) {
+	return s.timeEntryRepo.GetTimeEntriesByEntity(ctx, userID, entityType, entityID)
+}
+
+// getRunningEntry returns the running entry for a user, or nil if there is none.
+// An abandoned timer counts as not running; it is auto-stopped first when autoStop is set.
+func (s *timerService) getRunningEntry(ctx context.Context, userID string, autoStop bool) (*models.TimeEntry, error) {
+	running, err := s.timeEntryRepo.GetRunningTimeEntryByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	endedAt, abandoned := abandonedTimerEnd(running, time.Now(), userLocation(ctx, s.userRepo, userID))
+	if !abandoned {
+		return running, nil
+	}
+	if autoStop {
+		err := s.closeEntry(ctx, running, endedAt, "stopped", true)
+		if err != nil && !errors.Is(err, ErrNoActiveTimer) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// getActiveEntry returns the running entry, or the latest entry if it is paused, or nil.
+// autoStop is passed on to getRunningEntry.
+func (s *timerService) getActiveEntry(ctx context.Context, userID string, autoStop bool) (*models.TimeEntry, error) {
+	running, err := s.getRunningEntry(ctx, userID, autoStop)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		return running, nil
+	}
+
+	latest, err := s.timeEntryRepo.GetLatestTimeEntryByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if latest.Status != "paused" {
+		return nil, nil
+	}
+	return latest, nil
+}
+
+// getAutoStoppedEntry returns the user's latest entry if it was auto-stopped, or ErrNoActiveTimer.
+func (s *timerService) getAutoStoppedEntry(ctx context.Context, userID string) (*models.TimeEntry, error) {
+	latest, err := s.timeEntryRepo.GetLatestTimeEntryByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoActiveTimer
+		}
+		return nil, err
+	}
+	if !latest.AutoStopped {
+		return nil, ErrNoActiveTimer
+	}
+	return latest, nil
+}
+
+// abandonedTimerEnd reports whether a running entry was abandoned by now: it kept running past
+// abandonedTimerGrace after the local midnight following its start, or for longer than maxTimerDuration.
+// An abandoned entry is taken to have ended at that midnight, or at the cap when it comes first.
+func abandonedTimerEnd(entry *models.TimeEntry, now time.Time, loc *time.Location) (time.Time, bool) {
+	started := entry.StartedAt.In(loc)
+	midnight := time.Date(started.Year(), started.Month(), started.Day()+1, 0, 0, 0, 0, loc)
+	capped := entry.StartedAt.Add(maxTimerDuration)
+	if now.Before(midnight.Add(abandonedTimerGrace)) && now.Before(capped) {
+		return time.Time{}, false
+	}
+	if capped.Before(midnight) {
+		return capped, true
+	}
+	return midnight, true
+}
+
+// closeEntry ends a running entry at endedAt and, in the same transaction, rolls its duration into the
+// tracked entity and daily stats. It returns ErrNoActiveTimer when the entry was closed concurrently.
+func (s *timerService) closeEntry(ctx context.Context, entry *models.TimeEntry, endedAt time.Time, status string, autoStopped bool) error {
+	minutes := int32(math.Round(endedAt.Sub(entry.StartedAt).Minutes()))
+	if minutes < 0 {
+		minutes = 0
+	}
+
+	entry.EndedAt = sql.NullTime{Time: endedAt, Valid: true}
+	entry.DurationMinutes = sql.NullInt32{Int32: minutes, Valid: true}
+	entry.Status = status
+	entry.AutoStopped = autoStopped
+
+	loc := userLocation(ctx, s.userRepo, entry.UserID)
+	closed, err := s.timeEntryRepo.CloseTimeEntry(ctx, entry, "running", splitMinutesByDay(entry.StartedAt, endedAt, loc))
+	if err != nil {
+		return fmt.Errorf("failed to close timer: %w", err)
+	}
+	if !closed {
+		return ErrNoActiveTimer
+	}
+	return nil
+}
+
+// replacePausedEntry stops a paused entry and starts entry in its place. It returns ErrNoActiveTimer
+// when the paused entry was resumed or stopped concurrently.
+func (s *timerService) replacePausedEntry(ctx context.Context, paused, entry *models.TimeEntry) error {
+	replaced, err := s.timeEntryRepo.ReplacePausedTimeEntry(ctx, paused, entry)
+	if err != nil {
+		return err
+	}
+	if !replaced {
+		return ErrNoActiveTimer
+	}
+	return nil
+}
+
+// checkNoActivePomodoro returns ErrPomodoroActive when the user has a running or paused pomodoro, whose
+// focus time would otherwise be counted a second time by the timer.
+func (s *timerService) checkNoActivePomodoro(ctx context.Context, userID string) error {
+	state, err := s.pomodoroService.GetCurrentPomodoro(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrNoActivePomodoro) {
+			return nil
+		}
+		return fmt.Errorf("failed to get current pomodoro: %w", err)
+	}
+	if state.Pomodoro.Status == "running" || state.Pomodoro.Status == "paused" {
+		return ErrPomodoroActive
+	}
+	return nil
+}
+
+// checkEntityOwnership verifies that the timed entity exists and belongs to the user.
+func (s *timerService) checkEntityOwnership(ctx context.Context, userID, entityType, entityID string) error {
+	var ownerID string
+	switch entityType {
+	case "assignment":
+		assignment, err := s.assignmentRepo.GetAssignmentByID(ctx, entityID)
+		if err != nil {
+			return fmt.Errorf("assignment not found: %w", err)
+		}
+		ownerID = assignment.UserID
+	case "exam":
+		exam, err := s.examRepo.GetExamByID(ctx, entityID)
+		if err != nil {
+			return fmt.Errorf("exam not found: %w", err)
+		}
+		ownerID = exam.UserID
+	case "study_session":
+		session, err := s.sessionRepo.GetStudySessionByID(ctx, entityID)
+		if err != nil {
+			return fmt.Errorf("study session not found: %w", err)
+		}
+		ownerID = session.UserID
+	default:
+		return fmt.Errorf("invalid entity type: %s", entityType)
+	}
+
+	if ownerID != userID {
+		return fmt.Errorf("%s does not belong to user", entityType)
+	}
+	return nil
+}
+
+// markSessionStarted flags a study session as in progress when its first timer starts.
+func (s *timerService) markSessionStarted(ctx context.Context, entry *models.TimeEntry) error {
+	session, err := s.sessionRepo.GetStudySessionByID(ctx, entry.EntityID)
+	if err != nil {
+		return fmt.Errorf("study session not found: %w", err)
+	}
+	if !session.ActualStartTime.Valid {
+		session.ActualStartTime = sql.NullTime{Time: entry.StartedAt, Valid: true}
+	}
+	if session.Status == "planned" {
+		session.Status = "in_progress"
+	}
+	if err := s.sessionRepo.UpdateStudySession(ctx, session); err != nil {
+		return fmt.Errorf("failed to update study session: %w", err)
+	}
+	return nil
+}
+
+// userLocation returns the user's configured timezone, falling back to UTC.
+func userLocation(ctx context.Context, userRepo repository.UserRepository, userID string) *time.Location {
+	user, err := userRepo.GetUserByID(ctx, userID)
+	if err != nil || user.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// splitMinutesByDay splits the span between start and end into minutes per local calendar day.
+// The returned keys are midnight UTC dates suitable for a DATE column.
+func splitMinutesByDay(start, end time.Time, loc *time.Location) map[time.Time]int32 {
+	result := make(map[time.Time]int32)
+	start = start.In(loc)
+	end = end.In(loc)
+
+	for start.Before(end) {
+		nextMidnight := time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, loc)
+		segmentEnd := end
+		if nextMidnight.Before(end) {
+			segmentEnd = nextMidnight
+		}
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+		result[day] += int32(math.Round(segmentEnd.Sub(start).Minutes()))
+		start = segmentEnd
+	}
+	return result
+}
+
+// isUniqueViolation reports whether err was caused by a unique constraint, such as the one allowing a
+// single running timer per user.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
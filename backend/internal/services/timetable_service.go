@@ -2,15 +2,27 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/arran4/golang-ical" // Import the golang-ical library
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/events"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
 	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
 )
 
+// defaultExamDuration is the length of an exam's calendar event when it has no end time or duration.
+const defaultExamDuration = 3 * time.Hour
+
+// ErrInvalidClassAttendance is returned when attendance is recorded for a class that did not take place.
+var ErrInvalidClassAttendance = errors.New("invalid class attendance")
+
+// classSlotTypes are the slot types that count as classes for attendance.
+var classSlotTypes = []string{"lecture", "lab", "tutorial"}
+
 // TimetableService defines the interface for timetable-related business logic.
 type TimetableService interface {
 	CreateSubject(ctx context.Context, subject *models.Subject) error
@@ -23,6 +35,8 @@ type TimetableService interface {
 	GetUserTimetableByDay(ctx context.Context, userID string, dayOfWeek int32) ([]models.TimetableSlot, error)
 	GetUserTimetableByDateRange(ctx context.Context, userID string, start, end time.Time) ([]models.TimetableSlot, error)
 	GenerateICSCalendar(ctx context.Context, userID string, start, end time.Time) (string, error)
+	RecordClassAttendance(ctx context.Context, userID string, input *models.ClassAttendanceInput) (*models.ClassAttendance, error)
+	GetClassAttendance(ctx context.Context, userID string, fromDate, toDate time.Time) ([]models.ClassAttendance, error)
 }
 
 // timetableService implements TimetableService.
@@ -31,6 +45,12 @@ type timetableService struct {
 	staffRepo   repository.StaffRepository
 	venueRepo   repository.VenueRepository
 	slotRepo    repository.TimetableSlotRepository
+
+	attendanceRepo  repository.ClassAttendanceRepository
+	examRepo        repository.ExamRepository
+	examSeatingRepo repository.ExamSeatingRepository
+	userRepo        repository.UserRepository
+	eventBus        events.Bus
 }
 
 // NewTimetableService creates a new timetable service.
@@ -39,12 +59,22 @@ func NewTimetableService(
 	staffRepo repository.StaffRepository,
 	venueRepo repository.VenueRepository,
 	slotRepo repository.TimetableSlotRepository,
+	attendanceRepo repository.ClassAttendanceRepository,
+	examRepo repository.ExamRepository,
+	examSeatingRepo repository.ExamSeatingRepository,
+	userRepo repository.UserRepository,
+	eventBus events.Bus,
 ) TimetableService {
 	return &timetableService{
-		subjectRepo: subjectRepo,
-		staffRepo:   staffRepo,
-		venueRepo:   venueRepo,
-		slotRepo:    slotRepo,
+		subjectRepo:     subjectRepo,
+		staffRepo:       staffRepo,
+		venueRepo:       venueRepo,
+		slotRepo:        slotRepo,
+		attendanceRepo:  attendanceRepo,
+		examRepo:        examRepo,
+		examSeatingRepo: examSeatingRepo,
+		userRepo:        userRepo,
+		eventBus:        eventBus,
 	}
 }
 
@@ -93,6 +123,72 @@ func (s *timetableService) GetUserTimetableByDateRange(ctx context.Context, user
 	return s.slotRepo.GetTimetableSlotsByUserIDAndDateRange(ctx, userID, start, end)
 }
 
+// RecordClassAttendance records whether the user attended the class of one of their timetable slots on a
+// date. The slot must be an active lecture, lab or tutorial of the user's batch that takes place on that
+// date, and the date cannot be in the future. Recording a class again replaces the earlier record.
+func (s *timetableService) RecordClassAttendance(ctx context.Context, userID string, input *models.ClassAttendanceInput) (*models.ClassAttendance, error) {
+	classDate, err := time.Parse("2006-01-02", input.ClassDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid class date format: %w", ErrInvalidClassAttendance, err)
+	}
+	slot, err := s.slotRepo.GetTimetableSlotByID(ctx, input.SlotID)
+	if err != nil {
+		return nil, fmt.Errorf("timetable slot not found: %w", err)
+	}
+	if slot.UserID != userID {
+		return nil, fmt.Errorf("timetable slot does not belong to user")
+	}
+	if !slot.IsActive || !containsString(classSlotTypes, slot.SlotType) {
+		return nil, fmt.Errorf("%w: the slot is not an active lecture, lab or tutorial", ErrInvalidClassAttendance)
+	}
+	if slot.IsRecurring && int32(classDate.Weekday()) != slot.DayOfWeek ||
+		!slot.IsRecurring && (!slot.SpecificDate.Valid || !sameDate(slot.SpecificDate.Time, classDate)) {
+		return nil, fmt.Errorf("%w: the slot has no class on %s", ErrInvalidClassAttendance, input.ClassDate)
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	if slot.BatchFilter.Valid && (user.Batch == nil || *user.Batch != slot.BatchFilter.String) {
+		return nil, fmt.Errorf("%w: the slot is for another batch", ErrInvalidClassAttendance)
+	}
+	now := time.Now().In(userLocation(ctx, s.userRepo, userID))
+	if classDate.After(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)) {
+		return nil, fmt.Errorf("%w: the class date is in the future", ErrInvalidClassAttendance)
+	}
+
+	attendance := &models.ClassAttendance{
+		UserID:    userID,
+		SlotID:    slot.ID,
+		SubjectID: slot.SubjectID,
+		ClassDate: classDate,
+		Attended:  *input.Attended,
+	}
+	if err := s.attendanceRepo.UpsertClassAttendance(ctx, attendance); err != nil {
+		return nil, err
+	}
+
+	description := "Missed class on " + input.ClassDate
+	if attendance.Attended {
+		description = "Attended class on " + input.ClassDate
+	}
+	s.eventBus.Publish(ctx, events.Event{
+		Type:        events.ClassAttendanceRecorded,
+		UserID:      userID,
+		EntityType:  "class_attendance",
+		EntityID:    attendance.ID,
+		Description: description,
+		Metadata:    map[string]interface{}{"slotId": slot.ID, "classDate": input.ClassDate, "attended": attendance.Attended},
+	})
+	return attendance, nil
+}
+
+// GetClassAttendance retrieves the user's attendance records between two dates, inclusive.
+func (s *timetableService) GetClassAttendance(ctx context.Context, userID string, fromDate, toDate time.Time) ([]models.ClassAttendance, error) {
+	return s.attendanceRepo.GetClassAttendanceByUserIDAndDateRange(ctx, userID, fromDate, toDate)
+}
+
 // GenerateICSCalendar generates an ICS calendar string for a user's timetable within a date range.
 func (s *timetableService) GenerateICSCalendar(ctx context.Context, userID string, start, end time.Time) (string, error) {
 	slots, err := s.slotRepo.GetTimetableSlotsByUserIDAndDateRange(ctx, userID, start, end)
@@ -172,5 +268,88 @@ func (s *timetableService) GenerateICSCalendar(ctx context.Context, userID strin
 		// Example: event.AddRrule(fmt.Sprintf("FREQ=WEEKLY;BYDAY=%s", daysOfWeekShort[slot.DayOfWeek]))
 	}
 
+	if err := s.addExamEvents(ctx, cal, userID, start, end); err != nil {
+		return "", err
+	}
+
 	return cal.Serialize(), nil
 }
+
+// addExamEvents adds the user's exams within the date range to the calendar, with the seat, room
+// and reporting time from their hall ticket details where known.
+func (s *timetableService) addExamEvents(ctx context.Context, cal *ics.Calendar, userID string, start, end time.Time) error {
+	exams, err := s.examRepo.GetExamsByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve exams: %w", err)
+	}
+	seatings, err := s.examSeatingRepo.GetExamSeatingsByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve exam seatings: %w", err)
+	}
+	seatingByExam := make(map[string]*models.ExamSeating, len(seatings))
+	for i := range seatings {
+		seatingByExam[seatings[i].ExamID] = &seatings[i]
+	}
+	loc := userLocation(ctx, s.userRepo, userID)
+
+	for _, exam := range exams {
+		if exam.ExamDate.Before(start.Truncate(24*time.Hour)) || exam.ExamDate.After(end) {
+			continue
+		}
+		seating := seatingByExam[exam.ID]
+
+		var location []string
+		if seating != nil {
+			if seating.VenueName.Valid {
+				location = append(location, seating.VenueName.String)
+			}
+			if seating.Room.Valid {
+				location = append(location, "Room "+seating.Room.String)
+			}
+			if seating.SeatNumber.Valid {
+				location = append(location, "Seat "+seating.SeatNumber.String)
+			}
+		}
+		if len(location) == 0 && exam.VenueID.Valid {
+			if venue, err := s.venueRepo.GetVenueByID(ctx, exam.VenueID.String); err == nil {
+				location = append(location, venue.Name)
+			} else {
+				log.Printf("Warning: Could not find venue for exam %s: %v", exam.ID, err)
+			}
+		}
+
+		description := []string{fmt.Sprintf("Exam type: %s", exam.ExamType)}
+		if seating != nil {
+			if seating.SeatNumber.Valid {
+				description = append(description, "Seat: "+seating.SeatNumber.String)
+			}
+			if len(seating.ItemsAllowed) > 0 {
+				description = append(description, "Items allowed: "+strings.Join(seating.ItemsAllowed, ", "))
+			}
+			if seating.Instructions.Valid {
+				description = append(description, seating.Instructions.String)
+			}
+		}
+
+		event := cal.AddEvent("exam-" + exam.ID)
+		event.SetSummary(fmt.Sprintf("Exam: %s", exam.Title))
+		event.SetLocation(strings.Join(location, ", "))
+
+		if startsAt, reportBy, ok := examReportingTimes(exam, seating, loc); ok {
+			description = append([]string{"Report by: " + reportBy.Format("3:04 PM")}, description...)
+			endsAt := startsAt.Add(defaultExamDuration)
+			if examStart, examEnd, ok := examTimeRange(exam); ok {
+				endsAt = startsAt.Add(examEnd.Sub(examStart))
+			}
+			event.SetDtStart(startsAt)
+			event.SetDtEnd(endsAt)
+		} else {
+			// Without a start time the exam is an all-day event, which calendars show on its date alone.
+			day := time.Date(exam.ExamDate.Year(), exam.ExamDate.Month(), exam.ExamDate.Day(), 0, 0, 0, 0, time.UTC)
+			event.SetAllDayStartAt(day)
+			event.SetAllDayEndAt(day.AddDate(0, 0, 1))
+		}
+		event.SetDescription(strings.Join(description, "\n"))
+	}
+	return nil
+}
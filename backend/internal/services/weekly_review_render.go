@@ -0,0 +1,141 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+)
+
+// weeklyReviewData is what the weekly review templates are rendered with.
+type weeklyReviewData struct {
+	Name   string
+	Week   string
+	Loc    *time.Location // The user's timezone, for due times
+	Review *models.WeeklyReview
+}
+
+// weeklyReviewFuncs are the helpers shared by the HTML and text templates.
+var weeklyReviewFuncs = map[string]interface{}{
+	"hours": func(minutes int32) string {
+		return fmt.Sprintf("%.1f h", float64(minutes)/60)
+	},
+	"percent": func(value sql.NullFloat64) string {
+		if !value.Valid {
+			return "–"
+		}
+		return fmt.Sprintf("%.0f%%", value.Float64)
+	},
+	"change": func(value sql.NullFloat64) string {
+		if !value.Valid {
+			return ""
+		}
+		return fmt.Sprintf("%+.0f pts", value.Float64)
+	},
+	"prep": prepStatusLabel,
+	"date": func(date time.Time) string {
+		return date.Format("Mon Jan 2")
+	},
+	"localDate": func(loc *time.Location, t time.Time) string {
+		return t.In(loc).Format("Mon Jan 2")
+	},
+}
+
+var weeklyReviewHTMLTemplate = htmltemplate.Must(htmltemplate.New("weekly_review").Funcs(weeklyReviewFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Your week in review: {{.Week}}</title>
+</head>
+<body style="margin:0;padding:24px;background:#f4f5f7;font-family:Arial,Helvetica,sans-serif;color:#1f2933;">
+<div style="max-width:640px;margin:0 auto;background:#ffffff;border-radius:8px;padding:24px;">
+<h1 style="font-size:22px;margin:0 0 4px;">Your week in review</h1>
+<p style="margin:0 0 24px;color:#616e7c;">{{.Week}}{{if .Name}} · {{.Name}}{{end}}</p>
+{{with .Review.Report}}
+<h2 style="font-size:17px;">Study sessions</h2>
+<p>Completed <strong>{{.Sessions.Completed}}</strong> of {{.Sessions.Planned}} planned sessions ({{percent .Sessions.CompletionRate}}){{if .Sessions.Partial}}, {{.Sessions.Partial}} partly{{end}}{{if .Sessions.Skipped}}, {{.Sessions.Skipped}} skipped{{end}}.
+Studied <strong>{{hours .Sessions.ActualMinutes}}</strong> of {{hours .Sessions.PlannedMinutes}} planned.</p>
+{{if .SubjectHours}}
+<h2 style="font-size:17px;">Hours per subject</h2>
+<table style="width:100%;border-collapse:collapse;">
+<tr style="text-align:left;color:#616e7c;"><th>Subject</th><th>This week</th><th>Last week</th></tr>
+{{range .SubjectHours}}<tr><td>{{.SubjectName}}</td><td>{{hours .Minutes}}</td><td>{{hours .PreviousMinutes}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2 style="font-size:17px;">Assignments</h2>
+<p>Completed {{len .Assignments.Completed}} ({{.Assignments.CompletedOnTime}} on time), missed {{len .Assignments.Missed}}.</p>
+{{if .Assignments.Missed}}<ul>{{range .Assignments.Missed}}<li>{{.Title}}, due {{localDate $.Loc .DueDate}}</li>{{end}}</ul>{{end}}
+{{if .ExamPrep}}
+<h2 style="font-size:17px;">Exam prep</h2>
+<ul>{{range .ExamPrep}}<li>{{.Title}} on {{date .ExamDate}} ({{.DaysLeft}} days): {{prep .PrepStatus}}{{if .Changed}}, was {{prep .PreviousStatus.String}}{{end}}</li>{{end}}</ul>
+{{end}}
+<h2 style="font-size:17px;">Attendance</h2>
+<p>{{if .Attendance.TotalClasses}}Attended {{.Attendance.ClassesAttended}} of {{.Attendance.TotalClasses}} classes ({{percent .Attendance.AttendanceRate}}){{if .Attendance.Change.Valid}}, {{change .Attendance.Change}} on last week{{end}}.{{else}}No classes this week.{{end}}</p>
+{{if .SuggestedFocus}}
+<h2 style="font-size:17px;">Focus for next week</h2>
+<ol>{{range .SuggestedFocus}}<li>{{.Message}}</li>{{end}}</ol>
+{{end}}
+{{end}}
+</div>
+</body>
+</html>
+`))
+
+var weeklyReviewTextTemplate = texttemplate.Must(texttemplate.New("weekly_review").Funcs(weeklyReviewFuncs).Parse(`Your week in review: {{.Week}}
+{{with .Review.Report}}
+STUDY SESSIONS
+Completed {{.Sessions.Completed}} of {{.Sessions.Planned}} planned sessions ({{percent .Sessions.CompletionRate}}). Studied {{hours .Sessions.ActualMinutes}} of {{hours .Sessions.PlannedMinutes}} planned.
+{{if .SubjectHours}}
+HOURS PER SUBJECT
+{{range .SubjectHours}}- {{.SubjectName}}: {{hours .Minutes}} (last week {{hours .PreviousMinutes}})
+{{end}}{{end}}
+ASSIGNMENTS
+Completed {{len .Assignments.Completed}} ({{.Assignments.CompletedOnTime}} on time), missed {{len .Assignments.Missed}}.
+{{range .Assignments.Missed}}- Missed: {{.Title}}, due {{localDate $.Loc .DueDate}}
+{{end}}{{if .ExamPrep}}
+EXAM PREP
+{{range .ExamPrep}}- {{.Title}} on {{date .ExamDate}} ({{.DaysLeft}} days): {{prep .PrepStatus}}{{if .Changed}}, was {{prep .PreviousStatus.String}}{{end}}
+{{end}}{{end}}
+ATTENDANCE
+{{if .Attendance.TotalClasses}}Attended {{.Attendance.ClassesAttended}} of {{.Attendance.TotalClasses}} classes ({{percent .Attendance.AttendanceRate}}){{if .Attendance.Change.Valid}}, {{change .Attendance.Change}} on last week{{end}}.{{else}}No classes this week.{{end}}
+{{if .SuggestedFocus}}
+FOCUS FOR NEXT WEEK
+{{range .SuggestedFocus}}- {{.Message}}
+{{end}}{{end}}{{end}}`))
+
+// renderWeeklyReviewHTML renders a weekly review as a standalone HTML page with inline styles, so that it
+// displays the same in a browser and in email clients.
+func renderWeeklyReviewHTML(review *models.WeeklyReview, user *models.User) (string, error) {
+	var out bytes.Buffer
+	if err := weeklyReviewHTMLTemplate.Execute(&out, weeklyReviewTemplateData(review, user)); err != nil {
+		return "", fmt.Errorf("failed to render weekly review: %w", err)
+	}
+	return out.String(), nil
+}
+
+// renderWeeklyReviewText renders a weekly review as plain text for email clients without HTML.
+func renderWeeklyReviewText(review *models.WeeklyReview, user *models.User) (string, error) {
+	var out bytes.Buffer
+	if err := weeklyReviewTextTemplate.Execute(&out, weeklyReviewTemplateData(review, user)); err != nil {
+		return "", fmt.Errorf("failed to render weekly review: %w", err)
+	}
+	return out.String(), nil
+}
+
+// weeklyReviewTemplateData prepares a review for rendering in its user's timezone.
+func weeklyReviewTemplateData(review *models.WeeklyReview, user *models.User) weeklyReviewData {
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil || user.Timezone == "" {
+		loc = time.UTC
+	}
+	return weeklyReviewData{
+		Name:   user.FullName,
+		Week:   weekRangeLabel(review.WeekStart, review.WeekEnd),
+		Loc:    loc,
+		Review: review,
+	}
+}
@@ -0,0 +1,578 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/mailer"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/models"
+	"github.com/princetheprogrammer/campus-pilot/backend/internal/repository"
+)
+
+// ErrWeeklyReviewNotFound is returned when a weekly review does not exist.
+var ErrWeeklyReviewNotFound = errors.New("weekly review not found")
+
+const (
+	// weeklyReviewHour is the local hour on Sunday from which the week's review is generated.
+	weeklyReviewHour = 18
+	// examPrepSnapshotGraceDays is how many days after a week the current prep statuses still count as
+	// that week's, so that a review generated late by the scheduler keeps its prep section.
+	examPrepSnapshotGraceDays = 1
+	// maxWeeklyReviewEmailAttempts is how many failed sends of a review the scheduler makes before it
+	// stops retrying; the user can still email the review on request.
+	maxWeeklyReviewEmailAttempts = 3
+	// focusExamDays is how soon after the week an exam must be for its prep to be a suggested focus.
+	focusExamDays = 14
+	// maxFocusSuggestions caps the suggestions in a review.
+	maxFocusSuggestions = 5
+	// lowAttendanceRate and lowCompletionRate are the percentages below which attendance or session
+	// completion becomes a suggested focus.
+	lowAttendanceRate = 75.0
+	lowCompletionRate = 60.0
+)
+
+// WeeklyReviewService defines the interface for weekly review reports.
+type WeeklyReviewService interface {
+	GenerateWeeklyReview(ctx context.Context, userID string, input *models.WeeklyReviewGenerateInput) (*models.WeeklyReview, error)
+	GetWeeklyReviews(ctx context.Context, userID string) ([]models.WeeklyReview, error)
+	GetWeeklyReview(ctx context.Context, userID, id string) (*models.WeeklyReview, error)
+	RenderWeeklyReviewHTML(ctx context.Context, userID, id string) (string, error)
+	EmailWeeklyReview(ctx context.Context, userID, id string) (*models.WeeklyReview, error)
+	GenerateDueReviews(ctx context.Context) error
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
+
+// weeklyReviewService implements WeeklyReviewService.
+type weeklyReviewService struct {
+	weeklyReviewRepo    repository.WeeklyReviewRepository
+	exportRepo          repository.ExportRepository
+	examRepo            repository.ExamRepository
+	assignmentRepo      repository.AssignmentRepository
+	dailyStatsRepo      repository.DailyStatsRepository
+	subjectRepo         repository.SubjectRepository
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+	mailer              mailer.Mailer
+}
+
+// NewWeeklyReviewService creates a new weekly review service.
+func NewWeeklyReviewService(
+	weeklyReviewRepo repository.WeeklyReviewRepository,
+	exportRepo repository.ExportRepository,
+	examRepo repository.ExamRepository,
+	assignmentRepo repository.AssignmentRepository,
+	dailyStatsRepo repository.DailyStatsRepository,
+	subjectRepo repository.SubjectRepository,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+	mailer mailer.Mailer,
+) WeeklyReviewService {
+	return &weeklyReviewService{
+		weeklyReviewRepo:    weeklyReviewRepo,
+		exportRepo:          exportRepo,
+		examRepo:            examRepo,
+		assignmentRepo:      assignmentRepo,
+		dailyStatsRepo:      dailyStatsRepo,
+		subjectRepo:         subjectRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		mailer:              mailer,
+	}
+}
+
+// GenerateWeeklyReview builds, or rebuilds, the user's review of a week. Any date of the week can be given;
+// without one the current week is reviewed so far.
+func (s *weeklyReviewService) GenerateWeeklyReview(ctx context.Context, userID string, input *models.WeeklyReviewGenerateInput) (*models.WeeklyReview, error) {
+	loc := userLocation(ctx, s.userRepo, userID)
+	currentWeek := weekStartOf(time.Now().In(loc))
+	weekStart := currentWeek
+	if input.WeekStart != nil && *input.WeekStart != "" {
+		date, err := time.Parse("2006-01-02", *input.WeekStart)
+		if err != nil {
+			return nil, fmt.Errorf("invalid week start format: %w", err)
+		}
+		weekStart = weekStartOf(date)
+	}
+	if weekStart.After(currentWeek) {
+		return nil, fmt.Errorf("%w: the week has not started yet", ErrInvalidDateRange)
+	}
+	return s.generate(ctx, userID, weekStart, loc)
+}
+
+// GetWeeklyReviews retrieves the user's weekly reviews, newest week first.
+func (s *weeklyReviewService) GetWeeklyReviews(ctx context.Context, userID string) ([]models.WeeklyReview, error) {
+	reviews, err := s.weeklyReviewRepo.GetWeeklyReviewsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if reviews == nil {
+		reviews = []models.WeeklyReview{}
+	}
+	return reviews, nil
+}
+
+// GetWeeklyReview retrieves one of the user's weekly reviews.
+func (s *weeklyReviewService) GetWeeklyReview(ctx context.Context, userID, id string) (*models.WeeklyReview, error) {
+	review, err := s.weeklyReviewRepo.GetWeeklyReviewByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrWeeklyReviewNotFound, id)
+		}
+		return nil, err
+	}
+	if review.UserID != userID {
+		return nil, fmt.Errorf("weekly review does not belong to user")
+	}
+	return review, nil
+}
+
+// RenderWeeklyReviewHTML renders one of the user's weekly reviews as a standalone HTML page, the same page
+// that is emailed.
+func (s *weeklyReviewService) RenderWeeklyReviewHTML(ctx context.Context, userID, id string) (string, error) {
+	review, err := s.GetWeeklyReview(ctx, userID, id)
+	if err != nil {
+		return "", err
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("user not found: %w", err)
+	}
+	return renderWeeklyReviewHTML(review, user)
+}
+
+// EmailWeeklyReview emails one of the user's weekly reviews to them and records when it was sent.
+func (s *weeklyReviewService) EmailWeeklyReview(ctx context.Context, userID, id string) (*models.WeeklyReview, error) {
+	review, err := s.GetWeeklyReview(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.email(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// GenerateDueReviews generates the review of each active user's most recently finished week if it does
+// not exist yet or was generated before the week finished, then notifies the user and emails it when email
+// is configured. A week counts as finished from weeklyReviewHour on its Sunday, in the user's timezone.
+// Weeks without any activity are skipped, and a review whose email failed is emailed again, up to
+// maxWeeklyReviewEmailAttempts times.
+func (s *weeklyReviewService) GenerateDueReviews(ctx context.Context) error {
+	userIDs, err := s.userRepo.GetActiveUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		loc := userLocation(ctx, s.userRepo, userID)
+		now := time.Now().In(loc)
+		weekStart := weekStartOf(now)
+		if now.Weekday() != time.Sunday || now.Hour() < weeklyReviewHour {
+			weekStart = weekStart.AddDate(0, 0, -7)
+		}
+
+		weekEnd := weekStart.AddDate(0, 0, 6)
+		finishedAt := time.Date(weekEnd.Year(), weekEnd.Month(), weekEnd.Day(), weeklyReviewHour, 0, 0, 0, loc)
+		existing, err := s.weeklyReviewRepo.GetWeeklyReviewByUserIDAndWeek(ctx, userID, weekStart)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("Warning: Could not check weekly review for user %s: %v", userID, err)
+			continue
+		}
+		// A review generated on request while the week was still running is replaced with the full week.
+		if err == nil && !existing.UpdatedAt.Before(finishedAt) {
+			if !existing.EmailedAt.Valid && existing.EmailAttempts < maxWeeklyReviewEmailAttempts && s.mailer.Enabled() {
+				if err := s.email(ctx, existing); err != nil {
+					log.Printf("Warning: Could not email weekly review to user %s: %v", userID, err)
+				}
+			}
+			continue
+		}
+
+		review, err := s.build(ctx, userID, weekStart, loc)
+		if err != nil {
+			log.Printf("Warning: Could not build weekly review for user %s: %v", userID, err)
+			continue
+		}
+		if isEmptyWeeklyReport(&review.Report) {
+			continue
+		}
+		if err := s.weeklyReviewRepo.UpsertWeeklyReview(ctx, review); err != nil {
+			log.Printf("Warning: Could not save weekly review for user %s: %v", userID, err)
+			continue
+		}
+
+		title := fmt.Sprintf("Your week in review: %s", weekRangeLabel(review.WeekStart, review.WeekEnd))
+		message := fmt.Sprintf("Completed %d of %d planned sessions and %d assignments.",
+			review.Report.Sessions.Completed, review.Report.Sessions.Planned, len(review.Report.Assignments.Completed))
+		if _, err := s.notificationService.Notify(ctx, userID, "weekly_review", title, message, "weekly_review", review.ID,
+			"weekly_review:"+review.WeekStart.Format("2006-01-02")); err != nil {
+			log.Printf("Warning: Could not notify user %s of weekly review: %v", userID, err)
+		}
+		if s.mailer.Enabled() {
+			if err := s.email(ctx, review); err != nil {
+				log.Printf("Warning: Could not email weekly review to user %s: %v", userID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// RunScheduler generates due weekly reviews right away and then every interval, until the context is
+// cancelled. The interval should be at most an hour so reviews go out soon after each user's week ends.
+func (s *weeklyReviewService) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.GenerateDueReviews(ctx); err != nil {
+			log.Printf("Warning: Weekly review generation failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// generate builds and stores the user's review of the week starting on weekStart.
+func (s *weeklyReviewService) generate(ctx context.Context, userID string, weekStart time.Time, loc *time.Location) (*models.WeeklyReview, error) {
+	review, err := s.build(ctx, userID, weekStart, loc)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.weeklyReviewRepo.UpsertWeeklyReview(ctx, review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// email sends a review to its user and records when it was sent, or counts the failed attempt.
+func (s *weeklyReviewService) email(ctx context.Context, review *models.WeeklyReview) error {
+	if !s.mailer.Enabled() {
+		return mailer.ErrNotConfigured
+	}
+	user, err := s.userRepo.GetUserByID(ctx, review.UserID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	html, err := renderWeeklyReviewHTML(review, user)
+	if err != nil {
+		return err
+	}
+	text, err := renderWeeklyReviewText(review, user)
+	if err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("Your week in review: %s", weekRangeLabel(review.WeekStart, review.WeekEnd))
+	if err := s.mailer.Send(ctx, user.Email, subject, text, html); err != nil {
+		attempts, recordErr := s.weeklyReviewRepo.RecordWeeklyReviewEmailFailure(ctx, review.ID)
+		if recordErr != nil {
+			log.Printf("Warning: Could not record failed email of weekly review %s: %v", review.ID, recordErr)
+		} else {
+			review.EmailAttempts = attempts
+		}
+		return err
+	}
+	emailedAt := time.Now()
+	if err := s.weeklyReviewRepo.MarkWeeklyReviewEmailed(ctx, review.ID, emailedAt); err != nil {
+		return err
+	}
+	review.EmailedAt = sql.NullTime{Time: emailedAt, Valid: true}
+	return nil
+}
+
+// build computes the user's review of the week starting on weekStart, a midnight UTC Monday interpreted in loc.
+func (s *weeklyReviewService) build(ctx context.Context, userID string, weekStart time.Time, loc *time.Location) (*models.WeeklyReview, error) {
+	weekEnd := weekStart.AddDate(0, 0, 6)
+	start := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 7)
+	previousStart := start.AddDate(0, 0, -7)
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	report := models.WeeklyReviewReport{
+		SubjectHours:   []models.WeeklySubjectHours{},
+		ExamPrep:       []models.WeeklyExamPrep{},
+		SuggestedFocus: []models.FocusSuggestion{},
+		Assignments: models.WeeklyAssignmentSummary{
+			Completed: []models.WeeklyReviewAssignment{},
+			Missed:    []models.WeeklyReviewAssignment{},
+		},
+	}
+
+	// Sessions of the week, plus the week before for the subject hour comparison.
+	minutes := map[string]int32{}
+	previousMinutes := map[string]int32{}
+	sessions := &report.Sessions
+	err := s.exportRepo.StreamStudySessions(ctx, userID, previousStart, end, func(session *models.StudySession) error {
+		at := session.CreatedAt
+		if session.ActualStartTime.Valid {
+			at = session.ActualStartTime.Time
+		} else if session.PlannedStartTime.Valid {
+			at = session.PlannedStartTime.Time
+		}
+		studied := session.Status == "completed" || session.Status == "partial"
+		if at.Before(start) {
+			if studied {
+				previousMinutes[session.SubjectID.String] += actualSessionMinutes(session)
+			}
+			return nil
+		}
+
+		sessions.Planned++
+		sessions.PlannedMinutes += plannedSessionMinutes(session)
+		switch session.Status {
+		case "completed":
+			sessions.Completed++
+		case "partial":
+			sessions.Partial++
+		case "skipped":
+			sessions.Skipped++
+		default:
+			sessions.NotStarted++
+		}
+		if studied {
+			actual := actualSessionMinutes(session)
+			sessions.ActualMinutes += actual
+			minutes[session.SubjectID.String] += actual
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sessions.Planned > 0 {
+		sessions.CompletionRate = sql.NullFloat64{Float64: roundTo(float64(sessions.Completed)/float64(sessions.Planned)*100, 1), Valid: true}
+	}
+
+	subjectNames := map[string]string{"": "No subject"}
+	subjectName := func(subjectID string) string {
+		if name, ok := subjectNames[subjectID]; ok {
+			return name
+		}
+		name := subjectID
+		if subject, err := s.subjectRepo.GetSubjectByID(ctx, subjectID); err == nil {
+			name = subject.Name
+		}
+		subjectNames[subjectID] = name
+		return name
+	}
+	for subjectID := range previousMinutes {
+		if _, ok := minutes[subjectID]; !ok {
+			minutes[subjectID] = 0
+		}
+	}
+	for subjectID, spent := range minutes {
+		report.SubjectHours = append(report.SubjectHours, models.WeeklySubjectHours{
+			SubjectID:       subjectID,
+			SubjectName:     subjectName(subjectID),
+			Minutes:         spent,
+			Hours:           roundTo(float64(spent)/60, 1),
+			PreviousMinutes: previousMinutes[subjectID],
+		})
+	}
+	sort.Slice(report.SubjectHours, func(i, j int) bool {
+		if report.SubjectHours[i].Minutes != report.SubjectHours[j].Minutes {
+			return report.SubjectHours[i].Minutes > report.SubjectHours[j].Minutes
+		}
+		return report.SubjectHours[i].SubjectName < report.SubjectHours[j].SubjectName
+	})
+
+	// Assignments completed during the week, and those that fell due in it without being completed.
+	assignments, err := s.weeklyReviewRepo.GetWeekAssignments(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, assignment := range assignments {
+		completed := assignment.CompletedAt.Valid
+		if completed && !assignment.CompletedAt.Time.Before(start) && assignment.CompletedAt.Time.Before(end) {
+			report.Assignments.Completed = append(report.Assignments.Completed, assignment)
+			if !assignment.CompletedAt.Time.After(assignment.DueDate) {
+				report.Assignments.CompletedOnTime++
+			}
+		}
+		done := assignment.Status == "completed" || assignment.Status == "submitted" || assignment.Status == "graded"
+		dueInWeek := !assignment.DueDate.Before(start) && assignment.DueDate.Before(end)
+		if dueInWeek && (!done || (completed && !assignment.CompletedAt.Time.Before(end))) {
+			report.Assignments.Missed = append(report.Assignments.Missed, assignment)
+		}
+	}
+
+	// Prep status of the exams still ahead at the end of the week, compared with the previous week's
+	// snapshot. Statuses are only known at the time they are taken, so once the week is over its review
+	// keeps the snapshot taken during the week, or has no prep section.
+	if today.After(weekEnd.AddDate(0, 0, examPrepSnapshotGraceDays)) {
+		if existing, err := s.weeklyReviewRepo.GetWeeklyReviewByUserIDAndWeek(ctx, userID, weekStart); err == nil {
+			if isExamPrepSnapshotOf(&existing.Report, weekEnd) {
+				report.ExamPrep = existing.Report.ExamPrep
+				report.ExamPrepAsOf = existing.Report.ExamPrepAsOf
+			}
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+	} else {
+		asOf := today
+		if asOf.After(weekEnd) {
+			asOf = weekEnd
+		}
+		previousStatus := map[string]string{}
+		if previous, err := s.weeklyReviewRepo.GetWeeklyReviewByUserIDAndWeek(ctx, userID, weekStart.AddDate(0, 0, -7)); err == nil {
+			if isExamPrepSnapshotOf(&previous.Report, weekStart.AddDate(0, 0, -1)) {
+				for _, prep := range previous.Report.ExamPrep {
+					previousStatus[prep.ExamID] = prep.PrepStatus
+				}
+			}
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+		exams, err := s.examRepo.GetExamsByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, exam := range exams {
+			examDay := time.Date(exam.ExamDate.Year(), exam.ExamDate.Month(), exam.ExamDate.Day(), 0, 0, 0, 0, time.UTC)
+			if examDay.Before(asOf) {
+				continue
+			}
+			prep := models.WeeklyExamPrep{
+				ExamID:     exam.ID,
+				Title:      exam.Title,
+				ExamDate:   exam.ExamDate,
+				DaysLeft:   int32(examDay.Sub(asOf).Hours() / 24),
+				PrepStatus: exam.PrepStatus,
+			}
+			if status, ok := previousStatus[exam.ID]; ok {
+				prep.PreviousStatus = sql.NullString{String: status, Valid: true}
+				prep.Changed = status != exam.PrepStatus
+			}
+			report.ExamPrep = append(report.ExamPrep, prep)
+		}
+		report.ExamPrepAsOf = sql.NullTime{Time: today, Valid: true}
+	}
+
+	// Attendance of the week against the week before.
+	statsList, err := s.dailyStatsRepo.GetDailyStatsByUserIDAndDateRange(ctx, userID, weekStart.AddDate(0, 0, -7), weekEnd)
+	if err != nil {
+		return nil, err
+	}
+	var previousAttended, previousTotal int32
+	for _, stats := range statsList {
+		if statDay(stats.StatDate).Before(weekStart) {
+			previousAttended += stats.ClassesAttended
+			previousTotal += stats.TotalClasses
+			continue
+		}
+		report.Attendance.ClassesAttended += stats.ClassesAttended
+		report.Attendance.TotalClasses += stats.TotalClasses
+	}
+	if report.Attendance.TotalClasses > 0 {
+		report.Attendance.AttendanceRate = sql.NullFloat64{Float64: roundTo(float64(report.Attendance.ClassesAttended)/float64(report.Attendance.TotalClasses)*100, 1), Valid: true}
+	}
+	if previousTotal > 0 {
+		report.Attendance.PreviousRate = sql.NullFloat64{Float64: roundTo(float64(previousAttended)/float64(previousTotal)*100, 1), Valid: true}
+	}
+	if report.Attendance.AttendanceRate.Valid && report.Attendance.PreviousRate.Valid {
+		report.Attendance.Change = sql.NullFloat64{Float64: roundTo(report.Attendance.AttendanceRate.Float64-report.Attendance.PreviousRate.Float64, 1), Valid: true}
+	}
+
+	pending, err := s.assignmentRepo.GetPendingAssignmentsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	report.SuggestedFocus = suggestFocus(&report, pending, end, loc)
+
+	return &models.WeeklyReview{
+		UserID:    userID,
+		WeekStart: weekStart,
+		WeekEnd:   weekEnd,
+		Report:    report,
+	}, nil
+}
+
+// suggestFocus picks what to focus on next week, most pressing first: exams close by that are not well
+// prepared, missed and upcoming assignments, low attendance, low session completion and subjects that
+// got no time this week after getting some the week before.
+func suggestFocus(report *models.WeeklyReviewReport, pending []models.Assignment, weekEnd time.Time, loc *time.Location) []models.FocusSuggestion {
+	suggestions := []models.FocusSuggestion{}
+	add := func(kind, message, entityType, entityID string) {
+		suggestions = append(suggestions, models.FocusSuggestion{
+			Kind:       kind,
+			Message:    message,
+			EntityType: sql.NullString{String: entityType, Valid: entityType != ""},
+			EntityID:   sql.NullString{String: entityID, Valid: entityID != ""},
+		})
+	}
+
+	for _, prep := range report.ExamPrep {
+		if prep.DaysLeft <= focusExamDays && (prep.PrepStatus == "not_started" || prep.PrepStatus == "in_progress") {
+			add("exam_prep", fmt.Sprintf("Prepare for %s: %d days left and prep is %s", prep.Title, prep.DaysLeft, prepStatusLabel(prep.PrepStatus)), "exam", prep.ExamID)
+		}
+	}
+	for _, assignment := range report.Assignments.Missed {
+		add("missed_assignment", fmt.Sprintf("Finish %s, which was due %s", assignment.Title, assignment.DueDate.In(loc).Format("Mon Jan 2")), "assignment", assignment.ID)
+	}
+	nextWeekEnd := weekEnd.AddDate(0, 0, 7)
+	for _, assignment := range pending {
+		if !assignment.DueDate.Before(weekEnd) && assignment.DueDate.Before(nextWeekEnd) {
+			add("assignment", fmt.Sprintf("Start %s, due %s", assignment.Title, assignment.DueDate.In(loc).Format("Mon Jan 2")), "assignment", assignment.ID)
+		}
+	}
+	if rate := report.Attendance.AttendanceRate; rate.Valid && rate.Float64 < lowAttendanceRate {
+		add("attendance", fmt.Sprintf("Attendance was %.0f%% this week; aim to attend every class", rate.Float64), "", "")
+	}
+	if rate := report.Sessions.CompletionRate; rate.Valid && rate.Float64 < lowCompletionRate && report.Sessions.Planned >= 3 {
+		add("plan_adherence", fmt.Sprintf("Only %d of %d planned sessions were completed; plan fewer, shorter sessions", report.Sessions.Completed, report.Sessions.Planned), "", "")
+	}
+	for _, hours := range report.SubjectHours {
+		if hours.Minutes == 0 && hours.PreviousMinutes > 0 && hours.SubjectID != "" {
+			add("neglected_subject", fmt.Sprintf("No study time on %s this week, down from %.1f hours", hours.SubjectName, float64(hours.PreviousMinutes)/60), "subject", hours.SubjectID)
+		}
+	}
+
+	if len(suggestions) > maxFocusSuggestions {
+		suggestions = suggestions[:maxFocusSuggestions]
+	}
+	return suggestions
+}
+
+// isExamPrepSnapshotOf reports whether a review's prep statuses were taken in the week ending on weekEnd,
+// or late enough after it to still count.
+func isExamPrepSnapshotOf(report *models.WeeklyReviewReport, weekEnd time.Time) bool {
+	return report.ExamPrepAsOf.Valid && !statDay(report.ExamPrepAsOf.Time).After(weekEnd.AddDate(0, 0, examPrepSnapshotGraceDays))
+}
+
+// isEmptyWeeklyReport reports whether a week had nothing worth reviewing.
+func isEmptyWeeklyReport(report *models.WeeklyReviewReport) bool {
+	return report.Sessions.Planned == 0 && len(report.Assignments.Completed) == 0 && len(report.Assignments.Missed) == 0 &&
+		report.Attendance.TotalClasses == 0 && len(report.ExamPrep) == 0
+}
+
+// weekStartOf returns the Monday of the week containing date, as a midnight UTC calendar date.
+func weekStartOf(date time.Time) time.Time {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// weekRangeLabel formats a week as e.g. "Mar 2 – Mar 8, 2026".
+func weekRangeLabel(weekStart, weekEnd time.Time) string {
+	return fmt.Sprintf("%s – %s", weekStart.Format("Jan 2"), weekEnd.Format("Jan 2, 2006"))
+}
+
+// prepStatusLabel turns a prep status into words, e.g. "not started".
+func prepStatusLabel(status string) string {
+	switch status {
+	case "not_started":
+		return "not started"
+	case "in_progress":
+		return "in progress"
+	}
+	return status
+}